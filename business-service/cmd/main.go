@@ -2,8 +2,10 @@ package main
 
 import (
 	"business-service/internal/config"
+	"business-service/internal/metrics"
 	"business-service/internal/server"
 	"business-service/internal/signals"
+	"business-service/internal/tracing"
 	"context"
 	"log"
 )
@@ -13,6 +15,14 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	shutdownTracing, err := tracing.Setup(ctx, cfg.TraceExporter)
+	if err != nil {
+		log.Fatalf("failed to set up tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
+	go metrics.Serve(cfg.MetricsAddr)
+
 	server.RunBusinessServer(cfg)
 
 	signals.WaitForShutdown(ctx, cancel)
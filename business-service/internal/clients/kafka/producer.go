@@ -1,6 +1,7 @@
 package kafka
 
 import (
+	"business-service/internal/tracing"
 	"context"
 	"encoding/base64"
 	kafka "github.com/segmentio/kafka-go"
@@ -9,17 +10,22 @@ import (
 	"time"
 )
 
-func PublishAlgoGraph(broker, topic, path string) {
+// PublishAlgoGraph отправляет картинку графа с контекстом trace в заголовках.
+// Отправка не прерывается вместе с запросом, ограничена только своим таймаутом.
+func PublishAlgoGraph(ctx context.Context, broker, topic, path string) {
 	writer := NewKafkaWriter(broker, topic)
 	encoded, err := encodeFile(path)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
 	defer cancel()
 
-	err = writer.WriteMessages(ctx, kafka.Message{
+	msg := kafka.Message{
 		Key:   []byte("image"),
 		Value: []byte(encoded),
-	})
+	}
+	tracing.InjectKafka(ctx, &msg)
+
+	err = writer.WriteMessages(ctx, msg)
 
 	if err != nil {
 		log.Printf("Ошибка при отправке картинки: %v\n", err)
@@ -11,6 +11,9 @@ type Config struct {
 	BusinessAddr string
 	KafkaBroker  string
 	KafkaTopic   string
+	MetricsAddr  string
+	// none или otlp; адрес коллектора задаётся OTEL_EXPORTER_OTLP_ENDPOINT
+	TraceExporter string
 }
 
 func Load() *Config {
@@ -20,9 +23,11 @@ func Load() *Config {
 	}
 
 	return &Config{
-		LoggerAddr:   os.Getenv("LOGGER_ADDR"),
-		BusinessAddr: os.Getenv("BUSINESS_ADDR"),
-		KafkaBroker:  os.Getenv("KAFKA_BROKER"),
-		KafkaTopic:   os.Getenv("KAFKA_TOPIC"),
+		LoggerAddr:    os.Getenv("LOGGER_ADDR"),
+		BusinessAddr:  os.Getenv("BUSINESS_ADDR"),
+		KafkaBroker:   os.Getenv("KAFKA_BROKER"),
+		KafkaTopic:    os.Getenv("KAFKA_TOPIC"),
+		MetricsAddr:   os.Getenv("METRICS_ADDR"),
+		TraceExporter: os.Getenv("TRACE_EXPORTER"),
 	}
 }
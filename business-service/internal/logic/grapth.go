@@ -7,7 +7,9 @@ import (
 	"strings"
 )
 
-func ExportToDOT(ops []*gen.Operation, alive map[string]bool, graph map[string][]string, filename string) error {
+// ExportToDOT записывает граф в filename и возвращает его DOT-описание,
+// которое сохраняется и при ошибке записи.
+func ExportToDOT(ops []*gen.Operation, alive map[string]bool, graph map[string][]string, filename string) (string, error) {
 	var sb strings.Builder
 	sb.WriteString("digraph G {\n")
 	sb.WriteString("  rankdir=LR;\n")
@@ -38,5 +40,5 @@ func ExportToDOT(ops []*gen.Operation, alive map[string]bool, graph map[string][
 	}
 
 	sb.WriteString("}\n")
-	return os.WriteFile(filename, []byte(sb.String()), 0644)
+	return sb.String(), os.WriteFile(filename, []byte(sb.String()), 0644)
 }
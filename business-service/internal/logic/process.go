@@ -2,14 +2,28 @@ package logic
 
 import (
 	"business-service/gen"
+	"business-service/internal/metrics"
+	"business-service/internal/tracing"
 	"container/list"
+	"context"
 	"fmt"
+	"go.opentelemetry.io/otel/attribute"
 	"strconv"
 	"sync"
 	"time"
 )
 
+// ProgressFunc получает число выполненных вычислений после каждой волны.
+type ProgressFunc func(completed, total int)
+
 func Process(operations []*gen.Operation, required map[string]bool) ([]*gen.VariableValue, []string) {
+	result, brokenVars, _ := ProcessWithProgress(context.Background(), operations, required, nil)
+	return result, brokenVars
+}
+
+// ProcessWithProgress выполняет операции волнами, как Process, сообщает о ходе
+// выполнения и прекращает запуск новых волн после отмены ctx.
+func ProcessWithProgress(ctx context.Context, operations []*gen.Operation, required map[string]bool, progressFn ProgressFunc) ([]*gen.VariableValue, []string, error) {
 	vars := NewVarStore()
 	var result []*gen.VariableValue
 	brokenVars := make([]string, 0, 10)
@@ -19,7 +33,23 @@ func Process(operations []*gen.Operation, required map[string]bool) ([]*gen.Vari
 
 	pending := append([]*gen.Operation{}, operations...)
 
+	total := countRequiredCalcs(operations, required)
+	completed := 0
+	if progressFn != nil {
+		progressFn(completed, total)
+	}
+
+	metrics.DeadOperationsEliminated.Add(float64(countDeadCalcs(operations, required)))
+	waves := 0
+	defer func() {
+		metrics.OperationsExecuted.Add(float64(completed))
+		metrics.WavesPerRequest.Observe(float64(waves))
+	}()
+
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
 
 		var (
 			progress  bool
@@ -33,6 +63,8 @@ func Process(operations []*gen.Operation, required map[string]bool) ([]*gen.Vari
 			break
 		}
 
+		// Каждая волна — отдельный span, чтобы в trace было видно, где тратится время
+		_, waveSpan := tracing.Start(ctx, "wave", attribute.Int("wave", waves+1), attribute.Int("ready", len(readyOps)))
 		for _, op := range readyOps {
 			wg.Add(1)
 			go func(op *gen.Operation) {
@@ -40,12 +72,20 @@ func Process(operations []*gen.Operation, required map[string]bool) ([]*gen.Vari
 				if doCalc(vars, op.GetVar(), op.GetLeft(), op.GetRight(), op.GetOp()) {
 					mu.Lock()
 					progress = true // Дает возможность добавить доп. условия
+					completed++
 					mu.Unlock()
 				}
 			}(op)
 		}
 
 		wg.Wait()
+		waves++
+		waveSpan.SetAttributes(attribute.Int("completed", completed))
+		waveSpan.End()
+
+		if progressFn != nil {
+			progressFn(completed, total)
+		}
 
 		if !progress {
 			break
@@ -55,8 +95,31 @@ func Process(operations []*gen.Operation, required map[string]bool) ([]*gen.Vari
 	}
 
 	processPrint(vars, &result, operations, &brokenVars)
+	metrics.BrokenPrints.Add(float64(len(brokenVars)))
 	fmt.Println(result)
-	return result, brokenVars
+	return result, brokenVars, nil
+}
+
+// Каждая переменная вычисляется один раз, поэтому считаем уникальные имена.
+func countRequiredCalcs(operations []*gen.Operation, required map[string]bool) int {
+	seen := make(map[string]bool, len(operations))
+	for _, op := range operations {
+		if op.GetType() == "calc" && required[op.GetVar()] {
+			seen[op.GetVar()] = true
+		}
+	}
+	return len(seen)
+}
+
+// Мёртвые операции — вычисления, от которых не зависит ни один print.
+func countDeadCalcs(operations []*gen.Operation, required map[string]bool) int {
+	dead := 0
+	for _, op := range operations {
+		if op.GetType() == "calc" && !required[op.GetVar()] {
+			dead++
+		}
+	}
+	return dead
 }
 
 func processPrint(vars *VarStore, result *[]*gen.VariableValue, operations []*gen.Operation, brokenVars *[]string) {
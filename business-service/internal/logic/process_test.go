@@ -2,6 +2,13 @@ package logic
 
 import (
 	"business-service/gen"
+	"business-service/internal/metrics"
+	"business-service/internal/tracing"
+	"business-service/internal/tracing/tracingtest"
+	"context"
+	"errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"reflect"
 	"testing"
 )
 
@@ -210,3 +217,103 @@ func TestProcess(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessWithProgress(t *testing.T) {
+	operations := []*gen.Operation{
+		{Type: "calc", Op: "+", Var: "a", Left: "1", Right: "2"},
+		{Type: "calc", Op: "*", Var: "b", Left: "a", Right: "3"},
+		{Type: "calc", Op: "-", Var: "c", Left: "b", Right: "a"},
+		{Type: "calc", Op: "+", Var: "dead", Left: "1", Right: "1"},
+		{Type: "print", Var: "c"},
+	}
+	required, _ := FindAliveVariables(operations)
+
+	var reports [][2]int
+	result, broken, err := ProcessWithProgress(context.Background(), operations, required, func(completed, total int) {
+		reports = append(reports, [2]int{completed, total})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(broken) != 0 || len(result) != 1 || result[0].GetValue() != 6 {
+		t.Fatalf("unexpected result %v, broken %v", result, broken)
+	}
+
+	want := [][2]int{{0, 3}, {1, 3}, {2, 3}, {3, 3}}
+	if !reflect.DeepEqual(reports, want) {
+		t.Errorf("expected progress %v, got %v", want, reports)
+	}
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, _, err := ProcessWithProgress(ctx, operations, required, nil)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+}
+
+func TestProcessMetrics(t *testing.T) {
+	operations := []*gen.Operation{
+		{Type: "calc", Op: "+", Var: "a", Left: "1", Right: "2"},
+		{Type: "calc", Op: "*", Var: "b", Left: "a", Right: "3"},
+		{Type: "calc", Op: "+", Var: "dead1", Left: "1", Right: "1"},
+		{Type: "calc", Op: "+", Var: "dead2", Left: "dead1", Right: "1"},
+		{Type: "print", Var: "b"},
+		{Type: "print", Var: "missing"},
+	}
+	required, _ := FindAliveVariables(operations)
+
+	executed := testutil.ToFloat64(metrics.OperationsExecuted)
+	dead := testutil.ToFloat64(metrics.DeadOperationsEliminated)
+	broken := testutil.ToFloat64(metrics.BrokenPrints)
+
+	if _, _, err := ProcessWithProgress(context.Background(), operations, required, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := testutil.ToFloat64(metrics.OperationsExecuted) - executed; got != 2 {
+		t.Errorf("expected 2 executed operations, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.DeadOperationsEliminated) - dead; got != 2 {
+		t.Errorf("expected 2 dead operations, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.BrokenPrints) - broken; got != 1 {
+		t.Errorf("expected 1 broken print, got %v", got)
+	}
+	if n := testutil.CollectAndCount(metrics.WavesPerRequest); n != 1 {
+		t.Errorf("expected waves histogram to be exported, got %d series", n)
+	}
+}
+
+func TestProcessWithProgressWaveSpans(t *testing.T) {
+	exporter := tracingtest.NewInMemory()
+	operations := []*gen.Operation{
+		{Type: "calc", Op: "+", Var: "a", Left: "1", Right: "2"},
+		{Type: "calc", Op: "*", Var: "b", Left: "a", Right: "3"},
+		{Type: "print", Var: "b"},
+	}
+	required, _ := FindAliveVariables(operations)
+
+	ctx, root := tracing.Start(context.Background(), "evaluate")
+	if _, _, err := ProcessWithProgress(ctx, operations, required, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	root.End()
+
+	waves := 0
+	for _, span := range exporter.GetSpans() {
+		if span.Name != "wave" {
+			continue
+		}
+		waves++
+		if span.Parent.SpanID() != root.SpanContext().SpanID() {
+			t.Errorf("expected wave span to be a child of evaluate")
+		}
+	}
+	if waves != 2 {
+		t.Errorf("expected 2 wave spans, got %d", waves)
+	}
+}
@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"log"
+	"net/http"
+)
+
+// Метрики регистрируются в стандартном реестре Prometheus, поэтому вместе с ними
+// отдаются go_* и process_* коллекторы.
+var (
+	OperationsExecuted = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "business_operations_executed_total",
+		Help: "Calc operations evaluated successfully.",
+	})
+
+	DeadOperationsEliminated = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "business_dead_operations_eliminated_total",
+		Help: "Calc operations skipped because no print depends on them.",
+	})
+
+	WavesPerRequest = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "business_waves_per_request",
+		Help:    "Number of parallel evaluation waves needed for one request.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 8),
+	})
+
+	BrokenPrints = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "business_broken_prints_total",
+		Help: "Prints of variables that could not be evaluated.",
+	})
+
+	GRPCServerHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_handled_total",
+		Help: "Completed incoming gRPC calls by service, method and status code.",
+	}, []string{"grpc_service", "grpc_method", "grpc_code"})
+
+	GRPCServerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_handling_seconds",
+		Help:    "Incoming gRPC call latency until the handler returns.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"grpc_service", "grpc_method"})
+
+	GRPCClientHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_client_handled_total",
+		Help: "Completed outgoing gRPC calls by service, method and status code.",
+	}, []string{"grpc_service", "grpc_method", "grpc_code"})
+
+	GRPCClientDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_client_handling_seconds",
+		Help:    "Outgoing gRPC call latency until the final status is received.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"grpc_service", "grpc_method"})
+)
+
+// Serve отдаёт /metrics на отдельном адресе, так как сам сервис работает только по gRPC.
+// Пустой адрес отключает эндпоинт.
+func Serve(addr string) {
+	if addr == "" {
+		log.Println("METRICS_ADDR is not set — metrics endpoint is disabled")
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	log.Printf("metrics server started on %s", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Printf("metrics server stopped: %v", err)
+	}
+}
@@ -6,8 +6,11 @@ import (
 	"business-service/internal/clients/kafka"
 	"business-service/internal/config"
 	"business-service/internal/logic"
+	"business-service/internal/tracing"
 	"context"
 	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"os/exec"
 	"reflect"
@@ -21,30 +24,57 @@ type BusinessLogicManager struct {
 }
 
 func (blm *BusinessLogicManager) Process(ctx context.Context, req *gen.OperationRequest) (*gen.OperationResponse, error) {
+	return blm.process(ctx, req, nil)
+}
+
+// ProcessStream выполняет ту же обработку, что и Process, но после каждой волны
+// вычислений отправляет клиенту прогресс. Последнее сообщение содержит результат.
+func (blm *BusinessLogicManager) ProcessStream(req *gen.OperationRequest, stream gen.BusinessLogic_ProcessStreamServer) error {
+	// Заголовки сообщают клиенту, что задача принята, ещё до первой волны
+	if err := stream.SendHeader(nil); err != nil {
+		return err
+	}
+	var completed, total int32
+	var sendErr error
+	resp, err := blm.process(stream.Context(), req, func(c, t int) {
+		completed, total = int32(c), int32(t)
+		if sendErr != nil {
+			return
+		}
+		sendErr = stream.Send(&gen.ProcessProgress{Completed: completed, Total: total})
+	})
+	if err != nil {
+		return err
+	}
+	if sendErr != nil {
+		return sendErr
+	}
+
+	return stream.Send(&gen.ProcessProgress{Completed: completed, Total: total, Result: resp})
+}
+
+func (blm *BusinessLogicManager) process(ctx context.Context, req *gen.OperationRequest, progressFn logic.ProgressFunc) (*gen.OperationResponse, error) {
 
 	cfg := config.Load()
 
 	operations := req.GetOperations()
 
+	_, span := tracing.Start(ctx, "find alive variables", attribute.Int("operations", len(operations)))
 	aliveVars, graph := logic.FindAliveVariables(operations)
+	span.SetAttributes(attribute.Int("alive", len(aliveVars)))
+	span.End()
 
-	err := logic.ExportToDOT(operations, aliveVars, graph, "graph.dot")
-
-	if err != nil {
-		fmt.Println("Error during export:", err)
-	} else {
-		cmd := exec.Command("dot", "-Tpng", "graph.dot", "-o", "graph.png")
-		if err := cmd.Run(); err != nil {
-			fmt.Println("Error generating PNG:", err)
-		} else {
-			fmt.Println("graph.png successfully generated")
-			kafka.PublishAlgoGraph(cfg.KafkaBroker, cfg.KafkaTopic, "graph.png")
-		}
-	}
+	dot := renderGraph(ctx, cfg, operations, aliveVars, graph)
 
 	start := time.Now()
 	fmt.Println("Программа запущена")
-	resultItems, brokenItems := logic.Process(operations, aliveVars)
+	evalCtx, span := tracing.Start(ctx, "evaluate")
+	resultItems, brokenItems, err := logic.ProcessWithProgress(evalCtx, operations, aliveVars, progressFn)
+	span.SetAttributes(attribute.Int("broken_prints", len(brokenItems)))
+	tracing.End(span, err)
+	if err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
 
 	elapsed := time.Since(start)
 	fmt.Printf("Время выполнения: %s\n", elapsed)
@@ -53,13 +83,15 @@ func (blm *BusinessLogicManager) Process(ctx context.Context, req *gen.Operation
 		Items: resultItems,
 	}
 
-	entry := formLogEntry(req, resp)
+	entry := formLogEntry(req, resp, dot)
 
 	if blm.GRPCClient == nil {
 		fmt.Println("GRPCClient is nil — log clients not initialized... Proceeding without it")
 	}
 	if blm.GRPCClient != nil && !isNil(blm.GRPCClient.LoggerClient) {
-		responseLog, err := blm.GRPCClient.LogDataGRPC(ctx, entry)
+		logCtx, span := tracing.Start(ctx, "log result")
+		responseLog, err := blm.GRPCClient.LogDataGRPC(logCtx, entry)
+		tracing.End(span, err)
 		if err != nil {
 			fmt.Println("Something went wrong during logging: ", err.Error())
 		}
@@ -82,14 +114,39 @@ func (blm *BusinessLogicManager) Process(ctx context.Context, req *gen.Operation
 
 }
 
-func formLogEntry(req *gen.OperationRequest, opsResp *gen.OperationResponse) *gen.LogEntry {
+// renderGraph рисует граф зависимостей через graphviz, публикует картинку в Kafka
+// и возвращает DOT-описание графа для лога результата.
+func renderGraph(ctx context.Context, cfg *config.Config, operations []*gen.Operation, aliveVars map[string]bool, graph map[string][]string) (dot string) {
+	ctx, span := tracing.Start(ctx, "render graph")
+	var err error
+	defer func() { tracing.End(span, err) }()
+
+	if dot, err = logic.ExportToDOT(operations, aliveVars, graph, "graph.dot"); err != nil {
+		fmt.Println("Error during export:", err)
+		return
+	}
+
+	cmd := exec.Command("dot", "-Tpng", "graph.dot", "-o", "graph.png")
+	if err = cmd.Run(); err != nil {
+		fmt.Println("Error generating PNG:", err)
+		return
+	}
+	fmt.Println("graph.png successfully generated")
+	kafka.PublishAlgoGraph(ctx, cfg.KafkaBroker, cfg.KafkaTopic, "graph.png")
+	return dot
+}
+
+// formLogEntry связывает результат с логом входящего запроса через request_id и parent_id.
+func formLogEntry(req *gen.OperationRequest, opsResp *gen.OperationResponse, dot string) *gen.LogEntry {
 
 	return &gen.LogEntry{
 		ServiceName: "business-server",
 		Level:       "INFO",
+		RequestId:   req.GetLogID().GetId(),
+		ParentId:    req.GetLogID().GetId(),
 		Message: &gen.StructuredMessage{
-			Path:   req.GetLogID().GetId(),
 			Result: opsResp,
+			Graph:  dot,
 		},
 		Metadata:      nil,
 		TimestampSend: 0,
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"business-service/gen"
+	"testing"
+)
+
+func TestFormLogEntryCorrelatesWithRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       *gen.OperationRequest
+		wantReqID string
+	}{
+		{name: "request was logged", req: &gen.OperationRequest{LogID: &gen.LogID{Id: "req123"}}, wantReqID: "req123"},
+		{name: "log service was unavailable", req: &gen.OperationRequest{}, wantReqID: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &gen.OperationResponse{Items: []*gen.VariableValue{{Var: "x", Value: 1}}}
+			entry := formLogEntry(tt.req, resp, "digraph G {}")
+
+			if entry.GetRequestId() != tt.wantReqID || entry.GetParentId() != tt.wantReqID {
+				t.Errorf("expected request_id and parent_id %q, got %q and %q", tt.wantReqID, entry.GetRequestId(), entry.GetParentId())
+			}
+			if entry.GetMessage().GetPath() != "" {
+				t.Errorf("expected path to stay empty, got %q", entry.GetMessage().GetPath())
+			}
+			if entry.GetMessage().GetResult() != resp || entry.GetMessage().GetGraph() != "digraph G {}" {
+				t.Errorf("expected result and graph in message, got %v", entry.GetMessage())
+			}
+		})
+	}
+}
@@ -4,7 +4,11 @@ import (
 	"business-service/gen"
 	grpcClient "business-service/internal/clients/grpc/log"
 	"business-service/internal/config"
+	"business-service/internal/metrics"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"log"
 	"net"
 )
@@ -24,8 +28,20 @@ func RunBusinessServer(cfg *config.Config) {
 	}
 }
 
+// StartGRPCServer регистрирует BusinessLogic и стандартный grpc.health.v1.
+// Лог-сервис не влияет на статус: без него вычисления продолжаются.
+// Контекст trace принимается из метаданных вызова (traceparent).
 func StartGRPCServer(listener net.Listener, businessServer gen.BusinessLogicServer) error {
-	s := grpc.NewServer()
+	s := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(metrics.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(metrics.StreamServerInterceptor()),
+	)
 	gen.RegisterBusinessLogicServer(s, businessServer)
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(s, healthServer)
+	healthServer.SetServingStatus(gen.BusinessLogic_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+
 	return s.Serve(listener)
 }
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"business-service/gen"
+	"business-service/internal/metrics"
+	"business-service/internal/tracing"
+	"business-service/internal/tracing/tracingtest"
+	"context"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"testing"
+)
+
+func startTestServer(t *testing.T, servers ...gen.BusinessLogicServer) *grpc.ClientConn {
+	t.Helper()
+	var businessServer gen.BusinessLogicServer = gen.UnimplementedBusinessLogicServer{}
+	if len(servers) > 0 {
+		businessServer = servers[0]
+	}
+	lis := bufconn.Listen(1024 * 1024)
+	go StartGRPCServer(lis, businessServer)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestStartGRPCServerRegistersHealth(t *testing.T) {
+	conn := startTestServer(t)
+
+	client := healthpb.NewHealthClient(conn)
+	for _, service := range []string{"", gen.BusinessLogic_ServiceDesc.ServiceName} {
+		resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("health check %q failed: %v", service, err)
+		}
+		if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			t.Errorf("service %q: expected SERVING, got %v", service, resp.GetStatus())
+		}
+	}
+}
+
+func TestStartGRPCServerRecordsMetrics(t *testing.T) {
+	conn := startTestServer(t)
+
+	counter := metrics.GRPCServerHandled.WithLabelValues("gen.BusinessLogic", "Process", "Unimplemented")
+	before := testutil.ToFloat64(counter)
+
+	_, err := gen.NewBusinessLogicClient(conn).Process(context.Background(), &gen.OperationRequest{})
+	if err == nil {
+		t.Fatal("expected Unimplemented error")
+	}
+
+	if got := testutil.ToFloat64(counter) - before; got != 1 {
+		t.Errorf("expected 1 handled call, got %v", got)
+	}
+}
+
+type traceRecorder struct {
+	gen.UnimplementedBusinessLogicServer
+	traceID string
+}
+
+func (s *traceRecorder) Process(ctx context.Context, req *gen.OperationRequest) (*gen.OperationResponse, error) {
+	s.traceID = tracing.TraceID(ctx)
+	return &gen.OperationResponse{}, nil
+}
+
+func TestStartGRPCServerContinuesTrace(t *testing.T) {
+	tracingtest.NewInMemory()
+	recorder := &traceRecorder{}
+	conn := startTestServer(t, recorder)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	if _, err := gen.NewBusinessLogicClient(conn).Process(ctx, &gen.OperationRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if recorder.traceID != traceID {
+		t.Errorf("expected handler to run in trace %s, got %q", traceID, recorder.traceID)
+	}
+}
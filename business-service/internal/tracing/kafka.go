@@ -0,0 +1,44 @@
+package tracing
+
+import (
+	"context"
+	kafka "github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+)
+
+// KafkaCarrier передаёт контекст trace в заголовках сообщения Kafka (traceparent, baggage).
+type KafkaCarrier struct {
+	Headers *[]kafka.Header
+}
+
+func (c KafkaCarrier) Get(key string) string {
+	for _, h := range *c.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c KafkaCarrier) Set(key, value string) {
+	for i, h := range *c.Headers {
+		if h.Key == key {
+			(*c.Headers)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c.Headers = append(*c.Headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c KafkaCarrier) Keys() []string {
+	keys := make([]string, 0, len(*c.Headers))
+	for _, h := range *c.Headers {
+		keys = append(keys, h.Key)
+	}
+	return keys
+}
+
+// InjectKafka записывает контекст trace из ctx в заголовки msg.
+func InjectKafka(ctx context.Context, msg *kafka.Message) {
+	otel.GetTextMapPropagator().Inject(ctx, KafkaCarrier{Headers: &msg.Headers})
+}
@@ -0,0 +1,27 @@
+package tracing
+
+import (
+	"context"
+	kafka "github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"testing"
+)
+
+func TestInjectKafka(t *testing.T) {
+	Install()
+
+	ctx, span := Start(context.Background(), "publish")
+	defer span.End()
+
+	msg := kafka.Message{Headers: []kafka.Header{{Key: "traceparent", Value: []byte("stale")}}}
+	InjectKafka(ctx, &msg)
+
+	if len(msg.Headers) != 1 {
+		t.Fatalf("expected traceparent to be replaced, got headers %v", msg.Headers)
+	}
+
+	extracted := otel.GetTextMapPropagator().Extract(context.Background(), KafkaCarrier{Headers: &msg.Headers})
+	if got := TraceID(extracted); got != TraceID(ctx) {
+		t.Errorf("expected trace %s after extraction, got %q", TraceID(ctx), got)
+	}
+}
@@ -0,0 +1,27 @@
+// Package tracingtest ставит провайдер трассировки для тестов. Пакет
+// импортируется только из тестов, поэтому tracetest не попадает в сборку сервиса.
+package tracingtest
+
+import (
+	"business-service/internal/tracing"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"sync"
+)
+
+var (
+	memoryOnce sync.Once
+	memory     *tracetest.InMemoryExporter
+)
+
+// NewInMemory ставит провайдер с синхронным экспортом в память для тестов.
+// Глобальный провайдер делегируется только один раз, поэтому экспортер общий
+// для всех тестов процесса и очищается при каждом вызове.
+func NewInMemory() *tracetest.InMemoryExporter {
+	memoryOnce.Do(func() {
+		memory = tracetest.NewInMemoryExporter()
+		tracing.Install(sdktrace.WithSyncer(memory))
+	})
+	memory.Reset()
+	return memory
+}
@@ -3,12 +3,17 @@ package main
 import (
 	"context"
 	"dashboard-service/internal/config"
+	"dashboard-service/internal/health"
 	"dashboard-service/internal/kafka"
 	"dashboard-service/internal/kafka/consumer"
+	"dashboard-service/internal/metrics"
 	"dashboard-service/internal/signals"
+	"dashboard-service/internal/tracing"
 	wscd "dashboard-service/internal/ws"
 	"github.com/gorilla/websocket"
 	"log"
+	"net/http"
+	"time"
 )
 
 func main() {
@@ -17,6 +22,12 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	shutdownTracing, err := tracing.Setup(ctx, cfg.TraceExporter)
+	if err != nil {
+		log.Fatalf("failed to set up tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	kafkaConn, err := kafka.Connect(ctx, cfg)
 	if err != nil {
 		log.Println(err)
@@ -24,8 +35,15 @@ func main() {
 	defer kafkaConn.Close()
 	clients := &wscd.Clients{Clients: make(map[*websocket.Conn]bool)}
 
+	tracker := health.NewTracker(30 * time.Second)
+	http.HandleFunc("/healthz", health.LivenessHandler())
+	http.HandleFunc("/readyz", tracker.ReadinessHandler())
+
+	metrics.RegisterClients(clients.Count)
+	http.Handle("/metrics", metrics.Handler())
+
 	go wscd.StartWebSocket(clients, cfg)
-	go consumer.StartAll(clients, ctx, cfg.KafkaBroker)
+	go consumer.StartAll(clients, ctx, cfg.KafkaBroker, tracker)
 
 	log.Println("dashboard-service is running...")
 
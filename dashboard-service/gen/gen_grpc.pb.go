@@ -19,9 +19,18 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Logger_HandleIncomingLog_FullMethodName = "/gen.Logger/HandleIncomingLog"
-	Logger_DeleteLog_FullMethodName         = "/gen.Logger/DeleteLog"
-	Logger_ReadLog_FullMethodName           = "/gen.Logger/ReadLog"
+	Logger_HandleIncomingLog_FullMethodName  = "/gen.Logger/HandleIncomingLog"
+	Logger_IngestLogs_FullMethodName         = "/gen.Logger/IngestLogs"
+	Logger_DeleteLog_FullMethodName          = "/gen.Logger/DeleteLog"
+	Logger_RestoreLog_FullMethodName         = "/gen.Logger/RestoreLog"
+	Logger_ReadLog_FullMethodName            = "/gen.Logger/ReadLog"
+	Logger_GetRequestTimeline_FullMethodName = "/gen.Logger/GetRequestTimeline"
+	Logger_SearchLogs_FullMethodName         = "/gen.Logger/SearchLogs"
+	Logger_DeleteLogs_FullMethodName         = "/gen.Logger/DeleteLogs"
+	Logger_TailLogs_FullMethodName           = "/gen.Logger/TailLogs"
+	Logger_SetLogPolicy_FullMethodName       = "/gen.Logger/SetLogPolicy"
+	Logger_GetLogPolicies_FullMethodName     = "/gen.Logger/GetLogPolicies"
+	Logger_GetOutboxStats_FullMethodName     = "/gen.Logger/GetOutboxStats"
 )
 
 // LoggerClient is the client API for Logger service.
@@ -29,8 +38,17 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type LoggerClient interface {
 	HandleIncomingLog(ctx context.Context, in *LogEntry, opts ...grpc.CallOption) (*LogCreationResponse, error)
+	IngestLogs(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[LogEntry, LogIngestResponse], error)
 	DeleteLog(ctx context.Context, in *LogInfo, opts ...grpc.CallOption) (*LogDeletionResponse, error)
+	RestoreLog(ctx context.Context, in *LogInfo, opts ...grpc.CallOption) (*LogRestorationResponse, error)
 	ReadLog(ctx context.Context, in *LogInfo, opts ...grpc.CallOption) (*LogReadingResponse, error)
+	GetRequestTimeline(ctx context.Context, in *RequestTimelineQuery, opts ...grpc.CallOption) (*RequestTimeline, error)
+	SearchLogs(ctx context.Context, in *LogSearchQuery, opts ...grpc.CallOption) (*LogSearchResult, error)
+	DeleteLogs(ctx context.Context, in *LogDeleteQuery, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogDeleteProgress], error)
+	TailLogs(ctx context.Context, in *LogTailQuery, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogTailEvent], error)
+	SetLogPolicy(ctx context.Context, in *LogPolicy, opts ...grpc.CallOption) (*LogPolicies, error)
+	GetLogPolicies(ctx context.Context, in *Nothing, opts ...grpc.CallOption) (*LogPolicies, error)
+	GetOutboxStats(ctx context.Context, in *Nothing, opts ...grpc.CallOption) (*OutboxStats, error)
 }
 
 type loggerClient struct {
@@ -51,6 +69,19 @@ func (c *loggerClient) HandleIncomingLog(ctx context.Context, in *LogEntry, opts
 	return out, nil
 }
 
+func (c *loggerClient) IngestLogs(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[LogEntry, LogIngestResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Logger_ServiceDesc.Streams[0], Logger_IngestLogs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[LogEntry, LogIngestResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Logger_IngestLogsClient = grpc.ClientStreamingClient[LogEntry, LogIngestResponse]
+
 func (c *loggerClient) DeleteLog(ctx context.Context, in *LogInfo, opts ...grpc.CallOption) (*LogDeletionResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(LogDeletionResponse)
@@ -61,6 +92,16 @@ func (c *loggerClient) DeleteLog(ctx context.Context, in *LogInfo, opts ...grpc.
 	return out, nil
 }
 
+func (c *loggerClient) RestoreLog(ctx context.Context, in *LogInfo, opts ...grpc.CallOption) (*LogRestorationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogRestorationResponse)
+	err := c.cc.Invoke(ctx, Logger_RestoreLog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *loggerClient) ReadLog(ctx context.Context, in *LogInfo, opts ...grpc.CallOption) (*LogReadingResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(LogReadingResponse)
@@ -71,13 +112,110 @@ func (c *loggerClient) ReadLog(ctx context.Context, in *LogInfo, opts ...grpc.Ca
 	return out, nil
 }
 
+func (c *loggerClient) GetRequestTimeline(ctx context.Context, in *RequestTimelineQuery, opts ...grpc.CallOption) (*RequestTimeline, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequestTimeline)
+	err := c.cc.Invoke(ctx, Logger_GetRequestTimeline_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loggerClient) SearchLogs(ctx context.Context, in *LogSearchQuery, opts ...grpc.CallOption) (*LogSearchResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogSearchResult)
+	err := c.cc.Invoke(ctx, Logger_SearchLogs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loggerClient) DeleteLogs(ctx context.Context, in *LogDeleteQuery, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogDeleteProgress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Logger_ServiceDesc.Streams[1], Logger_DeleteLogs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[LogDeleteQuery, LogDeleteProgress]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Logger_DeleteLogsClient = grpc.ServerStreamingClient[LogDeleteProgress]
+
+func (c *loggerClient) TailLogs(ctx context.Context, in *LogTailQuery, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogTailEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Logger_ServiceDesc.Streams[2], Logger_TailLogs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[LogTailQuery, LogTailEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Logger_TailLogsClient = grpc.ServerStreamingClient[LogTailEvent]
+
+func (c *loggerClient) SetLogPolicy(ctx context.Context, in *LogPolicy, opts ...grpc.CallOption) (*LogPolicies, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogPolicies)
+	err := c.cc.Invoke(ctx, Logger_SetLogPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loggerClient) GetLogPolicies(ctx context.Context, in *Nothing, opts ...grpc.CallOption) (*LogPolicies, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogPolicies)
+	err := c.cc.Invoke(ctx, Logger_GetLogPolicies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loggerClient) GetOutboxStats(ctx context.Context, in *Nothing, opts ...grpc.CallOption) (*OutboxStats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OutboxStats)
+	err := c.cc.Invoke(ctx, Logger_GetOutboxStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LoggerServer is the server API for Logger service.
 // All implementations must embed UnimplementedLoggerServer
 // for forward compatibility.
 type LoggerServer interface {
 	HandleIncomingLog(context.Context, *LogEntry) (*LogCreationResponse, error)
+	IngestLogs(grpc.ClientStreamingServer[LogEntry, LogIngestResponse]) error
 	DeleteLog(context.Context, *LogInfo) (*LogDeletionResponse, error)
+	RestoreLog(context.Context, *LogInfo) (*LogRestorationResponse, error)
 	ReadLog(context.Context, *LogInfo) (*LogReadingResponse, error)
+	GetRequestTimeline(context.Context, *RequestTimelineQuery) (*RequestTimeline, error)
+	SearchLogs(context.Context, *LogSearchQuery) (*LogSearchResult, error)
+	DeleteLogs(*LogDeleteQuery, grpc.ServerStreamingServer[LogDeleteProgress]) error
+	TailLogs(*LogTailQuery, grpc.ServerStreamingServer[LogTailEvent]) error
+	SetLogPolicy(context.Context, *LogPolicy) (*LogPolicies, error)
+	GetLogPolicies(context.Context, *Nothing) (*LogPolicies, error)
+	GetOutboxStats(context.Context, *Nothing) (*OutboxStats, error)
 	mustEmbedUnimplementedLoggerServer()
 }
 
@@ -91,12 +229,39 @@ type UnimplementedLoggerServer struct{}
 func (UnimplementedLoggerServer) HandleIncomingLog(context.Context, *LogEntry) (*LogCreationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method HandleIncomingLog not implemented")
 }
+func (UnimplementedLoggerServer) IngestLogs(grpc.ClientStreamingServer[LogEntry, LogIngestResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method IngestLogs not implemented")
+}
 func (UnimplementedLoggerServer) DeleteLog(context.Context, *LogInfo) (*LogDeletionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteLog not implemented")
 }
+func (UnimplementedLoggerServer) RestoreLog(context.Context, *LogInfo) (*LogRestorationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreLog not implemented")
+}
 func (UnimplementedLoggerServer) ReadLog(context.Context, *LogInfo) (*LogReadingResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ReadLog not implemented")
 }
+func (UnimplementedLoggerServer) GetRequestTimeline(context.Context, *RequestTimelineQuery) (*RequestTimeline, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRequestTimeline not implemented")
+}
+func (UnimplementedLoggerServer) SearchLogs(context.Context, *LogSearchQuery) (*LogSearchResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchLogs not implemented")
+}
+func (UnimplementedLoggerServer) DeleteLogs(*LogDeleteQuery, grpc.ServerStreamingServer[LogDeleteProgress]) error {
+	return status.Errorf(codes.Unimplemented, "method DeleteLogs not implemented")
+}
+func (UnimplementedLoggerServer) TailLogs(*LogTailQuery, grpc.ServerStreamingServer[LogTailEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method TailLogs not implemented")
+}
+func (UnimplementedLoggerServer) SetLogPolicy(context.Context, *LogPolicy) (*LogPolicies, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLogPolicy not implemented")
+}
+func (UnimplementedLoggerServer) GetLogPolicies(context.Context, *Nothing) (*LogPolicies, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLogPolicies not implemented")
+}
+func (UnimplementedLoggerServer) GetOutboxStats(context.Context, *Nothing) (*OutboxStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOutboxStats not implemented")
+}
 func (UnimplementedLoggerServer) mustEmbedUnimplementedLoggerServer() {}
 func (UnimplementedLoggerServer) testEmbeddedByValue()                {}
 
@@ -136,6 +301,13 @@ func _Logger_HandleIncomingLog_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Logger_IngestLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LoggerServer).IngestLogs(&grpc.GenericServerStream[LogEntry, LogIngestResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Logger_IngestLogsServer = grpc.ClientStreamingServer[LogEntry, LogIngestResponse]
+
 func _Logger_DeleteLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(LogInfo)
 	if err := dec(in); err != nil {
@@ -154,6 +326,24 @@ func _Logger_DeleteLog_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Logger_RestoreLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogInfo)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoggerServer).RestoreLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Logger_RestoreLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoggerServer).RestoreLog(ctx, req.(*LogInfo))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Logger_ReadLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(LogInfo)
 	if err := dec(in); err != nil {
@@ -172,6 +362,118 @@ func _Logger_ReadLog_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Logger_GetRequestTimeline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestTimelineQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoggerServer).GetRequestTimeline(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Logger_GetRequestTimeline_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoggerServer).GetRequestTimeline(ctx, req.(*RequestTimelineQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Logger_SearchLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogSearchQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoggerServer).SearchLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Logger_SearchLogs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoggerServer).SearchLogs(ctx, req.(*LogSearchQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Logger_DeleteLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LogDeleteQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LoggerServer).DeleteLogs(m, &grpc.GenericServerStream[LogDeleteQuery, LogDeleteProgress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Logger_DeleteLogsServer = grpc.ServerStreamingServer[LogDeleteProgress]
+
+func _Logger_TailLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LogTailQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LoggerServer).TailLogs(m, &grpc.GenericServerStream[LogTailQuery, LogTailEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Logger_TailLogsServer = grpc.ServerStreamingServer[LogTailEvent]
+
+func _Logger_SetLogPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogPolicy)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoggerServer).SetLogPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Logger_SetLogPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoggerServer).SetLogPolicy(ctx, req.(*LogPolicy))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Logger_GetLogPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Nothing)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoggerServer).GetLogPolicies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Logger_GetLogPolicies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoggerServer).GetLogPolicies(ctx, req.(*Nothing))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Logger_GetOutboxStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Nothing)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoggerServer).GetOutboxStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Logger_GetOutboxStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoggerServer).GetOutboxStats(ctx, req.(*Nothing))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Logger_ServiceDesc is the grpc.ServiceDesc for Logger service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -187,17 +489,58 @@ var Logger_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteLog",
 			Handler:    _Logger_DeleteLog_Handler,
 		},
+		{
+			MethodName: "RestoreLog",
+			Handler:    _Logger_RestoreLog_Handler,
+		},
 		{
 			MethodName: "ReadLog",
 			Handler:    _Logger_ReadLog_Handler,
 		},
+		{
+			MethodName: "GetRequestTimeline",
+			Handler:    _Logger_GetRequestTimeline_Handler,
+		},
+		{
+			MethodName: "SearchLogs",
+			Handler:    _Logger_SearchLogs_Handler,
+		},
+		{
+			MethodName: "SetLogPolicy",
+			Handler:    _Logger_SetLogPolicy_Handler,
+		},
+		{
+			MethodName: "GetLogPolicies",
+			Handler:    _Logger_GetLogPolicies_Handler,
+		},
+		{
+			MethodName: "GetOutboxStats",
+			Handler:    _Logger_GetOutboxStats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "IngestLogs",
+			Handler:       _Logger_IngestLogs_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DeleteLogs",
+			Handler:       _Logger_DeleteLogs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "TailLogs",
+			Handler:       _Logger_TailLogs_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "gen.proto",
 }
 
 const (
-	BusinessLogic_Process_FullMethodName = "/gen.BusinessLogic/Process"
+	BusinessLogic_Process_FullMethodName       = "/gen.BusinessLogic/Process"
+	BusinessLogic_ProcessStream_FullMethodName = "/gen.BusinessLogic/ProcessStream"
 )
 
 // BusinessLogicClient is the client API for BusinessLogic service.
@@ -205,6 +548,7 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type BusinessLogicClient interface {
 	Process(ctx context.Context, in *OperationRequest, opts ...grpc.CallOption) (*OperationResponse, error)
+	ProcessStream(ctx context.Context, in *OperationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProcessProgress], error)
 }
 
 type businessLogicClient struct {
@@ -225,11 +569,31 @@ func (c *businessLogicClient) Process(ctx context.Context, in *OperationRequest,
 	return out, nil
 }
 
+func (c *businessLogicClient) ProcessStream(ctx context.Context, in *OperationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProcessProgress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BusinessLogic_ServiceDesc.Streams[0], BusinessLogic_ProcessStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[OperationRequest, ProcessProgress]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BusinessLogic_ProcessStreamClient = grpc.ServerStreamingClient[ProcessProgress]
+
 // BusinessLogicServer is the server API for BusinessLogic service.
 // All implementations must embed UnimplementedBusinessLogicServer
 // for forward compatibility.
 type BusinessLogicServer interface {
 	Process(context.Context, *OperationRequest) (*OperationResponse, error)
+	ProcessStream(*OperationRequest, grpc.ServerStreamingServer[ProcessProgress]) error
 	mustEmbedUnimplementedBusinessLogicServer()
 }
 
@@ -243,6 +607,9 @@ type UnimplementedBusinessLogicServer struct{}
 func (UnimplementedBusinessLogicServer) Process(context.Context, *OperationRequest) (*OperationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Process not implemented")
 }
+func (UnimplementedBusinessLogicServer) ProcessStream(*OperationRequest, grpc.ServerStreamingServer[ProcessProgress]) error {
+	return status.Errorf(codes.Unimplemented, "method ProcessStream not implemented")
+}
 func (UnimplementedBusinessLogicServer) mustEmbedUnimplementedBusinessLogicServer() {}
 func (UnimplementedBusinessLogicServer) testEmbeddedByValue()                       {}
 
@@ -282,6 +649,17 @@ func _BusinessLogic_Process_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BusinessLogic_ProcessStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(OperationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BusinessLogicServer).ProcessStream(m, &grpc.GenericServerStream[OperationRequest, ProcessProgress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BusinessLogic_ProcessStreamServer = grpc.ServerStreamingServer[ProcessProgress]
+
 // BusinessLogic_ServiceDesc is the grpc.ServiceDesc for BusinessLogic service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -294,6 +672,12 @@ var BusinessLogic_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _BusinessLogic_Process_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ProcessStream",
+			Handler:       _BusinessLogic_ProcessStream_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "gen.proto",
 }
@@ -12,6 +12,8 @@ type Config struct {
 	KafkaBroker string
 	BizTopic    string
 	LogTopic    string
+	// none или otlp; адрес коллектора задаётся OTEL_EXPORTER_OTLP_ENDPOINT
+	TraceExporter string
 }
 
 func Load() *Config {
@@ -21,10 +23,11 @@ func Load() *Config {
 	}
 
 	return &Config{
-		WsAddr:      os.Getenv("WS_ADDR"),
-		KafkaBroker: os.Getenv("KAFKA_BROKER"),
-		ImageDir:    os.Getenv("STATIC_DIR"),
-		BizTopic:    os.Getenv("BUSINESS_TOPIC"),
-		LogTopic:    os.Getenv("LOG_TOPIC"),
+		WsAddr:        os.Getenv("WS_ADDR"),
+		KafkaBroker:   os.Getenv("KAFKA_BROKER"),
+		ImageDir:      os.Getenv("STATIC_DIR"),
+		BizTopic:      os.Getenv("BUSINESS_TOPIC"),
+		LogTopic:      os.Getenv("LOG_TOPIC"),
+		TraceExporter: os.Getenv("TRACE_EXPORTER"),
 	}
 }
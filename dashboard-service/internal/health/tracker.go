@@ -0,0 +1,161 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Consumer — состояние одного Kafka-консьюмера.
+type Consumer struct {
+	Topic         string     `json:"topic"`
+	Running       bool       `json:"running"`
+	Healthy       bool       `json:"healthy"`
+	Messages      int64      `json:"messages"`
+	Lag           int64      `json:"lag"`
+	LastMessageAt *time.Time `json:"last_message_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastErrorAt   *time.Time `json:"last_error_at,omitempty"`
+}
+
+type consumerState struct {
+	Consumer
+	lag func() int64
+}
+
+// Tracker собирает состояние консьюмеров. Консьюмер считается нездоровым,
+// если он остановлен или последняя ошибка чтения новее последнего сообщения
+// и случилась не раньше errorWindow назад.
+type Tracker struct {
+	errorWindow time.Duration
+	now         func() time.Time
+
+	mu        sync.Mutex
+	consumers map[string]*consumerState
+}
+
+func NewTracker(errorWindow time.Duration) *Tracker {
+	return &Tracker{
+		errorWindow: errorWindow,
+		now:         time.Now,
+		consumers:   make(map[string]*consumerState),
+	}
+}
+
+// Started регистрирует запущенный консьюмер. lag может быть nil.
+func (t *Tracker) Started(topic string, lag func() int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.consumers[topic] = &consumerState{Consumer: Consumer{Topic: topic, Running: true}, lag: lag}
+}
+
+func (t *Tracker) Stopped(topic string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if c, ok := t.consumers[topic]; ok {
+		c.Running = false
+		if err != nil {
+			t.setError(c, err.Error())
+		}
+	}
+}
+
+func (t *Tracker) Message(topic string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if c, ok := t.consumers[topic]; ok {
+		now := t.now()
+		c.Messages++
+		c.LastMessageAt = &now
+	}
+}
+
+func (t *Tracker) Error(topic, msg string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if c, ok := t.consumers[topic]; ok {
+		t.setError(c, msg)
+	}
+}
+
+func (t *Tracker) setError(c *consumerState, msg string) {
+	now := t.now()
+	c.LastError = msg
+	c.LastErrorAt = &now
+}
+
+// Snapshot возвращает состояние консьюмеров, отсортированное по топику,
+// и признак того, что все они здоровы.
+func (t *Tracker) Snapshot() (bool, []Consumer) {
+	t.mu.Lock()
+	states := make([]*consumerState, 0, len(t.consumers))
+	for _, c := range t.consumers {
+		states = append(states, c)
+	}
+	now := t.now()
+
+	ready := len(states) > 0
+	consumers := make([]Consumer, 0, len(states))
+	for _, c := range states {
+		snapshot := c.Consumer
+		snapshot.Healthy = c.Running && !t.failing(c, now)
+		ready = ready && snapshot.Healthy
+		consumers = append(consumers, snapshot)
+	}
+	lags := make([]func() int64, len(states))
+	for i, c := range states {
+		lags[i] = c.lag
+	}
+	t.mu.Unlock()
+
+	// Статистика ридера берёт его собственные блокировки, поэтому вне t.mu
+	for i, lag := range lags {
+		if lag != nil {
+			consumers[i].Lag = lag()
+		}
+	}
+
+	sort.Slice(consumers, func(i, j int) bool { return consumers[i].Topic < consumers[j].Topic })
+	return ready, consumers
+}
+
+func (t *Tracker) failing(c *consumerState, now time.Time) bool {
+	if c.LastErrorAt == nil || now.Sub(*c.LastErrorAt) > t.errorWindow {
+		return false
+	}
+	return c.LastMessageAt == nil || c.LastErrorAt.After(*c.LastMessageAt)
+}
+
+type readinessResponse struct {
+	Status    string     `json:"status"`
+	Consumers []Consumer `json:"consumers"`
+}
+
+// LivenessHandler отвечает 200, пока процесс обслуживает HTTP.
+func LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
+
+// ReadinessHandler отвечает 503, если хотя бы один консьюмер нездоров.
+func (t *Tracker) ReadinessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ready, consumers := t.Snapshot()
+		resp := readinessResponse{Status: "ready", Consumers: consumers}
+		status := http.StatusOK
+		if !ready {
+			resp.Status = "not_ready"
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, resp)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,67 @@
+package health
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrackerReadiness(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewTracker(30 * time.Second)
+	tracker.now = func() time.Time { return now }
+
+	if ready, _ := tracker.Snapshot(); ready {
+		t.Fatal("expected tracker without consumers to be not ready")
+	}
+
+	tracker.Started("operation_log", func() int64 { return 7 })
+	tracker.Started("alg_graph_pic", nil)
+	ready, consumers := tracker.Snapshot()
+	if !ready || len(consumers) != 2 || consumers[0].Topic != "alg_graph_pic" || consumers[1].Lag != 7 {
+		t.Fatalf("unexpected snapshot: %v %+v", ready, consumers)
+	}
+
+	tracker.Error("operation_log", "dial tcp kafka:9092: connection refused")
+	if ready, _ := tracker.Snapshot(); ready {
+		t.Error("expected recent read error to make consumer unhealthy")
+	}
+
+	now = now.Add(time.Second)
+	tracker.Message("operation_log")
+	if ready, _ := tracker.Snapshot(); !ready {
+		t.Error("expected message after error to restore health")
+	}
+
+	tracker.Error("alg_graph_pic", "leader not available")
+	now = now.Add(31 * time.Second)
+	if ready, _ := tracker.Snapshot(); !ready {
+		t.Error("expected old error to be ignored")
+	}
+
+	tracker.Stopped("alg_graph_pic", errors.New("fatal"))
+	ready, consumers = tracker.Snapshot()
+	if ready || consumers[0].Running || consumers[0].LastError != "fatal" {
+		t.Errorf("expected stopped consumer to be unhealthy, got %+v", consumers[0])
+	}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	tracker := NewTracker(time.Minute)
+
+	w := httptest.NewRecorder()
+	tracker.ReadinessHandler()(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), `"not_ready"`) {
+		t.Errorf("expected 503 not_ready, got %d: %s", w.Code, w.Body.String())
+	}
+
+	tracker.Started("operation_log", nil)
+	w = httptest.NewRecorder()
+	tracker.ReadinessHandler()(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"operation_log"`) {
+		t.Errorf("expected 200 with consumer, got %d: %s", w.Code, w.Body.String())
+	}
+}
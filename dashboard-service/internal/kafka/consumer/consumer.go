@@ -2,8 +2,12 @@ package consumer
 
 import (
 	"context"
+	"dashboard-service/internal/health"
+	"dashboard-service/internal/tracing"
 	wscd "dashboard-service/internal/ws"
+	"fmt"
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/attribute"
 	"log"
 )
 
@@ -12,9 +16,9 @@ type KafkaMessageHandler interface {
 	Broadcast(data []byte, clients *wscd.Clients)
 }
 
-func StartAll(clients *wscd.Clients, ctx context.Context, broker string) {
-	StartConsumer(ctx, broker, "alg_graph_pic", "dashbord-service", &BizHandler{}, clients)
-	StartConsumer(ctx, broker, "operation_log", "dashbord-service", &LogHandler{}, clients)
+func StartAll(clients *wscd.Clients, ctx context.Context, broker string, tracker *health.Tracker) {
+	StartConsumer(ctx, broker, "alg_graph_pic", "dashbord-service", &BizHandler{}, clients, tracker)
+	StartConsumer(ctx, broker, "operation_log", "dashbord-service", &LogHandler{}, clients, tracker)
 }
 
 func StartConsumer(
@@ -23,14 +27,20 @@ func StartConsumer(
 	topic string,
 	groupID string,
 	handler KafkaMessageHandler,
-	clients *wscd.Clients) {
+	clients *wscd.Clients,
+	tracker *health.Tracker) {
 	r := kafka.NewReader(kafka.ReaderConfig{
 		Brokers:  []string{broker},
 		Topic:    topic,
 		GroupID:  groupID,
 		MinBytes: 1,
 		MaxBytes: 10e6,
+		// Ридер сам переподключается к брокеру, о сбоях узнаём только отсюда
+		ErrorLogger: kafka.LoggerFunc(func(msg string, args ...interface{}) {
+			tracker.Error(topic, fmt.Sprintf(msg, args...))
+		}),
 	})
+	tracker.Started(topic, func() int64 { return r.Stats().Lag })
 
 	go func() {
 		defer r.Close()
@@ -39,15 +49,24 @@ func StartConsumer(
 			if err != nil {
 				if ctx.Err() != nil {
 					log.Printf("%s: context canceled, shutting down", topic)
+					tracker.Stopped(topic, nil)
 				} else {
 					log.Printf("%s: error: %v", topic, err)
+					tracker.Stopped(topic, err)
 				}
 				break
 			}
+			tracker.Message(topic)
+
+			// Сообщение продолжает trace отправителя из заголовков Kafka
+			_, span := tracing.Start(tracing.ExtractKafka(ctx, &m), "consume "+topic,
+				attribute.String("messaging.destination", topic),
+				attribute.Int64("messaging.kafka.offset", m.Offset))
 			data := handler.Handle(m.Value)
 			if data != nil {
 				handler.Broadcast(data, clients)
 			}
+			span.End()
 		}
 	}()
 }
@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+)
+
+// Broadcasts считает отправленные WebSocket-клиентам сообщения: kind — image или operation.
+var Broadcasts = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "dashboard_websocket_messages_sent_total",
+	Help: "Messages sent to WebSocket clients by kind and result.",
+}, []string{"kind", "result"})
+
+// RegisterClients экспортирует число подключённых WebSocket-клиентов.
+// Значение читается при каждом опросе, поэтому не расходится с реальной картой клиентов.
+func RegisterClients(count func() int) {
+	prometheus.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "dashboard_websocket_clients",
+		Help: "Currently connected WebSocket clients.",
+	}, func() float64 { return float64(count()) }))
+}
+
+// Handler отдаёт метрики в текстовом формате Prometheus.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,33 @@
+package tracing
+
+import (
+	"context"
+	kafka "github.com/segmentio/kafka-go"
+	"testing"
+)
+
+func TestExtractKafka(t *testing.T) {
+	Install()
+
+	tests := []struct {
+		name    string
+		headers []kafka.Header
+		want    string
+	}{
+		{
+			name:    "traceparent header",
+			headers: []kafka.Header{{Key: "traceparent", Value: []byte("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")}},
+			want:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{name: "message without headers", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := kafka.Message{Headers: tt.headers}
+			if got := TraceID(ExtractKafka(context.Background(), &msg)); got != tt.want {
+				t.Errorf("expected trace %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
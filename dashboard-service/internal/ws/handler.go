@@ -21,6 +21,7 @@ func handleConnections(clients *Clients) http.HandlerFunc {
 			if err != nil {
 				log.Println("Client disconnected: ", err)
 				clients.DeleteClient(conn)
+				return
 			}
 		}
 	}
@@ -2,6 +2,7 @@ package wscd
 
 import (
 	"dashboard-service/internal/config"
+	"dashboard-service/internal/metrics"
 	"fmt"
 	"github.com/gorilla/websocket"
 	"log"
@@ -28,6 +29,12 @@ func (c *Clients) DeleteClient(conn *websocket.Conn) {
 	c.clientsMu.Unlock()
 }
 
+func (c *Clients) Count() int {
+	c.clientsMu.Lock()
+	defer c.clientsMu.Unlock()
+	return len(c.Clients)
+}
+
 func StartWebSocket(clients *Clients, cfg *config.Config) {
 
 	http.HandleFunc("/ws", handleConnections(clients)) // обработчик WebSocket
@@ -126,10 +133,13 @@ func BroadcastImage(imageData []byte, clients *Clients) {
 	for client := range clients.Clients {
 		err := client.WriteMessage(websocket.BinaryMessage, imageData)
 		if err != nil {
+			metrics.Broadcasts.WithLabelValues("image", "error").Inc()
 			log.Println("Broadcast error:", err)
 			client.Close()
 			delete(clients.Clients, client)
+			continue
 		}
+		metrics.Broadcasts.WithLabelValues("image", "ok").Inc()
 	}
 }
 
@@ -140,9 +150,12 @@ func BroadcastOperation(operationData []byte, clients *Clients) {
 	for client := range clients.Clients {
 		err := client.WriteMessage(websocket.TextMessage, operationData)
 		if err != nil {
+			metrics.Broadcasts.WithLabelValues("operation", "error").Inc()
 			log.Println("Broadcast error:", err)
 			client.Close()
 			delete(clients.Clients, client)
+			continue
 		}
+		metrics.Broadcasts.WithLabelValues("operation", "ok").Inc()
 	}
 }
@@ -17,6 +17,11 @@ const docTemplate = `{
     "paths": {
         "/deleteLog": {
             "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Выполняет gRPC-запрос к лог-сервису для удаления лог-сообщения по указанным параметрам ` + "`" + `id` + "`" + ` и ` + "`" + `filename` + "`" + `.",
                 "consumes": [
                     "application/json"
@@ -31,7 +36,7 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Уникальный идентификатор лога (например, rozNzBFDWy)",
+                        "description": "Уникальный идентификатор лога (например, 01JWMCKG00B7Q8N4XZ5V2E3R6T)",
                         "name": "id",
                         "in": "query",
                         "required": true
@@ -42,6 +47,12 @@ const docTemplate = `{
                         "name": "filename",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Причина удаления для журнала аудита",
+                        "name": "reason",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -57,17 +68,46 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.DeleteResponse"
                         }
                     },
+                    "401": {
+                        "description": "API-ключ не передан или неизвестен",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "У ключа нет доступа к маршруту",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов или суточная квота, см. Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Внутренняя ошибка при выполнении gRPC-запроса или лог не найден",
                         "schema": {
                             "$ref": "#/definitions/main.DeleteResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Лог-сервис недоступен (разомкнут предохранитель)",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
         "/getLog": {
             "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Обрабатывает HTTP GET-запрос и выполняет gRPC-вызов к лог-сервису для получения структурированного лог-сообщения.",
                 "consumes": [
                     "application/json"
@@ -82,7 +122,7 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Уникальный идентификатор лога (например, rozNzBFDWy)",
+                        "description": "Уникальный идентификатор лога (например, 01JWMCKG00B7Q8N4XZ5V2E3R6T)",
                         "name": "id",
                         "in": "query",
                         "required": true
@@ -108,78 +148,1246 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.ReadResponse"
                         }
                     },
+                    "401": {
+                        "description": "API-ключ не передан или неизвестен",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "У ключа нет доступа к маршруту",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов или суточная квота, см. Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Внутренняя ошибка: сбой gRPC-запроса или лог/файл не найден на стороне сервиса",
                         "schema": {
                             "$ref": "#/definitions/main.ReadResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Лог-сервис недоступен (разомкнут предохранитель)",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/process": {
+        "/healthz": {
+            "get": {
+                "description": "Всегда возвращает 200, пока процесс обслуживает HTTP. Не требует API-ключа.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Проверка живости",
+                "responses": {
+                    "200": {
+                        "description": "Сервис жив",
+                        "schema": {
+                            "$ref": "#/definitions/main.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs": {
             "post": {
-                "description": "Принимает JSON с последовательностью операций (` + "`" + `calc` + "`" + `, ` + "`" + `print` + "`" + `), преобразует во внутренние Protobuf-сообщения и передаёт в бизнес-сервис и лог-сервис по gRPC.",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Принимает программу в тех же форматах, что и /process, ставит её в очередь и сразу возвращает идентификатор задачи.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "text/x-hasher",
+                    "application/yaml",
+                    "text/csv",
+                    "application/x-protobuf"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "operations"
+                    "jobs"
                 ],
-                "summary": "Обработка бизнес-операций",
+                "summary": "Асинхронная обработка операций",
                 "parameters": [
                     {
-                        "description": "Список операций. Поля left и right могут быть числом или строкой (переменной).",
+                        "description": "Список операций",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
                             "$ref": "#/definitions/main.requestJSON"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Адрес для уведомления о завершении задачи",
+                        "name": "callback_url",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Задача поставлена в очередь",
+                        "schema": {
+                            "$ref": "#/definitions/main.JobResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/main.JobResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API-ключ не передан или неизвестен",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "У ключа нет доступа к маршруту",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Неподдерживаемый Content-Type",
+                        "schema": {
+                            "$ref": "#/definitions/main.JobResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов или суточная квота, см. Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Очередь заполнена или недоступна",
+                        "schema": {
+                            "$ref": "#/definitions/main.JobResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает статус (queued, running, succeeded, failed, canceled), прогресс и, после завершения, результат задачи.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Статус задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Операции успешно обработаны",
+                        "description": "Текущее состояние задачи",
                         "schema": {
-                            "$ref": "#/definitions/main.CompositeResponse"
+                            "$ref": "#/definitions/main.JobResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API-ключ не передан или неизвестен",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "У ключа нет доступа к маршруту",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/main.JobResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов или суточная квота, см. Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Снимает задачу из очереди или прерывает выполняющуюся обработку.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Отмена задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задача отменена",
+                        "schema": {
+                            "$ref": "#/definitions/main.JobResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API-ключ не передан или неизвестен",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "У ключа нет доступа к маршруту",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/main.JobResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Задача уже завершена",
+                        "schema": {
+                            "$ref": "#/definitions/main.JobResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов или суточная квота, см. Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/logs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Ищет записи во всех файлах лог-сервиса по источнику, уровню, времени получения, пути, переменной,",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "logs"
+                ],
+                "summary": "Поиск логов",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Источник (HTTP-server, business-server); можно повторять или перечислить через запятую",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Уровень (debug, info, warn, error)",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало интервала по времени получения, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец интервала по времени получения, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Путь запроса (например, /process)",
+                        "name": "path",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Переменная в операциях или результате",
+                        "name": "var",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Минимальная задержка доставки (например, 250ms)",
+                        "name": "min_delay",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подстрока в записи без учёта регистра",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы, по умолчанию 50, не больше 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor предыдущей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Только записи, выданные после записи с этим id (id упорядочены по времени)",
+                        "name": "after_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "timestamp",
+                            "delay"
+                        ],
+                        "type": "string",
+                        "description": "Ключ сортировки",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Порядок сортировки, по умолчанию desc",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница найденных записей",
+                        "schema": {
+                            "$ref": "#/definitions/main.LogSearchResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректный запрос (например, отсутствует поле или неверный формат)",
+                        "description": "Некорректные параметры поиска",
                         "schema": {
-                            "$ref": "#/definitions/main.CompositeResponse"
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "API-ключ не передан или неизвестен",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "У ключа нет доступа к маршруту",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов или суточная квота, см. Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Внутренняя ошибка сервера при обработке запроса",
+                        "description": "Сбой gRPC-запроса к лог-сервису",
                         "schema": {
-                            "$ref": "#/definitions/main.CompositeResponse"
+                            "type": "string"
                         }
                     },
                     "503": {
-                        "description": "gRPC-сервисы недоступны",
+                        "description": "Лог-сервис недоступен (разомкнут предохранитель)",
                         "schema": {
-                            "$ref": "#/definitions/main.CompositeResponse"
+                            "type": "string"
                         }
                     }
                 }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет все записи, подходящие под фильтр: источник, время получения, ключ и значение метаданных, переменная.",
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "logs"
+                ],
+                "summary": "Массовое удаление логов по фильтру",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Источник (HTTP-server, business-server); можно повторять или перечислить через запятую",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало интервала по времени получения, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец интервала по времени получения, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ метаданных записи",
+                        "name": "metadata_key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Значение ключа метаданных; требует metadata_key",
+                        "name": "metadata_value",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Переменная в операциях или результате",
+                        "name": "var",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только посчитать подходящие записи",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер пачки между отчётами о прогрессе, по умолчанию 500, не больше 5000",
+                        "name": "batch_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Причина удаления для журнала аудита",
+                        "name": "reason",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Результат dry_run или строка прогресса NDJSON",
+                        "schema": {
+                            "$ref": "#/definitions/main.LogDeleteProgress"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный или пустой фильтр",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "API-ключ не передан или неизвестен",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "У ключа нет доступа к маршруту",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов или суточная квота, см. Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Сбой gRPC-запроса к лог-сервису до начала удаления",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Лог-сервис недоступен (разомкнут предохранитель)",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/logs/tail": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Передаёт новые записи по мере того, как лог-сервис их принимает, как Server-Sent Events — замена tail -f в контейнере.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "logs"
+                ],
+                "summary": "Живой поток логов",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Источник (HTTP-server, business-server); можно повторять или перечислить через запятую",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Уровень (debug, info, warn, error)",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Путь запроса (например, /process)",
+                        "name": "path",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Переменная в операциях или результате",
+                        "name": "var",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Минимальная задержка доставки (например, 250ms)",
+                        "name": "min_delay",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подстрока в записи без учёта регистра",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ метаданных записи",
+                        "name": "metadata_key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Значение ключа метаданных; требует metadata_key",
+                        "name": "metadata_value",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начать с записей, полученных не раньше этого времени, RFC 3339",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начать с записей, полученных после записи с этим id",
+                        "name": "after_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id последнего полученного события; используется, если не заданы since и after_id",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Поток событий log (и error), поле data — JSON",
+                        "schema": {
+                            "$ref": "#/definitions/main.LogTailEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "API-ключ не передан или неизвестен",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "У ключа нет доступа к маршруту",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов или суточная квота, см. Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Лог-сервис недоступен (разомкнут предохранитель)",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "description": "HTTP-запросы и задержки по шаблону маршрута, исходящие gRPC-вызовы по сервису, методу и коду,",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Метрики Prometheus",
+                "responses": {
+                    "200": {
+                        "description": "Метрики в текстовом формате Prometheus",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/process": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Принимает JSON с последовательностью операций (` + "`" + `calc` + "`" + `, ` + "`" + `print` + "`" + `), преобразует во внутренние Protobuf-сообщения и передаёт в бизнес-сервис и лог-сервис по gRPC.",
+                "consumes": [
+                    "application/json",
+                    "text/x-hasher",
+                    "application/yaml",
+                    "text/csv",
+                    "application/x-protobuf"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/x-protobuf",
+                    "text/plain"
+                ],
+                "tags": [
+                    "operations"
+                ],
+                "summary": "Обработка бизнес-операций",
+                "parameters": [
+                    {
+                        "description": "Список операций. Поля left и right могут быть числом или строкой (переменной).",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.requestJSON"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Адрес, на который после обработки будет отправлен POST с тем же CompositeResponse",
+                        "name": "callback_url",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор с тем же ключом и телом вернёт сохранённый ответ без повторной обработки",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Операции успешно обработаны",
+                        "schema": {
+                            "$ref": "#/definitions/main.CompositeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос (например, отсутствует поле или неверный формат)",
+                        "schema": {
+                            "$ref": "#/definitions/main.CompositeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API-ключ не передан или неизвестен",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "У ключа нет доступа к маршруту",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Ни один из форматов в Accept не поддерживается",
+                        "schema": {
+                            "$ref": "#/definitions/main.CompositeResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Неподдерживаемый Content-Type",
+                        "schema": {
+                            "$ref": "#/definitions/main.CompositeResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency-Key уже использован с другим телом запроса",
+                        "schema": {
+                            "$ref": "#/definitions/main.CompositeResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов или суточная квота, см. Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера при обработке запроса",
+                        "schema": {
+                            "$ref": "#/definitions/main.CompositeResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "gRPC-сервисы недоступны",
+                        "schema": {
+                            "$ref": "#/definitions/main.CompositeResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Опрашивает grpc.health.v1 бизнес-сервиса и лог-сервиса и возвращает состояние каждой зависимости,",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Проверка готовности",
+                "responses": {
+                    "200": {
+                        "description": "Сервис готов (ready или degraded)",
+                        "schema": {
+                            "$ref": "#/definitions/main.ReadinessResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Бизнес-сервис недоступен",
+                        "schema": {
+                            "$ref": "#/definitions/main.ReadinessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/requests/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает одной записью лог входящего запроса, лог результата его обработки и DOT-описание графа зависимостей.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "logs"
+                ],
+                "summary": "Хронология запроса",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор лога входящего запроса (например, 01JWMCKG00B7Q8N4XZ5V2E3R6T)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Запрос, результат и граф",
+                        "schema": {
+                            "$ref": "#/definitions/main.RequestTimelineResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API-ключ не передан или неизвестен",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "У ключа нет доступа к маршруту",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Запрос с таким идентификатором не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов или суточная квота, см. Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Сбой gRPC-запроса к лог-сервису",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Лог-сервис недоступен (разомкнут предохранитель)",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/restoreLog": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает лог из корзины лог-сервиса в исходный файл, пока срок восстановления не истёк.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "logs"
+                ],
+                "summary": "Восстановить удалённый лог",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор удалённого лога",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Имя файла, из которого лог был удалён (например, http_logs.json)",
+                        "name": "filename",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Причина восстановления для журнала аудита",
+                        "name": "reason",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Лог восстановлен",
+                        "schema": {
+                            "$ref": "#/definitions/main.RestoreResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Отсутствуют обязательные параметры id или filename",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "API-ключ не передан или неизвестен",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "У ключа нет доступа к маршруту",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов или суточная квота, см. Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Лога нет в корзине (не удалялся или уже стёрт) либо ошибка gRPC-запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Лог-сервис недоступен (разомкнут предохранитель)",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает доставки callback_url, старые первыми. Каждая доставка подписывается заголовками",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Журнал доставок вебхуков",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "delivered",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "Фильтр по статусу",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список доставок",
+                        "schema": {
+                            "$ref": "#/definitions/main.WebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неизвестный статус",
+                        "schema": {
+                            "$ref": "#/definitions/main.WebhookResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API-ключ не передан или неизвестен",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "У ключа нет доступа к маршруту",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов или суточная квота, см. Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/deliveries/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает состояние доставки, число попыток и последнюю ошибку.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Доставка вебхука",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор доставки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Доставка",
+                        "schema": {
+                            "$ref": "#/definitions/main.WebhookResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API-ключ не передан или неизвестен",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "У ключа нет доступа к маршруту",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Доставка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/main.WebhookResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов или суточная квота, см. Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/deliveries/{id}/replay": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Сбрасывает счётчик попыток у доставки со статусом failed и отправляет её заново.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Повторная отправка вебхука",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор доставки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Доставка поставлена в очередь",
+                        "schema": {
+                            "$ref": "#/definitions/main.WebhookResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API-ключ не передан или неизвестен",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "У ключа нет доступа к маршруту",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Доставка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/main.WebhookResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Доставка не в статусе failed",
+                        "schema": {
+                            "$ref": "#/definitions/main.WebhookResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов или суточная квота, см. Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "main.AuthErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "enum": [
+                        "missing_key",
+                        "unknown_key",
+                        "scope_denied",
+                        "rate_limited",
+                        "quota_exceeded"
+                    ]
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "main.ClientStatus": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "boolean"
+                },
+                "breaker": {
+                    "type": "string",
+                    "enum": [
+                        "closed",
+                        "open",
+                        "half-open"
+                    ]
+                },
+                "connectivity": {
+                    "type": "string"
+                },
+                "consecutive_failures": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_failure": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "retry_at": {
+                    "type": "string"
+                }
             }
-        }
-    },
-    "definitions": {
+        },
         "main.CompositeResponse": {
             "type": "object",
             "properties": {
+                "callback_delivery_id": {
+                    "type": "string"
+                },
+                "callback_error": {
+                    "type": "string"
+                },
                 "items": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/main.VariableValue"
                     }
                 },
+                "job_id": {
+                    "type": "string"
+                },
                 "log_error": {
                     "type": "string"
                 },
@@ -203,6 +1411,12 @@ const docTemplate = `{
                 },
                 "success": {
                     "type": "boolean"
+                },
+                "validation_error": {
+                    "type": "string"
+                },
+                "warning": {
+                    "type": "string"
                 }
             }
         },
@@ -212,11 +1426,38 @@ const docTemplate = `{
                 "message": {
                     "type": "string"
                 },
+                "purge_at": {
+                    "description": "Когда лог будет стёрт окончательно, unix ms",
+                    "type": "integer"
+                },
                 "success": {
                     "type": "boolean"
                 }
             }
         },
+        "main.DependencyStatus": {
+            "type": "object",
+            "properties": {
+                "client": {
+                    "$ref": "#/definitions/main.ClientStatus"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "healthy": {
+                    "type": "boolean"
+                },
+                "latency": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "required": {
+                    "type": "boolean"
+                }
+            }
+        },
         "main.Duration": {
             "type": "object",
             "properties": {
@@ -228,6 +1469,150 @@ const docTemplate = `{
                 }
             }
         },
+        "main.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "main.Job": {
+            "type": "object",
+            "properties": {
+                "callback_url": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.operationJSON"
+                    }
+                },
+                "progress": {
+                    "$ref": "#/definitions/main.JobProgress"
+                },
+                "result": {
+                    "$ref": "#/definitions/main.JobResult"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "queued",
+                        "running",
+                        "succeeded",
+                        "failed",
+                        "canceled"
+                    ]
+                }
+            }
+        },
+        "main.JobProgress": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "main.JobResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "job": {
+                    "$ref": "#/definitions/main.Job"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "main.JobResult": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.VariableValue"
+                    }
+                },
+                "log_error": {
+                    "type": "string"
+                },
+                "log_id": {
+                    "type": "string"
+                },
+                "processing_duration": {
+                    "type": "string"
+                },
+                "result_id": {
+                    "type": "string"
+                },
+                "warning": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.LogDeleteProgress": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "integer",
+                    "example": 500
+                },
+                "done": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "matched": {
+                    "type": "integer",
+                    "example": 1200
+                },
+                "missing": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "purge_at": {
+                    "type": "string",
+                    "example": "2025-07-01T00:00:00Z"
+                },
+                "sample_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "main.LogEntry": {
             "type": "object",
             "properties": {
@@ -243,6 +1628,12 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "parent_id": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
                 "service_name": {
                     "type": "string"
                 },
@@ -259,6 +1650,50 @@ const docTemplate = `{
                 }
             }
         },
+        "main.LogSearchHit": {
+            "type": "object",
+            "properties": {
+                "filename": {
+                    "type": "string",
+                    "example": "http_logs.json"
+                },
+                "log": {
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
+        "main.LogSearchResponse": {
+            "type": "object",
+            "properties": {
+                "logs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.LogSearchHit"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.LogTailEvent": {
+            "type": "object",
+            "properties": {
+                "dropped": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "filename": {
+                    "type": "string",
+                    "example": "http_logs.json"
+                },
+                "log": {
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
         "main.OperationResponse": {
             "type": "object",
             "properties": {
@@ -293,6 +1728,56 @@ const docTemplate = `{
                 }
             }
         },
+        "main.ReadinessResponse": {
+            "type": "object",
+            "properties": {
+                "dependencies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.DependencyStatus"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "ready",
+                        "degraded",
+                        "not_ready"
+                    ]
+                }
+            }
+        },
+        "main.RequestTimelineResponse": {
+            "type": "object",
+            "properties": {
+                "graph": {
+                    "type": "string",
+                    "example": "digraph G { ... }"
+                },
+                "request": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
+        "main.RestoreResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
         "main.StructuredMessage": {
             "type": "object",
             "properties": {
@@ -302,6 +1787,9 @@ const docTemplate = `{
                         "$ref": "#/definitions/main.operationJSON"
                     }
                 },
+                "graph": {
+                    "type": "string"
+                },
                 "method": {
                     "type": "string"
                 },
@@ -324,6 +1812,80 @@ const docTemplate = `{
                 }
             }
         },
+        "main.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string",
+                    "enum": [
+                        "process.completed",
+                        "job.succeeded",
+                        "job.failed"
+                    ]
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_status_code": {
+                    "type": "integer"
+                },
+                "payload": {
+                    "$ref": "#/definitions/main.CompositeResponse"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "delivered",
+                        "failed"
+                    ]
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.WebhookResponse": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.WebhookDelivery"
+                    }
+                },
+                "delivery": {
+                    "$ref": "#/definitions/main.WebhookDelivery"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
         "main.operationJSON": {
             "type": "object",
             "properties": {
@@ -352,6 +1914,14 @@ const docTemplate = `{
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "API-ключ из файла AUTH_KEYS_FILE. Можно передать и как Authorization: Bearer \u003cключ\u003e.",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        }
     }
 }`
 
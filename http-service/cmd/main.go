@@ -2,31 +2,136 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"google.golang.org/protobuf/runtime/protoimpl"
 	"http-service/internal/app"
+	"http-service/internal/auth"
 	grpcBiz "http-service/internal/client/grpc/business"
 	grpcLog "http-service/internal/client/grpc/log"
 	"http-service/internal/config"
+	"http-service/internal/idempotency"
+	"http-service/internal/jobs"
 	"http-service/internal/server"
 	"http-service/internal/signals"
+	"http-service/internal/tracing"
+	"http-service/internal/transport/http/handlers"
+	"http-service/internal/webhook"
+	"log"
+	"net/netip"
+	"time"
 )
 
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description API-ключ из файла AUTH_KEYS_FILE. Можно передать и как Authorization: Bearer <ключ>.
 func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	cfg := config.Load()
 
+	shutdownTracing, err := tracing.Setup(ctx, cfg.TraceExporter)
+	if err != nil {
+		log.Fatalf("failed to set up tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	clients := &app.Clients{
 		LogClient:      grpcLog.CreateLogClient(cfg),
 		BusinessClient: grpcBiz.CreateBusinessClient(cfg),
 	}
 
+	switch {
+	case cfg.AuthKeysFile != "":
+		keys, err := auth.LoadKeys(cfg.AuthKeysFile)
+		if err != nil {
+			log.Fatalf("failed to load API keys: %v", err)
+		}
+		clients.Auth = auth.NewAuthenticator(keys)
+		if cfg.AuthDisabled {
+			log.Println("AUTH_DISABLED is ignored because AUTH_KEYS_FILE is set")
+		}
+	case cfg.AuthDisabled:
+		clients.AuthDisabled = true
+		log.Println("WARNING: AUTH_DISABLED=true — API key authentication is OFF, every route is open to anyone who can reach this server")
+	default:
+		log.Fatal("AUTH_KEYS_FILE is not set: configure API keys or set AUTH_DISABLED=true to run without authentication")
+	}
+
+	idempotencyStore := idempotency.NewStore(cfg.IdempotencyTTL)
+	idempotencyStore.Start(ctx, time.Minute)
+	clients.Idempotency = idempotencyStore
+
+	dispatcher, err := newWebhookDispatcher(cfg)
+	if err != nil {
+		log.Fatalf("failed to start webhook dispatcher: %v", err)
+	}
+	dispatcher.Start(ctx)
+	clients.Webhooks = dispatcher
+
+	jobManager, err := newJobManager(cfg, clients)
+	if err != nil {
+		log.Fatalf("failed to start job manager: %v", err)
+	}
+	jobManager.OnFinish(handlers.NewJobNotifier(clients))
+	if err := jobManager.Start(ctx); err != nil {
+		log.Fatalf("failed to start job manager: %v", err)
+	}
+	clients.Jobs = jobManager
+
 	go server.RunHttpServer(clients, cfg)
 
 	signals.WaitForShutdown(ctx, cancel)
 }
 
+func newJobManager(cfg *config.Config, clients *app.Clients) (*jobs.Manager, error) {
+	var store jobs.Store
+	switch cfg.JobStore {
+	case "memory":
+		store = jobs.NewMemoryStore()
+	case "file":
+		fileStore, err := jobs.NewFileStore(cfg.JobStoreDir)
+		if err != nil {
+			return nil, err
+		}
+		store = fileStore
+	default:
+		return nil, fmt.Errorf("unknown job store %q: memory or file expected", cfg.JobStore)
+	}
+
+	return jobs.NewManager(store, handlers.NewJobRunner(clients), cfg.JobWorkers, cfg.JobQueueSize), nil
+}
+
+func newWebhookDispatcher(cfg *config.Config) (*webhook.Dispatcher, error) {
+	if cfg.WebhookSecret == "" {
+		log.Println("WEBHOOK_SECRET is empty — webhook signatures can be forged")
+	}
+
+	policy := webhook.URLPolicy{AllowedHosts: cfg.WebhookAllowedHosts}
+	for _, raw := range cfg.WebhookAllowedNetworks {
+		network, err := netip.ParsePrefix(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid WEBHOOK_ALLOWED_NETWORKS entry %q: %w", raw, err)
+		}
+		policy.AllowedNetworks = append(policy.AllowedNetworks, network)
+	}
+
+	deliveryLog, err := webhook.OpenDeliveryLog(cfg.WebhookLogPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook.NewDispatcher(deliveryLog, webhook.Options{
+		Secret:      []byte(cfg.WebhookSecret),
+		MaxAttempts: cfg.WebhookMaxAttempts,
+		Backoff:     cfg.WebhookBackoff,
+		MaxBackoff:  cfg.WebhookMaxBackoff,
+		Workers:     cfg.WebhookWorkers,
+		URLPolicy:   policy,
+	}), nil
+}
+
 // ProcessDataSwagger godoc
 // @Summary      Обработка бизнес-операций
 // @Description  Принимает JSON с последовательностью операций (`calc`, `print`), преобразует во внутренние Protobuf-сообщения и передаёт в бизнес-сервис и лог-сервис по gRPC.
@@ -41,14 +146,46 @@ func main() {
 //	  ]
 //	}
 //
+//	Тело также принимается в YAML (та же структура), CSV (строки type,op,var,left,right, заголовок необязателен)
+//	и protobuf OperationRequest (Content-Type: application/x-protobuf).
+//	Формат ответа выбирается по заголовку Accept: JSON (по умолчанию), CSV (строки var,value), NDJSON,
+//	protobuf OperationResponse или текстовая таблица. Ошибки всегда возвращаются в JSON.
+//	Та же программа может быть передана текстом с Content-Type: text/x-hasher:
+//	x = 10 + 5; y = x * 3; print y
+//	Поддерживаются операторы +, -, *, скобки и приоритет операций. Ошибки разбора содержат строку и колонку.
+//	Если передан callback_url, ответ дополнительно отправляется POST-запросом на этот адрес
+//	с подписью HMAC-SHA256 в заголовке X-Hasher-Signature (см. /webhooks/deliveries).
+//	С заголовком Idempotency-Key первый ответ сохраняется (ответы 5xx — нет) вместе с хешем тела на IDEMPOTENCY_TTL.
+//	Повтор отдаёт его с заголовком Idempotent-Replayed: true, параллельный дубликат ждёт завершения первого запроса,
+//	тот же ключ с другим телом возвращает 422.
+//	Входящий заголовок traceparent (W3C Trace Context) продолжается, trace_id запроса возвращается в X-Trace-Id
+//	и записывается в логи всех сервисов.
+//
 // @Tags         operations
 // @Accept       json
+// @Accept       text/x-hasher
+// @Accept       application/yaml
+// @Accept       text/csv
+// @Accept       application/x-protobuf
 // @Produce      json
+// @Produce      text/csv
+// @Produce      application/x-ndjson
+// @Produce      application/x-protobuf
+// @Produce      text/plain
 // @Param request body requestJSON true "Список операций. Поля left и right могут быть числом или строкой (переменной)."
+// @Param        callback_url query string false "Адрес, на который после обработки будет отправлен POST с тем же CompositeResponse"
+// @Param        Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом и телом вернёт сохранённый ответ без повторной обработки"
 // @Success      200 {object} CompositeResponse "Операции успешно обработаны"
 // @Failure      400 {object} CompositeResponse "Некорректный запрос (например, отсутствует поле или неверный формат)"
+// @Failure      406 {object} CompositeResponse "Ни один из форматов в Accept не поддерживается"
+// @Failure      415 {object} CompositeResponse "Неподдерживаемый Content-Type"
+// @Failure      422 {object} CompositeResponse "Idempotency-Key уже использован с другим телом запроса"
 // @Failure      500 {object} CompositeResponse "Внутренняя ошибка сервера при обработке запроса"
 // @Failure      503 {object} CompositeResponse "gRPC-сервисы недоступны"
+// @Failure      401 {object} AuthErrorResponse "API-ключ не передан или неизвестен"
+// @Failure      403 {object} AuthErrorResponse "У ключа нет доступа к маршруту"
+// @Failure      429 {object} AuthErrorResponse "Превышен лимит запросов или суточная квота, см. Retry-After"
+// @Security     ApiKeyAuth
 // @Router       /process [post]
 func ProcessDataSwagger() {}
 
@@ -60,8 +197,13 @@ type CompositeResponse struct {
 	ResultID           string          `json:"result_id,omitempty"`
 	LogError           string          `json:"log_error,omitempty"`
 	ProcessError       string          `json:"process_error,omitempty"`
+	ValidationError    string          `json:"validation_error,omitempty"`
 	Items              []VariableValue `json:"items,omitempty"`
+	Warning            string          `json:"warning,omitempty"`
 	ProcessingDuration string          `json:"processing_duration"`
+	JobID              string          `json:"job_id,omitempty"`
+	CallbackDeliveryID string          `json:"callback_delivery_id,omitempty"`
+	CallbackError      string          `json:"callback_error,omitempty"`
 }
 
 type VariableValue struct {
@@ -84,23 +226,291 @@ type operationJSON struct {
 	Right interface{} `json:"right,omitempty"`
 }
 
+// CreateJobSwagger godoc
+// @Summary      Асинхронная обработка операций
+// @Description  Принимает программу в тех же форматах, что и /process, ставит её в очередь и сразу возвращает идентификатор задачи.
+//
+//	Задача выполняется бизнес-сервисом в фоне, её статус и прогресс доступны по GET /jobs/{id}.
+//	Если очередь заполнена, возвращается 503 с заголовком Retry-After.
+//	Если передан callback_url, после успешного или неудачного завершения на него уйдёт CompositeResponse с полем job_id.
+//
+// @Tags         jobs
+// @Accept       json
+// @Accept       text/x-hasher
+// @Accept       application/yaml
+// @Accept       text/csv
+// @Accept       application/x-protobuf
+// @Produce      json
+// @Param request body requestJSON true "Список операций"
+// @Param        callback_url query string false "Адрес для уведомления о завершении задачи"
+// @Success      202 {object} JobResponse "Задача поставлена в очередь"
+// @Failure      400 {object} JobResponse "Некорректный запрос"
+// @Failure      415 {object} JobResponse "Неподдерживаемый Content-Type"
+// @Failure      503 {object} JobResponse "Очередь заполнена или недоступна"
+// @Failure      401 {object} AuthErrorResponse "API-ключ не передан или неизвестен"
+// @Failure      403 {object} AuthErrorResponse "У ключа нет доступа к маршруту"
+// @Failure      429 {object} AuthErrorResponse "Превышен лимит запросов или суточная квота, см. Retry-After"
+// @Security     ApiKeyAuth
+// @Router       /jobs [post]
+func CreateJobSwagger() {}
+
+// GetJobSwagger godoc
+// @Summary      Статус задачи
+// @Description  Возвращает статус (queued, running, succeeded, failed, canceled), прогресс и, после завершения, результат задачи.
+// @Tags         jobs
+// @Produce      json
+// @Param        id   path      string  true  "Идентификатор задачи"
+// @Success      200 {object} JobResponse "Текущее состояние задачи"
+// @Failure      404 {object} JobResponse "Задача не найдена"
+// @Failure      401 {object} AuthErrorResponse "API-ключ не передан или неизвестен"
+// @Failure      403 {object} AuthErrorResponse "У ключа нет доступа к маршруту"
+// @Failure      429 {object} AuthErrorResponse "Превышен лимит запросов или суточная квота, см. Retry-After"
+// @Security     ApiKeyAuth
+// @Router       /jobs/{id} [get]
+func GetJobSwagger() {}
+
+// CancelJobSwagger godoc
+// @Summary      Отмена задачи
+// @Description  Снимает задачу из очереди или прерывает выполняющуюся обработку.
+// @Tags         jobs
+// @Produce      json
+// @Param        id   path      string  true  "Идентификатор задачи"
+// @Success      200 {object} JobResponse "Задача отменена"
+// @Failure      404 {object} JobResponse "Задача не найдена"
+// @Failure      409 {object} JobResponse "Задача уже завершена"
+// @Failure      401 {object} AuthErrorResponse "API-ключ не передан или неизвестен"
+// @Failure      403 {object} AuthErrorResponse "У ключа нет доступа к маршруту"
+// @Failure      429 {object} AuthErrorResponse "Превышен лимит запросов или суточная квота, см. Retry-After"
+// @Security     ApiKeyAuth
+// @Router       /jobs/{id} [delete]
+func CancelJobSwagger() {}
+
+type JobResponse struct {
+	Success bool   `json:"success"`
+	Status  int    `json:"status"`
+	Message string `json:"message,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Job     Job    `json:"job,omitempty"`
+}
+
+type Job struct {
+	ID          string          `json:"id"`
+	Status      string          `json:"status" enums:"queued,running,succeeded,failed,canceled"`
+	Progress    JobProgress     `json:"progress"`
+	Error       string          `json:"error,omitempty"`
+	Result      JobResult       `json:"result,omitempty"`
+	Operations  []operationJSON `json:"operations,omitempty"`
+	CallbackURL string          `json:"callback_url,omitempty"`
+	CreatedAt   string          `json:"created_at"`
+	StartedAt   string          `json:"started_at,omitempty"`
+	FinishedAt  string          `json:"finished_at,omitempty"`
+}
+
+type JobProgress struct {
+	Completed int32 `json:"completed"`
+	Total     int32 `json:"total"`
+}
+
+type JobResult struct {
+	LogID              string          `json:"log_id,omitempty"`
+	ResultID           string          `json:"result_id,omitempty"`
+	LogError           string          `json:"log_error,omitempty"`
+	Items              []VariableValue `json:"items,omitempty"`
+	Warning            string          `json:"warning,omitempty"`
+	ProcessingDuration string          `json:"processing_duration,omitempty"`
+}
+
+// ListWebhookDeliveriesSwagger godoc
+// @Summary      Журнал доставок вебхуков
+// @Description  Возвращает доставки callback_url, старые первыми. Каждая доставка подписывается заголовками
+//
+//	X-Hasher-Timestamp и X-Hasher-Signature: sha256=HMAC-SHA256(WEBHOOK_SECRET, "<timestamp>.<тело>").
+//	Неудачные попытки повторяются с экспоненциальной задержкой, после исчерпания попыток доставка получает статус failed.
+//
+// @Tags         webhooks
+// @Produce      json
+// @Param        status query string false "Фильтр по статусу" Enums(pending, delivered, failed)
+// @Success      200 {object} WebhookResponse "Список доставок"
+// @Failure      400 {object} WebhookResponse "Неизвестный статус"
+// @Failure      401 {object} AuthErrorResponse "API-ключ не передан или неизвестен"
+// @Failure      403 {object} AuthErrorResponse "У ключа нет доступа к маршруту"
+// @Failure      429 {object} AuthErrorResponse "Превышен лимит запросов или суточная квота, см. Retry-After"
+// @Security     ApiKeyAuth
+// @Router       /webhooks/deliveries [get]
+func ListWebhookDeliveriesSwagger() {}
+
+// GetWebhookDeliverySwagger godoc
+// @Summary      Доставка вебхука
+// @Description  Возвращает состояние доставки, число попыток и последнюю ошибку.
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path      string  true  "Идентификатор доставки"
+// @Success      200 {object} WebhookResponse "Доставка"
+// @Failure      404 {object} WebhookResponse "Доставка не найдена"
+// @Failure      401 {object} AuthErrorResponse "API-ключ не передан или неизвестен"
+// @Failure      403 {object} AuthErrorResponse "У ключа нет доступа к маршруту"
+// @Failure      429 {object} AuthErrorResponse "Превышен лимит запросов или суточная квота, см. Retry-After"
+// @Security     ApiKeyAuth
+// @Router       /webhooks/deliveries/{id} [get]
+func GetWebhookDeliverySwagger() {}
+
+// ReplayWebhookDeliverySwagger godoc
+// @Summary      Повторная отправка вебхука
+// @Description  Сбрасывает счётчик попыток у доставки со статусом failed и отправляет её заново.
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path      string  true  "Идентификатор доставки"
+// @Success      202 {object} WebhookResponse "Доставка поставлена в очередь"
+// @Failure      404 {object} WebhookResponse "Доставка не найдена"
+// @Failure      409 {object} WebhookResponse "Доставка не в статусе failed"
+// @Failure      401 {object} AuthErrorResponse "API-ключ не передан или неизвестен"
+// @Failure      403 {object} AuthErrorResponse "У ключа нет доступа к маршруту"
+// @Failure      429 {object} AuthErrorResponse "Превышен лимит запросов или суточная квота, см. Retry-After"
+// @Security     ApiKeyAuth
+// @Router       /webhooks/deliveries/{id}/replay [post]
+func ReplayWebhookDeliverySwagger() {}
+
+type AuthErrorResponse struct {
+	Success bool   `json:"success"`
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	Error   string `json:"error" enums:"missing_key,unknown_key,scope_denied,rate_limited,quota_exceeded"`
+}
+
+type WebhookResponse struct {
+	Success    bool              `json:"success"`
+	Status     int               `json:"status"`
+	Message    string            `json:"message,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	Delivery   WebhookDelivery   `json:"delivery,omitempty"`
+	Deliveries []WebhookDelivery `json:"deliveries,omitempty"`
+}
+
+type WebhookDelivery struct {
+	ID          string            `json:"id"`
+	Event       string            `json:"event" enums:"process.completed,job.succeeded,job.failed"`
+	URL         string            `json:"url"`
+	Payload     CompositeResponse `json:"payload"`
+	Status      string            `json:"status" enums:"pending,delivered,failed"`
+	Attempts    int               `json:"attempts"`
+	LastError   string            `json:"last_error,omitempty"`
+	LastCode    int               `json:"last_status_code,omitempty"`
+	CreatedAt   string            `json:"created_at"`
+	UpdatedAt   string            `json:"updated_at"`
+	DeliveredAt string            `json:"delivered_at,omitempty"`
+}
+
+// HealthzSwagger godoc
+// @Summary      Проверка живости
+// @Description  Всегда возвращает 200, пока процесс обслуживает HTTP. Не требует API-ключа.
+// @Tags         health
+// @Produce      json
+// @Success      200 {object} HealthResponse "Сервис жив"
+// @Router       /healthz [get]
+func HealthzSwagger() {}
+
+// ReadyzSwagger godoc
+// @Summary      Проверка готовности
+// @Description  Опрашивает grpc.health.v1 бизнес-сервиса и лог-сервиса и возвращает состояние каждой зависимости,
+//
+//	включая состояние предохранителя клиента. Без лог-сервиса статус degraded (200), без бизнес-сервиса — not_ready (503).
+//	Не требует API-ключа.
+//
+// @Tags         health
+// @Produce      json
+// @Success      200 {object} ReadinessResponse "Сервис готов (ready или degraded)"
+// @Failure      503 {object} ReadinessResponse "Бизнес-сервис недоступен"
+// @Router       /readyz [get]
+func ReadyzSwagger() {}
+
+// MetricsSwagger godoc
+// @Summary      Метрики Prometheus
+// @Description  HTTP-запросы и задержки по шаблону маршрута, исходящие gRPC-вызовы по сервису, методу и коду,
+//
+//	а также стандартные метрики Go-рантайма и процесса. Не требует API-ключа.
+//
+// @Tags         health
+// @Produce      plain
+// @Success      200 {string} string "Метрики в текстовом формате Prometheus"
+// @Router       /metrics [get]
+func MetricsSwagger() {}
+
+type HealthResponse struct {
+	Status string `json:"status" example:"ok"`
+}
+
+type ReadinessResponse struct {
+	Status       string             `json:"status" enums:"ready,degraded,not_ready"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+}
+
+type DependencyStatus struct {
+	Name     string       `json:"name"`
+	Required bool         `json:"required"`
+	Healthy  bool         `json:"healthy"`
+	Error    string       `json:"error,omitempty"`
+	Latency  string       `json:"latency,omitempty"`
+	Client   ClientStatus `json:"client,omitempty"`
+}
+
+type ClientStatus struct {
+	Name                string `json:"name"`
+	Available           bool   `json:"available"`
+	Breaker             string `json:"breaker" enums:"closed,open,half-open"`
+	Connectivity        string `json:"connectivity,omitempty"`
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	LastError           string `json:"last_error,omitempty"`
+	LastFailure         string `json:"last_failure,omitempty"`
+	RetryAt             string `json:"retry_at,omitempty"`
+}
+
 // DeleteLogSwagger godoc
 // @Summary      Удалить лог по идентификатору и имени файла
 // @Description  Выполняет gRPC-запрос к лог-сервису для удаления лог-сообщения по указанным параметрам `id` и `filename`.
 //
+//	Лог переносится в корзину и до `purge_at` может быть восстановлен через `/restoreLog`, затем стирается окончательно.
+//	Удаление записывается в журнал аудита лог-сервиса вместе с именем API-ключа и причиной.
 //	В случае успеха возвращает сообщение об успешном удалении. В противном случае возвращает описание ошибки.
 //
 // @Tags         logs
 // @Accept       json
 // @Produce      json
-// @Param        id       query     string  true  "Уникальный идентификатор лога (например, rozNzBFDWy)"
+// @Param        id       query     string  true  "Уникальный идентификатор лога (например, 01JWMCKG00B7Q8N4XZ5V2E3R6T)"
 // @Param        filename query     string  true  "Имя файла, в котором содержится лог (например, http_logs.json)"
+// @Param        reason   query     string  false "Причина удаления для журнала аудита"
 // @Success      200 {object} DeleteResponse "Успешное удаление лога"
 // @Failure      400 {object} DeleteResponse "Ошибка валидации: отсутствуют обязательные параметры id или filename"
 // @Failure      500 {object} DeleteResponse "Внутренняя ошибка при выполнении gRPC-запроса или лог не найден"
+// @Failure      503 {string} string "Лог-сервис недоступен (разомкнут предохранитель)"
+// @Failure      401 {object} AuthErrorResponse "API-ключ не передан или неизвестен"
+// @Failure      403 {object} AuthErrorResponse "У ключа нет доступа к маршруту"
+// @Failure      429 {object} AuthErrorResponse "Превышен лимит запросов или суточная квота, см. Retry-After"
+// @Security     ApiKeyAuth
 // @Router       /deleteLog [delete]
 func DeleteLogSwagger() {}
 
+// RestoreLogSwagger godoc
+// @Summary      Восстановить удалённый лог
+// @Description  Возвращает лог из корзины лог-сервиса в исходный файл, пока срок восстановления не истёк.
+//
+//	Восстановление записывается в журнал аудита вместе с именем API-ключа и причиной.
+//
+// @Tags         logs
+// @Produce      json
+// @Param        id       query     string  true  "Идентификатор удалённого лога"
+// @Param        filename query     string  true  "Имя файла, из которого лог был удалён (например, http_logs.json)"
+// @Param        reason   query     string  false "Причина восстановления для журнала аудита"
+// @Success      200 {object} RestoreResponse "Лог восстановлен"
+// @Failure      400 {string} string "Отсутствуют обязательные параметры id или filename"
+// @Failure      500 {string} string "Лога нет в корзине (не удалялся или уже стёрт) либо ошибка gRPC-запроса"
+// @Failure      503 {string} string "Лог-сервис недоступен (разомкнут предохранитель)"
+// @Failure      401 {object} AuthErrorResponse "API-ключ не передан или неизвестен"
+// @Failure      403 {object} AuthErrorResponse "У ключа нет доступа к маршруту"
+// @Failure      429 {object} AuthErrorResponse "Превышен лимит запросов или суточная квота, см. Retry-After"
+// @Security     ApiKeyAuth
+// @Router       /restoreLog [post]
+func RestoreLogSwagger() {}
+
 // ReadLogSwagger godoc
 // @Summary      Получить лог по идентификатору и имени файла
 // @Description  Обрабатывает HTTP GET-запрос и выполняет gRPC-вызов к лог-сервису для получения структурированного лог-сообщения.
@@ -111,17 +521,183 @@ func DeleteLogSwagger() {}
 // @Tags         logs
 // @Accept       json
 // @Produce      json
-// @Param        id       query     string  true  "Уникальный идентификатор лога (например, rozNzBFDWy)"
+// @Param        id       query     string  true  "Уникальный идентификатор лога (например, 01JWMCKG00B7Q8N4XZ5V2E3R6T)"
 // @Param        filename query     string  true  "Имя файла, из которого необходимо извлечь лог (например, http_logs.json)"
 // @Success      200 {object} ReadResponse "Успешное чтение лога. Поле 'log' содержит структурированное сообщение."
 // @Failure      400 {object} ReadResponse "Ошибка валидации: отсутствует один или оба обязательных параметра (id, filename)"
 // @Failure      500 {object} ReadResponse "Внутренняя ошибка: сбой gRPC-запроса или лог/файл не найден на стороне сервиса"
+// @Failure      503 {string} string "Лог-сервис недоступен (разомкнут предохранитель)"
+// @Failure      401 {object} AuthErrorResponse "API-ключ не передан или неизвестен"
+// @Failure      403 {object} AuthErrorResponse "У ключа нет доступа к маршруту"
+// @Failure      429 {object} AuthErrorResponse "Превышен лимит запросов или суточная квота, см. Retry-After"
+// @Security     ApiKeyAuth
 // @Router       /getLog [get]
 func ReadLogSwagger() {}
 
+// SearchLogsSwagger godoc
+// @Summary      Поиск логов
+// @Description  Ищет записи во всех файлах лог-сервиса по источнику, уровню, времени получения, пути, переменной,
+//
+//	задержке доставки и тексту. Переменная ищется в операциях запроса (var, left, right) и в результатах.
+//	Например, все запросы, вычислявшие total за сутки: /logs?var=total&from=2025-06-01T00:00:00Z&to=2025-06-02T00:00:00Z.
+//	Страницы выдаются по курсору next_cursor: новые записи не сдвигают уже полученные страницы.
+//
+// @Tags         logs
+// @Produce      json
+// @Param        source    query  []string  false  "Источник (HTTP-server, business-server); можно повторять или перечислить через запятую"  collectionFormat(multi)
+// @Param        level     query  []string  false  "Уровень (debug, info, warn, error)"  collectionFormat(multi)
+// @Param        from      query  string    false  "Начало интервала по времени получения, RFC 3339"
+// @Param        to        query  string    false  "Конец интервала по времени получения, RFC 3339"
+// @Param        path      query  string    false  "Путь запроса (например, /process)"
+// @Param        var       query  string    false  "Переменная в операциях или результате"
+// @Param        min_delay query  string    false  "Минимальная задержка доставки (например, 250ms)"
+// @Param        q         query  string    false  "Подстрока в записи без учёта регистра"
+// @Param        limit     query  int       false  "Размер страницы, по умолчанию 50, не больше 500"
+// @Param        cursor    query  string    false  "next_cursor предыдущей страницы"
+// @Param        after_id  query  string    false  "Только записи, выданные после записи с этим id (id упорядочены по времени)"
+// @Param        sort      query  string    false  "Ключ сортировки"  Enums(timestamp, delay)
+// @Param        order     query  string    false  "Порядок сортировки, по умолчанию desc"  Enums(asc, desc)
+// @Success      200 {object} LogSearchResponse "Страница найденных записей"
+// @Failure      400 {string} string "Некорректные параметры поиска"
+// @Failure      500 {string} string "Сбой gRPC-запроса к лог-сервису"
+// @Failure      503 {string} string "Лог-сервис недоступен (разомкнут предохранитель)"
+// @Failure      401 {object} AuthErrorResponse "API-ключ не передан или неизвестен"
+// @Failure      403 {object} AuthErrorResponse "У ключа нет доступа к маршруту"
+// @Failure      429 {object} AuthErrorResponse "Превышен лимит запросов или суточная квота, см. Retry-After"
+// @Security     ApiKeyAuth
+// @Router       /logs [get]
+func SearchLogsSwagger() {}
+
+type LogSearchResponse struct {
+	Logs       []LogSearchHit `json:"logs"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+type LogSearchHit struct {
+	Filename string         `json:"filename" example:"http_logs.json"`
+	Log      map[string]any `json:"log"`
+}
+
+// TailLogsSwagger godoc
+// @Summary      Живой поток логов
+// @Description  Передаёт новые записи по мере того, как лог-сервис их принимает, как Server-Sent Events — замена tail -f в контейнере.
+//
+//	Фильтры те же, что у /logs, плюс ключ и значение метаданных. С since или after_id сначала приходят уже записанные
+//	записи, от старых к новым, затем поток продолжается новыми. Каждое событие log несёт id записи, поэтому EventSource
+//	после обрыва продолжит с заголовка Last-Event-ID. Если клиент не успевает читать, лишние записи пропускаются,
+//	а их число приходит в поле dropped следующего события. Ошибка лог-сервиса приходит событием error, после чего поток закрывается.
+//	В тихом потоке раз в 15 секунд приходит комментарий keepalive.
+//
+// @Tags         logs
+// @Produce      text/event-stream
+// @Param        source         query  []string  false  "Источник (HTTP-server, business-server); можно повторять или перечислить через запятую"  collectionFormat(multi)
+// @Param        level          query  []string  false  "Уровень (debug, info, warn, error)"  collectionFormat(multi)
+// @Param        path           query  string    false  "Путь запроса (например, /process)"
+// @Param        var            query  string    false  "Переменная в операциях или результате"
+// @Param        min_delay      query  string    false  "Минимальная задержка доставки (например, 250ms)"
+// @Param        q              query  string    false  "Подстрока в записи без учёта регистра"
+// @Param        metadata_key   query  string    false  "Ключ метаданных записи"
+// @Param        metadata_value query  string    false  "Значение ключа метаданных; требует metadata_key"
+// @Param        since          query  string    false  "Начать с записей, полученных не раньше этого времени, RFC 3339"
+// @Param        after_id       query  string    false  "Начать с записей, полученных после записи с этим id"
+// @Param        Last-Event-ID  header string    false  "id последнего полученного события; используется, если не заданы since и after_id"
+// @Success      200 {object} LogTailEvent "Поток событий log (и error), поле data — JSON"
+// @Failure      400 {string} string "Некорректные параметры"
+// @Failure      503 {string} string "Лог-сервис недоступен (разомкнут предохранитель)"
+// @Failure      401 {object} AuthErrorResponse "API-ключ не передан или неизвестен"
+// @Failure      403 {object} AuthErrorResponse "У ключа нет доступа к маршруту"
+// @Failure      429 {object} AuthErrorResponse "Превышен лимит запросов или суточная квота, см. Retry-After"
+// @Security     ApiKeyAuth
+// @Router       /logs/tail [get]
+func TailLogsSwagger() {}
+
+type LogTailEvent struct {
+	Filename string         `json:"filename" example:"http_logs.json"`
+	Log      map[string]any `json:"log"`
+	Dropped  int64          `json:"dropped,omitempty" example:"0"`
+}
+
+// DeleteLogsSwagger godoc
+// @Summary      Массовое удаление логов по фильтру
+// @Description  Удаляет все записи, подходящие под фильтр: источник, время получения, ключ и значение метаданных, переменная.
+//
+//	Хотя бы одно условие обязательно. С dry_run=true ничего не удаляется: возвращается число найденных записей
+//	и до 20 их id. Иначе удаление идёт пачками по batch_size, и после каждой пачки в ответ пишется строка NDJSON
+//	с накопленным прогрессом; последняя отмечена done. Ошибка посреди удаления приходит последней строкой с полем error.
+//	Удалённые записи попадают в корзину и до purge_at восстанавливаются через /restoreLog.
+//	Например, все логи тенанта acme за сутки: DELETE /logs?metadata_key=tenant&metadata_value=acme&from=2025-06-01T00:00:00Z&to=2025-06-02T00:00:00Z.
+//
+// @Tags         logs
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Param        source         query  []string  false  "Источник (HTTP-server, business-server); можно повторять или перечислить через запятую"  collectionFormat(multi)
+// @Param        from           query  string    false  "Начало интервала по времени получения, RFC 3339"
+// @Param        to             query  string    false  "Конец интервала по времени получения, RFC 3339"
+// @Param        metadata_key   query  string    false  "Ключ метаданных записи"
+// @Param        metadata_value query  string    false  "Значение ключа метаданных; требует metadata_key"
+// @Param        var            query  string    false  "Переменная в операциях или результате"
+// @Param        dry_run        query  bool      false  "Только посчитать подходящие записи"
+// @Param        batch_size     query  int       false  "Размер пачки между отчётами о прогрессе, по умолчанию 500, не больше 5000"
+// @Param        reason         query  string    false  "Причина удаления для журнала аудита"
+// @Success      200 {object} LogDeleteProgress "Результат dry_run или строка прогресса NDJSON"
+// @Failure      400 {string} string "Некорректный или пустой фильтр"
+// @Failure      500 {string} string "Сбой gRPC-запроса к лог-сервису до начала удаления"
+// @Failure      503 {string} string "Лог-сервис недоступен (разомкнут предохранитель)"
+// @Failure      401 {object} AuthErrorResponse "API-ключ не передан или неизвестен"
+// @Failure      403 {object} AuthErrorResponse "У ключа нет доступа к маршруту"
+// @Failure      429 {object} AuthErrorResponse "Превышен лимит запросов или суточная квота, см. Retry-After"
+// @Security     ApiKeyAuth
+// @Router       /logs [delete]
+func DeleteLogsSwagger() {}
+
+type LogDeleteProgress struct {
+	Matched   int64    `json:"matched" example:"1200"`
+	Deleted   int64    `json:"deleted" example:"500"`
+	Missing   int64    `json:"missing" example:"0"`
+	SampleIDs []string `json:"sample_ids,omitempty"`
+	Done      bool     `json:"done"`
+	PurgeAt   string   `json:"purge_at,omitempty" example:"2025-07-01T00:00:00Z"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// RequestTimelineSwagger godoc
+// @Summary      Хронология запроса
+// @Description  Возвращает одной записью лог входящего запроса, лог результата его обработки и DOT-описание графа зависимостей.
+//
+//	Идентификатор — log_id из ответа POST /process. Все записи запроса связаны полем request_id,
+//	результат ссылается на запрос через parent_id. Пока запрос не обработан, result и graph отсутствуют.
+//
+// @Tags         logs
+// @Produce      json
+// @Param        id   path      string  true  "Идентификатор лога входящего запроса (например, 01JWMCKG00B7Q8N4XZ5V2E3R6T)"
+// @Success      200 {object} RequestTimelineResponse "Запрос, результат и граф"
+// @Failure      404 {string} string "Запрос с таким идентификатором не найден"
+// @Failure      500 {string} string "Сбой gRPC-запроса к лог-сервису"
+// @Failure      503 {string} string "Лог-сервис недоступен (разомкнут предохранитель)"
+// @Failure      401 {object} AuthErrorResponse "API-ключ не передан или неизвестен"
+// @Failure      403 {object} AuthErrorResponse "У ключа нет доступа к маршруту"
+// @Failure      429 {object} AuthErrorResponse "Превышен лимит запросов или суточная квота, см. Retry-After"
+// @Security     ApiKeyAuth
+// @Router       /requests/{id} [get]
+func RequestTimelineSwagger() {}
+
+type RequestTimelineResponse struct {
+	RequestID string         `json:"request_id"`
+	Request   map[string]any `json:"request"`
+	Result    map[string]any `json:"result,omitempty"`
+	Graph     string         `json:"graph,omitempty" example:"digraph G { ... }"`
+}
+
 type DeleteResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message,omitempty"`
+	// Когда лог будет стёрт окончательно, unix ms
+	PurgeAt int64 `json:"purge_at,omitempty"`
+}
+
+type RestoreResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message,omitempty"`
 }
 
 type ReadResponse struct {
@@ -137,6 +713,8 @@ type LogEntry struct {
 	Message       *StructuredMessage     `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
 	Metadata      map[string]string      `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	TimestampSend int64                  `protobuf:"varint,5,opt,name=timestamp_send,json=timestampSend,proto3" json:"timestamp_send,omitempty"`
+	RequestId     string                 `protobuf:"bytes,6,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	ParentId      string                 `protobuf:"bytes,7,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -147,6 +725,7 @@ type StructuredMessage struct {
 	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
 	Body          []*operationJSON       `protobuf:"bytes,3,rep,name=body,proto3" json:"body,omitempty"`
 	Result        OperationResponse      `protobuf:"bytes,4,opt,name=result,proto3" json:"result,omitempty"`
+	Graph         string                 `protobuf:"bytes,5,opt,name=graph,proto3" json:"graph,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
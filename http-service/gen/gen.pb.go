@@ -75,11 +75,13 @@ func (x *VariableValue) GetValue() int64 {
 }
 
 type StructuredMessage struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Method        string                 `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
-	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
-	Body          []*Operation           `protobuf:"bytes,3,rep,name=body,proto3" json:"body,omitempty"`
-	Result        *OperationResponse     `protobuf:"bytes,4,opt,name=result,proto3" json:"result,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Method string                 `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	Path   string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Body   []*Operation           `protobuf:"bytes,3,rep,name=body,proto3" json:"body,omitempty"`
+	Result *OperationResponse     `protobuf:"bytes,4,opt,name=result,proto3" json:"result,omitempty"`
+	// DOT-описание графа зависимостей, построенного при обработке
+	Graph         string `protobuf:"bytes,5,opt,name=graph,proto3" json:"graph,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -142,6 +144,13 @@ func (x *StructuredMessage) GetResult() *OperationResponse {
 	return nil
 }
 
+func (x *StructuredMessage) GetGraph() string {
+	if x != nil {
+		return x.Graph
+	}
+	return ""
+}
+
 type Operation struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
@@ -225,6 +234,11 @@ type LogEntry struct {
 	Message       *StructuredMessage     `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
 	Metadata      map[string]string      `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	TimestampSend int64                  `protobuf:"varint,5,opt,name=timestamp_send,json=timestampSend,proto3" json:"timestamp_send,omitempty"`
+	// Идентификатор лога входящего запроса, общий для всех записей этого запроса.
+	// Пустой у самого входящего запроса: лог-сервис подставляет выданный ему id.
+	RequestId string `protobuf:"bytes,6,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Идентификатор записи, из которой получена эта (результат ссылается на запрос)
+	ParentId      string `protobuf:"bytes,7,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -294,6 +308,20 @@ func (x *LogEntry) GetTimestampSend() int64 {
 	return 0
 }
 
+func (x *LogEntry) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *LogEntry) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
 type LogID struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -383,9 +411,12 @@ func (x *Nothing) GetDummy() bool {
 }
 
 type LogInfo struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
-	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Filename string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Id       string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// Кто и почему удаляет или восстанавливает запись; пишется в журнал аудита
+	Actor         string `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	Reason        string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -434,10 +465,26 @@ func (x *LogInfo) GetId() string {
 	return ""
 }
 
+func (x *LogInfo) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *LogInfo) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
 type LogDeletionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Когда удалённая запись будет стёрта окончательно (unix ms); до этого её можно восстановить
+	PurgeAt       int64 `protobuf:"varint,3,opt,name=purge_at,json=purgeAt,proto3" json:"purge_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -486,17 +533,78 @@ func (x *LogDeletionResponse) GetMessage() string {
 	return ""
 }
 
-type LogCreationResponse struct {
+func (x *LogDeletionResponse) GetPurgeAt() int64 {
+	if x != nil {
+		return x.PurgeAt
+	}
+	return 0
+}
+
+type LogRestorationResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            *LogID                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *LogRestorationResponse) Reset() {
+	*x = LogRestorationResponse{}
+	mi := &file_gen_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogRestorationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogRestorationResponse) ProtoMessage() {}
+
+func (x *LogRestorationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gen_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogRestorationResponse.ProtoReflect.Descriptor instead.
+func (*LogRestorationResponse) Descriptor() ([]byte, []int) {
+	return file_gen_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *LogRestorationResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *LogRestorationResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type LogCreationResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Id      *LogID                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Запись не сохранена по политике сервиса (SetLogPolicy); id всё равно выдан
+	Dropped       bool `protobuf:"varint,3,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
 func (x *LogCreationResponse) Reset() {
 	*x = LogCreationResponse{}
-	mi := &file_gen_proto_msgTypes[8]
+	mi := &file_gen_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -508,7 +616,7 @@ func (x *LogCreationResponse) String() string {
 func (*LogCreationResponse) ProtoMessage() {}
 
 func (x *LogCreationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_gen_proto_msgTypes[8]
+	mi := &file_gen_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -521,7 +629,7 @@ func (x *LogCreationResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogCreationResponse.ProtoReflect.Descriptor instead.
 func (*LogCreationResponse) Descriptor() ([]byte, []int) {
-	return file_gen_proto_rawDescGZIP(), []int{8}
+	return file_gen_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *LogCreationResponse) GetId() *LogID {
@@ -538,6 +646,13 @@ func (x *LogCreationResponse) GetMessage() string {
 	return ""
 }
 
+func (x *LogCreationResponse) GetDropped() bool {
+	if x != nil {
+		return x.Dropped
+	}
+	return false
+}
+
 type LogReadingResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
@@ -549,7 +664,7 @@ type LogReadingResponse struct {
 
 func (x *LogReadingResponse) Reset() {
 	*x = LogReadingResponse{}
-	mi := &file_gen_proto_msgTypes[9]
+	mi := &file_gen_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -561,7 +676,7 @@ func (x *LogReadingResponse) String() string {
 func (*LogReadingResponse) ProtoMessage() {}
 
 func (x *LogReadingResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_gen_proto_msgTypes[9]
+	mi := &file_gen_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -574,7 +689,7 @@ func (x *LogReadingResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogReadingResponse.ProtoReflect.Descriptor instead.
 func (*LogReadingResponse) Descriptor() ([]byte, []int) {
-	return file_gen_proto_rawDescGZIP(), []int{9}
+	return file_gen_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *LogReadingResponse) GetSuccess() bool {
@@ -598,29 +713,28 @@ func (x *LogReadingResponse) GetError() string {
 	return ""
 }
 
-type OperationRequest struct {
+type RequestTimelineQuery struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	LogID         *LogID                 `protobuf:"bytes,1,opt,name=LogID,proto3" json:"LogID,omitempty"`
-	Operations    []*Operation           `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty"`
+	RequestId     string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OperationRequest) Reset() {
-	*x = OperationRequest{}
-	mi := &file_gen_proto_msgTypes[10]
+func (x *RequestTimelineQuery) Reset() {
+	*x = RequestTimelineQuery{}
+	mi := &file_gen_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OperationRequest) String() string {
+func (x *RequestTimelineQuery) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OperationRequest) ProtoMessage() {}
+func (*RequestTimelineQuery) ProtoMessage() {}
 
-func (x *OperationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_gen_proto_msgTypes[10]
+func (x *RequestTimelineQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_gen_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -631,50 +745,127 @@ func (x *OperationRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OperationRequest.ProtoReflect.Descriptor instead.
-func (*OperationRequest) Descriptor() ([]byte, []int) {
-	return file_gen_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use RequestTimelineQuery.ProtoReflect.Descriptor instead.
+func (*RequestTimelineQuery) Descriptor() ([]byte, []int) {
+	return file_gen_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *OperationRequest) GetLogID() *LogID {
+func (x *RequestTimelineQuery) GetRequestId() string {
 	if x != nil {
-		return x.LogID
+		return x.RequestId
 	}
-	return nil
+	return ""
 }
 
-func (x *OperationRequest) GetOperations() []*Operation {
+// Строки логов хранятся в том виде, в каком записаны в файлы (JSON)
+type RequestTimeline struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestId     string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Request       string                 `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+	Result        string                 `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
+	Graph         string                 `protobuf:"bytes,4,opt,name=graph,proto3" json:"graph,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestTimeline) Reset() {
+	*x = RequestTimeline{}
+	mi := &file_gen_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestTimeline) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestTimeline) ProtoMessage() {}
+
+func (x *RequestTimeline) ProtoReflect() protoreflect.Message {
+	mi := &file_gen_proto_msgTypes[12]
 	if x != nil {
-		return x.Operations
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-type OperationResponse struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	LogID          *LogID                 `protobuf:"bytes,1,opt,name=LogID,proto3" json:"LogID,omitempty"`
-	Items          []*VariableValue       `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
-	Warning        *string                `protobuf:"bytes,3,opt,name=warning,proto3,oneof" json:"warning,omitempty"`
-	ProcessingTime *durationpb.Duration   `protobuf:"bytes,4,opt,name=processing_time,json=processingTime,proto3" json:"processing_time,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+// Deprecated: Use RequestTimeline.ProtoReflect.Descriptor instead.
+func (*RequestTimeline) Descriptor() ([]byte, []int) {
+	return file_gen_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *OperationResponse) Reset() {
-	*x = OperationResponse{}
-	mi := &file_gen_proto_msgTypes[11]
+func (x *RequestTimeline) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *RequestTimeline) GetRequest() string {
+	if x != nil {
+		return x.Request
+	}
+	return ""
+}
+
+func (x *RequestTimeline) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *RequestTimeline) GetGraph() string {
+	if x != nil {
+		return x.Graph
+	}
+	return ""
+}
+
+// Фильтры поиска; пустые поля не ограничивают выборку
+type LogSearchQuery struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Sources []string               `protobuf:"bytes,1,rep,name=sources,proto3" json:"sources,omitempty"`
+	Levels  []string               `protobuf:"bytes,2,rep,name=levels,proto3" json:"levels,omitempty"`
+	// Границы по времени получения, unix ms, включительно
+	From int64  `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`
+	To   int64  `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`
+	Path string `protobuf:"bytes,5,opt,name=path,proto3" json:"path,omitempty"`
+	// Переменная, встречающаяся в операциях или результате
+	Variable   string `protobuf:"bytes,6,opt,name=variable,proto3" json:"variable,omitempty"`
+	MinDelayMs int64  `protobuf:"varint,7,opt,name=min_delay_ms,json=minDelayMs,proto3" json:"min_delay_ms,omitempty"`
+	Text       string `protobuf:"bytes,8,opt,name=text,proto3" json:"text,omitempty"`
+	Limit      int32  `protobuf:"varint,9,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor     string `protobuf:"bytes,10,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// timestamp (по умолчанию) или delay
+	Sort string `protobuf:"bytes,11,opt,name=sort,proto3" json:"sort,omitempty"`
+	// asc или desc (по умолчанию)
+	Order string `protobuf:"bytes,12,opt,name=order,proto3" json:"order,omitempty"`
+	// Только записи с id в формате ULID, выданные после этого id
+	AfterId       string `protobuf:"bytes,13,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogSearchQuery) Reset() {
+	*x = LogSearchQuery{}
+	mi := &file_gen_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OperationResponse) String() string {
+func (x *LogSearchQuery) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OperationResponse) ProtoMessage() {}
+func (*LogSearchQuery) ProtoMessage() {}
 
-func (x *OperationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_gen_proto_msgTypes[11]
+func (x *LogSearchQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_gen_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -685,105 +876,1242 @@ func (x *OperationResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OperationResponse.ProtoReflect.Descriptor instead.
-func (*OperationResponse) Descriptor() ([]byte, []int) {
-	return file_gen_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use LogSearchQuery.ProtoReflect.Descriptor instead.
+func (*LogSearchQuery) Descriptor() ([]byte, []int) {
+	return file_gen_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *OperationResponse) GetLogID() *LogID {
+func (x *LogSearchQuery) GetSources() []string {
 	if x != nil {
-		return x.LogID
+		return x.Sources
 	}
 	return nil
 }
 
-func (x *OperationResponse) GetItems() []*VariableValue {
+func (x *LogSearchQuery) GetLevels() []string {
 	if x != nil {
-		return x.Items
+		return x.Levels
 	}
 	return nil
 }
 
-func (x *OperationResponse) GetWarning() string {
-	if x != nil && x.Warning != nil {
-		return *x.Warning
+func (x *LogSearchQuery) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *LogSearchQuery) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *LogSearchQuery) GetPath() string {
+	if x != nil {
+		return x.Path
 	}
 	return ""
 }
 
-func (x *OperationResponse) GetProcessingTime() *durationpb.Duration {
+func (x *LogSearchQuery) GetVariable() string {
 	if x != nil {
-		return x.ProcessingTime
+		return x.Variable
 	}
-	return nil
+	return ""
 }
 
-var File_gen_proto protoreflect.FileDescriptor
+func (x *LogSearchQuery) GetMinDelayMs() int64 {
+	if x != nil {
+		return x.MinDelayMs
+	}
+	return 0
+}
 
-const file_gen_proto_rawDesc = "" +
-	"\n" +
-	"\tgen.proto\x12\x03gen\x1a\x1egoogle/protobuf/duration.proto\"7\n" +
-	"\rVariableValue\x12\x10\n" +
-	"\x03var\x18\x01 \x01(\tR\x03var\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\x03R\x05value\"\x93\x01\n" +
-	"\x11StructuredMessage\x12\x16\n" +
-	"\x06method\x18\x01 \x01(\tR\x06method\x12\x12\n" +
-	"\x04path\x18\x02 \x01(\tR\x04path\x12\"\n" +
-	"\x04body\x18\x03 \x03(\v2\x0e.gen.OperationR\x04body\x12.\n" +
-	"\x06result\x18\x04 \x01(\v2\x16.gen.OperationResponseR\x06result\"k\n" +
-	"\tOperation\x12\x12\n" +
-	"\x04type\x18\x01 \x01(\tR\x04type\x12\x0e\n" +
-	"\x02op\x18\x02 \x01(\tR\x02op\x12\x10\n" +
-	"\x03var\x18\x03 \x01(\tR\x03var\x12\x12\n" +
-	"\x04left\x18\x04 \x01(\tR\x04left\x12\x14\n" +
-	"\x05right\x18\x05 \x01(\tR\x05right\"\x92\x02\n" +
-	"\bLogEntry\x12!\n" +
-	"\fservice_name\x18\x01 \x01(\tR\vserviceName\x12\x14\n" +
-	"\x05level\x18\x02 \x01(\tR\x05level\x120\n" +
-	"\amessage\x18\x03 \x01(\v2\x16.gen.StructuredMessageR\amessage\x127\n" +
-	"\bmetadata\x18\x04 \x03(\v2\x1b.gen.LogEntry.MetadataEntryR\bmetadata\x12%\n" +
-	"\x0etimestamp_send\x18\x05 \x01(\x03R\rtimestampSend\x1a;\n" +
-	"\rMetadataEntry\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x17\n" +
-	"\x05LogID\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"\x1f\n" +
-	"\aNothing\x12\x14\n" +
-	"\x05dummy\x18\x01 \x01(\bR\x05dummy\"5\n" +
-	"\aLogInfo\x12\x1a\n" +
-	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x0e\n" +
-	"\x02id\x18\x02 \x01(\tR\x02id\"I\n" +
-	"\x13LogDeletionResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"K\n" +
-	"\x13LogCreationResponse\x12\x1a\n" +
-	"\x02id\x18\x01 \x01(\v2\n" +
-	".gen.LogIDR\x02id\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"V\n" +
-	"\x12LogReadingResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x10\n" +
-	"\x03log\x18\x02 \x01(\tR\x03log\x12\x14\n" +
-	"\x05error\x18\x03 \x01(\tR\x05error\"d\n" +
-	"\x10OperationRequest\x12 \n" +
-	"\x05LogID\x18\x01 \x01(\v2\n" +
-	".gen.LogIDR\x05LogID\x12.\n" +
-	"\n" +
-	"operations\x18\x02 \x03(\v2\x0e.gen.OperationR\n" +
-	"operations\"\xce\x01\n" +
-	"\x11OperationResponse\x12 \n" +
-	"\x05LogID\x18\x01 \x01(\v2\n" +
-	".gen.LogIDR\x05LogID\x12(\n" +
-	"\x05items\x18\x02 \x03(\v2\x12.gen.VariableValueR\x05items\x12\x1d\n" +
-	"\awarning\x18\x03 \x01(\tH\x00R\awarning\x88\x01\x01\x12B\n" +
-	"\x0fprocessing_time\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x0eprocessingTimeB\n" +
-	"\n" +
-	"\b_warning2\xad\x01\n" +
+func (x *LogSearchQuery) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *LogSearchQuery) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *LogSearchQuery) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *LogSearchQuery) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *LogSearchQuery) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+func (x *LogSearchQuery) GetAfterId() string {
+	if x != nil {
+		return x.AfterId
+	}
+	return ""
+}
+
+type LogSearchHit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Log           string                 `protobuf:"bytes,2,opt,name=log,proto3" json:"log,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogSearchHit) Reset() {
+	*x = LogSearchHit{}
+	mi := &file_gen_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogSearchHit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogSearchHit) ProtoMessage() {}
+
+func (x *LogSearchHit) ProtoReflect() protoreflect.Message {
+	mi := &file_gen_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogSearchHit.ProtoReflect.Descriptor instead.
+func (*LogSearchHit) Descriptor() ([]byte, []int) {
+	return file_gen_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *LogSearchHit) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *LogSearchHit) GetLog() string {
+	if x != nil {
+		return x.Log
+	}
+	return ""
+}
+
+type LogSearchResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Hits  []*LogSearchHit        `protobuf:"bytes,1,rep,name=hits,proto3" json:"hits,omitempty"`
+	// Пустой, если страниц больше нет
+	NextCursor    string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogSearchResult) Reset() {
+	*x = LogSearchResult{}
+	mi := &file_gen_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogSearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogSearchResult) ProtoMessage() {}
+
+func (x *LogSearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_gen_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogSearchResult.ProtoReflect.Descriptor instead.
+func (*LogSearchResult) Descriptor() ([]byte, []int) {
+	return file_gen_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *LogSearchResult) GetHits() []*LogSearchHit {
+	if x != nil {
+		return x.Hits
+	}
+	return nil
+}
+
+func (x *LogSearchResult) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+// Фильтр массового удаления; пустые поля не ограничивают, но хотя бы одно
+// условие обязательно
+type LogDeleteQuery struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Sources []string               `protobuf:"bytes,1,rep,name=sources,proto3" json:"sources,omitempty"`
+	// Границы по времени получения, unix ms, включительно
+	From int64 `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`
+	To   int64 `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`
+	// Ключ метаданных записи и, если задано, его значение
+	MetadataKey   string `protobuf:"bytes,4,opt,name=metadata_key,json=metadataKey,proto3" json:"metadata_key,omitempty"`
+	MetadataValue string `protobuf:"bytes,5,opt,name=metadata_value,json=metadataValue,proto3" json:"metadata_value,omitempty"`
+	// Переменная, встречающаяся в операциях или результате
+	Variable string `protobuf:"bytes,6,opt,name=variable,proto3" json:"variable,omitempty"`
+	// Только посчитать подходящие записи и вернуть образец их id
+	DryRun bool `protobuf:"varint,7,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// Сколько записей удалять между отчётами о прогрессе, по умолчанию 500
+	BatchSize int32 `protobuf:"varint,8,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	// Кто и почему удаляет; пишется в журнал аудита
+	Actor         string `protobuf:"bytes,9,opt,name=actor,proto3" json:"actor,omitempty"`
+	Reason        string `protobuf:"bytes,10,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogDeleteQuery) Reset() {
+	*x = LogDeleteQuery{}
+	mi := &file_gen_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogDeleteQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogDeleteQuery) ProtoMessage() {}
+
+func (x *LogDeleteQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_gen_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogDeleteQuery.ProtoReflect.Descriptor instead.
+func (*LogDeleteQuery) Descriptor() ([]byte, []int) {
+	return file_gen_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *LogDeleteQuery) GetSources() []string {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *LogDeleteQuery) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *LogDeleteQuery) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *LogDeleteQuery) GetMetadataKey() string {
+	if x != nil {
+		return x.MetadataKey
+	}
+	return ""
+}
+
+func (x *LogDeleteQuery) GetMetadataValue() string {
+	if x != nil {
+		return x.MetadataValue
+	}
+	return ""
+}
+
+func (x *LogDeleteQuery) GetVariable() string {
+	if x != nil {
+		return x.Variable
+	}
+	return ""
+}
+
+func (x *LogDeleteQuery) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *LogDeleteQuery) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+func (x *LogDeleteQuery) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *LogDeleteQuery) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// Первое сообщение DeleteLogs несёт число найденных записей и образец id,
+// следующие — прогресс после каждой пачки; последнее отмечено done
+type LogDeleteProgress struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Matched int64                  `protobuf:"varint,1,opt,name=matched,proto3" json:"matched,omitempty"`
+	Deleted int64                  `protobuf:"varint,2,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	// Записи, которых уже не было к моменту удаления
+	Missing   int64    `protobuf:"varint,3,opt,name=missing,proto3" json:"missing,omitempty"`
+	SampleIds []string `protobuf:"bytes,4,rep,name=sample_ids,json=sampleIds,proto3" json:"sample_ids,omitempty"`
+	Done      bool     `protobuf:"varint,5,opt,name=done,proto3" json:"done,omitempty"`
+	// До этого времени удалённое можно восстановить (unix ms); 0 — удалено безвозвратно
+	PurgeAt       int64 `protobuf:"varint,6,opt,name=purge_at,json=purgeAt,proto3" json:"purge_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogDeleteProgress) Reset() {
+	*x = LogDeleteProgress{}
+	mi := &file_gen_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogDeleteProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogDeleteProgress) ProtoMessage() {}
+
+func (x *LogDeleteProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_gen_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogDeleteProgress.ProtoReflect.Descriptor instead.
+func (*LogDeleteProgress) Descriptor() ([]byte, []int) {
+	return file_gen_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *LogDeleteProgress) GetMatched() int64 {
+	if x != nil {
+		return x.Matched
+	}
+	return 0
+}
+
+func (x *LogDeleteProgress) GetDeleted() int64 {
+	if x != nil {
+		return x.Deleted
+	}
+	return 0
+}
+
+func (x *LogDeleteProgress) GetMissing() int64 {
+	if x != nil {
+		return x.Missing
+	}
+	return 0
+}
+
+func (x *LogDeleteProgress) GetSampleIds() []string {
+	if x != nil {
+		return x.SampleIds
+	}
+	return nil
+}
+
+func (x *LogDeleteProgress) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+func (x *LogDeleteProgress) GetPurgeAt() int64 {
+	if x != nil {
+		return x.PurgeAt
+	}
+	return 0
+}
+
+// id записей IngestLogs в порядке их отправки
+type LogIngestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []*LogID               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogIngestResponse) Reset() {
+	*x = LogIngestResponse{}
+	mi := &file_gen_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogIngestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogIngestResponse) ProtoMessage() {}
+
+func (x *LogIngestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gen_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogIngestResponse.ProtoReflect.Descriptor instead.
+func (*LogIngestResponse) Descriptor() ([]byte, []int) {
+	return file_gen_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *LogIngestResponse) GetIds() []*LogID {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+// Фильтры живого потока — те же, что у поиска. since или after_id
+// сначала досылают уже записанное, затем поток продолжается новыми записями
+type LogTailQuery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sources       []string               `protobuf:"bytes,1,rep,name=sources,proto3" json:"sources,omitempty"`
+	Levels        []string               `protobuf:"bytes,2,rep,name=levels,proto3" json:"levels,omitempty"`
+	Path          string                 `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	Variable      string                 `protobuf:"bytes,4,opt,name=variable,proto3" json:"variable,omitempty"`
+	MinDelayMs    int64                  `protobuf:"varint,5,opt,name=min_delay_ms,json=minDelayMs,proto3" json:"min_delay_ms,omitempty"`
+	Text          string                 `protobuf:"bytes,6,opt,name=text,proto3" json:"text,omitempty"`
+	MetadataKey   string                 `protobuf:"bytes,7,opt,name=metadata_key,json=metadataKey,proto3" json:"metadata_key,omitempty"`
+	MetadataValue string                 `protobuf:"bytes,8,opt,name=metadata_value,json=metadataValue,proto3" json:"metadata_value,omitempty"`
+	// Начать с записей, полученных не раньше, unix ms
+	Since int64 `protobuf:"varint,9,opt,name=since,proto3" json:"since,omitempty"`
+	// Начать с записей, полученных после записи с этим id
+	AfterId       string `protobuf:"bytes,10,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogTailQuery) Reset() {
+	*x = LogTailQuery{}
+	mi := &file_gen_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogTailQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogTailQuery) ProtoMessage() {}
+
+func (x *LogTailQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_gen_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogTailQuery.ProtoReflect.Descriptor instead.
+func (*LogTailQuery) Descriptor() ([]byte, []int) {
+	return file_gen_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *LogTailQuery) GetSources() []string {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *LogTailQuery) GetLevels() []string {
+	if x != nil {
+		return x.Levels
+	}
+	return nil
+}
+
+func (x *LogTailQuery) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *LogTailQuery) GetVariable() string {
+	if x != nil {
+		return x.Variable
+	}
+	return ""
+}
+
+func (x *LogTailQuery) GetMinDelayMs() int64 {
+	if x != nil {
+		return x.MinDelayMs
+	}
+	return 0
+}
+
+func (x *LogTailQuery) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *LogTailQuery) GetMetadataKey() string {
+	if x != nil {
+		return x.MetadataKey
+	}
+	return ""
+}
+
+func (x *LogTailQuery) GetMetadataValue() string {
+	if x != nil {
+		return x.MetadataValue
+	}
+	return ""
+}
+
+func (x *LogTailQuery) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *LogTailQuery) GetAfterId() string {
+	if x != nil {
+		return x.AfterId
+	}
+	return ""
+}
+
+type LogTailEvent struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Filename string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Log      string                 `protobuf:"bytes,2,opt,name=log,proto3" json:"log,omitempty"`
+	// Сколько записей пропущено перед этой, потому что подписчик не успевал их читать
+	Dropped       int64 `protobuf:"varint,3,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogTailEvent) Reset() {
+	*x = LogTailEvent{}
+	mi := &file_gen_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogTailEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogTailEvent) ProtoMessage() {}
+
+func (x *LogTailEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_gen_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogTailEvent.ProtoReflect.Descriptor instead.
+func (*LogTailEvent) Descriptor() ([]byte, []int) {
+	return file_gen_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *LogTailEvent) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *LogTailEvent) GetLog() string {
+	if x != nil {
+		return x.Log
+	}
+	return ""
+}
+
+func (x *LogTailEvent) GetDropped() int64 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
+
+// Политика приёма записей сервиса; пустой service — политика сервисов без своей.
+// Политика без ограничений удаляет прежнюю
+type LogPolicy struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Service string                 `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	// debug, info, warn или error; записи ниже отбрасываются, пусто — все уровни
+	MinLevel string `protobuf:"bytes,2,opt,name=min_level,json=minLevel,proto3" json:"min_level,omitempty"`
+	// Доля сохраняемых записей debug и info, от 0 до 1; 0 — сохранять все
+	DebugSampleRate float64 `protobuf:"fixed64,3,opt,name=debug_sample_rate,json=debugSampleRate,proto3" json:"debug_sample_rate,omitempty"`
+	InfoSampleRate  float64 `protobuf:"fixed64,4,opt,name=info_sample_rate,json=infoSampleRate,proto3" json:"info_sample_rate,omitempty"`
+	// Сколько записей политика отбросила с запуска сервиса или её изменения
+	DroppedByLevel    uint64 `protobuf:"varint,5,opt,name=dropped_by_level,json=droppedByLevel,proto3" json:"dropped_by_level,omitempty"`
+	DroppedBySampling uint64 `protobuf:"varint,6,opt,name=dropped_by_sampling,json=droppedBySampling,proto3" json:"dropped_by_sampling,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *LogPolicy) Reset() {
+	*x = LogPolicy{}
+	mi := &file_gen_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogPolicy) ProtoMessage() {}
+
+func (x *LogPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_gen_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogPolicy.ProtoReflect.Descriptor instead.
+func (*LogPolicy) Descriptor() ([]byte, []int) {
+	return file_gen_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *LogPolicy) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *LogPolicy) GetMinLevel() string {
+	if x != nil {
+		return x.MinLevel
+	}
+	return ""
+}
+
+func (x *LogPolicy) GetDebugSampleRate() float64 {
+	if x != nil {
+		return x.DebugSampleRate
+	}
+	return 0
+}
+
+func (x *LogPolicy) GetInfoSampleRate() float64 {
+	if x != nil {
+		return x.InfoSampleRate
+	}
+	return 0
+}
+
+func (x *LogPolicy) GetDroppedByLevel() uint64 {
+	if x != nil {
+		return x.DroppedByLevel
+	}
+	return 0
+}
+
+func (x *LogPolicy) GetDroppedBySampling() uint64 {
+	if x != nil {
+		return x.DroppedBySampling
+	}
+	return 0
+}
+
+type LogPolicies struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policies      []*LogPolicy           `protobuf:"bytes,1,rep,name=policies,proto3" json:"policies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogPolicies) Reset() {
+	*x = LogPolicies{}
+	mi := &file_gen_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogPolicies) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogPolicies) ProtoMessage() {}
+
+func (x *LogPolicies) ProtoReflect() protoreflect.Message {
+	mi := &file_gen_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogPolicies.ProtoReflect.Descriptor instead.
+func (*LogPolicies) Descriptor() ([]byte, []int) {
+	return file_gen_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *LogPolicies) GetPolicies() []*LogPolicy {
+	if x != nil {
+		return x.Policies
+	}
+	return nil
+}
+
+// Очередь результатов операций на отправку в Kafka. Сообщения доставляются
+// по порядку, поля попыток относятся к первому из них
+type OutboxStats struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Pending uint64                 `protobuf:"varint,1,opt,name=pending,proto3" json:"pending,omitempty"`
+	// Время постановки в очередь первого сообщения, unix ms; 0 — очередь пуста
+	OldestEnqueuedAt int64  `protobuf:"varint,2,opt,name=oldest_enqueued_at,json=oldestEnqueuedAt,proto3" json:"oldest_enqueued_at,omitempty"`
+	Attempts         uint32 `protobuf:"varint,3,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	// Время следующей попытки после неудачи, unix ms
+	NextAttemptAt int64  `protobuf:"varint,4,opt,name=next_attempt_at,json=nextAttemptAt,proto3" json:"next_attempt_at,omitempty"`
+	LastError     string `protobuf:"bytes,5,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	// Доставлено с запуска сервиса
+	Delivered uint64 `protobuf:"varint,6,opt,name=delivered,proto3" json:"delivered,omitempty"`
+	// Не доставлено за все попытки и записано в файл dead letter
+	DeadLettered  uint64 `protobuf:"varint,7,opt,name=dead_lettered,json=deadLettered,proto3" json:"dead_lettered,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OutboxStats) Reset() {
+	*x = OutboxStats{}
+	mi := &file_gen_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OutboxStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutboxStats) ProtoMessage() {}
+
+func (x *OutboxStats) ProtoReflect() protoreflect.Message {
+	mi := &file_gen_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutboxStats.ProtoReflect.Descriptor instead.
+func (*OutboxStats) Descriptor() ([]byte, []int) {
+	return file_gen_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *OutboxStats) GetPending() uint64 {
+	if x != nil {
+		return x.Pending
+	}
+	return 0
+}
+
+func (x *OutboxStats) GetOldestEnqueuedAt() int64 {
+	if x != nil {
+		return x.OldestEnqueuedAt
+	}
+	return 0
+}
+
+func (x *OutboxStats) GetAttempts() uint32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *OutboxStats) GetNextAttemptAt() int64 {
+	if x != nil {
+		return x.NextAttemptAt
+	}
+	return 0
+}
+
+func (x *OutboxStats) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *OutboxStats) GetDelivered() uint64 {
+	if x != nil {
+		return x.Delivered
+	}
+	return 0
+}
+
+func (x *OutboxStats) GetDeadLettered() uint64 {
+	if x != nil {
+		return x.DeadLettered
+	}
+	return 0
+}
+
+type OperationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LogID         *LogID                 `protobuf:"bytes,1,opt,name=LogID,proto3" json:"LogID,omitempty"`
+	Operations    []*Operation           `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OperationRequest) Reset() {
+	*x = OperationRequest{}
+	mi := &file_gen_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OperationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OperationRequest) ProtoMessage() {}
+
+func (x *OperationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gen_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OperationRequest.ProtoReflect.Descriptor instead.
+func (*OperationRequest) Descriptor() ([]byte, []int) {
+	return file_gen_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *OperationRequest) GetLogID() *LogID {
+	if x != nil {
+		return x.LogID
+	}
+	return nil
+}
+
+func (x *OperationRequest) GetOperations() []*Operation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+type OperationResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	LogID          *LogID                 `protobuf:"bytes,1,opt,name=LogID,proto3" json:"LogID,omitempty"`
+	Items          []*VariableValue       `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	Warning        *string                `protobuf:"bytes,3,opt,name=warning,proto3,oneof" json:"warning,omitempty"`
+	ProcessingTime *durationpb.Duration   `protobuf:"bytes,4,opt,name=processing_time,json=processingTime,proto3" json:"processing_time,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *OperationResponse) Reset() {
+	*x = OperationResponse{}
+	mi := &file_gen_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OperationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OperationResponse) ProtoMessage() {}
+
+func (x *OperationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gen_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OperationResponse.ProtoReflect.Descriptor instead.
+func (*OperationResponse) Descriptor() ([]byte, []int) {
+	return file_gen_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *OperationResponse) GetLogID() *LogID {
+	if x != nil {
+		return x.LogID
+	}
+	return nil
+}
+
+func (x *OperationResponse) GetItems() []*VariableValue {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *OperationResponse) GetWarning() string {
+	if x != nil && x.Warning != nil {
+		return *x.Warning
+	}
+	return ""
+}
+
+func (x *OperationResponse) GetProcessingTime() *durationpb.Duration {
+	if x != nil {
+		return x.ProcessingTime
+	}
+	return nil
+}
+
+type ProcessProgress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Completed     int32                  `protobuf:"varint,1,opt,name=completed,proto3" json:"completed,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Result        *OperationResponse     `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProcessProgress) Reset() {
+	*x = ProcessProgress{}
+	mi := &file_gen_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessProgress) ProtoMessage() {}
+
+func (x *ProcessProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_gen_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessProgress.ProtoReflect.Descriptor instead.
+func (*ProcessProgress) Descriptor() ([]byte, []int) {
+	return file_gen_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ProcessProgress) GetCompleted() int32 {
+	if x != nil {
+		return x.Completed
+	}
+	return 0
+}
+
+func (x *ProcessProgress) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ProcessProgress) GetResult() *OperationResponse {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+var File_gen_proto protoreflect.FileDescriptor
+
+const file_gen_proto_rawDesc = "" +
+	"\n" +
+	"\tgen.proto\x12\x03gen\x1a\x1egoogle/protobuf/duration.proto\"7\n" +
+	"\rVariableValue\x12\x10\n" +
+	"\x03var\x18\x01 \x01(\tR\x03var\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value\"\xa9\x01\n" +
+	"\x11StructuredMessage\x12\x16\n" +
+	"\x06method\x18\x01 \x01(\tR\x06method\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\"\n" +
+	"\x04body\x18\x03 \x03(\v2\x0e.gen.OperationR\x04body\x12.\n" +
+	"\x06result\x18\x04 \x01(\v2\x16.gen.OperationResponseR\x06result\x12\x14\n" +
+	"\x05graph\x18\x05 \x01(\tR\x05graph\"k\n" +
+	"\tOperation\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x0e\n" +
+	"\x02op\x18\x02 \x01(\tR\x02op\x12\x10\n" +
+	"\x03var\x18\x03 \x01(\tR\x03var\x12\x12\n" +
+	"\x04left\x18\x04 \x01(\tR\x04left\x12\x14\n" +
+	"\x05right\x18\x05 \x01(\tR\x05right\"\xce\x02\n" +
+	"\bLogEntry\x12!\n" +
+	"\fservice_name\x18\x01 \x01(\tR\vserviceName\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\tR\x05level\x120\n" +
+	"\amessage\x18\x03 \x01(\v2\x16.gen.StructuredMessageR\amessage\x127\n" +
+	"\bmetadata\x18\x04 \x03(\v2\x1b.gen.LogEntry.MetadataEntryR\bmetadata\x12%\n" +
+	"\x0etimestamp_send\x18\x05 \x01(\x03R\rtimestampSend\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x06 \x01(\tR\trequestId\x12\x1b\n" +
+	"\tparent_id\x18\a \x01(\tR\bparentId\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x17\n" +
+	"\x05LogID\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x1f\n" +
+	"\aNothing\x12\x14\n" +
+	"\x05dummy\x18\x01 \x01(\bR\x05dummy\"c\n" +
+	"\aLogInfo\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"d\n" +
+	"\x13LogDeletionResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x19\n" +
+	"\bpurge_at\x18\x03 \x01(\x03R\apurgeAt\"L\n" +
+	"\x16LogRestorationResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"e\n" +
+	"\x13LogCreationResponse\x12\x1a\n" +
+	"\x02id\x18\x01 \x01(\v2\n" +
+	".gen.LogIDR\x02id\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x18\n" +
+	"\adropped\x18\x03 \x01(\bR\adropped\"V\n" +
+	"\x12LogReadingResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x10\n" +
+	"\x03log\x18\x02 \x01(\tR\x03log\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"5\n" +
+	"\x14RequestTimelineQuery\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\"x\n" +
+	"\x0fRequestTimeline\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12\x18\n" +
+	"\arequest\x18\x02 \x01(\tR\arequest\x12\x16\n" +
+	"\x06result\x18\x03 \x01(\tR\x06result\x12\x14\n" +
+	"\x05graph\x18\x04 \x01(\tR\x05graph\"\xbf\x02\n" +
+	"\x0eLogSearchQuery\x12\x18\n" +
+	"\asources\x18\x01 \x03(\tR\asources\x12\x16\n" +
+	"\x06levels\x18\x02 \x03(\tR\x06levels\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\x03R\x02to\x12\x12\n" +
+	"\x04path\x18\x05 \x01(\tR\x04path\x12\x1a\n" +
+	"\bvariable\x18\x06 \x01(\tR\bvariable\x12 \n" +
+	"\fmin_delay_ms\x18\a \x01(\x03R\n" +
+	"minDelayMs\x12\x12\n" +
+	"\x04text\x18\b \x01(\tR\x04text\x12\x14\n" +
+	"\x05limit\x18\t \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\n" +
+	" \x01(\tR\x06cursor\x12\x12\n" +
+	"\x04sort\x18\v \x01(\tR\x04sort\x12\x14\n" +
+	"\x05order\x18\f \x01(\tR\x05order\x12\x19\n" +
+	"\bafter_id\x18\r \x01(\tR\aafterId\"<\n" +
+	"\fLogSearchHit\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x10\n" +
+	"\x03log\x18\x02 \x01(\tR\x03log\"Y\n" +
+	"\x0fLogSearchResult\x12%\n" +
+	"\x04hits\x18\x01 \x03(\v2\x11.gen.LogSearchHitR\x04hits\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"\x9a\x02\n" +
+	"\x0eLogDeleteQuery\x12\x18\n" +
+	"\asources\x18\x01 \x03(\tR\asources\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x03R\x02to\x12!\n" +
+	"\fmetadata_key\x18\x04 \x01(\tR\vmetadataKey\x12%\n" +
+	"\x0emetadata_value\x18\x05 \x01(\tR\rmetadataValue\x12\x1a\n" +
+	"\bvariable\x18\x06 \x01(\tR\bvariable\x12\x17\n" +
+	"\adry_run\x18\a \x01(\bR\x06dryRun\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\b \x01(\x05R\tbatchSize\x12\x14\n" +
+	"\x05actor\x18\t \x01(\tR\x05actor\x12\x16\n" +
+	"\x06reason\x18\n" +
+	" \x01(\tR\x06reason\"\xaf\x01\n" +
+	"\x11LogDeleteProgress\x12\x18\n" +
+	"\amatched\x18\x01 \x01(\x03R\amatched\x12\x18\n" +
+	"\adeleted\x18\x02 \x01(\x03R\adeleted\x12\x18\n" +
+	"\amissing\x18\x03 \x01(\x03R\amissing\x12\x1d\n" +
+	"\n" +
+	"sample_ids\x18\x04 \x03(\tR\tsampleIds\x12\x12\n" +
+	"\x04done\x18\x05 \x01(\bR\x04done\x12\x19\n" +
+	"\bpurge_at\x18\x06 \x01(\x03R\apurgeAt\"1\n" +
+	"\x11LogIngestResponse\x12\x1c\n" +
+	"\x03ids\x18\x01 \x03(\v2\n" +
+	".gen.LogIDR\x03ids\"\xa1\x02\n" +
+	"\fLogTailQuery\x12\x18\n" +
+	"\asources\x18\x01 \x03(\tR\asources\x12\x16\n" +
+	"\x06levels\x18\x02 \x03(\tR\x06levels\x12\x12\n" +
+	"\x04path\x18\x03 \x01(\tR\x04path\x12\x1a\n" +
+	"\bvariable\x18\x04 \x01(\tR\bvariable\x12 \n" +
+	"\fmin_delay_ms\x18\x05 \x01(\x03R\n" +
+	"minDelayMs\x12\x12\n" +
+	"\x04text\x18\x06 \x01(\tR\x04text\x12!\n" +
+	"\fmetadata_key\x18\a \x01(\tR\vmetadataKey\x12%\n" +
+	"\x0emetadata_value\x18\b \x01(\tR\rmetadataValue\x12\x14\n" +
+	"\x05since\x18\t \x01(\x03R\x05since\x12\x19\n" +
+	"\bafter_id\x18\n" +
+	" \x01(\tR\aafterId\"V\n" +
+	"\fLogTailEvent\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x10\n" +
+	"\x03log\x18\x02 \x01(\tR\x03log\x12\x18\n" +
+	"\adropped\x18\x03 \x01(\x03R\adropped\"\xf2\x01\n" +
+	"\tLogPolicy\x12\x18\n" +
+	"\aservice\x18\x01 \x01(\tR\aservice\x12\x1b\n" +
+	"\tmin_level\x18\x02 \x01(\tR\bminLevel\x12*\n" +
+	"\x11debug_sample_rate\x18\x03 \x01(\x01R\x0fdebugSampleRate\x12(\n" +
+	"\x10info_sample_rate\x18\x04 \x01(\x01R\x0einfoSampleRate\x12(\n" +
+	"\x10dropped_by_level\x18\x05 \x01(\x04R\x0edroppedByLevel\x12.\n" +
+	"\x13dropped_by_sampling\x18\x06 \x01(\x04R\x11droppedBySampling\"9\n" +
+	"\vLogPolicies\x12*\n" +
+	"\bpolicies\x18\x01 \x03(\v2\x0e.gen.LogPolicyR\bpolicies\"\xfb\x01\n" +
+	"\vOutboxStats\x12\x18\n" +
+	"\apending\x18\x01 \x01(\x04R\apending\x12,\n" +
+	"\x12oldest_enqueued_at\x18\x02 \x01(\x03R\x10oldestEnqueuedAt\x12\x1a\n" +
+	"\battempts\x18\x03 \x01(\rR\battempts\x12&\n" +
+	"\x0fnext_attempt_at\x18\x04 \x01(\x03R\rnextAttemptAt\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x05 \x01(\tR\tlastError\x12\x1c\n" +
+	"\tdelivered\x18\x06 \x01(\x04R\tdelivered\x12#\n" +
+	"\rdead_lettered\x18\a \x01(\x04R\fdeadLettered\"d\n" +
+	"\x10OperationRequest\x12 \n" +
+	"\x05LogID\x18\x01 \x01(\v2\n" +
+	".gen.LogIDR\x05LogID\x12.\n" +
+	"\n" +
+	"operations\x18\x02 \x03(\v2\x0e.gen.OperationR\n" +
+	"operations\"\xce\x01\n" +
+	"\x11OperationResponse\x12 \n" +
+	"\x05LogID\x18\x01 \x01(\v2\n" +
+	".gen.LogIDR\x05LogID\x12(\n" +
+	"\x05items\x18\x02 \x03(\v2\x12.gen.VariableValueR\x05items\x12\x1d\n" +
+	"\awarning\x18\x03 \x01(\tH\x00R\awarning\x88\x01\x01\x12B\n" +
+	"\x0fprocessing_time\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x0eprocessingTimeB\n" +
+	"\n" +
+	"\b_warning\"u\n" +
+	"\x0fProcessProgress\x12\x1c\n" +
+	"\tcompleted\x18\x01 \x01(\x05R\tcompleted\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12.\n" +
+	"\x06result\x18\x03 \x01(\v2\x16.gen.OperationResponseR\x06result2\xa4\x05\n" +
 	"\x06Logger\x12<\n" +
-	"\x11HandleIncomingLog\x12\r.gen.LogEntry\x1a\x18.gen.LogCreationResponse\x123\n" +
-	"\tDeleteLog\x12\f.gen.LogInfo\x1a\x18.gen.LogDeletionResponse\x120\n" +
-	"\aReadLog\x12\f.gen.LogInfo\x1a\x17.gen.LogReadingResponse2I\n" +
+	"\x11HandleIncomingLog\x12\r.gen.LogEntry\x1a\x18.gen.LogCreationResponse\x125\n" +
+	"\n" +
+	"IngestLogs\x12\r.gen.LogEntry\x1a\x16.gen.LogIngestResponse(\x01\x123\n" +
+	"\tDeleteLog\x12\f.gen.LogInfo\x1a\x18.gen.LogDeletionResponse\x127\n" +
+	"\n" +
+	"RestoreLog\x12\f.gen.LogInfo\x1a\x1b.gen.LogRestorationResponse\x120\n" +
+	"\aReadLog\x12\f.gen.LogInfo\x1a\x17.gen.LogReadingResponse\x12E\n" +
+	"\x12GetRequestTimeline\x12\x19.gen.RequestTimelineQuery\x1a\x14.gen.RequestTimeline\x127\n" +
+	"\n" +
+	"SearchLogs\x12\x13.gen.LogSearchQuery\x1a\x14.gen.LogSearchResult\x12;\n" +
+	"\n" +
+	"DeleteLogs\x12\x13.gen.LogDeleteQuery\x1a\x16.gen.LogDeleteProgress0\x01\x122\n" +
+	"\bTailLogs\x12\x11.gen.LogTailQuery\x1a\x11.gen.LogTailEvent0\x01\x120\n" +
+	"\fSetLogPolicy\x12\x0e.gen.LogPolicy\x1a\x10.gen.LogPolicies\x120\n" +
+	"\x0eGetLogPolicies\x12\f.gen.Nothing\x1a\x10.gen.LogPolicies\x120\n" +
+	"\x0eGetOutboxStats\x12\f.gen.Nothing\x1a\x10.gen.OutboxStats2\x89\x01\n" +
 	"\rBusinessLogic\x128\n" +
-	"\aProcess\x12\x15.gen.OperationRequest\x1a\x16.gen.OperationResponseB\x03Z\x01.b\x06proto3"
+	"\aProcess\x12\x15.gen.OperationRequest\x1a\x16.gen.OperationResponse\x12>\n" +
+	"\rProcessStream\x12\x15.gen.OperationRequest\x1a\x14.gen.ProcessProgress0\x01B\x03Z\x01.b\x06proto3"
 
 var (
 	file_gen_proto_rawDescOnce sync.Once
@@ -797,47 +2125,86 @@ func file_gen_proto_rawDescGZIP() []byte {
 	return file_gen_proto_rawDescData
 }
 
-var file_gen_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_gen_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
 var file_gen_proto_goTypes = []any{
-	(*VariableValue)(nil),       // 0: gen.VariableValue
-	(*StructuredMessage)(nil),   // 1: gen.StructuredMessage
-	(*Operation)(nil),           // 2: gen.Operation
-	(*LogEntry)(nil),            // 3: gen.LogEntry
-	(*LogID)(nil),               // 4: gen.LogID
-	(*Nothing)(nil),             // 5: gen.Nothing
-	(*LogInfo)(nil),             // 6: gen.LogInfo
-	(*LogDeletionResponse)(nil), // 7: gen.LogDeletionResponse
-	(*LogCreationResponse)(nil), // 8: gen.LogCreationResponse
-	(*LogReadingResponse)(nil),  // 9: gen.LogReadingResponse
-	(*OperationRequest)(nil),    // 10: gen.OperationRequest
-	(*OperationResponse)(nil),   // 11: gen.OperationResponse
-	nil,                         // 12: gen.LogEntry.MetadataEntry
-	(*durationpb.Duration)(nil), // 13: google.protobuf.Duration
+	(*VariableValue)(nil),          // 0: gen.VariableValue
+	(*StructuredMessage)(nil),      // 1: gen.StructuredMessage
+	(*Operation)(nil),              // 2: gen.Operation
+	(*LogEntry)(nil),               // 3: gen.LogEntry
+	(*LogID)(nil),                  // 4: gen.LogID
+	(*Nothing)(nil),                // 5: gen.Nothing
+	(*LogInfo)(nil),                // 6: gen.LogInfo
+	(*LogDeletionResponse)(nil),    // 7: gen.LogDeletionResponse
+	(*LogRestorationResponse)(nil), // 8: gen.LogRestorationResponse
+	(*LogCreationResponse)(nil),    // 9: gen.LogCreationResponse
+	(*LogReadingResponse)(nil),     // 10: gen.LogReadingResponse
+	(*RequestTimelineQuery)(nil),   // 11: gen.RequestTimelineQuery
+	(*RequestTimeline)(nil),        // 12: gen.RequestTimeline
+	(*LogSearchQuery)(nil),         // 13: gen.LogSearchQuery
+	(*LogSearchHit)(nil),           // 14: gen.LogSearchHit
+	(*LogSearchResult)(nil),        // 15: gen.LogSearchResult
+	(*LogDeleteQuery)(nil),         // 16: gen.LogDeleteQuery
+	(*LogDeleteProgress)(nil),      // 17: gen.LogDeleteProgress
+	(*LogIngestResponse)(nil),      // 18: gen.LogIngestResponse
+	(*LogTailQuery)(nil),           // 19: gen.LogTailQuery
+	(*LogTailEvent)(nil),           // 20: gen.LogTailEvent
+	(*LogPolicy)(nil),              // 21: gen.LogPolicy
+	(*LogPolicies)(nil),            // 22: gen.LogPolicies
+	(*OutboxStats)(nil),            // 23: gen.OutboxStats
+	(*OperationRequest)(nil),       // 24: gen.OperationRequest
+	(*OperationResponse)(nil),      // 25: gen.OperationResponse
+	(*ProcessProgress)(nil),        // 26: gen.ProcessProgress
+	nil,                            // 27: gen.LogEntry.MetadataEntry
+	(*durationpb.Duration)(nil),    // 28: google.protobuf.Duration
 }
 var file_gen_proto_depIdxs = []int32{
 	2,  // 0: gen.StructuredMessage.body:type_name -> gen.Operation
-	11, // 1: gen.StructuredMessage.result:type_name -> gen.OperationResponse
+	25, // 1: gen.StructuredMessage.result:type_name -> gen.OperationResponse
 	1,  // 2: gen.LogEntry.message:type_name -> gen.StructuredMessage
-	12, // 3: gen.LogEntry.metadata:type_name -> gen.LogEntry.MetadataEntry
+	27, // 3: gen.LogEntry.metadata:type_name -> gen.LogEntry.MetadataEntry
 	4,  // 4: gen.LogCreationResponse.id:type_name -> gen.LogID
-	4,  // 5: gen.OperationRequest.LogID:type_name -> gen.LogID
-	2,  // 6: gen.OperationRequest.operations:type_name -> gen.Operation
-	4,  // 7: gen.OperationResponse.LogID:type_name -> gen.LogID
-	0,  // 8: gen.OperationResponse.items:type_name -> gen.VariableValue
-	13, // 9: gen.OperationResponse.processing_time:type_name -> google.protobuf.Duration
-	3,  // 10: gen.Logger.HandleIncomingLog:input_type -> gen.LogEntry
-	6,  // 11: gen.Logger.DeleteLog:input_type -> gen.LogInfo
-	6,  // 12: gen.Logger.ReadLog:input_type -> gen.LogInfo
-	10, // 13: gen.BusinessLogic.Process:input_type -> gen.OperationRequest
-	8,  // 14: gen.Logger.HandleIncomingLog:output_type -> gen.LogCreationResponse
-	7,  // 15: gen.Logger.DeleteLog:output_type -> gen.LogDeletionResponse
-	9,  // 16: gen.Logger.ReadLog:output_type -> gen.LogReadingResponse
-	11, // 17: gen.BusinessLogic.Process:output_type -> gen.OperationResponse
-	14, // [14:18] is the sub-list for method output_type
-	10, // [10:14] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	14, // 5: gen.LogSearchResult.hits:type_name -> gen.LogSearchHit
+	4,  // 6: gen.LogIngestResponse.ids:type_name -> gen.LogID
+	21, // 7: gen.LogPolicies.policies:type_name -> gen.LogPolicy
+	4,  // 8: gen.OperationRequest.LogID:type_name -> gen.LogID
+	2,  // 9: gen.OperationRequest.operations:type_name -> gen.Operation
+	4,  // 10: gen.OperationResponse.LogID:type_name -> gen.LogID
+	0,  // 11: gen.OperationResponse.items:type_name -> gen.VariableValue
+	28, // 12: gen.OperationResponse.processing_time:type_name -> google.protobuf.Duration
+	25, // 13: gen.ProcessProgress.result:type_name -> gen.OperationResponse
+	3,  // 14: gen.Logger.HandleIncomingLog:input_type -> gen.LogEntry
+	3,  // 15: gen.Logger.IngestLogs:input_type -> gen.LogEntry
+	6,  // 16: gen.Logger.DeleteLog:input_type -> gen.LogInfo
+	6,  // 17: gen.Logger.RestoreLog:input_type -> gen.LogInfo
+	6,  // 18: gen.Logger.ReadLog:input_type -> gen.LogInfo
+	11, // 19: gen.Logger.GetRequestTimeline:input_type -> gen.RequestTimelineQuery
+	13, // 20: gen.Logger.SearchLogs:input_type -> gen.LogSearchQuery
+	16, // 21: gen.Logger.DeleteLogs:input_type -> gen.LogDeleteQuery
+	19, // 22: gen.Logger.TailLogs:input_type -> gen.LogTailQuery
+	21, // 23: gen.Logger.SetLogPolicy:input_type -> gen.LogPolicy
+	5,  // 24: gen.Logger.GetLogPolicies:input_type -> gen.Nothing
+	5,  // 25: gen.Logger.GetOutboxStats:input_type -> gen.Nothing
+	24, // 26: gen.BusinessLogic.Process:input_type -> gen.OperationRequest
+	24, // 27: gen.BusinessLogic.ProcessStream:input_type -> gen.OperationRequest
+	9,  // 28: gen.Logger.HandleIncomingLog:output_type -> gen.LogCreationResponse
+	18, // 29: gen.Logger.IngestLogs:output_type -> gen.LogIngestResponse
+	7,  // 30: gen.Logger.DeleteLog:output_type -> gen.LogDeletionResponse
+	8,  // 31: gen.Logger.RestoreLog:output_type -> gen.LogRestorationResponse
+	10, // 32: gen.Logger.ReadLog:output_type -> gen.LogReadingResponse
+	12, // 33: gen.Logger.GetRequestTimeline:output_type -> gen.RequestTimeline
+	15, // 34: gen.Logger.SearchLogs:output_type -> gen.LogSearchResult
+	17, // 35: gen.Logger.DeleteLogs:output_type -> gen.LogDeleteProgress
+	20, // 36: gen.Logger.TailLogs:output_type -> gen.LogTailEvent
+	22, // 37: gen.Logger.SetLogPolicy:output_type -> gen.LogPolicies
+	22, // 38: gen.Logger.GetLogPolicies:output_type -> gen.LogPolicies
+	23, // 39: gen.Logger.GetOutboxStats:output_type -> gen.OutboxStats
+	25, // 40: gen.BusinessLogic.Process:output_type -> gen.OperationResponse
+	26, // 41: gen.BusinessLogic.ProcessStream:output_type -> gen.ProcessProgress
+	28, // [28:42] is the sub-list for method output_type
+	14, // [14:28] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_gen_proto_init() }
@@ -845,14 +2212,14 @@ func file_gen_proto_init() {
 	if File_gen_proto != nil {
 		return
 	}
-	file_gen_proto_msgTypes[11].OneofWrappers = []any{}
+	file_gen_proto_msgTypes[25].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_gen_proto_rawDesc), len(file_gen_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   13,
+			NumMessages:   28,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
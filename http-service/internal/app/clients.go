@@ -3,16 +3,53 @@ package app
 import (
 	"context"
 	"http-service/gen"
+	"http-service/internal/auth"
+	"http-service/internal/client/grpc/resilience"
+	"http-service/internal/idempotency"
+	"http-service/internal/jobs"
+	"http-service/internal/webhook"
 )
 
 type BusinessClientInterface interface {
 	Process(ctx context.Context, req *gen.OperationRequest) (*gen.OperationResponse, error)
+	ProcessStream(ctx context.Context, req *gen.OperationRequest, onProgress func(completed, total int32)) (*gen.OperationResponse, error)
+	Status() resilience.Status
+	CheckHealth(ctx context.Context) error
 }
 
 type LogClientInterface interface {
 	ReadLogGRPC(id, filename string) (*gen.LogReadingResponse, error)
-	DeleteLogGRPC(id, filename string) (*gen.LogDeletionResponse, error)
+	DeleteLogGRPC(id, filename, actor, reason string) (*gen.LogDeletionResponse, error)
+	RestoreLogGRPC(id, filename, actor, reason string) (*gen.LogRestorationResponse, error)
 	LogDataGRPC(ctx context.Context, entry *gen.LogEntry) (id *gen.LogID, err error)
+	GetRequestTimelineGRPC(ctx context.Context, requestID string) (*gen.RequestTimeline, error)
+	SearchLogsGRPC(ctx context.Context, query *gen.LogSearchQuery) (*gen.LogSearchResult, error)
+	DeleteLogsGRPC(ctx context.Context, query *gen.LogDeleteQuery, onProgress func(*gen.LogDeleteProgress)) (*gen.LogDeleteProgress, error)
+	TailLogsGRPC(ctx context.Context, query *gen.LogTailQuery, onEvent func(*gen.LogTailEvent) error) error
+	Status() resilience.Status
+	CheckHealth(ctx context.Context) error
+}
+
+type JobManagerInterface interface {
+	Submit(owner string, operations []*gen.Operation, callbackURL string) (*jobs.Job, error)
+	Get(id, owner string) (*jobs.Job, error)
+	Cancel(id, owner string) (*jobs.Job, error)
+}
+
+type WebhookDispatcherInterface interface {
+	ValidateURL(ctx context.Context, raw string) error
+	Enqueue(owner, url, event string, payload any) (*webhook.Delivery, error)
+	Get(id, owner string) (*webhook.Delivery, error)
+	List(owner string, status webhook.Status) []*webhook.Delivery
+	Replay(id, owner string) (*webhook.Delivery, error)
+}
+
+type IdempotencyStoreInterface interface {
+	Do(ctx context.Context, key string, request []byte, fn func() *idempotency.Response) (*idempotency.Response, bool, error)
+}
+
+type AuthenticatorInterface interface {
+	Authorize(apiKey string, scope auth.Scope) auth.Decision
 }
 
 // Dependency Inversion Principle
@@ -20,4 +57,11 @@ type LogClientInterface interface {
 type Clients struct {
 	LogClient      LogClientInterface
 	BusinessClient BusinessClientInterface
+	Jobs           JobManagerInterface
+	Webhooks       WebhookDispatcherInterface
+	Idempotency    IdempotencyStoreInterface
+	Auth           AuthenticatorInterface
+	// Пропускать запросы без проверки ключей; без Auth и этого флага
+	// защищённые маршруты отвечают 503
+	AuthDisabled bool
 }
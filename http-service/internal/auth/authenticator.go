@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+type Reason string
+
+const (
+	ReasonMissingKey    Reason = "missing_key"
+	ReasonUnknownKey    Reason = "unknown_key"
+	ReasonScopeDenied   Reason = "scope_denied"
+	ReasonRateLimited   Reason = "rate_limited"
+	ReasonQuotaExceeded Reason = "quota_exceeded"
+	// Ключи не загружены, а проверка не отключена явно
+	ReasonNotConfigured Reason = "auth_not_configured"
+)
+
+// Decision — результат проверки запроса. Для отказов по лимитам
+// RetryAfter подсказывает, когда повторить запрос.
+type Decision struct {
+	Allowed    bool
+	KeyName    string
+	Reason     Reason
+	RetryAfter time.Duration
+}
+
+type keyState struct {
+	Key
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	day    time.Time
+	used   int
+}
+
+// Authenticator проверяет ключ, права на scope, корзину токенов и суточную квоту.
+// Состояние лимитов хранится в памяти и сбрасывается при перезапуске.
+type Authenticator struct {
+	keys map[[sha256.Size]byte]*keyState
+	now  func() time.Time
+}
+
+func NewAuthenticator(keys []Key) *Authenticator {
+	a := &Authenticator{
+		keys: make(map[[sha256.Size]byte]*keyState, len(keys)),
+		now:  time.Now,
+	}
+	for _, key := range keys {
+		if key.Burst < 1 {
+			key.Burst = int(math.Max(1, math.Ceil(key.RatePerSecond)))
+		}
+		// Ищем по хешу, чтобы время поиска не зависело от совпадения префикса ключа
+		a.keys[sha256.Sum256([]byte(key.Key))] = &keyState{Key: key, tokens: float64(key.Burst)}
+	}
+	return a
+}
+
+func (a *Authenticator) Authorize(apiKey string, scope Scope) Decision {
+	if apiKey == "" {
+		return Decision{Reason: ReasonMissingKey}
+	}
+	state, ok := a.keys[sha256.Sum256([]byte(apiKey))]
+	if !ok {
+		return Decision{Reason: ReasonUnknownKey}
+	}
+	if !slices.Contains(state.Scopes, scope) {
+		return Decision{KeyName: state.Name, Reason: ReasonScopeDenied}
+	}
+	return state.take(a.now())
+}
+
+func (s *keyState) take(now time.Time) Decision {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	day := now.UTC().Truncate(24 * time.Hour)
+	if !day.Equal(s.day) {
+		s.day = day
+		s.used = 0
+	}
+	if s.DailyQuota > 0 && s.used >= s.DailyQuota {
+		return Decision{KeyName: s.Name, Reason: ReasonQuotaExceeded, RetryAfter: day.Add(24 * time.Hour).Sub(now)}
+	}
+
+	if !s.last.IsZero() {
+		elapsed := now.Sub(s.last).Seconds()
+		s.tokens = math.Min(float64(s.Burst), s.tokens+elapsed*s.RatePerSecond)
+	}
+	s.last = now
+	if s.tokens < 1 {
+		wait := (1 - s.tokens) / s.RatePerSecond
+		return Decision{KeyName: s.Name, Reason: ReasonRateLimited, RetryAfter: time.Duration(wait * float64(time.Second))}
+	}
+
+	s.tokens--
+	s.used++
+	return Decision{Allowed: true, KeyName: s.Name}
+}
+
+type keyNameCtxKey struct{}
+
+// WithKeyName сохраняет имя проверенного ключа в контексте запроса.
+func WithKeyName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, keyNameCtxKey{}, name)
+}
+
+// KeyName возвращает имя ключа, от имени которого выполняется запрос, или "".
+func KeyName(ctx context.Context) string {
+	name, _ := ctx.Value(keyNameCtxKey{}).(string)
+	return name
+}
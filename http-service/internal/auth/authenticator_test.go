@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestAuthenticator(now *time.Time, keys ...Key) *Authenticator {
+	a := NewAuthenticator(keys)
+	a.now = func() time.Time { return *now }
+	return a
+}
+
+func TestAuthorizeKeysAndScopes(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	a := newTestAuthenticator(&now, Key{Name: "reader", Key: "r-secret", Scopes: []Scope{ScopeLogsRead}, RatePerSecond: 100})
+
+	tests := []struct {
+		name    string
+		key     string
+		scope   Scope
+		allowed bool
+		reason  Reason
+	}{
+		{"missing key", "", ScopeLogsRead, false, ReasonMissingKey},
+		{"unknown key", "nope", ScopeLogsRead, false, ReasonUnknownKey},
+		{"scope not granted", "r-secret", ScopeLogsDelete, false, ReasonScopeDenied},
+		{"allowed", "r-secret", ScopeLogsRead, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := a.Authorize(tt.key, tt.scope)
+			if d.Allowed != tt.allowed || d.Reason != tt.reason {
+				t.Errorf("expected allowed=%v reason=%q, got %+v", tt.allowed, tt.reason, d)
+			}
+		})
+	}
+}
+
+func TestAuthorizeTokenBucket(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	a := newTestAuthenticator(&now, Key{Name: "ci", Key: "k", Scopes: []Scope{ScopeProcess}, RatePerSecond: 2, Burst: 3})
+
+	for i := 0; i < 3; i++ {
+		if d := a.Authorize("k", ScopeProcess); !d.Allowed {
+			t.Fatalf("request %d: expected burst to allow, got %+v", i+1, d)
+		}
+	}
+
+	d := a.Authorize("k", ScopeProcess)
+	if d.Allowed || d.Reason != ReasonRateLimited || d.RetryAfter != 500*time.Millisecond {
+		t.Fatalf("expected rate limit with 500ms retry, got %+v", d)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if d := a.Authorize("k", ScopeProcess); !d.Allowed {
+		t.Errorf("expected token to be refilled, got %+v", d)
+	}
+}
+
+func TestAuthorizeDailyQuota(t *testing.T) {
+	now := time.Date(2025, 6, 1, 23, 0, 0, 0, time.UTC)
+	a := newTestAuthenticator(&now, Key{Name: "ci", Key: "k", Scopes: []Scope{ScopeProcess}, RatePerSecond: 100, DailyQuota: 2})
+
+	a.Authorize("k", ScopeProcess)
+	a.Authorize("k", ScopeProcess)
+
+	d := a.Authorize("k", ScopeProcess)
+	if d.Allowed || d.Reason != ReasonQuotaExceeded || d.RetryAfter != time.Hour {
+		t.Fatalf("expected quota exceeded until midnight, got %+v", d)
+	}
+
+	now = now.Add(time.Hour)
+	if d := a.Authorize("k", ScopeProcess); !d.Allowed {
+		t.Errorf("expected quota to reset at midnight UTC, got %+v", d)
+	}
+}
+
+func TestLoadKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			content: `keys:
+  - name: ci
+    key: secret
+    scopes: [process, logs:read]
+    rate_per_second: 5
+    daily_quota: 100
+`,
+		},
+		{"no keys", "keys: []", "contains no keys"},
+		{"unknown field", "keys:\n  - {name: a, key: b, colour: red}", "failed to parse"},
+		{"unknown scope", "keys:\n  - {name: a, key: b, scopes: [admin], rate_per_second: 1}", `unknown scope "admin"`},
+		{"duplicate name", "keys:\n  - {name: a, key: b, scopes: [process], rate_per_second: 1}\n  - {name: a, key: c, scopes: [process], rate_per_second: 1}", "duplicate name"},
+		{"missing rate", "keys:\n  - {name: a, key: b, scopes: [process]}", "rate_per_second must be positive"},
+		{"placeholder key", "keys:\n  - {name: a, key: change-me-admin-key, scopes: [process], rate_per_second: 1}", "placeholder"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "keys.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			keys, err := LoadKeys(path)
+			if tt.wantErr == "" {
+				if err != nil || len(keys) != 1 || keys[0].Scopes[1] != ScopeLogsRead {
+					t.Errorf("unexpected result: %+v, %v", keys, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadKeysRejectsExampleFile(t *testing.T) {
+	// Ключи образца общеизвестны, его нельзя использовать как рабочий файл
+	if _, err := LoadKeys("../../api_keys.example.yaml"); err == nil || !strings.Contains(err.Error(), "placeholder") {
+		t.Errorf("expected the example keys file to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"os"
+	"strings"
+)
+
+type Scope string
+
+const (
+	ScopeProcess    Scope = "process"
+	ScopeLogsRead   Scope = "logs:read"
+	ScopeLogsDelete Scope = "logs:delete"
+)
+
+func (s Scope) valid() bool {
+	return s == ScopeProcess || s == ScopeLogsRead || s == ScopeLogsDelete
+}
+
+// Key — API-ключ из файла конфигурации.
+type Key struct {
+	Name   string  `yaml:"name"`
+	Key    string  `yaml:"key"`
+	Scopes []Scope `yaml:"scopes"`
+	// Скорость пополнения корзины токенов (запросов в секунду) и её ёмкость
+	RatePerSecond float64 `yaml:"rate_per_second"`
+	Burst         int     `yaml:"burst"`
+	// Запросов в сутки (UTC), 0 — без ограничения
+	DailyQuota int `yaml:"daily_quota"`
+}
+
+// Ключи из api_keys.example.yaml начинаются с этого префикса; они
+// общеизвестны, поэтому не загружаются
+const placeholderPrefix = "change-me-"
+
+type keysFile struct {
+	Keys []Key `yaml:"keys"`
+}
+
+// LoadKeys читает ключи из YAML-файла вида:
+//
+//	keys:
+//	  - name: dashboard
+//	    key: 9f2c...
+//	    scopes: [process, logs:read]
+//	    rate_per_second: 5
+//	    burst: 10
+//	    daily_quota: 10000
+func LoadKeys(path string) ([]Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API keys file: %w", err)
+	}
+
+	var file keysFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse API keys file: %w", err)
+	}
+	if len(file.Keys) == 0 {
+		return nil, errors.New("API keys file contains no keys")
+	}
+
+	names := make(map[string]bool)
+	secrets := make(map[string]bool)
+	for i, key := range file.Keys {
+		switch {
+		case key.Name == "":
+			return nil, fmt.Errorf("key %d: name is required", i+1)
+		case key.Key == "":
+			return nil, fmt.Errorf("key %q: key is required", key.Name)
+		case strings.HasPrefix(key.Key, placeholderPrefix):
+			return nil, fmt.Errorf("key %q: replace the %s placeholder with a real secret", key.Name, placeholderPrefix)
+		case names[key.Name]:
+			return nil, fmt.Errorf("key %q: duplicate name", key.Name)
+		case secrets[key.Key]:
+			return nil, fmt.Errorf("key %q: duplicate key value", key.Name)
+		case len(key.Scopes) == 0:
+			return nil, fmt.Errorf("key %q: at least one scope is required", key.Name)
+		case key.RatePerSecond <= 0:
+			return nil, fmt.Errorf("key %q: rate_per_second must be positive", key.Name)
+		case key.Burst < 0 || key.DailyQuota < 0:
+			return nil, fmt.Errorf("key %q: burst and daily_quota must not be negative", key.Name)
+		}
+		for _, scope := range key.Scopes {
+			if !scope.valid() {
+				return nil, fmt.Errorf("key %q: unknown scope %q", key.Name, scope)
+			}
+		}
+		names[key.Name] = true
+		secrets[key.Key] = true
+	}
+
+	return file.Keys, nil
+}
@@ -2,71 +2,54 @@ package business
 
 import (
 	"context"
-	"fmt"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 	gen "http-service/gen"
+	"http-service/internal/client/grpc/resilience"
 	"http-service/internal/config"
 	"log"
-	"time"
 )
 
-const connectionError = "rpc error: code = Unavailable desc = connection error: desc = \"transport: Error while dialing: dial tcp 127.0.0.1:9091: connect: connection refused\""
-
 type BusinessClient struct {
-	GRPCClient gen.BusinessLogicClient // Wrap GRPCclient
+	GRPCClient   gen.BusinessLogicClient // Wrap GRPCclient
+	HealthClient healthpb.HealthClient
+
+	downstream *resilience.Downstream
 }
 
+// CreateBusinessClient создаёт клиента без обращения к бизнес-сервису: соединение
+// устанавливается при первом вызове и восстанавливается автоматически.
 func CreateBusinessClient(cfg *config.Config) *BusinessClient {
-	conn, err := grpc.NewClient(cfg.BusinessAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
-
+	conn, err := grpc.NewClient(cfg.BusinessAddr, resilience.DialOptions(cfg)...)
 	if err != nil {
-		log.Fatalf("failed to connect to business server: %v", err)
-	}
-
-	client := &BusinessClient{
-		GRPCClient: gen.NewBusinessLogicClient(conn),
+		log.Printf("failed to create business client: %v", err)
+		return nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 40*time.Second)
-	defer cancel()
-
-	testEntry := createTestEntry()
-
-	resp, err := client.GRPCClient.Process(ctx, testEntry)
-
-	if err != nil {
-		fmt.Println(err.Error())
-
-		if err.Error() == connectionError {
-			log.Println("Business server connection error")
-			return nil
-		} else {
-			log.Printf("Business server connected, but test message failed: %v", err)
-			return nil
-		}
+	return &BusinessClient{
+		GRPCClient:   gen.NewBusinessLogicClient(conn),
+		HealthClient: healthpb.NewHealthClient(conn),
+		downstream:   resilience.NewDownstream("business-service", conn, resilience.OptionsFromConfig(cfg)),
 	}
-
-	log.Printf("Handshake successful, response: %v", resp)
-
-	return client
-
 }
 
-func createTestEntry() (testEntry *gen.OperationRequest) {
-	testOperationCalc := &gen.Operation{
-		Type:  "Test calc",
-		Op:    "Test +",
-		Var:   "Test var x",
-		Left:  "Test 2",
-		Right: "Test 3",
-	}
-
-	testOperationPrint := &gen.Operation{
-		Type: "Test print",
-		Var:  "test x",
-	}
+func (c *BusinessClient) Status() resilience.Status {
+	return c.downstream.Status()
+}
 
-	testEntry = &gen.OperationRequest{Operations: []*gen.Operation{testOperationCalc, testOperationPrint}}
-	return testEntry
+// CheckHealth опрашивает grpc.health.v1 через тот же предохранитель,
+// что и обычные вызовы, поэтому успешная проверка может его замкнуть.
+func (c *BusinessClient) CheckHealth(ctx context.Context) error {
+	return c.downstream.Call(ctx, false, func(ctx context.Context) error {
+		resp, err := c.HealthClient.Check(ctx, &healthpb.HealthCheckRequest{Service: gen.BusinessLogic_ServiceDesc.ServiceName})
+		if err != nil {
+			return err
+		}
+		if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			return status.Errorf(codes.Unavailable, "%s is %s", gen.BusinessLogic_ServiceDesc.ServiceName, resp.GetStatus())
+		}
+		return nil
+	})
 }
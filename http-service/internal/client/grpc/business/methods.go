@@ -2,19 +2,68 @@ package business
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	gen "http-service/gen"
+	"io"
 	"time"
 )
 
+// Process не повторяется: бизнес-сервис логирует результат каждого вызова.
 func (c *BusinessClient) Process(ctx context.Context, req *gen.OperationRequest) (*gen.OperationResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, 40*time.Second)
 	defer cancel()
 
-	resp, err := c.GRPCClient.Process(ctx, req)
+	var resp *gen.OperationResponse
+	err := c.downstream.Call(ctx, false, func(ctx context.Context) (err error) {
+		resp, err = c.GRPCClient.Process(ctx, req)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to call Process: %w", err)
 	}
 
 	return resp, nil
 }
+
+// ProcessStream запускает обработку через потоковый RPC и передаёт прогресс в onProgress.
+// Время выполнения ограничивается только ctx, так как длинные программы выполняются в фоне.
+func (c *BusinessClient) ProcessStream(ctx context.Context, req *gen.OperationRequest, onProgress func(completed, total int32)) (*gen.OperationResponse, error) {
+	var (
+		stream gen.BusinessLogic_ProcessStreamClient
+		result *gen.OperationResponse
+	)
+	err := c.downstream.Stream(ctx, func(ctx context.Context) (err error) {
+		if stream, err = c.GRPCClient.ProcessStream(ctx, req); err != nil {
+			return err
+		}
+		// Бизнес-сервис отправляет заголовки, как только принял задачу
+		_, err = stream.Header()
+		return err
+	}, func() error {
+		// Поток дочитывается до io.EOF: только тогда вызов считается завершённым
+		for {
+			msg, err := stream.Recv()
+			if err == io.EOF {
+				if result == nil {
+					return errors.New("ProcessStream finished without result")
+				}
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			if onProgress != nil {
+				onProgress(msg.GetCompleted(), msg.GetTotal())
+			}
+			if msg.GetResult() != nil {
+				result = msg.GetResult()
+			}
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call ProcessStream: %w", err)
+	}
+	return result, nil
+}
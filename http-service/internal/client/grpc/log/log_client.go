@@ -2,82 +2,54 @@ package log
 
 import (
 	"context"
-	"fmt"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 	gen "http-service/gen"
+	"http-service/internal/client/grpc/resilience"
 	"http-service/internal/config"
 	"log"
-	"time"
 )
 
-const connectionError = "rpc error: code = Unavailable desc = connection error: desc = \"transport: Error while dialing: dial tcp 127.0.0.1:9090: connect: connection refused\""
-
 type LogClient struct {
 	LoggerClient gen.LoggerClient
+	HealthClient healthpb.HealthClient
+
+	downstream *resilience.Downstream
 }
 
+// CreateLogClient создаёт клиента без обращения к лог-сервису: соединение
+// устанавливается при первом вызове, поэтому лог-сервис может стартовать позже.
 func CreateLogClient(cfg *config.Config) *LogClient {
-	conn, err := grpc.NewClient(cfg.LoggerAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
-
+	conn, err := grpc.NewClient(cfg.LoggerAddr, resilience.DialOptions(cfg)...)
 	if err != nil {
-		log.Printf("failed to connect to log server: %v", err)
+		log.Printf("failed to create log client: %v", err)
 		return nil
 	}
 
-	client := &LogClient{
+	return &LogClient{
 		LoggerClient: gen.NewLoggerClient(conn),
+		HealthClient: healthpb.NewHealthClient(conn),
+		downstream:   resilience.NewDownstream("log-service", conn, resilience.OptionsFromConfig(cfg)),
 	}
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
-
-	testEntry := &gen.LogEntry{
-		TimestampSend: time.Now().UnixMilli(),
-		Message: &gen.StructuredMessage{
-			Method: "POST",
-			Path:   "/process",
-			Body: []*gen.Operation{
-				{
-					Type:  "Test type",
-					Op:    "Test operation",
-					Var:   "Test variable",
-					Left:  "Test Left",
-					Right: "Test Right",
-				},
-			},
-			Result: &gen.OperationResponse{
-				Items: []*gen.VariableValue{
-					{
-						Var:   "Test var",
-						Value: 999,
-					},
-				},
-			},
-		},
-		Metadata: map[string]string{
-			"test": "true",
-		},
-		ServiceName: "HTTP-server",
-		Level:       "DEBUG",
-	}
-
-	resp, err := client.LoggerClient.HandleIncomingLog(ctx, testEntry)
-
-	if err != nil {
-		fmt.Println(err.Error())
-		fmt.Println()
+func (c *LogClient) Status() resilience.Status {
+	return c.downstream.Status()
+}
 
-		if err.Error() == connectionError {
-			log.Println("Log server connection error")
-			return nil
-		} else {
-			log.Printf("log server connected, but test message failed: %v", err)
-			return nil
+// CheckHealth опрашивает grpc.health.v1 через тот же предохранитель,
+// что и обычные вызовы, поэтому успешная проверка может его замкнуть.
+func (c *LogClient) CheckHealth(ctx context.Context) error {
+	return c.downstream.Call(ctx, false, func(ctx context.Context) error {
+		resp, err := c.HealthClient.Check(ctx, &healthpb.HealthCheckRequest{Service: gen.Logger_ServiceDesc.ServiceName})
+		if err != nil {
+			return err
 		}
-	}
-	log.Printf("Log server handshake successful, log ID: %v", resp.GetId())
-
-	return client
-
+		if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			return status.Errorf(codes.Unavailable, "%s is %s", gen.Logger_ServiceDesc.ServiceName, resp.GetStatus())
+		}
+		return nil
+	})
 }
@@ -2,15 +2,23 @@ package log
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	gen "http-service/gen"
+	"io"
 	"time"
 )
 
+// LogDataGRPC не повторяется: повтор после таймаута мог бы записать лог дважды.
 func (c *LogClient) LogDataGRPC(ctx context.Context, entry *gen.LogEntry) (*gen.LogID, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
-	resp, err := c.LoggerClient.HandleIncomingLog(ctx, entry)
+
+	var resp *gen.LogCreationResponse
+	err := c.downstream.Call(ctx, false, func(ctx context.Context) (err error) {
+		resp, err = c.LoggerClient.HandleIncomingLog(ctx, entry)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to send log to gRPC server: %w", err)
 	}
@@ -23,9 +31,13 @@ func (c *LogClient) ReadLogGRPC(id string, filename string) (*gen.LogReadingResp
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	readLogResponse, err := c.LoggerClient.ReadLog(ctx, &gen.LogInfo{
-		Id:       id,
-		Filename: filename,
+	var readLogResponse *gen.LogReadingResponse
+	err := c.downstream.Call(ctx, true, func(ctx context.Context) (err error) {
+		readLogResponse, err = c.LoggerClient.ReadLog(ctx, &gen.LogInfo{
+			Id:       id,
+			Filename: filename,
+		})
+		return err
 	})
 
 	fmt.Println(readLogResponse)
@@ -38,14 +50,20 @@ func (c *LogClient) ReadLogGRPC(id string, filename string) (*gen.LogReadingResp
 
 }
 
-func (c *LogClient) DeleteLogGRPC(id string, filename string) (*gen.LogDeletionResponse, error) {
+func (c *LogClient) DeleteLogGRPC(id, filename, actor, reason string) (*gen.LogDeletionResponse, error) {
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	deleteResponse, err := c.LoggerClient.DeleteLog(ctx, &gen.LogInfo{
-		Id:       id,
-		Filename: filename,
+	var deleteResponse *gen.LogDeletionResponse
+	err := c.downstream.Call(ctx, true, func(ctx context.Context) (err error) {
+		deleteResponse, err = c.LoggerClient.DeleteLog(ctx, &gen.LogInfo{
+			Id:       id,
+			Filename: filename,
+			Actor:    actor,
+			Reason:   reason,
+		})
+		return err
 	})
 
 	if err != nil {
@@ -56,3 +74,130 @@ func (c *LogClient) DeleteLogGRPC(id string, filename string) (*gen.LogDeletionR
 	return deleteResponse, err
 
 }
+
+// RestoreLogGRPC повторяется: повторное восстановление той же записи безвредно.
+func (c *LogClient) RestoreLogGRPC(id, filename, actor, reason string) (*gen.LogRestorationResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var restoreResponse *gen.LogRestorationResponse
+	err := c.downstream.Call(ctx, true, func(ctx context.Context) (err error) {
+		restoreResponse, err = c.LoggerClient.RestoreLog(ctx, &gen.LogInfo{
+			Id:       id,
+			Filename: filename,
+			Actor:    actor,
+			Reason:   reason,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call RestoreLog: %w", err)
+	}
+
+	return restoreResponse, nil
+}
+
+func (c *LogClient) GetRequestTimelineGRPC(ctx context.Context, requestID string) (*gen.RequestTimeline, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	var timeline *gen.RequestTimeline
+	err := c.downstream.Call(ctx, true, func(ctx context.Context) (err error) {
+		timeline, err = c.LoggerClient.GetRequestTimeline(ctx, &gen.RequestTimelineQuery{RequestId: requestID})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call GetRequestTimeline: %w", err)
+	}
+
+	return timeline, nil
+}
+
+func (c *LogClient) SearchLogsGRPC(ctx context.Context, query *gen.LogSearchQuery) (*gen.LogSearchResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var result *gen.LogSearchResult
+	err := c.downstream.Call(ctx, true, func(ctx context.Context) (err error) {
+		result, err = c.LoggerClient.SearchLogs(ctx, query)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call SearchLogs: %w", err)
+	}
+
+	return result, nil
+}
+
+// DeleteLogsGRPC удаляет записи по фильтру через потоковый RPC и передаёт
+// прогресс в onProgress. Не повторяется: повтор начал бы удаление заново.
+// Время выполнения ограничивается только ctx.
+func (c *LogClient) DeleteLogsGRPC(ctx context.Context, query *gen.LogDeleteQuery, onProgress func(*gen.LogDeleteProgress)) (*gen.LogDeleteProgress, error) {
+	var (
+		stream gen.Logger_DeleteLogsClient
+		last   *gen.LogDeleteProgress
+	)
+	err := c.downstream.Stream(ctx, func(ctx context.Context) (err error) {
+		if stream, err = c.LoggerClient.DeleteLogs(ctx, query); err != nil {
+			return err
+		}
+		// Лог-сервис отправляет заголовки, как только принял вызов, а первое
+		// сообщение — только после поиска записей
+		_, err = stream.Header()
+		return err
+	}, func() error {
+		for {
+			msg, err := stream.Recv()
+			if err == io.EOF {
+				if !last.GetDone() {
+					return errors.New("DeleteLogs finished before done")
+				}
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			last = msg
+			if onProgress != nil {
+				onProgress(msg)
+			}
+		}
+	})
+	if err != nil {
+		return last, fmt.Errorf("failed to call DeleteLogs: %w", err)
+	}
+	return last, nil
+}
+
+// TailLogsGRPC подписывается на новые записи лог-сервиса и передаёт их в
+// onEvent, пока ctx не отменён или onEvent не вернёт ошибку. Не повторяется:
+// переподключение решает вызывающий, продолжая с последнего полученного id.
+func (c *LogClient) TailLogsGRPC(ctx context.Context, query *gen.LogTailQuery, onEvent func(*gen.LogTailEvent) error) error {
+	var stream gen.Logger_TailLogsClient
+	err := c.downstream.Stream(ctx, func(ctx context.Context) (err error) {
+		if stream, err = c.LoggerClient.TailLogs(ctx, query); err != nil {
+			return err
+		}
+		// Заголовки приходят сразу после подписки, записи — когда появятся
+		_, err = stream.Header()
+		return err
+	}, func() error {
+		for {
+			msg, err := stream.Recv()
+			if err == io.EOF {
+				return nil // Лог-сервис остановился
+			}
+			if err != nil {
+				return err
+			}
+			if err := onEvent(msg); err != nil {
+				return err
+			}
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to call TailLogs: %w", err)
+	}
+	return nil
+}
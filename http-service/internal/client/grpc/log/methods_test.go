@@ -0,0 +1,108 @@
+package log
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	gen "http-service/gen"
+	"http-service/internal/client/grpc/resilience"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// streamServer держит потоки открытыми, пока клиент их не отменит.
+type streamServer struct {
+	gen.UnimplementedLoggerServer
+	logCalls atomic.Int32
+	opened   chan struct{}
+}
+
+func (s *streamServer) HandleIncomingLog(ctx context.Context, entry *gen.LogEntry) (*gen.LogCreationResponse, error) {
+	if s.logCalls.Add(1) == 1 {
+		return nil, status.Error(codes.Unavailable, "starting")
+	}
+	return &gen.LogCreationResponse{Id: &gen.LogID{Id: "1"}}, nil
+}
+
+func (s *streamServer) TailLogs(query *gen.LogTailQuery, stream gen.Logger_TailLogsServer) error {
+	if err := stream.SendHeader(nil); err != nil {
+		return err
+	}
+	close(s.opened)
+	<-stream.Context().Done()
+	return nil
+}
+
+func (s *streamServer) DeleteLogs(query *gen.LogDeleteQuery, stream gen.Logger_DeleteLogsServer) error {
+	if err := stream.SendHeader(nil); err != nil {
+		return err
+	}
+	close(s.opened)
+	<-stream.Context().Done()
+	return nil
+}
+
+func TestStreamsDoNotHoldBreakerProbe(t *testing.T) {
+	tests := []struct {
+		name string
+		open func(ctx context.Context, c *LogClient)
+	}{
+		{name: "tail", open: func(ctx context.Context, c *LogClient) {
+			c.TailLogsGRPC(ctx, &gen.LogTailQuery{}, func(*gen.LogTailEvent) error { return nil })
+		}},
+		{name: "bulk delete", open: func(ctx context.Context, c *LogClient) {
+			c.DeleteLogsGRPC(ctx, &gen.LogDeleteQuery{}, nil)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lis := bufconn.Listen(1024 * 1024)
+			srv := grpc.NewServer()
+			server := &streamServer{opened: make(chan struct{})}
+			gen.RegisterLoggerServer(srv, server)
+			go srv.Serve(lis)
+			defer srv.Stop()
+
+			conn, err := grpc.NewClient("passthrough:///bufnet",
+				grpc.WithTransportCredentials(insecure.NewCredentials()),
+				grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+			)
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+			defer conn.Close()
+			c := &LogClient{
+				LoggerClient: gen.NewLoggerClient(conn),
+				downstream:   resilience.NewDownstream("log-service", conn, resilience.Options{FailureThreshold: 1, OpenTimeout: 10 * time.Millisecond}),
+			}
+
+			// Первый отказ размыкает предохранитель, после OpenTimeout он полуоткрыт
+			if _, err := c.LogDataGRPC(context.Background(), &gen.LogEntry{}); err == nil {
+				t.Fatal("expected the first call to fail")
+			}
+			time.Sleep(20 * time.Millisecond)
+
+			// Пробным вызовом становится поток, который не заканчивается
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go tt.open(ctx, c)
+			<-server.opened
+
+			deadline := time.Now().Add(5 * time.Second)
+			for c.Status().Breaker != resilience.StateClosed {
+				if time.Now().After(deadline) {
+					t.Fatalf("expected the open stream to release the probe, got %+v", c.Status())
+				}
+				time.Sleep(time.Millisecond)
+			}
+			if _, err := c.LogDataGRPC(context.Background(), &gen.LogEntry{}); err != nil {
+				t.Errorf("expected logging to go through while the stream is open, got %v", err)
+			}
+		})
+	}
+}
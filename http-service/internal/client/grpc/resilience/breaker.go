@@ -0,0 +1,115 @@
+package resilience
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type State string
+
+const (
+	StateClosed   State = "closed"
+	StateOpen     State = "open"
+	StateHalfOpen State = "half-open"
+)
+
+// Breaker размыкается после FailureThreshold отказов подряд и не пропускает
+// вызовы OpenTimeout. Затем пропускает один пробный вызов: успех замыкает
+// цепь, отказ снова размыкает.
+type Breaker struct {
+	threshold   int
+	openTimeout time.Duration
+	now         func() time.Time
+
+	mu        sync.Mutex
+	state     State
+	failures  int
+	openedAt  time.Time
+	probing   bool
+	lastError string
+	lastFail  time.Time
+}
+
+func NewBreaker(threshold int, openTimeout time.Duration) *Breaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &Breaker{
+		threshold:   threshold,
+		openTimeout: openTimeout,
+		now:         time.Now,
+		state:       StateClosed,
+	}
+}
+
+// Allow сообщает, можно ли выполнить вызов. После разрешённого вызова
+// нужно вызвать Success, Failure или Release.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			return ErrCircuitOpen
+		}
+		b.state = StateHalfOpen
+		fallthrough
+	case StateHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = StateClosed
+	b.failures = 0
+	b.probing = false
+}
+
+func (b *Breaker) Failure(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.lastError = err.Error()
+	b.lastFail = b.now()
+	b.probing = false
+	if b.state == StateHalfOpen || b.failures >= b.threshold {
+		b.state = StateOpen
+		b.openedAt = b.now()
+	}
+}
+
+// Release завершает вызов, результат которого ничего не говорит о
+// состоянии сервиса (например, клиент отменил запрос).
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+func (b *Breaker) snapshot() (State, int, string, time.Time, time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := b.state
+	if state == StateOpen && b.now().Sub(b.openedAt) >= b.openTimeout {
+		// Следующий вызов будет пробным
+		state = StateHalfOpen
+	}
+	var retryAt time.Time
+	if state == StateOpen {
+		retryAt = b.openedAt.Add(b.openTimeout)
+	}
+	return state, b.failures, b.lastError, b.lastFail, retryAt
+}
@@ -0,0 +1,41 @@
+package resilience
+
+import (
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/credentials/insecure"
+	"http-service/internal/config"
+	"http-service/internal/metrics"
+	"time"
+)
+
+// DialOptions настраивает переподключение: после обрыва соединение
+// восстанавливается с экспоненциальной задержкой до GRPCReconnectMaxDelay.
+// Все вызовы проходят через интерсепторы метрик, а контекст trace уходит
+// в метаданных gRPC (traceparent).
+func DialOptions(cfg *config.Config) []grpc.DialOption {
+	reconnect := backoff.DefaultConfig
+	reconnect.MaxDelay = cfg.GRPCReconnectMaxDelay
+
+	return []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           reconnect,
+			MinConnectTimeout: 5 * time.Second,
+		}),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(metrics.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(metrics.StreamClientInterceptor()),
+	}
+}
+
+func OptionsFromConfig(cfg *config.Config) Options {
+	return Options{
+		MaxAttempts:      cfg.GRPCRetryAttempts,
+		Backoff:          cfg.GRPCRetryBackoff,
+		MaxBackoff:       cfg.GRPCRetryMaxBackoff,
+		FailureThreshold: cfg.GRPCBreakerThreshold,
+		OpenTimeout:      cfg.GRPCBreakerOpenTimeout,
+	}
+}
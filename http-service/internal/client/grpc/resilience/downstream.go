@@ -0,0 +1,174 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+type Options struct {
+	// Попыток для идемпотентных вызовов, включая первую
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+
+	FailureThreshold int
+	OpenTimeout      time.Duration
+}
+
+// Status — текущее состояние зависимости для обработчиков и проверок готовности.
+type Status struct {
+	Name                string     `json:"name"`
+	Available           bool       `json:"available"`
+	Breaker             State      `json:"breaker"`
+	Connectivity        string     `json:"connectivity,omitempty"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastFailure         *time.Time `json:"last_failure,omitempty"`
+	RetryAt             *time.Time `json:"retry_at,omitempty"`
+}
+
+// Downstream оборачивает вызовы одного gRPC-сервиса: предохранитель
+// и повторы с экспоненциальной задержкой для идемпотентных методов.
+// Само соединение grpc.ClientConn устанавливается лениво при первом вызове
+// и переподключается автоматически, поэтому сервисы можно запускать в любом порядке.
+type Downstream struct {
+	name    string
+	conn    *grpc.ClientConn
+	opts    Options
+	breaker *Breaker
+}
+
+func NewDownstream(name string, conn *grpc.ClientConn, opts Options) *Downstream {
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = 1
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = 100 * time.Millisecond
+	}
+	if opts.MaxBackoff < opts.Backoff {
+		opts.MaxBackoff = opts.Backoff
+	}
+	return &Downstream{
+		name:    name,
+		conn:    conn,
+		opts:    opts,
+		breaker: NewBreaker(opts.FailureThreshold, opts.OpenTimeout),
+	}
+}
+
+// Call выполняет fn через предохранитель. Идемпотентные вызовы повторяются,
+// пока сервис недоступен и остаются попытки.
+func (d *Downstream) Call(ctx context.Context, idempotent bool, fn func(ctx context.Context) error) error {
+	attempts := 1
+	if idempotent {
+		attempts = d.opts.MaxAttempts
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			timer := time.NewTimer(d.backoff(attempt - 1))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+		}
+
+		if allowErr := d.breaker.Allow(); allowErr != nil {
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("%s unavailable: %w", d.name, allowErr)
+		}
+
+		err = fn(ctx)
+		switch {
+		case err == nil:
+			d.breaker.Success()
+			return nil
+		case ctx.Err() != nil:
+			// Вызывающий отменил запрос или исчерпал свой дедлайн — о сервисе
+			// это ничего не говорит
+			d.breaker.Release()
+			return err
+		case isDownstreamFailure(err):
+			d.breaker.Failure(err)
+		default:
+			// Сервис ответил ошибкой бизнес-логики — он доступен
+			d.breaker.Success()
+			return err
+		}
+
+		if !retryable(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// Stream выполняет потоковый вызов. Через предохранитель проходит только
+// open: она открывает поток и ждёт заголовков ответа, после чего пробный
+// вызов полуоткрытого предохранителя завершается. Сам поток read читает уже
+// вне предохранителя, поэтому подписка или долгая задача не занимает пробу и
+// не блокирует остальные вызовы сервиса. Не повторяется.
+func (d *Downstream) Stream(ctx context.Context, open func(ctx context.Context) error, read func() error) error {
+	if err := d.Call(ctx, false, open); err != nil {
+		return err
+	}
+	return read()
+}
+
+func (d *Downstream) Status() Status {
+	state, failures, lastErr, lastFail, retryAt := d.breaker.snapshot()
+	s := Status{
+		Name:                d.name,
+		Available:           state != StateOpen,
+		Breaker:             state,
+		ConsecutiveFailures: failures,
+		LastError:           lastErr,
+	}
+	if d.conn != nil {
+		s.Connectivity = d.conn.GetState().String()
+	}
+	if !lastFail.IsZero() {
+		s.LastFailure = &lastFail
+	}
+	if !retryAt.IsZero() {
+		s.RetryAt = &retryAt
+	}
+	return s
+}
+
+func (d *Downstream) backoff(retry int) time.Duration {
+	delay := d.opts.Backoff
+	for i := 1; i < retry && delay < d.opts.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, d.opts.MaxBackoff)
+}
+
+// isDownstreamFailure отделяет недоступность сервиса от ошибок в ответе.
+// Вызывается, только пока контекст вызывающего жив, поэтому DeadlineExceeded
+// здесь — таймаут, выставленный самим сервисом.
+func isDownstreamFailure(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
+
+// retryable — ошибки, после которых запрос можно безопасно повторить.
+func retryable(err error) bool {
+	return status.Code(err) == codes.Unavailable
+}
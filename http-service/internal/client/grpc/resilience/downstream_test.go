@@ -0,0 +1,244 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+var errUnavailable = status.Error(codes.Unavailable, "connection refused")
+
+func newTestDownstream(now *time.Time) *Downstream {
+	d := NewDownstream("test", nil, Options{
+		MaxAttempts:      3,
+		Backoff:          time.Millisecond,
+		MaxBackoff:       2 * time.Millisecond,
+		FailureThreshold: 3,
+		OpenTimeout:      time.Minute,
+	})
+	d.breaker.now = func() time.Time { return *now }
+	return d
+}
+
+func TestDownstreamRetriesIdempotentCalls(t *testing.T) {
+	now := time.Now()
+	d := newTestDownstream(&now)
+
+	calls := 0
+	err := d.Call(context.Background(), true, func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errUnavailable
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("expected success on third attempt, got %v after %d calls", err, calls)
+	}
+	if s := d.Status(); !s.Available || s.Breaker != StateClosed || s.ConsecutiveFailures != 0 {
+		t.Errorf("expected closed breaker after success, got %+v", s)
+	}
+}
+
+func TestDownstreamDoesNotRetryNonIdempotentCalls(t *testing.T) {
+	now := time.Now()
+	d := newTestDownstream(&now)
+
+	calls := 0
+	err := d.Call(context.Background(), false, func(ctx context.Context) error {
+		calls++
+		return errUnavailable
+	})
+	if status.Code(err) != codes.Unavailable || calls != 1 {
+		t.Errorf("expected single failed call, got %v after %d calls", err, calls)
+	}
+}
+
+func TestDownstreamBusinessErrorsDoNotTripBreaker(t *testing.T) {
+	now := time.Now()
+	d := newTestDownstream(&now)
+
+	for i := 0; i < 5; i++ {
+		err := d.Call(context.Background(), true, func(ctx context.Context) error {
+			return status.Error(codes.NotFound, "log not found")
+		})
+		if status.Code(err) != codes.NotFound {
+			t.Fatalf("expected NotFound, got %v", err)
+		}
+	}
+	if s := d.Status(); !s.Available || s.ConsecutiveFailures != 0 {
+		t.Errorf("expected breaker to stay closed, got %+v", s)
+	}
+}
+
+func TestDownstreamCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	d := newTestDownstream(&now)
+	fail := func(ctx context.Context) error { return errUnavailable }
+
+	// Одна идемпотентная попытка с повторами = 3 отказа подряд
+	d.Call(context.Background(), true, fail)
+
+	s := d.Status()
+	if s.Available || s.Breaker != StateOpen || s.ConsecutiveFailures != 3 || s.RetryAt == nil || s.LastError == "" {
+		t.Fatalf("expected open breaker, got %+v", s)
+	}
+
+	called := false
+	err := d.Call(context.Background(), true, func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, ErrCircuitOpen) || called {
+		t.Fatalf("expected call to fail fast, got %v (called=%v)", err, called)
+	}
+
+	// После OpenTimeout пропускается пробный вызов
+	now = now.Add(time.Minute)
+	if s := d.Status(); !s.Available || s.Breaker != StateHalfOpen {
+		t.Fatalf("expected half-open breaker, got %+v", s)
+	}
+	if err := d.Call(context.Background(), false, fail); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected probe to reach downstream, got %v", err)
+	}
+	if s := d.Status(); s.Breaker != StateOpen {
+		t.Fatalf("expected failed probe to reopen breaker, got %+v", s)
+	}
+
+	now = now.Add(time.Minute)
+	if err := d.Call(context.Background(), false, func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatalf("expected successful probe, got %v", err)
+	}
+	if s := d.Status(); s.Breaker != StateClosed || !s.Available {
+		t.Errorf("expected closed breaker after successful probe, got %+v", s)
+	}
+}
+
+func TestBreakerAllowsSingleProbe(t *testing.T) {
+	now := time.Now()
+	b := NewBreaker(1, time.Second)
+	b.now = func() time.Time { return now }
+
+	b.Allow()
+	b.Failure(errUnavailable)
+	now = now.Add(time.Second)
+
+	if err := b.Allow(); err != nil {
+		t.Fatalf("expected probe to be allowed, got %v", err)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected second concurrent probe to be rejected, got %v", err)
+	}
+
+	b.Release()
+	if err := b.Allow(); err != nil {
+		t.Errorf("expected probe after release, got %v", err)
+	}
+}
+
+func TestDownstreamCanceledCallsAreNeutral(t *testing.T) {
+	now := time.Now()
+	d := newTestDownstream(&now)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 5; i++ {
+		d.Call(ctx, false, func(ctx context.Context) error { return status.Error(codes.Canceled, "canceled") })
+	}
+	if s := d.Status(); s.Breaker != StateClosed || s.ConsecutiveFailures != 0 {
+		t.Errorf("expected canceled calls not to count, got %+v", s)
+	}
+}
+
+func TestDownstreamCallerDeadlineIsNeutral(t *testing.T) {
+	now := time.Now()
+	d := newTestDownstream(&now)
+
+	for i := 0; i < 5; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		d.Call(ctx, true, func(ctx context.Context) error {
+			<-ctx.Done()
+			return status.Error(codes.DeadlineExceeded, "context deadline exceeded")
+		})
+		cancel()
+	}
+	if s := d.Status(); s.Breaker != StateClosed || s.ConsecutiveFailures != 0 {
+		t.Errorf("expected the caller's own deadline not to count, got %+v", s)
+	}
+
+	// DeadlineExceeded от сервиса при живом контексте — отказ сервиса
+	d.Call(context.Background(), false, func(ctx context.Context) error {
+		return status.Error(codes.DeadlineExceeded, "upstream timeout")
+	})
+	if s := d.Status(); s.ConsecutiveFailures != 1 {
+		t.Errorf("expected a server-side deadline to count as a failure, got %+v", s)
+	}
+}
+
+func TestDownstreamStreamReleasesProbeOnceOpen(t *testing.T) {
+	now := time.Now()
+	d := newTestDownstream(&now)
+	for i := 0; i < 3; i++ {
+		d.Call(context.Background(), false, func(ctx context.Context) error { return errUnavailable })
+	}
+	now = now.Add(time.Minute)
+
+	// Поток открывается пробным вызовом и читается долго
+	opened, stop := make(chan struct{}), make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- d.Stream(context.Background(), func(ctx context.Context) error { return nil }, func() error {
+			close(opened)
+			<-stop
+			return nil
+		})
+	}()
+	<-opened
+
+	if s := d.Status(); s.Breaker != StateClosed {
+		t.Errorf("expected an established stream to close the breaker, got %+v", s)
+	}
+	if err := d.Call(context.Background(), false, func(ctx context.Context) error { return nil }); err != nil {
+		t.Errorf("expected calls to go through while the stream is read, got %v", err)
+	}
+	close(stop)
+	if err := <-done; err != nil {
+		t.Errorf("unexpected stream error: %v", err)
+	}
+}
+
+func TestDownstreamStreamOpenFailureReopensBreaker(t *testing.T) {
+	now := time.Now()
+	d := newTestDownstream(&now)
+	for i := 0; i < 3; i++ {
+		d.Call(context.Background(), false, func(ctx context.Context) error { return errUnavailable })
+	}
+	now = now.Add(time.Minute)
+
+	read := false
+	err := d.Stream(context.Background(), func(ctx context.Context) error { return errUnavailable }, func() error {
+		read = true
+		return nil
+	})
+	if status.Code(err) != codes.Unavailable || read {
+		t.Errorf("expected a failed open to skip reading, got %v (read %v)", err, read)
+	}
+	if s := d.Status(); s.Breaker != StateOpen {
+		t.Errorf("expected a failed probe stream to reopen the breaker, got %+v", s)
+	}
+
+	// Ошибка чтения уже установленного потока пробу не занимает
+	now = now.Add(time.Minute)
+	err = d.Stream(context.Background(), func(ctx context.Context) error { return nil }, func() error {
+		if err := d.Call(context.Background(), false, func(ctx context.Context) error { return nil }); err != nil {
+			t.Errorf("expected calls to go through while a long job runs, got %v", err)
+		}
+		return errUnavailable
+	})
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("expected the read error to be returned, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package codec
+
+import (
+	"strconv"
+	"strings"
+)
+
+type mediaRange struct {
+	mediaType string
+	q         float64
+}
+
+// Negotiate выбирает из offers тип с наибольшим q-фактором в заголовке Accept.
+// При равных q побеждает тип, стоящий раньше в offers. Пустой Accept
+// означает согласие на любой тип. ok=false, если подходящего типа нет.
+func Negotiate(accept string, offers []string) (mediaType string, ok bool) {
+	if len(offers) == 0 {
+		return "", false
+	}
+	if strings.TrimSpace(accept) == "" {
+		return offers[0], true
+	}
+
+	ranges := parseAccept(accept)
+	bestQ := 0.0
+	for _, offer := range offers {
+		q, specificity := -1.0, -1
+		for _, r := range ranges {
+			if s := matchRange(r.mediaType, offer); s > specificity {
+				q, specificity = r.q, s
+			}
+		}
+		if q > bestQ {
+			mediaType, bestQ = offer, q
+		}
+	}
+
+	return mediaType, mediaType != ""
+}
+
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		r := mediaRange{mediaType: strings.ToLower(strings.TrimSpace(params[0])), q: 1}
+		if r.mediaType == "" {
+			continue
+		}
+		for _, param := range params[1:] {
+			key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || strings.ToLower(strings.TrimSpace(key)) != "q" {
+				continue
+			}
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && q >= 0 && q <= 1 {
+				r.q = q
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// matchRange возвращает специфичность совпадения: 2 — точное, 1 — type/*, 0 — */*, -1 — нет совпадения.
+func matchRange(pattern, mediaType string) int {
+	if pattern == mediaType {
+		return 2
+	}
+	if pattern == "*/*" || pattern == "*" {
+		return 0
+	}
+	if prefix, found := strings.CutSuffix(pattern, "/*"); found && strings.HasPrefix(mediaType, prefix+"/") {
+		return 1
+	}
+	return -1
+}
@@ -0,0 +1,34 @@
+package codec
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	offers := []string{"application/json", "text/csv", "application/x-ndjson", "text/plain"}
+
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+		wantOk bool
+	}{
+		{"empty header defaults to first offer", "", "application/json", true},
+		{"wildcard", "*/*", "application/json", true},
+		{"exact match", "text/csv", "text/csv", true},
+		{"type wildcard keeps server order", "text/*", "text/csv", true},
+		{"q values", "application/json;q=0.5, text/plain", "text/plain", true},
+		{"specific range overrides wildcard", "text/*;q=0.9, text/csv;q=0.1", "text/plain", true},
+		{"q=0 excludes type", "application/json;q=0, */*;q=0.1", "text/csv", true},
+		{"case insensitive", "Application/X-NDJSON", "application/x-ndjson", true},
+		{"nothing acceptable", "image/png", "", false},
+		{"explicitly refused", "text/csv;q=0", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Negotiate(tt.accept, offers)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("Negotiate(%q) = %q, %v; want %q, %v", tt.accept, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
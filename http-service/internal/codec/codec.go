@@ -0,0 +1,80 @@
+package codec
+
+import (
+	"errors"
+	"fmt"
+	gen "http-service/gen"
+	"mime"
+	"sort"
+)
+
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeYAML     = "application/yaml"
+	ContentTypeCSV      = "text/csv"
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeHasher   = "text/x-hasher"
+	ContentTypeNDJSON   = "application/x-ndjson"
+	ContentTypeText     = "text/plain"
+)
+
+var (
+	ErrUnsupportedContentType = errors.New("unsupported Content-Type")
+	ErrEmptyBody              = errors.New("request body is empty")
+)
+
+// Decoder превращает тело запроса в список операций.
+type Decoder func(body []byte) ([]*gen.Operation, error)
+
+var decoders = map[string]Decoder{
+	ContentTypeJSON:        decodeJSON,
+	ContentTypeYAML:        decodeYAML,
+	"application/x-yaml":   decodeYAML,
+	"text/yaml":            decodeYAML,
+	ContentTypeCSV:         decodeCSV,
+	ContentTypeProtobuf:    decodeProtobuf,
+	"application/protobuf": decodeProtobuf,
+	ContentTypeHasher:      decodeHasher,
+}
+
+// Decode выбирает декодер по Content-Type и проверяет полученные операции.
+// Ошибки валидации одинаковы для всех форматов.
+func Decode(contentType string, body []byte) ([]*gen.Operation, error) {
+	mediaType := MediaType(contentType)
+	decode, ok := decoders[mediaType]
+	if !ok {
+		return nil, fmt.Errorf("%w %q, supported: %v", ErrUnsupportedContentType, mediaType, SupportedContentTypes())
+	}
+	if len(body) == 0 {
+		return nil, ErrEmptyBody
+	}
+
+	operations, err := decode(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s body: %w", mediaType, err)
+	}
+
+	if err := Validate(operations); err != nil {
+		return nil, err
+	}
+
+	return operations, nil
+}
+
+func SupportedContentTypes() []string {
+	types := make([]string, 0, len(decoders))
+	for mediaType := range decoders {
+		types = append(types, mediaType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// MediaType возвращает тип из заголовка без параметров (charset и т.п.).
+func MediaType(header string) string {
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return header
+	}
+	return mediaType
+}
@@ -0,0 +1,172 @@
+package codec
+
+import (
+	"errors"
+	"google.golang.org/protobuf/proto"
+	gen "http-service/gen"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var expectedOperations = []*gen.Operation{
+	{Type: "calc", Op: "+", Var: "x", Left: "10", Right: "5"},
+	{Type: "calc", Op: "*", Var: "y", Left: "x", Right: "3"},
+	{Type: "print", Var: "y"},
+}
+
+func TestDecode(t *testing.T) {
+	protoBody, err := proto.Marshal(&gen.OperationRequest{Operations: expectedOperations})
+	if err != nil {
+		t.Fatalf("failed to marshal protobuf: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{
+			name:        "json",
+			contentType: "application/json",
+			body: `{"operations":[
+				{"type":"calc","op":"+","var":"x","left":10,"right":5},
+				{"type":"calc","op":"*","var":"y","left":"x","right":3},
+				{"type":"print","var":"y"}]}`,
+		},
+		{
+			name:        "json with charset",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"operations":[{"type":"calc","op":"+","var":"x","left":"10","right":"5"},{"type":"calc","op":"*","var":"y","left":"x","right":"3"},{"type":"print","var":"y"}]}`,
+		},
+		{
+			name:        "yaml",
+			contentType: "application/yaml",
+			body: `operations:
+  - {type: calc, op: "+", var: x, left: 10, right: 5}
+  - type: calc
+    op: "*"
+    var: y
+    left: x
+    right: 3
+  - {type: print, var: y}
+`,
+		},
+		{
+			name:        "csv without header",
+			contentType: "text/csv",
+			body:        "calc,+,x,10,5\ncalc,*,y,x,3\nprint,,y\n",
+		},
+		{
+			name:        "csv with reordered header",
+			contentType: "text/csv",
+			body:        "var,type,op,left,right\nx,calc,+,10,5\n# comment\ny, calc, *, x, 3\ny,print\n",
+		},
+		{
+			name:        "protobuf",
+			contentType: "application/x-protobuf",
+			body:        string(protoBody),
+		},
+		{
+			name:        "hasher program",
+			contentType: "text/x-hasher",
+			body:        "x = 10 + 5; y = x * 3; print y",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.contentType, []byte(tt.body))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(expectedOperations) {
+				t.Fatalf("expected %d operations, got %d", len(expectedOperations), len(got))
+			}
+			for i := range got {
+				if !proto.Equal(got[i], expectedOperations[i]) {
+					t.Errorf("operation %d: expected %v, got %v", i, expectedOperations[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantErr     error
+		wantMsg     string
+	}{
+		{"unsupported content type", "text/plain", "x", ErrUnsupportedContentType, `unsupported Content-Type "text/plain"`},
+		{"empty body", "application/json", "", ErrEmptyBody, "request body is empty"},
+		{"invalid json", "application/json", `{"operations": [}`, nil, "failed to decode application/json body"},
+		{"invalid yaml", "application/yaml", "operations: [", nil, "failed to decode application/yaml body"},
+		{"unknown yaml field", "application/yaml", "operations:\n  - {type: print, var: x, colour: red}", nil, "failed to decode application/yaml body"},
+		{"unknown json field", "application/json", `{"operations":[{"type":"print","var":"x","colour":"red"}]}`, nil, "failed to decode application/json body"},
+		{"too many csv fields", "text/csv", "calc,+,x,1,2,3", nil, "line 1: expected at most 5 fields"},
+		{"invalid protobuf", "application/x-protobuf", "\xff\xff", nil, "failed to decode application/x-protobuf body"},
+		{"hasher syntax error", "text/x-hasher", "x = ", nil, "line 1, column 5"},
+		{"json without operations", "application/json", `{"operations":[]}`, ErrNoOperations, "no operations in request"},
+		{"csv without operations", "text/csv", "type,op,var,left,right\n", ErrNoOperations, "no operations in request"},
+		{"json missing var", "application/json", `{"operations":[{"type":"print"}]}`, nil, `operation 1: field "var" is required`},
+		{"csv missing var", "text/csv", "print,,", nil, `operation 1: field "var" is required`},
+		{"yaml unknown type", "application/yaml", "operations:\n  - {type: div, var: x}", nil, `operation 1: unknown type "div"`},
+		{"csv calc without operands", "text/csv", "calc,+,x,1", nil, `operation 1: fields "left" and "right" are required for calc`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(tt.contentType, []byte(tt.body))
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("expected error to contain %q, got %q", tt.wantMsg, err.Error())
+			}
+		})
+	}
+}
+
+func TestUnknownFieldsAreRejectedInEveryFormat(t *testing.T) {
+	// Один и тот же документ с опечаткой в имени поля
+	bodies := map[string]string{
+		"application/json": `{"operations":[{"type":"print","var":"x","colour":"red"}]}`,
+		"application/yaml": `{"operations":[{"type":"print","var":"x","colour":"red"}]}`,
+	}
+	for contentType, body := range bodies {
+		if _, err := Decode(contentType, []byte(body)); err == nil || !strings.Contains(err.Error(), "colour") {
+			t.Errorf("%s: expected unknown field to be rejected, got %v", contentType, err)
+		}
+	}
+
+	if _, err := Decode("application/json", []byte(`{"operations":[{"type":"print","var":"x"}]} {}`)); err == nil {
+		t.Error("expected trailing data after the JSON document to be rejected")
+	}
+}
+
+func TestValidationErrorsAreSharedAcrossFormats(t *testing.T) {
+	bodies := map[string]string{
+		"application/json": `{"operations":[{"type":"calc","op":"+","var":"x","left":"1"}]}`,
+		"application/yaml": "operations:\n  - {type: calc, op: '+', var: x, left: 1}",
+		"text/csv":         "calc,+,x,1,",
+	}
+
+	var first error
+	for contentType, body := range bodies {
+		_, err := Decode(contentType, []byte(body))
+		var opErr *OperationError
+		if !errors.As(err, &opErr) {
+			t.Fatalf("%s: expected OperationError, got %v", contentType, err)
+		}
+		if first != nil && !reflect.DeepEqual(err, first) {
+			t.Errorf("%s: expected %v, got %v", contentType, first, err)
+		}
+		first = err
+	}
+}
@@ -0,0 +1,88 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	gen "http-service/gen"
+	"io"
+	"strings"
+)
+
+var csvColumns = []string{"type", "op", "var", "left", "right"}
+
+// decodeCSV читает по одной операции на строку. Первая строка может быть
+// заголовком с именами колонок в произвольном порядке, иначе используется
+// порядок type,op,var,left,right. Пустые хвостовые колонки можно опускать.
+func decodeCSV(body []byte) ([]*gen.Operation, error) {
+	reader := csv.NewReader(bytes.NewReader(body))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	columns := map[string]int{}
+	for i, name := range csvColumns {
+		columns[name] = i
+	}
+
+	var operations []*gen.Operation
+	for row := 0; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if row == 0 && isCSVHeader(record) {
+			columns = make(map[string]int, len(record))
+			for i, name := range record {
+				columns[strings.ToLower(strings.TrimSpace(name))] = i
+			}
+			continue
+		}
+
+		if len(record) > len(csvColumns) {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("line %d: expected at most %d fields, got %d", line, len(csvColumns), len(record))
+		}
+
+		field := func(name string) string {
+			idx, ok := columns[name]
+			if !ok || idx >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[idx])
+		}
+
+		operations = append(operations, &gen.Operation{
+			Type:  field("type"),
+			Op:    field("op"),
+			Var:   field("var"),
+			Left:  field("left"),
+			Right: field("right"),
+		})
+	}
+
+	return operations, nil
+}
+
+// Заголовок — первая строка, в которой все значения являются именами колонок.
+func isCSVHeader(record []string) bool {
+	for _, name := range record {
+		if !isCSVColumn(strings.ToLower(strings.TrimSpace(name))) {
+			return false
+		}
+	}
+	return true
+}
+
+func isCSVColumn(name string) bool {
+	for _, column := range csvColumns {
+		if name == column {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,10 @@
+package codec
+
+import (
+	gen "http-service/gen"
+	"http-service/internal/dsl"
+)
+
+func decodeHasher(body []byte) ([]*gen.Operation, error) {
+	return dsl.Compile(string(body))
+}
@@ -0,0 +1,53 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	gen "http-service/gen"
+	"http-service/internal/utils"
+	"io"
+)
+
+type requestJSON struct {
+	Operations []operationJSON `json:"operations" yaml:"operations"`
+}
+
+type operationJSON struct {
+	Type  string           `json:"type" yaml:"type"`
+	Op    string           `json:"op" yaml:"op"`
+	Var   string           `json:"var" yaml:"var"`
+	Left  utils.FlexString `json:"left" yaml:"left"`
+	Right utils.FlexString `json:"right" yaml:"right"`
+}
+
+func (r requestJSON) toOperations() []*gen.Operation {
+	operations := make([]*gen.Operation, 0, len(r.Operations))
+	for _, op := range r.Operations {
+		operations = append(operations, &gen.Operation{
+			Type:  op.Type,
+			Op:    op.Op,
+			Var:   op.Var,
+			Left:  string(op.Left),
+			Right: string(op.Right),
+		})
+	}
+	return operations
+}
+
+// decodeJSON, как и decodeYAML, отклоняет неизвестные поля, чтобы опечатка
+// в имени поля не превращалась в пустое значение.
+func decodeJSON(body []byte) ([]*gen.Operation, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+
+	var reqParsed requestJSON
+	if err := dec.Decode(&reqParsed); err != nil {
+		return nil, err
+	}
+	// json.Unmarshal не допускал данных после документа, Decoder проверяет это отдельно
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after JSON document")
+	}
+	return reqParsed.toOperations(), nil
+}
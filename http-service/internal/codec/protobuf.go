@@ -0,0 +1,14 @@
+package codec
+
+import (
+	"google.golang.org/protobuf/proto"
+	gen "http-service/gen"
+)
+
+func decodeProtobuf(body []byte) ([]*gen.Operation, error) {
+	var req gen.OperationRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	return req.GetOperations(), nil
+}
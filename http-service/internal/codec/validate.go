@@ -0,0 +1,45 @@
+package codec
+
+import (
+	"errors"
+	"fmt"
+	gen "http-service/gen"
+)
+
+var ErrNoOperations = errors.New("no operations in request")
+
+type OperationError struct {
+	Index int
+	Msg   string
+}
+
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("operation %d: %s", e.Index+1, e.Msg)
+}
+
+func Validate(operations []*gen.Operation) error {
+	if len(operations) == 0 {
+		return ErrNoOperations
+	}
+
+	for i, op := range operations {
+		if op.GetVar() == "" {
+			return &OperationError{Index: i, Msg: `field "var" is required`}
+		}
+
+		switch op.GetType() {
+		case "print":
+		case "calc":
+			if op.GetOp() == "" {
+				return &OperationError{Index: i, Msg: `field "op" is required for calc`}
+			}
+			if op.GetLeft() == "" || op.GetRight() == "" {
+				return &OperationError{Index: i, Msg: `fields "left" and "right" are required for calc`}
+			}
+		default:
+			return &OperationError{Index: i, Msg: fmt.Sprintf("unknown type %q: calc or print expected", op.GetType())}
+		}
+	}
+
+	return nil
+}
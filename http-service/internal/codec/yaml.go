@@ -0,0 +1,19 @@
+package codec
+
+import (
+	"gopkg.in/yaml.v2"
+	gen "http-service/gen"
+)
+
+// Документ имеет ту же структуру, что и JSON:
+//
+//	operations:
+//	  - {type: calc, op: "+", var: x, left: 10, right: 5}
+//	  - {type: print, var: x}
+func decodeYAML(body []byte) ([]*gen.Operation, error) {
+	var reqParsed requestJSON
+	if err := yaml.UnmarshalStrict(body, &reqParsed); err != nil {
+		return nil, err
+	}
+	return reqParsed.toOperations(), nil
+}
@@ -4,12 +4,44 @@ import (
 	"github.com/joho/godotenv"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
 	LoggerAddr   string
 	BusinessAddr string
 	HttpAddr     string
+
+	JobWorkers   int
+	JobQueueSize int
+	JobStore     string
+	JobStoreDir  string
+
+	WebhookSecret      string
+	WebhookMaxAttempts int
+	WebhookBackoff     time.Duration
+	WebhookMaxBackoff  time.Duration
+	WebhookWorkers     int
+	WebhookLogPath     string
+	// Через запятую; пустые списки — любой хост с публичным адресом
+	WebhookAllowedHosts    []string
+	WebhookAllowedNetworks []string
+
+	IdempotencyTTL time.Duration
+
+	AuthKeysFile string
+	AuthDisabled bool
+
+	GRPCRetryAttempts      int
+	GRPCRetryBackoff       time.Duration
+	GRPCRetryMaxBackoff    time.Duration
+	GRPCBreakerThreshold   int
+	GRPCBreakerOpenTimeout time.Duration
+	GRPCReconnectMaxDelay  time.Duration
+
+	TraceExporter string
 }
 
 func Load() *Config {
@@ -22,5 +54,89 @@ func Load() *Config {
 		LoggerAddr:   os.Getenv("LOGGER_ADDR"),
 		BusinessAddr: os.Getenv("BUSINESS_ADR"),
 		HttpAddr:     os.Getenv("HTTP_ADDR"),
+
+		JobWorkers:   getEnvInt("JOB_WORKERS", 4),
+		JobQueueSize: getEnvInt("JOB_QUEUE_SIZE", 100),
+		JobStore:     getEnv("JOB_STORE", "memory"),
+		JobStoreDir:  getEnv("JOB_STORE_DIR", "./jobs"),
+
+		WebhookSecret:          os.Getenv("WEBHOOK_SECRET"),
+		WebhookMaxAttempts:     getEnvInt("WEBHOOK_MAX_ATTEMPTS", 5),
+		WebhookBackoff:         getEnvDuration("WEBHOOK_BACKOFF", time.Second),
+		WebhookMaxBackoff:      getEnvDuration("WEBHOOK_MAX_BACKOFF", 5*time.Minute),
+		WebhookWorkers:         getEnvInt("WEBHOOK_WORKERS", 4),
+		WebhookLogPath:         getEnv("WEBHOOK_LOG_PATH", "./webhooks/deliveries.jsonl"),
+		WebhookAllowedHosts:    getEnvList("WEBHOOK_ALLOWED_HOSTS"),
+		WebhookAllowedNetworks: getEnvList("WEBHOOK_ALLOWED_NETWORKS"),
+
+		IdempotencyTTL: getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+
+		AuthKeysFile: os.Getenv("AUTH_KEYS_FILE"),
+		AuthDisabled: getEnvBool("AUTH_DISABLED", false),
+
+		GRPCRetryAttempts:      getEnvInt("GRPC_RETRY_ATTEMPTS", 3),
+		GRPCRetryBackoff:       getEnvDuration("GRPC_RETRY_BACKOFF", 100*time.Millisecond),
+		GRPCRetryMaxBackoff:    getEnvDuration("GRPC_RETRY_MAX_BACKOFF", 2*time.Second),
+		GRPCBreakerThreshold:   getEnvInt("GRPC_BREAKER_THRESHOLD", 5),
+		GRPCBreakerOpenTimeout: getEnvDuration("GRPC_BREAKER_OPEN_TIMEOUT", 10*time.Second),
+		GRPCReconnectMaxDelay:  getEnvDuration("GRPC_RECONNECT_MAX_DELAY", 10*time.Second),
+
+		TraceExporter: getEnv("TRACE_EXPORTER", "none"),
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getEnvList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func getEnvInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("invalid %s=%q, using default %d", key, value, fallback)
+		return fallback
+	}
+	return n
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("invalid %s=%q, using default %t", key, value, fallback)
+		return fallback
+	}
+	return b
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("invalid %s=%q, using default %s", key, value, fallback)
+		return fallback
 	}
+	return d
 }
@@ -0,0 +1,186 @@
+package dsl
+
+import (
+	"fmt"
+	gen "http-service/gen"
+	"strings"
+)
+
+// Временные переменные начинаются с '$', поэтому не пересекаются с именами из программы.
+const tempPrefix = "$t"
+
+type compiler struct {
+	ops      []*gen.Operation
+	assigned map[string]Pos
+	temps    int
+}
+
+// Compile разбирает программу вида `x = 10 + 5; y = x * 3; print y`
+// и превращает её в последовательность операций calc/print.
+// Вложенные выражения раскладываются во временные переменные.
+func Compile(src string) ([]*gen.Operation, error) {
+	stmts, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(stmts) == 0 {
+		return nil, &SyntaxError{Pos: Pos{Line: 1, Column: 1}, Msg: "program is empty"}
+	}
+
+	c := &compiler{assigned: make(map[string]Pos)}
+
+	// Переменные вычисляются один раз, поэтому повторное присваивание — ошибка.
+	for _, stmt := range stmts {
+		if stmt.print {
+			continue
+		}
+		if prev, ok := c.assigned[stmt.target.name]; ok {
+			return nil, &SyntaxError{
+				Pos: stmt.target.pos,
+				Msg: fmt.Sprintf("variable %s is already assigned at line %d, column %d", stmt.target.name, prev.Line, prev.Column),
+			}
+		}
+		c.assigned[stmt.target.name] = stmt.target.pos
+	}
+
+	for _, stmt := range stmts {
+		if err := c.statement(stmt); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.ops, nil
+}
+
+func (c *compiler) statement(stmt *statement) error {
+	if stmt.print {
+		name, err := c.operand(stmt.value)
+		if err != nil {
+			return err
+		}
+		if isFolded(stmt.value) {
+			// print работает только с переменными, поэтому константу кладём во временную
+			name = c.emitTemp("+", name, "0")
+		}
+		c.ops = append(c.ops, &gen.Operation{Type: "print", Var: name})
+		return nil
+	}
+
+	return c.assign(stmt.target.name, stmt.value)
+}
+
+func (c *compiler) assign(target string, value expr) error {
+	switch e := value.(type) {
+	case *binaryExpr:
+		left, right, err := c.operands(e)
+		if err != nil {
+			return err
+		}
+		c.emit(target, e.op, left, right)
+	case *negExpr:
+		if isFolded(e) {
+			c.emit(target, "+", foldNeg(e), "0")
+			return nil
+		}
+		operand, err := c.operand(e.operand)
+		if err != nil {
+			return err
+		}
+		c.emit(target, "-", "0", operand)
+	default:
+		operand, err := c.operand(e)
+		if err != nil {
+			return err
+		}
+		c.emit(target, "+", operand, "0")
+	}
+	return nil
+}
+
+// operand возвращает литерал или имя переменной, при необходимости
+// вычисляя подвыражение во временную переменную.
+func (c *compiler) operand(value expr) (string, error) {
+	switch e := value.(type) {
+	case *numberExpr:
+		return e.value, nil
+	case *identExpr:
+		if _, ok := c.assigned[e.name]; !ok {
+			return "", &SyntaxError{Pos: e.pos, Msg: fmt.Sprintf("undefined variable %s", e.name)}
+		}
+		return e.name, nil
+	case *negExpr:
+		if isFolded(e) {
+			return foldNeg(e), nil
+		}
+		operand, err := c.operand(e.operand)
+		if err != nil {
+			return "", err
+		}
+		return c.emitTemp("-", "0", operand), nil
+	case *binaryExpr:
+		left, right, err := c.operands(e)
+		if err != nil {
+			return "", err
+		}
+		return c.emitTemp(e.op, left, right), nil
+	}
+	return "", &SyntaxError{Pos: value.position(), Msg: "unsupported expression"}
+}
+
+func (c *compiler) operands(e *binaryExpr) (string, string, error) {
+	left, err := c.operand(e.left)
+	if err != nil {
+		return "", "", err
+	}
+	right, err := c.operand(e.right)
+	if err != nil {
+		return "", "", err
+	}
+	return left, right, nil
+}
+
+func (c *compiler) emit(target, op, left, right string) {
+	c.ops = append(c.ops, &gen.Operation{
+		Type:  "calc",
+		Op:    op,
+		Var:   target,
+		Left:  left,
+		Right: right,
+	})
+}
+
+func (c *compiler) emitTemp(op, left, right string) string {
+	c.temps++
+	name := fmt.Sprintf("%s%d", tempPrefix, c.temps)
+	c.emit(name, op, left, right)
+	return name
+}
+
+// isFolded сообщает, что выражение — число, возможно с унарными минусами.
+func isFolded(value expr) bool {
+	for {
+		e, ok := value.(*negExpr)
+		if !ok {
+			_, isNumber := value.(*numberExpr)
+			return isNumber
+		}
+		value = e.operand
+	}
+}
+
+func foldNeg(value expr) string {
+	negative := false
+	for {
+		switch e := value.(type) {
+		case *negExpr:
+			negative = !negative
+			value = e.operand
+		case *numberExpr:
+			digits := strings.TrimLeft(e.value, "0")
+			if digits == "" || !negative {
+				return e.value
+			}
+			return "-" + e.value
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package dsl
+
+import (
+	"errors"
+	gen "http-service/gen"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func calcOp(v, op, left, right string) *gen.Operation {
+	return &gen.Operation{Type: "calc", Op: op, Var: v, Left: left, Right: right}
+}
+
+func printOp(v string) *gen.Operation {
+	return &gen.Operation{Type: "print", Var: v}
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []*gen.Operation
+	}{
+		{
+			name: "simple program",
+			src:  "x = 10 + 5; y = x * 3; print y",
+			want: []*gen.Operation{
+				calcOp("x", "+", "10", "5"),
+				calcOp("y", "*", "x", "3"),
+				printOp("y"),
+			},
+		},
+		{
+			name: "precedence creates temporaries",
+			src:  "x = 1 + 2 * 3\nprint x",
+			want: []*gen.Operation{
+				calcOp("$t1", "*", "2", "3"),
+				calcOp("x", "+", "1", "$t1"),
+				printOp("x"),
+			},
+		},
+		{
+			name: "parentheses override precedence",
+			src:  "x = (1 + 2) * 3; print x",
+			want: []*gen.Operation{
+				calcOp("$t1", "+", "1", "2"),
+				calcOp("x", "*", "$t1", "3"),
+				printOp("x"),
+			},
+		},
+		{
+			name: "left associativity",
+			src:  "x = 10 - 2 - 3; print x",
+			want: []*gen.Operation{
+				calcOp("$t1", "-", "10", "2"),
+				calcOp("x", "-", "$t1", "3"),
+				printOp("x"),
+			},
+		},
+		{
+			name: "plain assignment and negative literal",
+			src:  "a = -5\nb = a\nprint b",
+			want: []*gen.Operation{
+				calcOp("a", "+", "-5", "0"),
+				calcOp("b", "+", "a", "0"),
+				printOp("b"),
+			},
+		},
+		{
+			name: "negated expression",
+			src:  "a = 2; b = -(a * 4); print b",
+			want: []*gen.Operation{
+				calcOp("a", "+", "2", "0"),
+				calcOp("$t1", "*", "a", "4"),
+				calcOp("b", "-", "0", "$t1"),
+				printOp("b"),
+			},
+		},
+		{
+			name: "print of an expression",
+			src:  "x = 1 + 1 # comment\nprint x * 2",
+			want: []*gen.Operation{
+				calcOp("x", "+", "1", "1"),
+				calcOp("$t1", "*", "x", "2"),
+				printOp("$t1"),
+			},
+		},
+		{
+			name: "newlines inside parentheses",
+			src:  "x = (1 +\n 2)\nprint x",
+			want: []*gen.Operation{
+				calcOp("x", "+", "1", "2"),
+				printOp("x"),
+			},
+		},
+		{
+			name: "use before definition is allowed",
+			src:  "print y; y = x + 1; x = 2",
+			want: []*gen.Operation{
+				printOp("y"),
+				calcOp("y", "+", "x", "1"),
+				calcOp("x", "+", "2", "0"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Compile(tt.src)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		wantPos Pos
+		wantMsg string
+	}{
+		{"empty program", "  \n ; ", Pos{1, 1}, "program is empty"},
+		{"missing operand", "x = 1 +\n", Pos{1, 8}, "expected number, identifier or '(', got newline"},
+		{"unknown character", "x = 1\ny = x / 2", Pos{2, 7}, `unexpected character '/'`},
+		{"unclosed parenthesis", "x = (1 + 2\nprint x", Pos{2, 1}, "expected ')', got 'print'"},
+		{"missing assignment", "x 1", Pos{1, 3}, `expected '=', got number "1"`},
+		{"two statements on a line", "x = 1 y = 2", Pos{1, 7}, `expected ';' or newline, got identifier "y"`},
+		{"undefined variable", "x = 1\nprint z", Pos{2, 7}, "undefined variable z"},
+		{"reassignment", "x = 1\n  x = 2", Pos{2, 3}, "variable x is already assigned at line 1, column 1"},
+		{"number out of range", "x = 99999999999999999999", Pos{1, 5}, "out of range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.src)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("expected SyntaxError, got %v", err)
+			}
+			if syntaxErr.Pos != tt.wantPos {
+				t.Errorf("expected position %+v, got %+v", tt.wantPos, syntaxErr.Pos)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("expected error to contain %q, got %q", tt.wantMsg, err.Error())
+			}
+		})
+	}
+}
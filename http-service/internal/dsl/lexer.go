@@ -0,0 +1,173 @@
+package dsl
+
+import (
+	"fmt"
+	"strconv"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNewline
+	tokSemicolon
+	tokIdent
+	tokNumber
+	tokPrint
+	tokAssign
+	tokPlus
+	tokMinus
+	tokStar
+	tokLParen
+	tokRParen
+)
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokEOF:
+		return "end of input"
+	case tokNewline:
+		return "newline"
+	case tokSemicolon:
+		return "';'"
+	case tokIdent:
+		return "identifier"
+	case tokNumber:
+		return "number"
+	case tokPrint:
+		return "'print'"
+	case tokAssign:
+		return "'='"
+	case tokPlus:
+		return "'+'"
+	case tokMinus:
+		return "'-'"
+	case tokStar:
+		return "'*'"
+	case tokLParen:
+		return "'('"
+	case tokRParen:
+		return "')'"
+	}
+	return "unknown token"
+}
+
+// Pos — позиция в исходном тексте, строки и колонки считаются с 1.
+type Pos struct {
+	Line   int
+	Column int
+}
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  Pos
+}
+
+type lexer struct {
+	src  []rune
+	off  int
+	line int
+	col  int
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: []rune(src), line: 1, col: 1}
+}
+
+func (l *lexer) peekRune() (rune, bool) {
+	if l.off >= len(l.src) {
+		return 0, false
+	}
+	return l.src[l.off], true
+}
+
+func (l *lexer) advance() rune {
+	r := l.src[l.off]
+	l.off++
+	if r == '\n' {
+		l.line++
+		l.col = 1
+	} else {
+		l.col++
+	}
+	return r
+}
+
+func (l *lexer) next() (token, error) {
+	for {
+		r, ok := l.peekRune()
+		if !ok {
+			return token{kind: tokEOF, pos: Pos{l.line, l.col}}, nil
+		}
+		if r == '#' {
+			// Комментарий до конца строки
+			for r, ok = l.peekRune(); ok && r != '\n'; r, ok = l.peekRune() {
+				l.advance()
+			}
+			continue
+		}
+		if r != '\n' && unicode.IsSpace(r) {
+			l.advance()
+			continue
+		}
+		break
+	}
+
+	pos := Pos{l.line, l.col}
+	r := l.advance()
+
+	switch {
+	case r == '\n':
+		return token{kind: tokNewline, text: "\n", pos: pos}, nil
+	case r == ';':
+		return token{kind: tokSemicolon, text: ";", pos: pos}, nil
+	case r == '=':
+		return token{kind: tokAssign, text: "=", pos: pos}, nil
+	case r == '+':
+		return token{kind: tokPlus, text: "+", pos: pos}, nil
+	case r == '-':
+		return token{kind: tokMinus, text: "-", pos: pos}, nil
+	case r == '*':
+		return token{kind: tokStar, text: "*", pos: pos}, nil
+	case r == '(':
+		return token{kind: tokLParen, text: "(", pos: pos}, nil
+	case r == ')':
+		return token{kind: tokRParen, text: ")", pos: pos}, nil
+	case isDigit(r):
+		text := l.readWhile(r, isDigit)
+		if _, err := strconv.Atoi(text); err != nil {
+			return token{}, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("number %s is out of range", text)}
+		}
+		return token{kind: tokNumber, text: text, pos: pos}, nil
+	case isIdentStart(r):
+		text := l.readWhile(r, isIdentPart)
+		if text == "print" {
+			return token{kind: tokPrint, text: text, pos: pos}, nil
+		}
+		return token{kind: tokIdent, text: text, pos: pos}, nil
+	}
+
+	return token{}, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("unexpected character %q", r)}
+}
+
+func (l *lexer) readWhile(first rune, accept func(rune) bool) string {
+	text := []rune{first}
+	for r, ok := l.peekRune(); ok && accept(r); r, ok = l.peekRune() {
+		text = append(text, l.advance())
+	}
+	return string(text)
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,240 @@
+package dsl
+
+import "fmt"
+
+// SyntaxError — ошибка разбора или компиляции программы с указанием позиции.
+type SyntaxError struct {
+	Pos Pos
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Pos.Line, e.Pos.Column, e.Msg)
+}
+
+type expr interface {
+	position() Pos
+}
+
+type numberExpr struct {
+	pos   Pos
+	value string
+}
+
+type identExpr struct {
+	pos  Pos
+	name string
+}
+
+type binaryExpr struct {
+	pos         Pos
+	op          string
+	left, right expr
+}
+
+type negExpr struct {
+	pos     Pos
+	operand expr
+}
+
+func (e *numberExpr) position() Pos { return e.pos }
+func (e *identExpr) position() Pos  { return e.pos }
+func (e *binaryExpr) position() Pos { return e.pos }
+func (e *negExpr) position() Pos    { return e.pos }
+
+type statement struct {
+	pos    Pos
+	print  bool
+	target *identExpr
+	value  expr
+}
+
+type parser struct {
+	lex *lexer
+	tok token
+	// Внутри скобок перевод строки не завершает инструкцию
+	depth int
+}
+
+func parse(src string) ([]*statement, error) {
+	p := &parser{lex: newLexer(src)}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	var stmts []*statement
+	for p.tok.kind != tokEOF {
+		if p.tok.kind == tokNewline || p.tok.kind == tokSemicolon {
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		stmt, err := p.parseStatement()
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, stmt)
+
+		switch p.tok.kind {
+		case tokNewline, tokSemicolon, tokEOF:
+		default:
+			return nil, p.unexpected("';' or newline")
+		}
+	}
+
+	return stmts, nil
+}
+
+func (p *parser) next() error {
+	for {
+		tok, err := p.lex.next()
+		if err != nil {
+			return err
+		}
+		if tok.kind == tokNewline && p.depth > 0 {
+			continue
+		}
+		p.tok = tok
+		return nil
+	}
+}
+
+func (p *parser) expect(kind tokenKind) (token, error) {
+	tok := p.tok
+	if tok.kind != kind {
+		return token{}, p.unexpected(kind.String())
+	}
+	return tok, p.next()
+}
+
+func (p *parser) unexpected(want string) error {
+	got := p.tok.kind.String()
+	if p.tok.kind == tokIdent || p.tok.kind == tokNumber {
+		got = fmt.Sprintf("%s %q", got, p.tok.text)
+	}
+	return &SyntaxError{Pos: p.tok.pos, Msg: fmt.Sprintf("expected %s, got %s", want, got)}
+}
+
+func (p *parser) parseStatement() (*statement, error) {
+	pos := p.tok.pos
+
+	if p.tok.kind == tokPrint {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		value, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		return &statement{pos: pos, print: true, value: value}, nil
+	}
+
+	name, err := p.expect(tokIdent)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(tokAssign); err != nil {
+		return nil, err
+	}
+	value, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	return &statement{
+		pos:    pos,
+		target: &identExpr{pos: name.pos, name: name.text},
+		value:  value,
+	}, nil
+}
+
+// expr := term { ('+' | '-') term }
+func (p *parser) parseExpr() (expr, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.tok.kind == tokPlus || p.tok.kind == tokMinus {
+		op := p.tok
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{pos: op.pos, op: op.text, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+// term := unary { '*' unary }
+func (p *parser) parseTerm() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.tok.kind == tokStar {
+		op := p.tok
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{pos: op.pos, op: op.text, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+// unary := '-' unary | primary
+func (p *parser) parseUnary() (expr, error) {
+	if p.tok.kind != tokMinus {
+		return p.parsePrimary()
+	}
+
+	pos := p.tok.pos
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return &negExpr{pos: pos, operand: operand}, nil
+}
+
+// primary := NUMBER | IDENT | '(' expr ')'
+func (p *parser) parsePrimary() (expr, error) {
+	tok := p.tok
+
+	switch tok.kind {
+	case tokNumber:
+		return &numberExpr{pos: tok.pos, value: tok.text}, p.next()
+	case tokIdent:
+		return &identExpr{pos: tok.pos, name: tok.text}, p.next()
+	case tokLParen:
+		p.depth++
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		inner, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokRParen {
+			return nil, p.unexpected("')'")
+		}
+		p.depth--
+		return inner, p.next()
+	}
+
+	return nil, p.unexpected("number, identifier or '('")
+}
@@ -0,0 +1,139 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var ErrKeyReused = errors.New("idempotency key was already used with a different request body, Accept or callback_url")
+
+// Response — сохранённый ответ, который отдаётся повторным запросам с тем же ключом.
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+type entry struct {
+	hash [sha256.Size]byte
+	// done закрывается, когда первый запрос завершился
+	done      chan struct{}
+	response  *Response
+	expiresAt time.Time
+}
+
+// Store хранит ответы по ключу Idempotency-Key в памяти в течение ttl.
+type Store struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+func NewStore(ttl time.Duration) *Store {
+	return &Store{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]*entry),
+	}
+}
+
+// Request собирает всё, от чего зависит ответ: тело, Accept и callback_url.
+// Каждая часть предваряется длиной, чтобы границу между ними нельзя было сдвинуть.
+func Request(body []byte, accept, callbackURL string) []byte {
+	var request []byte
+	for _, part := range [][]byte{body, []byte(accept), []byte(callbackURL)} {
+		request = binary.BigEndian.AppendUint64(request, uint64(len(part)))
+		request = append(request, part...)
+	}
+	return request
+}
+
+// Do выполняет fn один раз для ключа key. Повторный запрос с тем же request
+// (см. Request) получает сохранённый ответ (replayed = true), а если первый
+// ещё выполняется — ждёт его. Ответы 5xx не сохраняются, чтобы клиент мог
+// повторить запрос.
+func (s *Store) Do(ctx context.Context, key string, request []byte, fn func() *Response) (resp *Response, replayed bool, err error) {
+	hash := sha256.Sum256(request)
+
+	for {
+		s.mu.Lock()
+		e, ok := s.entries[key]
+		if ok && e.response != nil && !s.now().Before(e.expiresAt) {
+			delete(s.entries, key)
+			ok = false
+		}
+		if !ok {
+			e = &entry{hash: hash, done: make(chan struct{})}
+			s.entries[key] = e
+			s.mu.Unlock()
+			return s.run(key, e, fn), false, nil
+		}
+		s.mu.Unlock()
+
+		if e.hash != hash {
+			return nil, false, ErrKeyReused
+		}
+
+		select {
+		case <-e.done:
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+		if e.response != nil {
+			return e.response, true, nil
+		}
+		// Первый запрос ничего не сохранил — выполняем сами
+	}
+}
+
+func (s *Store) run(key string, e *entry, fn func() *Response) (resp *Response) {
+	defer func() {
+		s.mu.Lock()
+		if resp != nil && resp.Status < http.StatusInternalServerError {
+			e.response = resp
+			e.expiresAt = s.now().Add(s.ttl)
+		} else {
+			// В том числе при панике в fn
+			delete(s.entries, key)
+		}
+		s.mu.Unlock()
+		close(e.done)
+	}()
+
+	return fn()
+}
+
+// Start периодически удаляет просроченные ответы, пока не завершится ctx.
+func (s *Store) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.sweep()
+			}
+		}
+	}()
+}
+
+func (s *Store) sweep() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for key, e := range s.entries {
+		if e.response != nil && !now.Before(e.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
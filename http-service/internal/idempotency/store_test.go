@@ -0,0 +1,148 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func okResponse(body string) func() *Response {
+	return func() *Response {
+		return &Response{Status: http.StatusOK, Body: []byte(body)}
+	}
+}
+
+func TestStoreReplaysResponse(t *testing.T) {
+	s := NewStore(time.Hour)
+	ctx := context.Background()
+
+	first, replayed, err := s.Do(ctx, "k1", []byte("body"), okResponse("first"))
+	if err != nil || replayed || string(first.Body) != "first" {
+		t.Fatalf("unexpected first call: %v, %v, %v", first, replayed, err)
+	}
+
+	second, replayed, err := s.Do(ctx, "k1", []byte("body"), okResponse("second"))
+	if err != nil || !replayed || string(second.Body) != "first" {
+		t.Errorf("expected replay of first response, got %v, %v, %v", second, replayed, err)
+	}
+
+	if _, _, err := s.Do(ctx, "k1", []byte("other body"), okResponse("third")); !errors.Is(err, ErrKeyReused) {
+		t.Errorf("expected ErrKeyReused, got %v", err)
+	}
+}
+
+func TestStoreExpiresResponses(t *testing.T) {
+	now := time.Now()
+	s := NewStore(time.Minute)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	s.Do(ctx, "k1", []byte("body"), okResponse("first"))
+	now = now.Add(time.Minute)
+
+	resp, replayed, _ := s.Do(ctx, "k1", []byte("other body"), okResponse("second"))
+	if replayed || string(resp.Body) != "second" {
+		t.Errorf("expected expired key to be reusable, got %s", resp.Body)
+	}
+
+	now = now.Add(time.Minute)
+	s.sweep()
+	if len(s.entries) != 0 {
+		t.Errorf("expected sweep to remove expired entries, %d left", len(s.entries))
+	}
+}
+
+func TestStoreDoesNotKeepServerErrors(t *testing.T) {
+	s := NewStore(time.Hour)
+	ctx := context.Background()
+
+	s.Do(ctx, "k1", []byte("body"), func() *Response { return &Response{Status: http.StatusServiceUnavailable} })
+	resp, replayed, _ := s.Do(ctx, "k1", []byte("body"), okResponse("retried"))
+	if replayed || string(resp.Body) != "retried" {
+		t.Errorf("expected 5xx response to be retried, got %s", resp.Body)
+	}
+}
+
+func TestStoreConcurrentDuplicateWaits(t *testing.T) {
+	s := NewStore(time.Hour)
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	started := make(chan struct{})
+	fn := func() *Response {
+		calls.Add(1)
+		close(started)
+		<-release
+		return &Response{Status: http.StatusOK, Body: []byte("once")}
+	}
+
+	var wg sync.WaitGroup
+	results := make([]*Response, 3)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], _, _ = s.Do(ctx, "k1", []byte("body"), fn)
+	}()
+	<-started
+
+	for i := 1; i < len(results); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _, _ = s.Do(ctx, "k1", []byte("body"), fn)
+		}(i)
+	}
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("expected fn to run once, ran %d times", calls.Load())
+	}
+	for i, resp := range results {
+		if resp == nil || string(resp.Body) != "once" {
+			t.Errorf("result %d: unexpected response %v", i, resp)
+		}
+	}
+}
+
+func TestStoreWaiterHonoursContext(t *testing.T) {
+	s := NewStore(time.Hour)
+	release := make(chan struct{})
+	defer close(release)
+
+	started := make(chan struct{})
+	go s.Do(context.Background(), "k1", []byte("body"), func() *Response {
+		close(started)
+		<-release
+		return &Response{Status: http.StatusOK}
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := s.Do(ctx, "k1", []byte("body"), okResponse("x")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestRequest(t *testing.T) {
+	base := Request([]byte("body"), "application/json", "")
+	for name, other := range map[string][]byte{
+		"accept":         Request([]byte("body"), "application/x-protobuf", ""),
+		"callback_url":   Request([]byte("body"), "application/json", "https://example.com/hook"),
+		"shifted border": Request([]byte("bodyapplication/json"), "", ""),
+	} {
+		if bytes.Equal(base, other) {
+			t.Errorf("%s: expected a different request", name)
+		}
+	}
+	if !bytes.Equal(base, Request([]byte("body"), "application/json", "")) {
+		t.Error("expected the same request to match")
+	}
+}
@@ -0,0 +1,97 @@
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FileStore хранит каждую задачу в отдельном JSON-файле <id>.json.
+// Запись идёт через временный файл и rename, поэтому файл задачи не бывает
+// записан наполовину.
+type FileStore struct {
+	dir string
+	mu  sync.RWMutex
+}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create job store directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+func (s *FileStore) Save(job *Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp, err := os.CreateTemp(s.dir, job.ID+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write job: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write job: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), s.path(job.ID))
+}
+
+func (s *FileStore) Get(id string) (*Job, error) {
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return nil, ErrNotFound
+	}
+
+	s.mu.RLock()
+	data, err := os.ReadFile(s.path(id))
+	s.mu.RUnlock()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read job: %w", err)
+	}
+
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("failed to parse job %s: %w", id, err)
+	}
+	return &job, nil
+}
+
+func (s *FileStore) List() ([]*Job, error) {
+	s.mu.RLock()
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	s.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*Job, 0, len(paths))
+	for _, path := range paths {
+		job, err := s.Get(strings.TrimSuffix(filepath.Base(path), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
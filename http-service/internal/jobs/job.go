@@ -0,0 +1,58 @@
+package jobs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	gen "http-service/gen"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCanceled  Status = "canceled"
+)
+
+func (s Status) Finished() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCanceled
+}
+
+type Progress struct {
+	Completed int32 `json:"completed"`
+	Total     int32 `json:"total"`
+}
+
+type Result struct {
+	LogID              string               `json:"log_id,omitempty"`
+	ResultID           string               `json:"result_id,omitempty"`
+	LogError           string               `json:"log_error,omitempty"`
+	Items              []*gen.VariableValue `json:"items,omitempty"`
+	Warning            string               `json:"warning,omitempty"`
+	ProcessingDuration string               `json:"processing_duration,omitempty"`
+}
+
+type Job struct {
+	ID         string           `json:"id"`
+	Status     Status           `json:"status"`
+	Progress   Progress         `json:"progress"`
+	Error      string           `json:"error,omitempty"`
+	Result     *Result          `json:"result,omitempty"`
+	Operations []*gen.Operation `json:"operations,omitempty"`
+	// Адрес, на который уйдёт результат после завершения задачи
+	CallbackURL string     `json:"callback_url,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	// Имя API-ключа, создавшего задачу; задачи других ключей ему не видны
+	Owner string `json:"owner,omitempty"`
+}
+
+func newJobID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
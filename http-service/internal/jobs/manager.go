@@ -0,0 +1,243 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	gen "http-service/gen"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrQueueFull = errors.New("job queue is full")
+	ErrFinished  = errors.New("job is already finished")
+)
+
+type ProgressFunc func(completed, total int32)
+
+// Runner выполняет задачу. Реализация должна прекращать работу при отмене ctx.
+type Runner func(ctx context.Context, job *Job, progress ProgressFunc) (*Result, error)
+
+// Manager держит ограниченную очередь задач и пул воркеров, которые её разбирают.
+type Manager struct {
+	store   Store
+	runner  Runner
+	workers int
+	queue   chan string
+
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+
+	onFinish func(job *Job)
+}
+
+func NewManager(store Store, runner Runner, workers, queueSize int) *Manager {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 1 {
+		queueSize = 1
+	}
+	return &Manager{
+		store:   store,
+		runner:  runner,
+		workers: workers,
+		queue:   make(chan string, queueSize),
+		cancels: make(map[string]context.CancelFunc),
+	}
+}
+
+// OnFinish задаёт функцию, которую воркер вызывает после успешного или
+// неудачного завершения задачи. Отменённые клиентом задачи её не вызывают.
+// Должна быть задана до Start.
+func (m *Manager) OnFinish(fn func(job *Job)) {
+	m.onFinish = fn
+}
+
+// Start восстанавливает задачи, оставшиеся после перезапуска, и запускает воркеры.
+// Воркеры останавливаются вместе с ctx.
+func (m *Manager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	jobs, err := m.store.List()
+	if err != nil {
+		return fmt.Errorf("failed to load jobs: %w", err)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.Before(jobs[j].CreatedAt) })
+
+	for _, job := range jobs {
+		switch job.Status {
+		case StatusRunning:
+			// Частичный результат не сохраняется, поэтому задача запускается заново.
+			job.Status = StatusQueued
+			job.Progress = Progress{}
+			job.StartedAt = nil
+			fallthrough
+		case StatusQueued:
+			select {
+			case m.queue <- job.ID:
+			default:
+				m.finish(job, StatusFailed, "job queue overflow on restart")
+			}
+			if err := m.store.Save(job); err != nil {
+				return fmt.Errorf("failed to restore job %s: %w", job.ID, err)
+			}
+		}
+	}
+
+	for i := 0; i < m.workers; i++ {
+		go m.worker(ctx)
+	}
+	return nil
+}
+
+// Submit ставит задачу владельца owner в очередь.
+func (m *Manager) Submit(owner string, operations []*gen.Operation, callbackURL string) (*Job, error) {
+	job := &Job{
+		ID:          newJobID(),
+		Owner:       owner,
+		Status:      StatusQueued,
+		Operations:  operations,
+		CallbackURL: callbackURL,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Кладут в очередь только Submit и Start под m.mu, поэтому после проверки место не исчезнет.
+	if len(m.queue) == cap(m.queue) {
+		return nil, ErrQueueFull
+	}
+	if err := m.store.Save(job); err != nil {
+		return nil, fmt.Errorf("failed to save job: %w", err)
+	}
+	m.queue <- job.ID
+
+	return job, nil
+}
+
+// Get возвращает задачу владельца owner. Задача другого владельца не
+// отличается от несуществующей: ErrNotFound.
+func (m *Manager) Get(id, owner string) (*Job, error) {
+	job, err := m.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Owner != owner {
+		return nil, ErrNotFound
+	}
+	return job, nil
+}
+
+// Cancel снимает задачу владельца owner из очереди или прерывает выполняющуюся.
+func (m *Manager) Cancel(id, owner string) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.Get(id, owner)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status.Finished() {
+		return job, ErrFinished
+	}
+
+	if cancel, ok := m.cancels[id]; ok {
+		cancel()
+	}
+	m.finish(job, StatusCanceled, "")
+	if err := m.store.Save(job); err != nil {
+		return nil, fmt.Errorf("failed to save job: %w", err)
+	}
+	return job, nil
+}
+
+func (m *Manager) worker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-m.queue:
+			m.run(ctx, id)
+		}
+	}
+}
+
+func (m *Manager) run(ctx context.Context, id string) {
+	m.mu.Lock()
+	job, err := m.store.Get(id)
+	if err != nil || job.Status != StatusQueued {
+		// Задачу отменили, пока она ждала в очереди
+		m.mu.Unlock()
+		return
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	m.cancels[id] = cancel
+
+	now := time.Now().UTC()
+	job.Status = StatusRunning
+	job.StartedAt = &now
+	if err := m.store.Save(job); err != nil {
+		log.Printf("failed to save job %s: %v", id, err)
+	}
+	m.mu.Unlock()
+
+	result, runErr := m.runner(jobCtx, job, func(completed, total int32) {
+		m.update(id, func(job *Job) {
+			job.Progress = Progress{Completed: completed, Total: total}
+		})
+	})
+
+	m.mu.Lock()
+	delete(m.cancels, id)
+	m.mu.Unlock()
+
+	finished := m.update(id, func(job *Job) {
+		switch {
+		case ctx.Err() != nil:
+			// Сервис останавливается — задача будет выполнена после перезапуска
+			job.Status = StatusQueued
+			job.Progress = Progress{}
+			job.StartedAt = nil
+		case runErr != nil:
+			m.finish(job, StatusFailed, runErr.Error())
+		default:
+			job.Result = result
+			m.finish(job, StatusSucceeded, "")
+		}
+	})
+	if finished != nil && finished.Status.Finished() && m.onFinish != nil {
+		m.onFinish(finished)
+	}
+}
+
+// update применяет fn к выполняющейся задаче, сохраняет и возвращает её.
+// Завершённые (например, отменённые) задачи не изменяются, для них возвращается nil.
+func (m *Manager) update(id string, fn func(job *Job)) *Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.store.Get(id)
+	if err != nil || job.Status != StatusRunning {
+		return nil
+	}
+	fn(job)
+	if err := m.store.Save(job); err != nil {
+		log.Printf("failed to save job %s: %v", id, err)
+	}
+	return job
+}
+
+func (m *Manager) finish(job *Job, status Status, errMsg string) {
+	now := time.Now().UTC()
+	job.Status = status
+	job.Error = errMsg
+	job.FinishedAt = &now
+}
@@ -0,0 +1,199 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	gen "http-service/gen"
+	"testing"
+	"time"
+)
+
+var testOperations = []*gen.Operation{
+	{Type: "calc", Op: "+", Var: "x", Left: "1", Right: "2"},
+	{Type: "print", Var: "x"},
+}
+
+func waitForStatus(t *testing.T, m *Manager, id string, want Status) *Job {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := m.Get(id, "")
+		if err != nil {
+			t.Fatalf("failed to get job: %v", err)
+		}
+		if job.Status == want {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	job, _ := m.Get(id, "")
+	t.Fatalf("job %s: expected status %s, got %s", id, want, job.Status)
+	return nil
+}
+
+func TestManagerRunsJob(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := NewManager(NewMemoryStore(), func(ctx context.Context, job *Job, progress ProgressFunc) (*Result, error) {
+		progress(0, 1)
+		progress(1, 1)
+		return &Result{ResultID: "res1", Items: []*gen.VariableValue{{Var: "x", Value: 3}}}, nil
+	}, 2, 10)
+	if err := m.Start(ctx); err != nil {
+		t.Fatalf("failed to start manager: %v", err)
+	}
+
+	job, err := m.Submit("", testOperations, "")
+	if err != nil {
+		t.Fatalf("failed to submit job: %v", err)
+	}
+
+	done := waitForStatus(t, m, job.ID, StatusSucceeded)
+	if done.Result == nil || done.Result.ResultID != "res1" || done.Result.Items[0].GetValue() != 3 {
+		t.Errorf("unexpected result: %+v", done.Result)
+	}
+	if done.Progress != (Progress{Completed: 1, Total: 1}) {
+		t.Errorf("unexpected progress: %+v", done.Progress)
+	}
+	if done.StartedAt == nil || done.FinishedAt == nil {
+		t.Error("expected start and finish times to be set")
+	}
+}
+
+func TestManagerFailedJob(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := NewManager(NewMemoryStore(), func(ctx context.Context, job *Job, progress ProgressFunc) (*Result, error) {
+		return nil, errors.New("business service unavailable")
+	}, 1, 1)
+	if err := m.Start(ctx); err != nil {
+		t.Fatalf("failed to start manager: %v", err)
+	}
+
+	job, _ := m.Submit("", testOperations, "")
+	failed := waitForStatus(t, m, job.ID, StatusFailed)
+	if failed.Error != "business service unavailable" {
+		t.Errorf("unexpected error: %q", failed.Error)
+	}
+}
+
+func TestManagerOnFinish(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := NewManager(NewMemoryStore(), func(ctx context.Context, job *Job, progress ProgressFunc) (*Result, error) {
+		return &Result{ResultID: "res1"}, nil
+	}, 1, 1)
+	finished := make(chan *Job, 1)
+	m.OnFinish(func(job *Job) { finished <- job })
+	if err := m.Start(ctx); err != nil {
+		t.Fatalf("failed to start manager: %v", err)
+	}
+
+	job, _ := m.Submit("", testOperations, "https://example.com/hook")
+	select {
+	case got := <-finished:
+		if got.ID != job.ID || got.Status != StatusSucceeded || got.CallbackURL != "https://example.com/hook" {
+			t.Errorf("unexpected finished job: %+v", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("OnFinish was not called")
+	}
+}
+
+func TestManagerCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := make(chan struct{})
+	stopped := make(chan error, 1)
+	m := NewManager(NewMemoryStore(), func(ctx context.Context, job *Job, progress ProgressFunc) (*Result, error) {
+		close(started)
+		<-ctx.Done()
+		stopped <- ctx.Err()
+		return nil, ctx.Err()
+	}, 1, 2)
+	if err := m.Start(ctx); err != nil {
+		t.Fatalf("failed to start manager: %v", err)
+	}
+
+	running, _ := m.Submit("", testOperations, "")
+	<-started
+	queued, _ := m.Submit("", testOperations, "")
+
+	if _, err := m.Cancel(queued.ID, ""); err != nil {
+		t.Fatalf("failed to cancel queued job: %v", err)
+	}
+	if _, err := m.Cancel(running.ID, ""); err != nil {
+		t.Fatalf("failed to cancel running job: %v", err)
+	}
+
+	if err := <-stopped; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected runner context to be canceled, got %v", err)
+	}
+	waitForStatus(t, m, running.ID, StatusCanceled)
+	waitForStatus(t, m, queued.ID, StatusCanceled)
+
+	if _, err := m.Cancel(running.ID, ""); !errors.Is(err, ErrFinished) {
+		t.Errorf("expected ErrFinished, got %v", err)
+	}
+	if _, err := m.Cancel("unknown", ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestManagerQueueFull(t *testing.T) {
+	// Воркеры не запущены, поэтому очередь не разбирается
+	m := NewManager(NewMemoryStore(), nil, 1, 1)
+
+	if _, err := m.Submit("", testOperations, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := m.Submit("", testOperations, ""); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("expected ErrQueueFull, got %v", err)
+	}
+}
+
+func TestManagerRestoresJobsFromStore(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	now := time.Now().UTC()
+	interrupted := &Job{ID: "interrupted", Status: StatusRunning, StartedAt: &now, Progress: Progress{Completed: 2, Total: 5}, Operations: testOperations, CreatedAt: now}
+	waiting := &Job{ID: "waiting", Status: StatusQueued, Operations: testOperations, CreatedAt: now.Add(time.Millisecond)}
+	finished := &Job{ID: "finished", Status: StatusSucceeded, Result: &Result{ResultID: "old"}, CreatedAt: now}
+	for _, job := range []*Job{interrupted, waiting, finished} {
+		if err := store.Save(job); err != nil {
+			t.Fatalf("failed to save job: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := NewManager(store, func(ctx context.Context, job *Job, progress ProgressFunc) (*Result, error) {
+		return &Result{ResultID: "new-" + job.ID}, nil
+	}, 1, 10)
+	if err := m.Start(ctx); err != nil {
+		t.Fatalf("failed to start manager: %v", err)
+	}
+
+	for _, id := range []string{"interrupted", "waiting"} {
+		job := waitForStatus(t, m, id, StatusSucceeded)
+		if job.Result.ResultID != "new-"+id {
+			t.Errorf("job %s: unexpected result %+v", id, job.Result)
+		}
+		if len(job.Operations) != len(testOperations) || job.Operations[0].GetVar() != "x" {
+			t.Errorf("job %s: operations were not restored: %v", id, job.Operations)
+		}
+	}
+
+	job, _ := m.Get("finished", "")
+	if job.Result.ResultID != "old" {
+		t.Errorf("finished job should not be rerun, got %+v", job.Result)
+	}
+}
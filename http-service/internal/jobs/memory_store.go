@@ -0,0 +1,47 @@
+package jobs
+
+import "sync"
+
+type MemoryStore struct {
+	mu   sync.RWMutex
+	jobs map[string]*Job
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{jobs: make(map[string]*Job)}
+}
+
+func (s *MemoryStore) Save(job *Job) error {
+	clone, err := cloneJob(job)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = clone
+	return nil
+}
+
+func (s *MemoryStore) Get(id string) (*Job, error) {
+	s.mu.RLock()
+	job, ok := s.jobs[id]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return cloneJob(job)
+}
+
+func (s *MemoryStore) List() ([]*Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	jobs := make([]*Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		clone, err := cloneJob(job)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, clone)
+	}
+	return jobs, nil
+}
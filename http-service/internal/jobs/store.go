@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+var ErrNotFound = errors.New("job not found")
+
+// Store хранит состояние задач. Реализации должны быть потокобезопасны
+// и возвращать копии, чтобы вызывающий код не менял сохранённые данные.
+type Store interface {
+	Save(job *Job) error
+	Get(id string) (*Job, error)
+	List() ([]*Job, error)
+}
+
+func cloneJob(job *Job) (*Job, error) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return nil, err
+	}
+	var clone Job
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return nil, err
+	}
+	return &clone, nil
+}
@@ -0,0 +1,51 @@
+package jobs
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStores(t *testing.T) {
+	fileStore, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create file store: %v", err)
+	}
+
+	stores := map[string]Store{
+		"memory": NewMemoryStore(),
+		"file":   fileStore,
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			job := &Job{ID: "job1", Status: StatusQueued, Operations: testOperations, CreatedAt: time.Now().UTC()}
+			if err := store.Save(job); err != nil {
+				t.Fatalf("failed to save job: %v", err)
+			}
+
+			// Изменение исходного объекта не должно влиять на сохранённую копию
+			job.Status = StatusFailed
+
+			got, err := store.Get("job1")
+			if err != nil {
+				t.Fatalf("failed to get job: %v", err)
+			}
+			if got.Status != StatusQueued || got.Operations[0].GetLeft() != "1" {
+				t.Errorf("unexpected job: %+v", got)
+			}
+
+			if _, err := store.Get("missing"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("expected ErrNotFound, got %v", err)
+			}
+			if _, err := store.Get("../job1"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("expected ErrNotFound for path traversal, got %v", err)
+			}
+
+			list, err := store.List()
+			if err != nil || len(list) != 1 {
+				t.Errorf("expected one job, got %d (%v)", len(list), err)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UnaryClientInterceptor учитывает каждую попытку вызова отдельно, поэтому
+// повторы из resilience видны как несколько вызовов.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		observeClient(method, start, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor фиксирует поток, когда RecvMsg возвращает io.EOF или ошибку.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			observeClient(method, start, err)
+			return nil, err
+		}
+		return &monitoredClientStream{ClientStream: stream, method: method, start: start}, nil
+	}
+}
+
+type monitoredClientStream struct {
+	grpc.ClientStream
+	method string
+	start  time.Time
+	once   sync.Once
+}
+
+func (s *monitoredClientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.once.Do(func() {
+			if errors.Is(err, io.EOF) {
+				observeClient(s.method, s.start, nil)
+				return
+			}
+			observeClient(s.method, s.start, err)
+		})
+	}
+	return err
+}
+
+func observeClient(fullMethod string, start time.Time, err error) {
+	service, method := splitMethod(fullMethod)
+	GRPCClientDuration.WithLabelValues(service, method).Observe(time.Since(start).Seconds())
+	GRPCClientHandled.WithLabelValues(service, method, status.Code(err).String()).Inc()
+}
+
+// splitMethod разбирает "/gen.Logger/HandleIncomingLog" на сервис и метод.
+func splitMethod(fullMethod string) (string, string) {
+	name := strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "unknown", name
+}
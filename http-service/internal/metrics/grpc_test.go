@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"testing"
+)
+
+func TestSplitMethod(t *testing.T) {
+	tests := []struct {
+		fullMethod string
+		service    string
+		method     string
+	}{
+		{"/gen.Logger/HandleIncomingLog", "gen.Logger", "HandleIncomingLog"},
+		{"/grpc.health.v1.Health/Check", "grpc.health.v1.Health", "Check"},
+		{"Broken", "unknown", "Broken"},
+	}
+
+	for _, tt := range tests {
+		service, method := splitMethod(tt.fullMethod)
+		if service != tt.service || method != tt.method {
+			t.Errorf("%s: expected %s %s, got %s %s", tt.fullMethod, tt.service, tt.method, service, method)
+		}
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	interceptor := UnaryClientInterceptor()
+	ok := GRPCClientHandled.WithLabelValues("test.Unary", "Call", "OK")
+	unavailable := GRPCClientHandled.WithLabelValues("test.Unary", "Call", "Unavailable")
+	okBefore, unavailableBefore := testutil.ToFloat64(ok), testutil.ToFloat64(unavailable)
+
+	invoke := func(err error) grpc.UnaryInvoker {
+		return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return err
+		}
+	}
+
+	_ = interceptor(context.Background(), "/test.Unary/Call", nil, nil, nil, invoke(nil))
+	err := interceptor(context.Background(), "/test.Unary/Call", nil, nil, nil, invoke(status.Error(codes.Unavailable, "down")))
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("expected error to be passed through, got %v", err)
+	}
+
+	if got := testutil.ToFloat64(ok) - okBefore; got != 1 {
+		t.Errorf("expected 1 OK call, got %v", got)
+	}
+	if got := testutil.ToFloat64(unavailable) - unavailableBefore; got != 1 {
+		t.Errorf("expected 1 Unavailable call, got %v", got)
+	}
+}
+
+type fakeClientStream struct {
+	grpc.ClientStream
+	errs []error
+}
+
+func (s *fakeClientStream) RecvMsg(m any) error {
+	err := s.errs[0]
+	s.errs = s.errs[1:]
+	return err
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		errs         []error
+		expectedCode string
+	}{
+		{"finished with EOF", "/test.Stream/Eof", []error{nil, nil, io.EOF}, "OK"},
+		{"failed mid-stream", "/test.Stream/Fail", []error{nil, status.Error(codes.Canceled, "canceled")}, "Canceled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, method := splitMethod(tt.method)
+			counter := GRPCClientHandled.WithLabelValues(service, method, tt.expectedCode)
+			before := testutil.ToFloat64(counter)
+
+			streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				return &fakeClientStream{errs: tt.errs}, nil
+			}
+			stream, err := StreamClientInterceptor()(context.Background(), &grpc.StreamDesc{ServerStreams: true}, nil, tt.method, streamer)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for stream.RecvMsg(nil) == nil {
+			}
+			if got := testutil.ToFloat64(counter) - before; got != 1 {
+				t.Errorf("expected stream to be counted once with %s, got %v", tt.expectedCode, got)
+			}
+		})
+	}
+}
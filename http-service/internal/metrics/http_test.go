@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestInstrumentRoute(t *testing.T) {
+	tests := []struct {
+		name         string
+		handler      httprouter.Handle
+		expectedCode string
+	}{
+		{
+			name: "implicit 200",
+			handler: func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+				w.Write([]byte("ok"))
+			},
+			expectedCode: "200",
+		},
+		{
+			name: "explicit status",
+			handler: func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+				w.WriteHeader(http.StatusNotFound)
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			expectedCode: "404",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := "/test/" + strings.ReplaceAll(tt.name, " ", "-") + "/:id"
+			counter := HTTPRequests.WithLabelValues(route, http.MethodGet, tt.expectedCode)
+			before := testutil.ToFloat64(counter)
+
+			router := httprouter.New()
+			router.GET(route, InstrumentRoute(route, tt.handler))
+			for _, id := range []string{"1", "2"} {
+				router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, strings.Replace(route, ":id", id, 1), nil))
+			}
+
+			if got := testutil.ToFloat64(counter) - before; got != 2 {
+				t.Errorf("expected 2 requests under route pattern %s, got %v", route, got)
+			}
+			if n := testutil.CollectAndCount(HTTPDuration, "http_request_duration_seconds"); n == 0 {
+				t.Error("expected latency histogram to be populated")
+			}
+		})
+	}
+}
+
+func TestStatusRecorderFlush(t *testing.T) {
+	w := httptest.NewRecorder()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+	var _ http.Flusher = rec
+	rec.Flush()
+
+	if !w.Flushed {
+		t.Error("expected Flush to reach the underlying writer")
+	}
+}
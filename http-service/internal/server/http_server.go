@@ -5,6 +5,7 @@ import (
 	"http-service/internal/config"
 
 	"http-service/internal/app"
+	"http-service/internal/tracing"
 	"http-service/internal/transport/http"
 	stdHttp "net/http"
 	"time"
@@ -15,7 +16,7 @@ func RunHttpServer(app *app.Clients, cfg *config.Config) {
 	router := http.NewRouter(app)
 	server := &stdHttp.Server{
 		Addr:           cfg.HttpAddr,
-		Handler:        router,
+		Handler:        tracing.Handler(router),
 		ReadTimeout:    10 * time.Second,
 		WriteTimeout:   10 * time.Second,
 		IdleTimeout:    120 * time.Second,
@@ -0,0 +1,34 @@
+package tracing
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+// TraceIDHeader возвращает клиенту trace_id запроса, чтобы по нему найти логи.
+const TraceIDHeader = "X-Trace-Id"
+
+// Handler создаёт серверный span на каждый запрос. Входящий traceparent
+// продолжается, иначе trace начинается на границе HTTP.
+func Handler(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, ServiceName)
+}
+
+// Route называет span по шаблону маршрута: httprouter не сообщает его
+// обёртке сервера, поэтому имя задаётся уже после выбора маршрута.
+func Route(route string, next httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		span := trace.SpanFromContext(r.Context())
+		span.SetName(r.Method + " " + route)
+		span.SetAttributes(attribute.String("http.route", route))
+
+		if id := TraceID(r.Context()); id != "" {
+			w.Header().Set(TraceIDHeader, id)
+		}
+
+		next(w, r, ps)
+	}
+}
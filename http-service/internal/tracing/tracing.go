@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName попадает в ресурс span'ов и в имя tracer'а.
+const ServiceName = "http-service"
+
+var ErrUnknownExporter = errors.New("unknown trace exporter")
+
+// Setup устанавливает глобальный TracerProvider и W3C-пропагатор (traceparent, baggage).
+// Span'ы создаются и без экспортера: trace_id нужен в логах и в заголовке X-Trace-Id.
+// Для otlp адрес коллектора берётся из стандартных OTEL_EXPORTER_OTLP_* переменных.
+func Setup(ctx context.Context, exporter string) (shutdown func(context.Context) error, err error) {
+	var opts []sdktrace.TracerProviderOption
+	switch exporter {
+	case "", "none":
+	case "otlp":
+		exp, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exp))
+	default:
+		return nil, fmt.Errorf("%w %q: none or otlp expected", ErrUnknownExporter, exporter)
+	}
+
+	return Install(opts...).Shutdown, nil
+}
+
+// Install ставит глобальный TracerProvider с opts и W3C-пропагатор. Setup
+// выбирает opts по экспортеру, тесты ставят провайдер через tracingtest.
+func Install(opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	opts = append(opts, sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", ServiceName))))
+	tp := sdktrace.NewTracerProvider(opts...)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return tp
+}
+
+func Tracer() trace.Tracer {
+	return otel.Tracer(ServiceName)
+}
+
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End завершает span и помечает его ошибкой, если err не nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TraceID возвращает trace_id текущего span'а или пустую строку.
+func TraceID(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
@@ -0,0 +1,116 @@
+package tracing
+
+import (
+	"context"
+	"github.com/julienschmidt/httprouter"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+	"http-service/gen"
+	"http-service/internal/client/grpc/resilience"
+	"http-service/internal/config"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandlerStartsAndContinuesTraces(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	Install(sdktrace.WithSyncer(exporter))
+
+	router := httprouter.New()
+	router.GET("/jobs/:id", Route("/jobs/:id", func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	handler := Handler(router)
+
+	tests := []struct {
+		name        string
+		traceparent string
+		wantTraceID string
+	}{
+		{name: "new trace at the edge"},
+		{
+			name:        "incoming traceparent is continued",
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter.Reset()
+
+			req := httptest.NewRequest(http.MethodGet, "/jobs/42", nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 span, got %d", len(spans))
+			}
+			span := spans[0]
+			if span.Name != "GET /jobs/:id" {
+				t.Errorf("expected span named by route pattern, got %q", span.Name)
+			}
+
+			traceID := span.SpanContext.TraceID().String()
+			if tt.wantTraceID != "" && traceID != tt.wantTraceID {
+				t.Errorf("expected trace %s to be continued, got %s", tt.wantTraceID, traceID)
+			}
+			if got := w.Header().Get(TraceIDHeader); got != traceID {
+				t.Errorf("expected %s header %s, got %q", TraceIDHeader, traceID, got)
+			}
+		})
+	}
+}
+
+type metadataLogger struct {
+	gen.UnimplementedLoggerServer
+	md metadata.MD
+}
+
+func (s *metadataLogger) HandleIncomingLog(ctx context.Context, entry *gen.LogEntry) (*gen.LogCreationResponse, error) {
+	s.md, _ = metadata.FromIncomingContext(ctx)
+	return &gen.LogCreationResponse{Id: &gen.LogID{Id: "1"}}, nil
+}
+
+func TestTraceContextPropagatesOverGRPC(t *testing.T) {
+	Install()
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	logger := &metadataLogger{}
+	gen.RegisterLoggerServer(srv, logger)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	opts := append(resilience.DialOptions(&config.Config{GRPCReconnectMaxDelay: time.Second}),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+	)
+	conn, err := grpc.NewClient("passthrough:///bufnet", opts...)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, span := Start(context.Background(), "test")
+	defer span.End()
+
+	if _, err := gen.NewLoggerClient(conn).HandleIncomingLog(ctx, &gen.LogEntry{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	traceparent := logger.md.Get("traceparent")
+	if len(traceparent) != 1 || !strings.Contains(traceparent[0], TraceID(ctx)) {
+		t.Errorf("expected traceparent with trace %s in metadata, got %v", TraceID(ctx), traceparent)
+	}
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"github.com/julienschmidt/httprouter"
+	"http-service/gen"
+	"http-service/internal/app"
+	"http-service/internal/auth"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+const auditTimeout = 2 * time.Second
+
+type AuthErrorResponse struct {
+	Success bool   `json:"success"`
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	Error   string `json:"error"`
+}
+
+// RequireScope пропускает запрос к next, только если API-ключ из X-API-Key
+// (или Authorization: Bearer) имеет scope и не исчерпал лимиты.
+// Каждое решение отправляется в лог-сервис. Без настроенных ключей запрос
+// отклоняется, если проверка не отключена явно через AuthDisabled.
+func RequireScope(clients *app.Clients, scope auth.Scope, next httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if isNil(clients.Auth) {
+			if clients.AuthDisabled {
+				next(w, r, ps)
+				return
+			}
+			writeAuthError(w, auth.Decision{Reason: auth.ReasonNotConfigured})
+			return
+		}
+
+		decision := clients.Auth.Authorize(apiKeyFromRequest(r), scope)
+		auditAuthDecision(clients, r, scope, decision)
+
+		if !decision.Allowed {
+			writeAuthError(w, decision)
+			return
+		}
+		next(w, r.WithContext(auth.WithKeyName(r.Context(), decision.KeyName)), ps)
+	}
+}
+
+func apiKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return key
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+func writeAuthError(w http.ResponseWriter, decision auth.Decision) {
+	status := http.StatusUnauthorized
+	message := "API key required"
+	switch decision.Reason {
+	case auth.ReasonUnknownKey:
+		message = "Invalid API key"
+	case auth.ReasonScopeDenied:
+		status = http.StatusForbidden
+		message = "API key is not allowed to access this route"
+	case auth.ReasonRateLimited:
+		status = http.StatusTooManyRequests
+		message = "Rate limit exceeded"
+	case auth.ReasonQuotaExceeded:
+		status = http.StatusTooManyRequests
+		message = "Daily quota exceeded"
+	case auth.ReasonNotConfigured:
+		status = http.StatusServiceUnavailable
+		message = "API key authentication is not configured"
+	}
+
+	if status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="hasher"`)
+	}
+	if decision.RetryAfter > 0 {
+		// Retry-After задаётся в целых секундах, округляем вверх
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(decision.RetryAfter.Seconds()))))
+	}
+
+	writeJSON(w, status, AuthErrorResponse{
+		Success: false,
+		Status:  status,
+		Message: message,
+		Error:   string(decision.Reason),
+	})
+}
+
+// auditAuthDecision пишет решение в лог-сервис в фоне, чтобы не задерживать запрос.
+func auditAuthDecision(clients *app.Clients, r *http.Request, scope auth.Scope, decision auth.Decision) {
+	if !serviceAvailable(clients.LogClient) {
+		return
+	}
+
+	level, result := "INFO", "allow"
+	if !decision.Allowed {
+		level, result = "WARN", "deny"
+	}
+
+	entry := &gen.LogEntry{
+		ServiceName: "HTTP-server",
+		Level:       level,
+		Message: &gen.StructuredMessage{
+			Method: r.Method,
+			Path:   r.URL.Path,
+		},
+		Metadata: map[string]string{
+			"event":       "auth",
+			"decision":    result,
+			"reason":      string(decision.Reason),
+			"api_key":     decision.KeyName,
+			"scope":       string(scope),
+			"remote_addr": r.RemoteAddr,
+		},
+		TimestampSend: time.Now().UnixMilli(),
+	}
+
+	// Запись переживает запрос, но остаётся в его trace
+	parent := context.WithoutCancel(r.Context())
+	go func() {
+		ctx, cancel := context.WithTimeout(parent, auditTimeout)
+		defer cancel()
+		if _, err := clients.LogClient.LogDataGRPC(ctx, entry); err != nil {
+			log.Printf("failed to write auth audit log: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"github.com/julienschmidt/httprouter"
+	"http-service/gen"
+	"http-service/internal/app"
+	"http-service/internal/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequireScope(t *testing.T) {
+	audit := make(chan *gen.LogEntry, 10)
+	clients := &app.Clients{
+		LogClient: &mockLogClient{
+			LogDataGRPCFunc: func(ctx context.Context, entry *gen.LogEntry) (*gen.LogID, error) {
+				audit <- entry
+				return &gen.LogID{Id: "audit"}, nil
+			},
+		},
+		Auth: auth.NewAuthenticator([]auth.Key{
+			{Name: "reader", Key: "reader-key", Scopes: []auth.Scope{auth.ScopeLogsRead}, RatePerSecond: 0.001, Burst: 1},
+			{Name: "quota", Key: "quota-key", Scopes: []auth.Scope{auth.ScopeLogsRead}, RatePerSecond: 100, DailyQuota: 1},
+		}),
+	}
+
+	var gotKeyName string
+	handler := RequireScope(clients, auth.ScopeLogsRead, func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		gotKeyName = auth.KeyName(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})
+	deleteHandler := RequireScope(clients, auth.ScopeLogsDelete, func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		t.Error("handler must not be called without scope")
+	})
+
+	tests := []struct {
+		name           string
+		handler        httprouter.Handle
+		header         string
+		value          string
+		expectedStatus int
+		retryAfter     bool
+		decision       string
+		reason         string
+	}{
+		{"missing key", handler, "", "", http.StatusUnauthorized, false, "deny", "missing_key"},
+		{"unknown key", handler, APIKeyHeader, "wrong", http.StatusUnauthorized, false, "deny", "unknown_key"},
+		{"scope denied", deleteHandler, APIKeyHeader, "reader-key", http.StatusForbidden, false, "deny", "scope_denied"},
+		{"allowed via bearer", handler, "Authorization", "Bearer reader-key", http.StatusNoContent, false, "allow", ""},
+		{"rate limited", handler, APIKeyHeader, "reader-key", http.StatusTooManyRequests, true, "deny", "rate_limited"},
+		{"first request within quota", handler, APIKeyHeader, "quota-key", http.StatusNoContent, false, "allow", ""},
+		{"quota exceeded", handler, APIKeyHeader, "quota-key", http.StatusTooManyRequests, true, "deny", "quota_exceeded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/getLog", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			w := httptest.NewRecorder()
+			tt.handler(w, req, httprouter.Params{})
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if got := w.Header().Get("Retry-After"); (got != "") != tt.retryAfter {
+				t.Errorf("unexpected Retry-After %q", got)
+			}
+
+			select {
+			case entry := <-audit:
+				md := entry.GetMetadata()
+				if md["event"] != "auth" || md["decision"] != tt.decision || md["reason"] != tt.reason || md["scope"] == "" {
+					t.Errorf("unexpected audit metadata: %v", md)
+				}
+			case <-time.After(time.Second):
+				t.Error("auth decision was not audited")
+			}
+		})
+	}
+
+	if gotKeyName != "quota" {
+		t.Errorf("expected key name in context, got %q", gotKeyName)
+	}
+}
+
+func TestRequireScopeNotConfigured(t *testing.T) {
+	called := false
+	rec := httptest.NewRecorder()
+	RequireScope(&app.Clients{}, auth.ScopeLogsDelete, func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		called = true
+	})(rec, httptest.NewRequest(http.MethodDelete, "/deleteLog", nil), httprouter.Params{})
+
+	if called || rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected request to be rejected with 503 when authentication is not configured, got %d", rec.Code)
+	}
+}
+
+func TestRequireScopeDisabled(t *testing.T) {
+	called := false
+	RequireScope(&app.Clients{AuthDisabled: true}, auth.ScopeLogsDelete, func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		called = true
+	})(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/deleteLog", nil), httprouter.Params{})
+
+	if !called {
+		t.Error("expected request to pass when authentication is explicitly disabled")
+	}
+}
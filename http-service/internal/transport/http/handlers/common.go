@@ -3,8 +3,13 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	gen "http-service/gen"
+	"http-service/internal/client/grpc/resilience"
 	"net/http"
+	"time"
 )
 
 type LogEntry struct {
@@ -16,6 +21,8 @@ type LogEntry struct {
 	TimestampSend   int64           `json:"timestamp_send"`
 	TimestampRecv   int64           `json:"timestamp_received"`
 	DeliveryDelayMs string          `json:"deliveryDelayMs"`
+	RequestID       string          `json:"request_id,omitempty"`
+	ParentID        string          `json:"parent_id,omitempty"`
 }
 
 func writeJSON(w http.ResponseWriter, status int, data any) {
@@ -26,6 +33,12 @@ func writeJSON(w http.ResponseWriter, status int, data any) {
 	}
 }
 
+// disableWriteDeadline снимает WriteTimeout сервера с потокового ответа,
+// который пишется дольше обычного запроса.
+func disableWriteDeadline(w http.ResponseWriter) {
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+}
+
 type ErrorResponse struct {
 	Success bool   `json:"success"`
 	Status  int    `json:"status"`
@@ -33,28 +46,124 @@ type ErrorResponse struct {
 	Reason  string `json:"reason"`
 }
 
+type statusReporter interface {
+	Status() resilience.Status
+}
+
+// serviceAvailable сообщает, стоит ли обращаться к зависимости:
+// клиент создан и его предохранитель не разомкнут.
+func serviceAvailable(client statusReporter) bool {
+	return !isNil(client) && client.Status().Available
+}
+
+// callUnavailable сообщает, что вызов не дошёл до сервиса: предохранитель
+// разомкнулся, соединения нет или сервис не ответил вовремя.
+func callUnavailable(err error) bool {
+	if errors.Is(err, resilience.ErrCircuitOpen) {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+func unavailableReason(client statusReporter) string {
+	if isNil(client) {
+		return "client is not configured"
+	}
+	status := client.Status()
+	if status.LastError == "" {
+		return "circuit breaker is " + string(status.Breaker)
+	}
+	return "circuit breaker is " + string(status.Breaker) + ": " + status.LastError
+}
+
 type mockLogClient struct {
 	ReadLogFunc     func(id, filename string) (*gen.LogReadingResponse, error)
-	DeleteLogFunc   func(id, filename string) (*gen.LogDeletionResponse, error)
+	DeleteLogFunc   func(id, filename, actor, reason string) (*gen.LogDeletionResponse, error)
+	RestoreLogFunc  func(id, filename, actor, reason string) (*gen.LogRestorationResponse, error)
 	LogDataGRPCFunc func(ctx context.Context, entry *gen.LogEntry) (*gen.LogID, error)
+	TimelineFunc    func(ctx context.Context, requestID string) (*gen.RequestTimeline, error)
+	SearchFunc      func(ctx context.Context, query *gen.LogSearchQuery) (*gen.LogSearchResult, error)
+	DeleteLogsFunc  func(ctx context.Context, query *gen.LogDeleteQuery, onProgress func(*gen.LogDeleteProgress)) (*gen.LogDeleteProgress, error)
+	TailFunc        func(ctx context.Context, query *gen.LogTailQuery, onEvent func(*gen.LogTailEvent) error) error
+	StatusFunc      func() resilience.Status
+	HealthFunc      func(ctx context.Context) error
 }
 
 func (m *mockLogClient) ReadLogGRPC(id, filename string) (*gen.LogReadingResponse, error) {
 	return m.ReadLogFunc(id, filename)
 }
 
-func (m *mockLogClient) DeleteLogGRPC(id, filename string) (*gen.LogDeletionResponse, error) {
-	return m.DeleteLogFunc(id, filename)
+func (m *mockLogClient) DeleteLogGRPC(id, filename, actor, reason string) (*gen.LogDeletionResponse, error) {
+	return m.DeleteLogFunc(id, filename, actor, reason)
+}
+
+func (m *mockLogClient) RestoreLogGRPC(id, filename, actor, reason string) (*gen.LogRestorationResponse, error) {
+	return m.RestoreLogFunc(id, filename, actor, reason)
 }
 
 func (m *mockLogClient) LogDataGRPC(ctx context.Context, entry *gen.LogEntry) (*gen.LogID, error) {
 	return m.LogDataGRPCFunc(ctx, entry)
 }
 
+func (m *mockLogClient) GetRequestTimelineGRPC(ctx context.Context, requestID string) (*gen.RequestTimeline, error) {
+	return m.TimelineFunc(ctx, requestID)
+}
+
+func (m *mockLogClient) SearchLogsGRPC(ctx context.Context, query *gen.LogSearchQuery) (*gen.LogSearchResult, error) {
+	return m.SearchFunc(ctx, query)
+}
+
+func (m *mockLogClient) DeleteLogsGRPC(ctx context.Context, query *gen.LogDeleteQuery, onProgress func(*gen.LogDeleteProgress)) (*gen.LogDeleteProgress, error) {
+	return m.DeleteLogsFunc(ctx, query, onProgress)
+}
+
+func (m *mockLogClient) TailLogsGRPC(ctx context.Context, query *gen.LogTailQuery, onEvent func(*gen.LogTailEvent) error) error {
+	return m.TailFunc(ctx, query, onEvent)
+}
+
+func (m *mockLogClient) Status() resilience.Status {
+	if m.StatusFunc == nil {
+		return resilience.Status{Name: "log-service", Available: true, Breaker: resilience.StateClosed}
+	}
+	return m.StatusFunc()
+}
+
+func (m *mockLogClient) CheckHealth(ctx context.Context) error {
+	if m.HealthFunc == nil {
+		return nil
+	}
+	return m.HealthFunc(ctx)
+}
+
 type mockBizClient struct {
-	ProcessFunc func(ctx context.Context, req *gen.OperationRequest) (*gen.OperationResponse, error)
+	ProcessFunc       func(ctx context.Context, req *gen.OperationRequest) (*gen.OperationResponse, error)
+	ProcessStreamFunc func(ctx context.Context, req *gen.OperationRequest, onProgress func(completed, total int32)) (*gen.OperationResponse, error)
+	StatusFunc        func() resilience.Status
+	HealthFunc        func(ctx context.Context) error
 }
 
 func (m *mockBizClient) Process(ctx context.Context, req *gen.OperationRequest) (*gen.OperationResponse, error) {
 	return m.ProcessFunc(ctx, req)
 }
+
+func (m *mockBizClient) ProcessStream(ctx context.Context, req *gen.OperationRequest, onProgress func(completed, total int32)) (*gen.OperationResponse, error) {
+	return m.ProcessStreamFunc(ctx, req, onProgress)
+}
+
+func (m *mockBizClient) CheckHealth(ctx context.Context) error {
+	if m.HealthFunc == nil {
+		return nil
+	}
+	return m.HealthFunc(ctx)
+}
+
+func (m *mockBizClient) Status() resilience.Status {
+	if m.StatusFunc == nil {
+		return resilience.Status{Name: "business-service", Available: true, Breaker: resilience.StateClosed}
+	}
+	return m.StatusFunc()
+}
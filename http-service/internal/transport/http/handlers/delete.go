@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"github.com/julienschmidt/httprouter"
 	"http-service/internal/app"
+	"http-service/internal/auth"
 	"net/http"
 )
 
@@ -17,7 +18,12 @@ func DeleteLogHandler(clients *app.Clients) httprouter.Handle {
 			return
 		}
 
-		deleteResponse, err := clients.LogClient.DeleteLogGRPC(id, filename)
+		if !serviceAvailable(clients.LogClient) {
+			writeJSON(w, http.StatusServiceUnavailable, "Log service unavailable: "+unavailableReason(clients.LogClient))
+			return
+		}
+
+		deleteResponse, err := clients.LogClient.DeleteLogGRPC(id, filename, auth.KeyName(r.Context()), r.URL.Query().Get("reason"))
 
 		if err != nil {
 			writeJSON(w, http.StatusInternalServerError, "Failed to delete log due to server mailfunction: "+err.Error())
@@ -44,4 +50,5 @@ func DeleteLogHandler(clients *app.Clients) httprouter.Handle {
 type DeleteResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message,omitempty"`
+	PurgeAt int64  `json:"purge_at,omitempty"`
 }
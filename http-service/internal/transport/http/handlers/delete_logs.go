@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/julienschmidt/httprouter"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	gen "http-service/gen"
+	"http-service/internal/app"
+	"http-service/internal/auth"
+	"http-service/internal/codec"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// LogDeleteProgress — строка NDJSON-ответа DELETE /logs или, при dry_run,
+// весь ответ.
+type LogDeleteProgress struct {
+	Matched   int64    `json:"matched"`
+	Deleted   int64    `json:"deleted"`
+	Missing   int64    `json:"missing"`
+	SampleIDs []string `json:"sample_ids,omitempty"`
+	Done      bool     `json:"done"`
+	PurgeAt   string   `json:"purge_at,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+func newLogDeleteProgress(p *gen.LogDeleteProgress) LogDeleteProgress {
+	progress := LogDeleteProgress{
+		Matched:   p.GetMatched(),
+		Deleted:   p.GetDeleted(),
+		Missing:   p.GetMissing(),
+		SampleIDs: p.GetSampleIds(),
+		Done:      p.GetDone(),
+	}
+	if p.GetPurgeAt() != 0 {
+		progress.PurgeAt = time.UnixMilli(p.GetPurgeAt()).UTC().Format(time.RFC3339)
+	}
+	return progress
+}
+
+// DeleteLogsHandler удаляет все логи, подходящие под фильтр. С dry_run
+// возвращает только число найденных записей и образец их id, иначе
+// передаёт прогресс удаления строками NDJSON по мере обработки пачек.
+func DeleteLogsHandler(clients *app.Clients) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		query, err := parseLogDeleteQuery(r.URL.Query())
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, "Invalid delete query: "+err.Error())
+			return
+		}
+		query.Actor = auth.KeyName(r.Context())
+
+		if !serviceAvailable(clients.LogClient) {
+			writeJSON(w, http.StatusServiceUnavailable, "Log service unavailable: "+unavailableReason(clients.LogClient))
+			return
+		}
+
+		if query.DryRun {
+			result, err := clients.LogClient.DeleteLogsGRPC(r.Context(), query, nil)
+			if status.Code(err) == codes.InvalidArgument {
+				writeJSON(w, http.StatusBadRequest, "Invalid delete query: "+status.Convert(err).Message())
+				return
+			}
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, "Failed to find logs: "+err.Error())
+				return
+			}
+			writeJSON(w, http.StatusOK, newLogDeleteProgress(result))
+			return
+		}
+
+		// Заголовки отправляются с первым сообщением: до него ошибку ещё можно
+		// вернуть обычным статусом
+		started := false
+		disableWriteDeadline(w)
+		encoder := json.NewEncoder(w)
+		flusher, _ := w.(http.Flusher)
+		_, err = clients.LogClient.DeleteLogsGRPC(r.Context(), query, func(p *gen.LogDeleteProgress) {
+			if !started {
+				w.Header().Set("Content-Type", codec.ContentTypeNDJSON)
+				w.WriteHeader(http.StatusOK)
+				started = true
+			}
+			encoder.Encode(newLogDeleteProgress(p))
+			if flusher != nil {
+				flusher.Flush()
+			}
+		})
+		if err == nil {
+			return
+		}
+		if !started {
+			if status.Code(err) == codes.InvalidArgument {
+				writeJSON(w, http.StatusBadRequest, "Invalid delete query: "+status.Convert(err).Message())
+				return
+			}
+			writeJSON(w, http.StatusInternalServerError, "Failed to delete logs: "+err.Error())
+			return
+		}
+		encoder.Encode(LogDeleteProgress{Error: "Failed to delete logs: " + err.Error()})
+	}
+}
+
+// parseLogDeleteQuery переводит query-параметры в фильтр массового удаления
+// в тех же форматах, что и поиск.
+func parseLogDeleteQuery(values url.Values) (*gen.LogDeleteQuery, error) {
+	query := &gen.LogDeleteQuery{
+		Sources:       splitList(values["source"]),
+		MetadataKey:   values.Get("metadata_key"),
+		MetadataValue: values.Get("metadata_value"),
+		Variable:      values.Get("var"),
+		Reason:        values.Get("reason"),
+	}
+
+	var err error
+	if query.From, err = parseTimeParam(values, "from"); err != nil {
+		return nil, err
+	}
+	if query.To, err = parseTimeParam(values, "to"); err != nil {
+		return nil, err
+	}
+
+	if raw := values.Get("dry_run"); raw != "" {
+		if query.DryRun, err = strconv.ParseBool(raw); err != nil {
+			return nil, fmt.Errorf("dry_run must be true or false")
+		}
+	}
+
+	if raw := values.Get("batch_size"); raw != "" {
+		size, err := strconv.Atoi(raw)
+		if err != nil || size <= 0 {
+			return nil, fmt.Errorf("batch_size must be a positive integer")
+		}
+		query.BatchSize = int32(size)
+	}
+
+	return query, nil
+}
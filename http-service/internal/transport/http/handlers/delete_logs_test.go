@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"github.com/julienschmidt/httprouter"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	gen "http-service/gen"
+	"http-service/internal/app"
+	"http-service/internal/codec"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDeleteLogsHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		progress       []*gen.LogDeleteProgress
+		mockError      error
+		expectedQuery  *gen.LogDeleteQuery
+		expectedStatus int
+		expectedType   string
+		expectedBody   []string
+	}{
+		{
+			name:     "dry run returns count and sample",
+			query:    "?source=HTTP-server,business-server&from=2025-06-01T00:00:00Z&to=2025-06-02T00:00:00Z&metadata_key=tenant&metadata_value=acme&var=total&dry_run=true",
+			progress: []*gen.LogDeleteProgress{{Matched: 2, SampleIds: []string{"a", "b"}, Done: true}},
+			expectedQuery: &gen.LogDeleteQuery{
+				Sources:       []string{"HTTP-server", "business-server"},
+				From:          1748736000000,
+				To:            1748822400000,
+				MetadataKey:   "tenant",
+				MetadataValue: "acme",
+				Variable:      "total",
+				DryRun:        true,
+			},
+			expectedStatus: http.StatusOK,
+			expectedType:   "application/json",
+			expectedBody:   []string{`"matched":2`, `"sample_ids":["a","b"]`, `"done":true`},
+		},
+		{
+			name:  "progress is streamed as NDJSON",
+			query: "?var=total&batch_size=1&reason=cleanup",
+			progress: []*gen.LogDeleteProgress{
+				{Matched: 2, SampleIds: []string{"a", "b"}},
+				{Matched: 2, Deleted: 1, PurgeAt: 1748736000000},
+				{Matched: 2, Deleted: 2, PurgeAt: 1748736000000, Done: true},
+			},
+			expectedQuery:  &gen.LogDeleteQuery{Variable: "total", BatchSize: 1, Reason: "cleanup"},
+			expectedStatus: http.StatusOK,
+			expectedType:   codec.ContentTypeNDJSON,
+			expectedBody: []string{
+				`{"matched":2,"deleted":0,"missing":0,"sample_ids":["a","b"],"done":false}` + "\n",
+				`{"matched":2,"deleted":1,"missing":0,"done":false,"purge_at":"2025-06-01T00:00:00Z"}` + "\n",
+				`"deleted":2,"missing":0,"done":true`,
+			},
+		},
+		{
+			name:  "failure after the first batch is reported in the stream",
+			query: "?var=total",
+			progress: []*gen.LogDeleteProgress{
+				{Matched: 2},
+				{Matched: 2, Deleted: 1},
+			},
+			mockError:      status.Error(codes.Internal, "disk failure"),
+			expectedQuery:  &gen.LogDeleteQuery{Variable: "total"},
+			expectedStatus: http.StatusOK,
+			expectedType:   codec.ContentTypeNDJSON,
+			expectedBody:   []string{`"deleted":1`, `"error":"Failed to delete logs: rpc error: code = Internal desc = disk failure"`},
+		},
+		{
+			name:           "invalid time",
+			query:          "?from=yesterday",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   []string{"from must be an RFC 3339 time"},
+		},
+		{
+			name:           "invalid batch size",
+			query:          "?var=total&batch_size=0",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   []string{"batch_size must be a positive integer"},
+		},
+		{
+			name:           "invalid dry run",
+			query:          "?var=total&dry_run=maybe",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   []string{"dry_run must be true or false"},
+		},
+		{
+			name:           "rejected by log service",
+			mockError:      status.Error(codes.InvalidArgument, "at least one filter is required"),
+			expectedQuery:  &gen.LogDeleteQuery{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   []string{"at least one filter is required"},
+		},
+		{
+			name:           "gRPC call fails before progress",
+			query:          "?var=total",
+			mockError:      status.Error(codes.Unavailable, "connection refused"),
+			expectedQuery:  &gen.LogDeleteQuery{Variable: "total"},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   []string{"Failed to delete logs"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotQuery *gen.LogDeleteQuery
+			mockClient := &mockLogClient{
+				DeleteLogsFunc: func(ctx context.Context, query *gen.LogDeleteQuery, onProgress func(*gen.LogDeleteProgress)) (*gen.LogDeleteProgress, error) {
+					gotQuery = query
+					var last *gen.LogDeleteProgress
+					for _, p := range tt.progress {
+						last = p
+						if onProgress != nil {
+							onProgress(p)
+						}
+					}
+					return last, tt.mockError
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/logs"+tt.query, nil)
+			w := httptest.NewRecorder()
+			DeleteLogsHandler(&app.Clients{LogClient: mockClient})(w, req, httprouter.Params{})
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedType != "" && w.Header().Get("Content-Type") != tt.expectedType {
+				t.Errorf("expected Content-Type %q, got %q", tt.expectedType, w.Header().Get("Content-Type"))
+			}
+			for _, want := range tt.expectedBody {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("expected body to contain %q, got %q", want, w.Body.String())
+				}
+			}
+			if tt.expectedQuery != nil && !proto.Equal(gotQuery, tt.expectedQuery) {
+				t.Errorf("expected query %v, got %v", tt.expectedQuery, gotQuery)
+			}
+		})
+	}
+}
@@ -54,7 +54,7 @@ func TestDeleteLogHandler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockClient := &mockLogClient{
-				DeleteLogFunc: func(id, filename string) (*gen.LogDeletionResponse, error) {
+				DeleteLogFunc: func(id, filename, actor, reason string) (*gen.LogDeletionResponse, error) {
 					return tt.mockResponse, tt.mockError
 				},
 			}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"google.golang.org/protobuf/proto"
+	gen "http-service/gen"
+	"http-service/internal/codec"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+type responseEncoder func(w io.Writer, resp CompositeResponse) error
+
+// Порядок задаёт предпочтение сервера при равных q в Accept.
+var processResponseTypes = []string{
+	codec.ContentTypeJSON,
+	codec.ContentTypeCSV,
+	codec.ContentTypeNDJSON,
+	codec.ContentTypeProtobuf,
+	codec.ContentTypeText,
+}
+
+// JSON в таблице нет: его, как и остальные ответы, пишет writeJSON.
+var processEncoders = map[string]responseEncoder{
+	codec.ContentTypeCSV:      encodeCSV,
+	codec.ContentTypeNDJSON:   encodeNDJSON,
+	codec.ContentTypeProtobuf: encodeProtobuf,
+	codec.ContentTypeText:     encodeText,
+}
+
+func negotiateProcessResponse(r *http.Request) (string, bool) {
+	return codec.Negotiate(r.Header.Get("Accept"), processResponseTypes)
+}
+
+func writeNotAcceptable(w http.ResponseWriter) {
+	writeJSON(w, http.StatusNotAcceptable, CompositeResponse{
+		Success:         false,
+		Status:          http.StatusNotAcceptable,
+		Message:         "Not acceptable",
+		ValidationError: "supported response types: " + strings.Join(processResponseTypes, ", "),
+	})
+}
+
+// writeProcessResponse кодирует результат в выбранный формат.
+// Ошибки всегда отдаются в JSON, чтобы не терять описание причины.
+func writeProcessResponse(w http.ResponseWriter, mediaType string, status int, resp CompositeResponse) {
+	w.Header().Add("Vary", "Accept")
+
+	encode, ok := processEncoders[mediaType]
+	if !ok || status >= http.StatusBadRequest {
+		writeJSON(w, status, resp)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := encode(&buf, resp); err != nil {
+		writeJSON(w, http.StatusInternalServerError, CompositeResponse{
+			Success: false,
+			Status:  http.StatusInternalServerError,
+			Message: "Failed to encode response: " + err.Error(),
+		})
+		return
+	}
+
+	contentType := mediaType
+	if strings.HasPrefix(mediaType, "text/") {
+		contentType += "; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	w.Write(buf.Bytes())
+}
+
+func encodeCSV(w io.Writer, resp CompositeResponse) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"var", "value"}); err != nil {
+		return err
+	}
+	for _, item := range resp.Items {
+		if err := writer.Write([]string{item.GetVar(), strconv.FormatInt(item.GetValue(), 10)}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func encodeNDJSON(w io.Writer, resp CompositeResponse) error {
+	encoder := json.NewEncoder(w)
+	for _, item := range resp.Items {
+		line := struct {
+			Var   string `json:"var"`
+			Value int64  `json:"value"`
+		}{item.GetVar(), item.GetValue()}
+		if err := encoder.Encode(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeProtobuf(w io.Writer, resp CompositeResponse) error {
+	result := resp.result
+	if result == nil {
+		result = &gen.OperationResponse{Items: resp.Items}
+		if resp.ResultID != "" {
+			result.LogID = &gen.LogID{Id: resp.ResultID}
+		}
+	}
+
+	data, err := proto.Marshal(result)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func encodeText(w io.Writer, resp CompositeResponse) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VAR\tVALUE")
+	for _, item := range resp.Items {
+		fmt.Fprintf(tw, "%s\t%d\n", item.GetVar(), item.GetValue())
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n%s\n", resp.Message)
+	if resp.ProcessingDuration != "" {
+		fmt.Fprintf(w, "processing: %s\n", resp.ProcessingDuration)
+	}
+	if resp.Warning != "" {
+		fmt.Fprintln(w, resp.Warning)
+	}
+	if resp.ProcessError != "" {
+		fmt.Fprintf(w, "process error: %s\n", resp.ProcessError)
+	}
+	if resp.LogError != "" {
+		fmt.Fprintf(w, "log error: %s\n", resp.LogError)
+	}
+	return nil
+}
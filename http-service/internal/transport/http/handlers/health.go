@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"github.com/julienschmidt/httprouter"
+	"http-service/internal/app"
+	"http-service/internal/client/grpc/resilience"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const readinessTimeout = 2 * time.Second
+
+const (
+	ReadinessReady    = "ready"
+	ReadinessDegraded = "degraded"
+	ReadinessNotReady = "not_ready"
+)
+
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+type DependencyStatus struct {
+	Name     string            `json:"name"`
+	Required bool              `json:"required"`
+	Healthy  bool              `json:"healthy"`
+	Error    string            `json:"error,omitempty"`
+	Latency  string            `json:"latency,omitempty"`
+	Client   resilience.Status `json:"client,omitempty"`
+}
+
+type ReadinessResponse struct {
+	Status       string             `json:"status"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+}
+
+type healthChecker interface {
+	statusReporter
+	CheckHealth(ctx context.Context) error
+}
+
+type dependency struct {
+	name     string
+	required bool
+	client   healthChecker
+}
+
+// HealthzHandler — проверка живости: процесс запущен и обслуживает HTTP.
+func HealthzHandler() httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		writeJSON(w, http.StatusOK, HealthResponse{Status: "ok"})
+	}
+}
+
+// ReadyzHandler опрашивает grpc.health.v1 зависимостей параллельно.
+// Без бизнес-сервиса запросы обрабатывать нельзя (503), без лог-сервиса
+// сервис работает в деградированном режиме (200, status=degraded).
+func ReadyzHandler(clients *app.Clients) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		deps := []dependency{
+			{name: "business-service", required: true},
+			{name: "log-service", required: false},
+		}
+		if !isNil(clients.BusinessClient) {
+			deps[0].client = clients.BusinessClient
+		}
+		if !isNil(clients.LogClient) {
+			deps[1].client = clients.LogClient
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+		defer cancel()
+
+		statuses := make([]DependencyStatus, len(deps))
+		var wg sync.WaitGroup
+		for i, dep := range deps {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				statuses[i] = checkDependency(ctx, dep)
+			}()
+		}
+		wg.Wait()
+
+		resp := ReadinessResponse{Status: ReadinessReady, Dependencies: statuses}
+		for _, s := range statuses {
+			switch {
+			case s.Healthy:
+			case s.Required:
+				resp.Status = ReadinessNotReady
+			case resp.Status == ReadinessReady:
+				resp.Status = ReadinessDegraded
+			}
+		}
+
+		status := http.StatusOK
+		if resp.Status == ReadinessNotReady {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, resp)
+	}
+}
+
+func checkDependency(ctx context.Context, dep dependency) DependencyStatus {
+	s := DependencyStatus{Name: dep.name, Required: dep.required}
+	if dep.client == nil {
+		s.Error = "client is not configured"
+		return s
+	}
+
+	start := time.Now()
+	err := dep.client.CheckHealth(ctx)
+	s.Latency = time.Since(start).Round(time.Microsecond).String()
+	s.Client = dep.client.Status()
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	s.Healthy = true
+	return s
+}
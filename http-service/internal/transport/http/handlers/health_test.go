@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"http-service/internal/app"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealthzHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	HealthzHandler()(w, httptest.NewRequest(http.MethodGet, "/healthz", nil), httprouter.Params{})
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", w.Code)
+	}
+}
+
+func TestReadyzHandler(t *testing.T) {
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name           string
+		clients        *app.Clients
+		expectedStatus int
+		expectedState  string
+		unhealthy      []string
+	}{
+		{
+			name:           "all dependencies serving",
+			clients:        &app.Clients{LogClient: &mockLogClient{}, BusinessClient: &mockBizClient{}},
+			expectedStatus: http.StatusOK,
+			expectedState:  ReadinessReady,
+		},
+		{
+			name:           "log service down",
+			clients:        &app.Clients{LogClient: &mockLogClient{HealthFunc: down}, BusinessClient: &mockBizClient{}},
+			expectedStatus: http.StatusOK,
+			expectedState:  ReadinessDegraded,
+			unhealthy:      []string{"log-service"},
+		},
+		{
+			name:           "business service down",
+			clients:        &app.Clients{LogClient: &mockLogClient{}, BusinessClient: &mockBizClient{HealthFunc: down}},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedState:  ReadinessNotReady,
+			unhealthy:      []string{"business-service"},
+		},
+		{
+			name:           "no clients",
+			clients:        &app.Clients{},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedState:  ReadinessNotReady,
+			unhealthy:      []string{"business-service", "log-service"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ReadyzHandler(tt.clients)(w, httptest.NewRequest(http.MethodGet, "/readyz", nil), httprouter.Params{})
+
+			var resp ReadinessResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if w.Code != tt.expectedStatus || resp.Status != tt.expectedState {
+				t.Errorf("expected %d/%s, got %d/%s", tt.expectedStatus, tt.expectedState, w.Code, resp.Status)
+			}
+
+			var unhealthy []string
+			for _, dep := range resp.Dependencies {
+				if !dep.Healthy {
+					if dep.Error == "" {
+						t.Errorf("%s: expected error for unhealthy dependency", dep.Name)
+					}
+					unhealthy = append(unhealthy, dep.Name)
+				}
+			}
+			if len(unhealthy) != len(tt.unhealthy) {
+				t.Errorf("expected unhealthy %v, got %v", tt.unhealthy, unhealthy)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/julienschmidt/httprouter"
+	"http-service/internal/app"
+	"http-service/internal/auth"
+	"http-service/internal/idempotency"
+	"io"
+	"net/http"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// WithIdempotency выполняет next не более одного раза для каждого
+// Idempotency-Key. Повтор с тем же телом, Accept и callback_url получает
+// сохранённый ответ, с другими — 422. Запросы без заголовка проходят как есть.
+func WithIdempotency(clients *app.Clients, next httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" || isNil(clients.Idempotency) {
+			next(w, r, ps)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			writeJSON(w, http.StatusBadRequest, CompositeResponse{
+				Success:         false,
+				Status:          http.StatusBadRequest,
+				Message:         "Invalid requesst",
+				ValidationError: fmt.Sprintf("%s must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength),
+			})
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, CompositeResponse{
+				Success:         false,
+				Status:          http.StatusBadRequest,
+				Message:         "Invalid requesst",
+				ValidationError: err.Error(),
+			})
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Ключи разных API-ключей не пересекаются
+		if name := auth.KeyName(r.Context()); name != "" {
+			key = name + ":" + key
+		}
+
+		request := idempotency.Request(body, r.Header.Get("Accept"), r.URL.Query().Get("callback_url"))
+		resp, replayed, err := clients.Idempotency.Do(r.Context(), key, request, func() *idempotency.Response {
+			rec := &responseRecorder{header: make(http.Header)}
+			next(rec, r, ps)
+			return rec.response()
+		})
+		if errors.Is(err, idempotency.ErrKeyReused) {
+			writeJSON(w, http.StatusUnprocessableEntity, CompositeResponse{
+				Success:         false,
+				Status:          http.StatusUnprocessableEntity,
+				Message:         "Idempotency key reused",
+				ValidationError: err.Error(),
+			})
+			return
+		}
+		if err != nil {
+			// Клиент ушёл, пока ждал первый запрос с тем же ключом
+			return
+		}
+
+		for name, values := range resp.Header {
+			w.Header()[name] = values
+		}
+		if replayed {
+			w.Header().Set(IdempotencyReplayedHeader, "true")
+		}
+		w.WriteHeader(resp.Status)
+		w.Write(resp.Body)
+	}
+}
+
+// responseRecorder запоминает ответ обработчика, чтобы сохранить его и отдать повторно.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	return rec.body.Write(b)
+}
+
+func (rec *responseRecorder) response() *idempotency.Response {
+	status := rec.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return &idempotency.Response{
+		Status: status,
+		Header: rec.header.Clone(),
+		Body:   bytes.Clone(rec.body.Bytes()),
+	}
+}
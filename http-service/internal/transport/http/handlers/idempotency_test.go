@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"github.com/julienschmidt/httprouter"
+	"http-service/gen"
+	"http-service/internal/app"
+	"http-service/internal/idempotency"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newIdempotentClients(logCalls, bizCalls *atomic.Int32, bizDelay time.Duration) *app.Clients {
+	return &app.Clients{
+		LogClient: &mockLogClient{
+			LogDataGRPCFunc: func(ctx context.Context, entry *gen.LogEntry) (*gen.LogID, error) {
+				logCalls.Add(1)
+				return &gen.LogID{Id: "log1"}, nil
+			},
+		},
+		BusinessClient: &mockBizClient{
+			ProcessFunc: func(ctx context.Context, req *gen.OperationRequest) (*gen.OperationResponse, error) {
+				bizCalls.Add(1)
+				time.Sleep(bizDelay)
+				return &gen.OperationResponse{Items: []*gen.VariableValue{{Var: "x", Value: 3}}}, nil
+			},
+		},
+		Idempotency: idempotency.NewStore(time.Hour),
+	}
+}
+
+func postIdempotent(clients *app.Clients, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/process", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/x-hasher")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	WithIdempotency(clients, ProcessDataHandler(clients))(w, req, httprouter.Params{})
+	return w
+}
+
+func TestWithIdempotency(t *testing.T) {
+	var logCalls, bizCalls atomic.Int32
+	clients := newIdempotentClients(&logCalls, &bizCalls, 0)
+
+	first := postIdempotent(clients, "key-1", "x = 1 + 2; print x")
+	if first.Code != http.StatusOK || first.Header().Get(IdempotencyReplayedHeader) != "" {
+		t.Fatalf("unexpected first response %d: %v", first.Code, first.Header())
+	}
+
+	second := postIdempotent(clients, "key-1", "x = 1 + 2; print x")
+	if second.Code != http.StatusOK || second.Header().Get(IdempotencyReplayedHeader) != "true" {
+		t.Errorf("expected replayed response, got %d: %v", second.Code, second.Header())
+	}
+	if second.Body.String() != first.Body.String() || second.Header().Get("Content-Type") != first.Header().Get("Content-Type") {
+		t.Errorf("replayed response differs:\n%s\n%s", first.Body.String(), second.Body.String())
+	}
+	if logCalls.Load() != 1 || bizCalls.Load() != 1 {
+		t.Errorf("expected one log and one process call, got %d and %d", logCalls.Load(), bizCalls.Load())
+	}
+
+	conflict := postIdempotent(clients, "key-1", "x = 2 + 2; print x")
+	if conflict.Code != http.StatusUnprocessableEntity || !strings.Contains(conflict.Body.String(), "different request body") {
+		t.Errorf("expected 422, got %d: %s", conflict.Code, conflict.Body.String())
+	}
+
+	// Тот же ключ и тело, но другой формат ответа или callback_url — другой запрос
+	for _, target := range []string{"/process", "/process?callback_url=https://example.com/hook"} {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader("x = 1 + 2; print x"))
+		req.Header.Set("Content-Type", "text/x-hasher")
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		if target == "/process" {
+			req.Header.Set("Accept", "application/x-protobuf")
+		}
+		w := httptest.NewRecorder()
+		WithIdempotency(clients, ProcessDataHandler(clients))(w, req, httprouter.Params{})
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: expected 422 for a key reused with another Accept or callback_url, got %d: %s", target, w.Code, w.Body.String())
+		}
+	}
+
+	postIdempotent(clients, "", "x = 1 + 2; print x")
+	if bizCalls.Load() != 2 {
+		t.Errorf("expected request without key to be processed, got %d calls", bizCalls.Load())
+	}
+
+	if w := postIdempotent(clients, strings.Repeat("k", 256), "x = 1; print x"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for long key, got %d", w.Code)
+	}
+}
+
+func TestWithIdempotencyConcurrentDuplicates(t *testing.T) {
+	var logCalls, bizCalls atomic.Int32
+	clients := newIdempotentClients(&logCalls, &bizCalls, 50*time.Millisecond)
+
+	var wg sync.WaitGroup
+	codes := make([]int, 5)
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			codes[i] = postIdempotent(clients, "key-1", "x = 1 + 2; print x").Code
+		}(i)
+	}
+	wg.Wait()
+
+	for i, code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("request %d: expected 200, got %d", i, code)
+		}
+	}
+	if logCalls.Load() != 1 || bizCalls.Load() != 1 {
+		t.Errorf("expected one log and one process call, got %d and %d", logCalls.Load(), bizCalls.Load())
+	}
+}
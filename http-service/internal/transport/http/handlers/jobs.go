@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	gen "http-service/gen"
+	"http-service/internal/app"
+	"http-service/internal/auth"
+	"http-service/internal/codec"
+	"http-service/internal/jobs"
+	"net/http"
+)
+
+type JobResponse struct {
+	Success bool      `json:"success"`
+	Status  int       `json:"status"`
+	Message string    `json:"message,omitempty"`
+	Error   string    `json:"error,omitempty"`
+	Job     *jobs.Job `json:"job,omitempty"`
+}
+
+func CreateJobHandler(clients *app.Clients) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		defer r.Body.Close()
+
+		if isNil(clients.Jobs) {
+			writeJobError(w, http.StatusServiceUnavailable, "Job queue unavailable", nil)
+			return
+		}
+
+		operations, err := decodeOperations(r)
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, codec.ErrUnsupportedContentType) {
+				status = http.StatusUnsupportedMediaType
+			}
+			writeJobError(w, status, "Invalid request", err)
+			return
+		}
+
+		callback, err := callbackURL(r, clients)
+		if err != nil {
+			writeJobError(w, http.StatusBadRequest, "Invalid request", err)
+			return
+		}
+
+		job, err := clients.Jobs.Submit(auth.KeyName(r.Context()), operations, callback)
+		if errors.Is(err, jobs.ErrQueueFull) {
+			w.Header().Set("Retry-After", "5")
+			writeJobError(w, http.StatusServiceUnavailable, "Job queue is full, try again later", err)
+			return
+		}
+		if err != nil {
+			writeJobError(w, http.StatusInternalServerError, "Failed to create job", err)
+			return
+		}
+
+		w.Header().Set("Location", "/jobs/"+job.ID)
+		writeJSON(w, http.StatusAccepted, JobResponse{
+			Success: true,
+			Status:  http.StatusAccepted,
+			Message: "Job queued",
+			Job:     job,
+		})
+	}
+}
+
+func GetJobHandler(clients *app.Clients) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if isNil(clients.Jobs) {
+			writeJobError(w, http.StatusServiceUnavailable, "Job queue unavailable", nil)
+			return
+		}
+
+		job, err := clients.Jobs.Get(ps.ByName("id"), auth.KeyName(r.Context()))
+		if errors.Is(err, jobs.ErrNotFound) {
+			writeJobError(w, http.StatusNotFound, "Job not found", err)
+			return
+		}
+		if err != nil {
+			writeJobError(w, http.StatusInternalServerError, "Failed to read job", err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, JobResponse{Success: true, Status: http.StatusOK, Job: job})
+	}
+}
+
+func CancelJobHandler(clients *app.Clients) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if isNil(clients.Jobs) {
+			writeJobError(w, http.StatusServiceUnavailable, "Job queue unavailable", nil)
+			return
+		}
+
+		job, err := clients.Jobs.Cancel(ps.ByName("id"), auth.KeyName(r.Context()))
+		switch {
+		case errors.Is(err, jobs.ErrNotFound):
+			writeJobError(w, http.StatusNotFound, "Job not found", err)
+		case errors.Is(err, jobs.ErrFinished):
+			writeJSON(w, http.StatusConflict, JobResponse{
+				Success: false,
+				Status:  http.StatusConflict,
+				Message: "Job is already finished",
+				Error:   err.Error(),
+				Job:     job,
+			})
+		case err != nil:
+			writeJobError(w, http.StatusInternalServerError, "Failed to cancel job", err)
+		default:
+			writeJSON(w, http.StatusOK, JobResponse{Success: true, Status: http.StatusOK, Message: "Job canceled", Job: job})
+		}
+	}
+}
+
+func writeJobError(w http.ResponseWriter, status int, message string, err error) {
+	resp := JobResponse{Success: false, Status: status, Message: message}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	writeJSON(w, status, resp)
+}
+
+// NewJobRunner выполняет задачу так же, как /process: логирует запрос и
+// отправляет операции в бизнес-сервис, но через потоковый RPC с прогрессом.
+func NewJobRunner(clients *app.Clients) jobs.Runner {
+	return func(ctx context.Context, job *jobs.Job, progress jobs.ProgressFunc) (*jobs.Result, error) {
+		if !serviceAvailable(clients.BusinessClient) {
+			return nil, errors.New("business service unavailable: " + unavailableReason(clients.BusinessClient))
+		}
+
+		result := &jobs.Result{}
+
+		var reqLogID *gen.LogID
+		if serviceAvailable(clients.LogClient) {
+			logID, err := logOperations(ctx, http.MethodPost, "/jobs", "", job.Operations, clients)
+			if err != nil {
+				result.LogError = err.Error()
+			}
+			reqLogID = logID
+			result.LogID = logID.GetId()
+		}
+
+		resp, err := clients.BusinessClient.ProcessStream(ctx, &gen.OperationRequest{
+			LogID:      reqLogID,
+			Operations: job.Operations,
+		}, progress)
+		if err != nil {
+			return nil, err
+		}
+
+		result.ResultID = resp.GetLogID().GetId()
+		result.Items = resp.GetItems()
+		result.Warning = resp.GetWarning()
+		result.ProcessingDuration = FormatDuration(resp.GetProcessingTime())
+		return result, nil
+	}
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/julienschmidt/httprouter"
+	"http-service/gen"
+	"http-service/internal/app"
+	"http-service/internal/auth"
+	"http-service/internal/jobs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newJobClients(t *testing.T, biz *mockBizClient) *app.Clients {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	clients := &app.Clients{BusinessClient: biz}
+	manager := jobs.NewManager(jobs.NewMemoryStore(), NewJobRunner(clients), 1, 4)
+	if err := manager.Start(ctx); err != nil {
+		t.Fatalf("failed to start job manager: %v", err)
+	}
+	clients.Jobs = manager
+	return clients
+}
+
+func getJob(t *testing.T, clients *app.Clients, id string) (int, JobResponse) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	GetJobHandler(clients)(w, httptest.NewRequest(http.MethodGet, "/jobs/"+id, nil), httprouter.Params{{Key: "id", Value: id}})
+
+	var resp JobResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return w.Code, resp
+}
+
+func TestJobLifecycle(t *testing.T) {
+	clients := newJobClients(t, &mockBizClient{
+		ProcessStreamFunc: func(ctx context.Context, req *gen.OperationRequest, onProgress func(completed, total int32)) (*gen.OperationResponse, error) {
+			if len(req.GetOperations()) != 2 {
+				t.Errorf("expected 2 operations, got %d", len(req.GetOperations()))
+			}
+			onProgress(1, 1)
+			return &gen.OperationResponse{
+				LogID: &gen.LogID{Id: "biz1"},
+				Items: []*gen.VariableValue{{Var: "x", Value: 3}},
+			}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader("x = 1 + 2; print x"))
+	req.Header.Set("Content-Type", "text/x-hasher")
+	w := httptest.NewRecorder()
+	CreateJobHandler(clients)(w, req, httprouter.Params{})
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", w.Code, w.Body.String())
+	}
+	var created JobResponse
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if created.Job == nil || created.Job.ID == "" {
+		t.Fatalf("expected job id in response, got %+v", created)
+	}
+	if loc := w.Header().Get("Location"); loc != "/jobs/"+created.Job.ID {
+		t.Errorf("unexpected Location header %q", loc)
+	}
+
+	var resp JobResponse
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		_, resp = getJob(t, clients, created.Job.ID)
+		if resp.Job.Status.Finished() {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if resp.Job.Status != jobs.StatusSucceeded {
+		t.Fatalf("expected job to succeed, got %+v", resp.Job)
+	}
+	if resp.Job.Progress.Completed != 1 || resp.Job.Result.ResultID != "biz1" || resp.Job.Result.Items[0].GetValue() != 3 {
+		t.Errorf("unexpected job state: %+v, result %+v", resp.Job, resp.Job.Result)
+	}
+
+	w = httptest.NewRecorder()
+	CancelJobHandler(clients)(w, httptest.NewRequest(http.MethodDelete, "/jobs/"+created.Job.ID, nil), httprouter.Params{{Key: "id", Value: created.Job.ID}})
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status 409 when canceling finished job, got %d", w.Code)
+	}
+}
+
+func TestJobHandlersErrors(t *testing.T) {
+	clients := newJobClients(t, &mockBizClient{})
+
+	if code, _ := getJob(t, clients, "missing"); code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", code)
+	}
+
+	w := httptest.NewRecorder()
+	CancelJobHandler(clients)(w, httptest.NewRequest(http.MethodDelete, "/jobs/missing", nil), httprouter.Params{{Key: "id", Value: "missing"}})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader("x = "))
+	req.Header.Set("Content-Type", "text/x-hasher")
+	w = httptest.NewRecorder()
+	CreateJobHandler(clients)(w, req, httprouter.Params{})
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "line 1, column 5") {
+		t.Errorf("expected 400 with syntax error, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	CreateJobHandler(&app.Clients{})(w, httptest.NewRequest(http.MethodPost, "/jobs", nil), httprouter.Params{})
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 without job manager, got %d", w.Code)
+	}
+}
+
+func TestJobsAreScopedToKey(t *testing.T) {
+	clients := newJobClients(t, &mockBizClient{
+		ProcessStreamFunc: func(ctx context.Context, req *gen.OperationRequest, onProgress func(completed, total int32)) (*gen.OperationResponse, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	})
+	as := func(req *http.Request, key string) *http.Request {
+		return req.WithContext(auth.WithKeyName(req.Context(), key))
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader("x = 1 + 2; print x"))
+	req.Header.Set("Content-Type", "text/x-hasher")
+	w := httptest.NewRecorder()
+	CreateJobHandler(clients)(w, as(req, "alice"), httprouter.Params{})
+	var created JobResponse
+	json.NewDecoder(w.Body).Decode(&created)
+	params := httprouter.Params{{Key: "id", Value: created.Job.ID}}
+
+	// Чужая задача неотличима от несуществующей
+	w = httptest.NewRecorder()
+	GetJobHandler(clients)(w, as(httptest.NewRequest(http.MethodGet, "/jobs/"+created.Job.ID, nil), "bob"), params)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for another key's job, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	CancelJobHandler(clients)(w, as(httptest.NewRequest(http.MethodDelete, "/jobs/"+created.Job.ID, nil), "bob"), params)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 when canceling another key's job, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	CancelJobHandler(clients)(w, as(httptest.NewRequest(http.MethodDelete, "/jobs/"+created.Job.ID, nil), "alice"), params)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected the owner to cancel the job, got %d: %s", w.Code, w.Body.String())
+	}
+}
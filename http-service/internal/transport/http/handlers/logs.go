@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/julienschmidt/httprouter"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	gen "http-service/gen"
+	"http-service/internal/app"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type LogSearchResponse struct {
+	Logs       []LogSearchHit `json:"logs"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+type LogSearchHit struct {
+	Filename string          `json:"filename"`
+	Log      json.RawMessage `json:"log"`
+}
+
+func SearchLogsHandler(clients *app.Clients) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		query, err := parseLogSearchQuery(r.URL.Query())
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, "Invalid search query: "+err.Error())
+			return
+		}
+
+		if !serviceAvailable(clients.LogClient) {
+			writeJSON(w, http.StatusServiceUnavailable, "Log service unavailable: "+unavailableReason(clients.LogClient))
+			return
+		}
+
+		result, err := clients.LogClient.SearchLogsGRPC(r.Context(), query)
+		if status.Code(err) == codes.InvalidArgument {
+			writeJSON(w, http.StatusBadRequest, "Invalid search query: "+status.Convert(err).Message())
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, "Failed to search logs: "+err.Error())
+			return
+		}
+
+		resp := LogSearchResponse{Logs: []LogSearchHit{}, NextCursor: result.GetNextCursor()}
+		for _, hit := range result.GetHits() {
+			entry, err := expandLog(hit.GetLog())
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, "Failed to parse log: "+err.Error())
+				return
+			}
+			resp.Logs = append(resp.Logs, LogSearchHit{Filename: hit.GetFilename(), Log: entry})
+		}
+
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// parseLogSearchQuery переводит query-параметры в фильтры лог-сервиса. Время
+// задаётся в RFC 3339, source и level можно повторять или перечислять через запятую.
+func parseLogSearchQuery(values url.Values) (*gen.LogSearchQuery, error) {
+	query := &gen.LogSearchQuery{
+		Sources:  splitList(values["source"]),
+		Levels:   splitList(values["level"]),
+		Path:     values.Get("path"),
+		Variable: values.Get("var"),
+		Text:     values.Get("q"),
+		Cursor:   values.Get("cursor"),
+		AfterId:  values.Get("after_id"),
+		Sort:     values.Get("sort"),
+		Order:    values.Get("order"),
+	}
+
+	var err error
+	if query.From, err = parseTimeParam(values, "from"); err != nil {
+		return nil, err
+	}
+	if query.To, err = parseTimeParam(values, "to"); err != nil {
+		return nil, err
+	}
+
+	if raw := values.Get("min_delay"); raw != "" {
+		delay, err := time.ParseDuration(raw)
+		if err != nil || delay < 0 {
+			return nil, fmt.Errorf("min_delay must be a non-negative duration such as 250ms")
+		}
+		query.MinDelayMs = delay.Milliseconds()
+	}
+
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("limit must be a positive integer")
+		}
+		query.Limit = int32(limit)
+	}
+
+	return query, nil
+}
+
+func parseTimeParam(values url.Values, name string) (int64, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return 0, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an RFC 3339 time such as 2025-06-01T00:00:00Z", name)
+	}
+	return t.UnixMilli(), nil
+}
+
+func splitList(values []string) []string {
+	var items []string
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"github.com/julienschmidt/httprouter"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	gen "http-service/gen"
+	"http-service/internal/app"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSearchLogsHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		mockResponse   *gen.LogSearchResult
+		mockError      error
+		expectedQuery  *gen.LogSearchQuery
+		expectedStatus int
+		expectedBody   []string
+	}{
+		{
+			name:  "all filters are passed to log service",
+			query: "?source=HTTP-server,business-server&level=info&level=warn&from=2025-06-01T00:00:00Z&to=2025-06-02T00:00:00Z&path=/process&var=total&min_delay=250ms&q=timeout&limit=10&cursor=abc&after_id=01JWMCKG00B7Q8N4XZ5V2E3R6T&sort=delay&order=asc",
+			mockResponse: &gen.LogSearchResult{
+				Hits:       []*gen.LogSearchHit{{Filename: "http_logs.json", Log: `{"id":"req1","message":"{\"path\":\"/process\"}"}`}},
+				NextCursor: "next",
+			},
+			expectedQuery: &gen.LogSearchQuery{
+				Sources:    []string{"HTTP-server", "business-server"},
+				Levels:     []string{"info", "warn"},
+				From:       1748736000000,
+				To:         1748822400000,
+				Path:       "/process",
+				Variable:   "total",
+				MinDelayMs: 250,
+				Text:       "timeout",
+				Limit:      10,
+				Cursor:     "abc",
+				AfterId:    "01JWMCKG00B7Q8N4XZ5V2E3R6T",
+				Sort:       "delay",
+				Order:      "asc",
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{`"filename":"http_logs.json"`, `"message":{"path":"/process"}`, `"next_cursor":"next"`},
+		},
+		{
+			name:           "no results",
+			mockResponse:   &gen.LogSearchResult{},
+			expectedQuery:  &gen.LogSearchQuery{},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{`"logs":[]`},
+		},
+		{
+			name:           "invalid time",
+			query:          "?from=yesterday",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   []string{"from must be an RFC 3339 time"},
+		},
+		{
+			name:           "invalid delay",
+			query:          "?min_delay=fast",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   []string{"min_delay"},
+		},
+		{
+			name:           "invalid limit",
+			query:          "?limit=0",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   []string{"limit must be a positive integer"},
+		},
+		{
+			name:           "rejected by log service",
+			query:          "?sort=level",
+			mockError:      status.Error(codes.InvalidArgument, `unknown sort "level"`),
+			expectedQuery:  &gen.LogSearchQuery{Sort: "level"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   []string{"unknown sort"},
+		},
+		{
+			name:           "gRPC call fails",
+			mockError:      status.Error(codes.Internal, "disk failure"),
+			expectedQuery:  &gen.LogSearchQuery{},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   []string{"Failed to search logs"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotQuery *gen.LogSearchQuery
+			mockClient := &mockLogClient{
+				SearchFunc: func(ctx context.Context, query *gen.LogSearchQuery) (*gen.LogSearchResult, error) {
+					gotQuery = query
+					return tt.mockResponse, tt.mockError
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/logs"+tt.query, nil)
+			w := httptest.NewRecorder()
+			SearchLogsHandler(&app.Clients{LogClient: mockClient})(w, req, httprouter.Params{})
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			for _, want := range tt.expectedBody {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("expected body to contain %q, got %q", want, w.Body.String())
+				}
+			}
+			if tt.expectedQuery != nil && !proto.Equal(gotQuery, tt.expectedQuery) {
+				t.Errorf("expected query %v, got %v", tt.expectedQuery, gotQuery)
+			}
+		})
+	}
+}
@@ -1,16 +1,18 @@
 package handlers
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/julienschmidt/httprouter"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/protobuf/types/known/durationpb"
 	gen "http-service/gen"
 	"http-service/internal/app"
+	"http-service/internal/auth"
+	"http-service/internal/codec"
+	"http-service/internal/tracing"
 	"http-service/internal/utils"
-	"io"
 	"net/http"
 	"reflect"
 	"time"
@@ -24,44 +26,53 @@ type CompositeResponse struct {
 	ResultID           string               `json:"result_id,omitempty"`
 	LogError           string               `json:"log_error,omitempty"`
 	ProcessError       string               `json:"process_error,omitempty"`
+	ValidationError    string               `json:"validation_error,omitempty"`
 	Items              []*gen.VariableValue `json:"items,omitempty"`
+	Warning            string               `json:"warning,omitempty"`
 	ProcessingDuration string               `json:"processing_duration"`
-}
-
-type requestJSON struct {
-	Operations []operationJSON `json:"operations"`
-}
+	JobID              string               `json:"job_id,omitempty"`
+	CallbackDeliveryID string               `json:"callback_delivery_id,omitempty"`
+	CallbackError      string               `json:"callback_error,omitempty"`
 
-type operationJSON struct {
-	Type  string           `json:"type"`
-	Op    string           `json:"op"`
-	Var   string           `json:"var"`
-	Left  utils.FlexString `json:"left"`
-	Right utils.FlexString `json:"right"`
+	// Исходный ответ бизнес-сервиса, нужен для protobuf-представления
+	result *gen.OperationResponse
 }
 
 func ProcessDataHandler(clients *app.Clients) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 		defer r.Body.Close()
 
-		if _, err := utils.ValidateHttpRequest(r); err != nil {
-			writeJSON(w, http.StatusBadRequest, CompositeResponse{
-				Success: false,
-				Status:  http.StatusBadRequest,
-				Message: "Invalid requesst",
+		mediaType, ok := negotiateProcessResponse(r)
+		if !ok {
+			writeNotAcceptable(w)
+			return
+		}
+
+		operations, err := decodeOperations(r)
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, codec.ErrUnsupportedContentType) {
+				status = http.StatusUnsupportedMediaType
+			}
+			writeJSON(w, status, CompositeResponse{
+				Success:         false,
+				Status:          status,
+				Message:         "Invalid requesst",
+				ValidationError: err.Error(),
 			})
 			return
 		}
-		body, err := io.ReadAll(r.Body)
+
+		callback, err := callbackURL(r, clients)
 		if err != nil {
-			writeJSON(w, http.StatusInternalServerError, CompositeResponse{
-				Success: false,
-				Status:  http.StatusInternalServerError,
-				Message: "Failed to read request body",
+			writeJSON(w, http.StatusBadRequest, CompositeResponse{
+				Success:         false,
+				Status:          http.StatusBadRequest,
+				Message:         "Invalid requesst",
+				ValidationError: err.Error(),
 			})
 			return
 		}
-		r.Body = io.NopCloser(bytes.NewBuffer(body))
 
 		resp := CompositeResponse{
 			Success: true,
@@ -74,9 +85,16 @@ func ProcessDataHandler(clients *app.Clients) httprouter.Handle {
 
 		fmt.Println(clients.LogClient)
 
-		if !isNil(clients.LogClient) {
+		// Доступность решается один раз до вызовов, а после них — по их ошибкам
+		logAvailable := serviceAvailable(clients.LogClient)
+		bizAvailable := serviceAvailable(clients.BusinessClient)
+
+		if logAvailable {
 			fmt.Println("Мы внутри")
-			reqLogID, logErr = logRequestData(r.Context(), r, clients)
+			reqLogID, logErr = logRequestData(r.Context(), r, operations, clients)
+			if callUnavailable(logErr) {
+				logAvailable = false
+			}
 			if reqLogID != nil {
 				resp.LogID = reqLogID.GetId()
 			}
@@ -90,28 +108,29 @@ func ProcessDataHandler(clients *app.Clients) httprouter.Handle {
 			resp.Message += ", Log service unavailable"
 		}
 
-		var resBizID *gen.LogID
-		var items []*gen.VariableValue
-		var procErr error
-		var processingTime string
-		if !isNil(clients.BusinessClient) {
-			resBizID, items, processingTime, procErr = processBusinessData(r.Context(), body, clients, reqLogID)
-			if resBizID != nil {
-				resp.ResultID = resBizID.GetId()
+		if bizAvailable {
+			result, procErr := processBusinessData(r.Context(), operations, clients, reqLogID)
+			if callUnavailable(procErr) {
+				bizAvailable = false
+			}
+			if result.GetLogID() != nil {
+				resp.ResultID = result.GetLogID().GetId()
 			}
 			if procErr != nil {
 				resp.ProcessError = procErr.Error()
 				resp.Message += ", FAILED processing"
 			} else {
-				resp.Items = items
+				resp.Items = result.GetItems()
+				resp.Warning = result.GetWarning()
 				resp.Message += ", SUCCESSFUL processing"
-				resp.ProcessingDuration = processingTime
+				resp.ProcessingDuration = FormatDuration(result.GetProcessingTime())
+				resp.result = result
 			}
 		} else {
 			resp.Message += ", Business service unavailable"
 		}
 
-		if clients.LogClient == nil && clients.BusinessClient == nil {
+		if !logAvailable && !bizAvailable {
 			resp.Success = false
 			resp.Status = http.StatusServiceUnavailable
 			resp.Message = "Both Log and Business services unavailable"
@@ -119,7 +138,9 @@ func ProcessDataHandler(clients *app.Clients) httprouter.Handle {
 			return
 		}
 
-		writeJSON(w, http.StatusOK, resp)
+		notifyCallback(clients, auth.KeyName(r.Context()), callback, &resp)
+
+		writeProcessResponse(w, mediaType, http.StatusOK, resp)
 
 	}
 }
@@ -150,34 +171,31 @@ func isNil(i interface{}) bool {
 	return false
 }
 
-func logRequestData(ctx context.Context, r *http.Request, client *app.Clients) (*gen.LogID, error) {
+// decodeOperations читает тело и разбирает его по Content-Type в отдельном span'е.
+func decodeOperations(r *http.Request) ([]*gen.Operation, error) {
+	contentType := r.Header.Get("Content-Type")
+	_, span := tracing.Start(r.Context(), "validate request", attribute.String("content_type", codec.MediaType(contentType)))
 
-	bodyBytes, err := io.ReadAll(r.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read request body: %w", err)
-	}
-	r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-
-	var reqParsed requestJSON
-	if err := json.Unmarshal(bodyBytes, &reqParsed); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal request body into operations: %w", err)
+	body, err := utils.ValidateHttpRequest(r)
+	var operations []*gen.Operation
+	if err == nil {
+		operations, err = codec.Decode(contentType, body)
 	}
+	span.SetAttributes(attribute.Int("operations", len(operations)))
 
-	structured := &gen.StructuredMessage{
-		Method: r.Method,
-		Path:   r.URL.Path,
-		Body:   make([]*gen.Operation, 0, len(reqParsed.Operations)),
-	}
+	tracing.End(span, err)
+	return operations, err
+}
 
-	for _, op := range reqParsed.Operations {
+func logRequestData(ctx context.Context, r *http.Request, operations []*gen.Operation, client *app.Clients) (*gen.LogID, error) {
+	return logOperations(ctx, r.Method, r.URL.Path, codec.MediaType(r.Header.Get("Content-Type")), operations, client)
+}
 
-		structured.Body = append(structured.Body, &gen.Operation{
-			Type:  op.Type,
-			Op:    op.Op,
-			Var:   op.Var,
-			Left:  string(op.Left),
-			Right: string(op.Right),
-		})
+func logOperations(ctx context.Context, method, path, contentType string, operations []*gen.Operation, client *app.Clients) (*gen.LogID, error) {
+	structured := &gen.StructuredMessage{
+		Method: method,
+		Path:   path,
+		Body:   operations,
 	}
 
 	entry := &gen.LogEntry{
@@ -185,43 +203,33 @@ func logRequestData(ctx context.Context, r *http.Request, client *app.Clients) (
 		Level:       "INFO",
 		Message:     structured,
 		Metadata: map[string]string{
-			"method": r.Method,
-			"path":   r.URL.Path,
+			"method":       method,
+			"path":         path,
+			"content_type": contentType,
 		},
 		TimestampSend: time.Now().UnixMilli(),
 	}
 
-	return client.LogClient.LogDataGRPC(ctx, entry)
-}
-
-func processBusinessData(ctx context.Context, body []byte, clients *app.Clients, logID *gen.LogID) (resultID *gen.LogID,
-	results []*gen.VariableValue, processingTime string, err error) {
-
-	var reqParsed requestJSON
-	if err := json.Unmarshal(body, &reqParsed); err != nil {
-		return nil, nil, "", fmt.Errorf("invalid JSON: %w", err)
+	ctx, span := tracing.Start(ctx, "log request")
+	logID, err := client.LogClient.LogDataGRPC(ctx, entry)
+	if logID != nil {
+		span.SetAttributes(attribute.String("log_id", logID.GetId()))
 	}
+	tracing.End(span, err)
+	return logID, err
+}
 
+func processBusinessData(ctx context.Context, operations []*gen.Operation, clients *app.Clients, logID *gen.LogID) (*gen.OperationResponse, error) {
 	converted := &gen.OperationRequest{
 		LogID:      logID,
-		Operations: make([]*gen.Operation, 0, len(reqParsed.Operations)),
-	}
-
-	for _, op := range reqParsed.Operations {
-		converted.Operations = append(converted.Operations, &gen.Operation{
-			Type:  op.Type,
-			Op:    op.Op,
-			Var:   op.Var,
-			Left:  string(op.Left),
-			Right: string(op.Right),
-		})
+		Operations: operations,
 	}
 
+	ctx, span := tracing.Start(ctx, "process operations", attribute.Int("operations", len(operations)))
 	resp, err := clients.BusinessClient.Process(ctx, converted)
-	results = resp.GetItems()
+	tracing.End(span, err)
 	if err != nil {
-		return nil, nil, "", fmt.Errorf("business logic error: %w", err)
+		return nil, fmt.Errorf("business logic error: %w", err)
 	}
-	processingTime = FormatDuration(resp.GetProcessingTime())
-	return resp.LogID, results, processingTime, nil
+	return resp, nil
 }
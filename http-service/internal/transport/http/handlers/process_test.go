@@ -5,9 +5,15 @@ import (
 	"context"
 	"errors"
 	"github.com/julienschmidt/httprouter"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"http-service/gen"
 	"http-service/internal/app"
+	"http-service/internal/client/grpc/resilience"
+	"http-service/internal/tracing"
+	"http-service/internal/tracing/tracingtest"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -20,10 +26,12 @@ func TestProcessDataHandler(t *testing.T) {
 	tests := []struct {
 		name              string
 		requestBody       string
+		contentType       string
 		mockLogResponse   *gen.LogID
 		mockLogError      error
 		mockBizResponse   *gen.OperationResponse
 		mockBizError      error
+		logBreakerOpen    bool
 		expectedStatus    int
 		expectedBodyMatch []string
 	}{
@@ -64,6 +72,72 @@ func TestProcessDataHandler(t *testing.T) {
 				`"message":"Request received, SUCCESSFULLY logged, SUCCESSFUL processing"`,
 			},
 		},
+		{
+			name:            "program in text/x-hasher format",
+			requestBody:     "x = 1 + 2 * 3\nprint x",
+			contentType:     "text/x-hasher; charset=utf-8",
+			mockLogResponse: &gen.LogID{Id: "log001"},
+			mockBizResponse: &gen.OperationResponse{
+				LogID: &gen.LogID{Id: "biz001"},
+				Items: []*gen.VariableValue{{Var: "x", Value: 7}},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBodyMatch: []string{
+				`"result_id":"biz001"`,
+				`"value":7`,
+			},
+		},
+		{
+			name:              "program with syntax error",
+			requestBody:       "x = (1 + 2\nprint x",
+			contentType:       "text/x-hasher",
+			expectedStatus:    http.StatusBadRequest,
+			expectedBodyMatch: []string{`"success":false`, `line 2, column 1: expected ')'`},
+		},
+		{
+			name:              "unsupported content type",
+			requestBody:       "x = 1",
+			contentType:       "text/plain",
+			expectedStatus:    http.StatusUnsupportedMediaType,
+			expectedBodyMatch: []string{`"success":false`, `unsupported Content-Type`},
+		},
+		{
+			name:            "operations as csv rows",
+			requestBody:     "type,op,var,left,right\ncalc,+,x,1,2\nprint,,x,,\n",
+			contentType:     "text/csv",
+			mockLogResponse: &gen.LogID{Id: "log002"},
+			mockBizResponse: &gen.OperationResponse{
+				LogID: &gen.LogID{Id: "biz002"},
+				Items: []*gen.VariableValue{{Var: "x", Value: 3}},
+			},
+			expectedStatus:    http.StatusOK,
+			expectedBodyMatch: []string{`"result_id":"biz002"`, `"value":3`},
+		},
+		{
+			name:            "log service circuit open",
+			requestBody:     `{"operations":[{"type":"calc","op":"add","var":"x","left":"1","right":"2"}]}`,
+			mockLogResponse: &gen.LogID{Id: "never"},
+			logBreakerOpen:  true,
+			mockBizResponse: &gen.OperationResponse{
+				Items: []*gen.VariableValue{{Var: "x", Value: 3}},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBodyMatch: []string{
+				`"message":"Request received, Log service unavailable, SUCCESSFUL processing"`,
+			},
+		},
+		{
+			name:           "both calls fail as unavailable",
+			requestBody:    `{"operations":[{"type":"calc","op":"add","var":"x","left":"1","right":"2"}]}`,
+			mockLogError:   status.Error(codes.Unavailable, "connection refused"),
+			mockBizError:   status.Error(codes.DeadlineExceeded, "deadline exceeded"),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBodyMatch: []string{
+				`"success":false`,
+				`"Both Log and Business services unavailable"`,
+				`"process_error":"business logic error: rpc error: code = DeadlineExceeded desc = deadline exceeded"`,
+			},
+		},
 		{
 			name:              "both services unavailable",
 			requestBody:       `{"operations":[{"type":"calc","op":"add","var":"x","left":"1","right":"2"}]}`,
@@ -76,9 +150,17 @@ func TestProcessDataHandler(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockLogClient := &mockLogClient{
 				LogDataGRPCFunc: func(ctx context.Context, entry *gen.LogEntry) (*gen.LogID, error) {
+					if tt.logBreakerOpen {
+						t.Error("log client must not be called while its circuit is open")
+					}
 					return tt.mockLogResponse, tt.mockLogError
 				},
 			}
+			if tt.logBreakerOpen {
+				mockLogClient.StatusFunc = func() resilience.Status {
+					return resilience.Status{Name: "log-service", Breaker: resilience.StateOpen}
+				}
+			}
 
 			mockBizClient := &mockBizClient{
 				ProcessFunc: func(ctx context.Context, req *gen.OperationRequest) (*gen.OperationResponse, error) {
@@ -99,7 +181,11 @@ func TestProcessDataHandler(t *testing.T) {
 			}
 
 			req := httptest.NewRequest(http.MethodPost, "/process", bytes.NewBufferString(tt.requestBody))
-			req.Header.Set("Content-Type", "application/json")
+			contentType := tt.contentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			req.Header.Set("Content-Type", contentType)
 
 			w := httptest.NewRecorder()
 
@@ -121,3 +207,146 @@ func TestProcessDataHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessDataHandlerAccept(t *testing.T) {
+	bizResponse := &gen.OperationResponse{
+		LogID:          &gen.LogID{Id: "biz42"},
+		Items:          []*gen.VariableValue{{Var: "x", Value: 3}, {Var: "y", Value: -9}},
+		ProcessingTime: durationpb.New(2 * time.Millisecond),
+	}
+
+	tests := []struct {
+		name                string
+		accept              string
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "csv rows",
+			accept:              "text/csv",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody:        "var,value\nx,3\ny,-9\n",
+		},
+		{
+			name:                "ndjson",
+			accept:              "application/x-ndjson",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedBody:        "{\"var\":\"x\",\"value\":3}\n{\"var\":\"y\",\"value\":-9}\n",
+		},
+		{
+			name:                "plain text table",
+			accept:              "text/plain, application/json;q=0.5",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "VAR  VALUE\nx    3\ny    -9\n",
+		},
+		{
+			name:                "json by default",
+			accept:              "*/*",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody:        `"result_id":"biz42"`,
+		},
+		{
+			name:                "nothing acceptable",
+			accept:              "image/png",
+			expectedStatus:      http.StatusNotAcceptable,
+			expectedContentType: "application/json",
+			expectedBody:        `supported response types: application/json, text/csv`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clients := &app.Clients{
+				BusinessClient: &mockBizClient{
+					ProcessFunc: func(ctx context.Context, req *gen.OperationRequest) (*gen.OperationResponse, error) {
+						return bizResponse, nil
+					},
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/process", strings.NewReader("x = 1 + 2; y = -12 + x; print x; print y"))
+			req.Header.Set("Content-Type", "text/x-hasher")
+			req.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+
+			ProcessDataHandler(clients)(w, req, httprouter.Params{})
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != tt.expectedContentType {
+				t.Errorf("expected Content-Type %q, got %q", tt.expectedContentType, ct)
+			}
+			if !strings.Contains(w.Body.String(), tt.expectedBody) {
+				t.Errorf("expected body to contain %q, got %q", tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+
+	t.Run("protobuf", func(t *testing.T) {
+		clients := &app.Clients{
+			BusinessClient: &mockBizClient{
+				ProcessFunc: func(ctx context.Context, req *gen.OperationRequest) (*gen.OperationResponse, error) {
+					return bizResponse, nil
+				},
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/process", strings.NewReader("x = 1 + 2; print x"))
+		req.Header.Set("Content-Type", "text/x-hasher")
+		req.Header.Set("Accept", "application/x-protobuf")
+		w := httptest.NewRecorder()
+
+		ProcessDataHandler(clients)(w, req, httprouter.Params{})
+
+		var got gen.OperationResponse
+		if err := proto.Unmarshal(w.Body.Bytes(), &got); err != nil {
+			t.Fatalf("failed to unmarshal protobuf response: %v", err)
+		}
+		if !proto.Equal(&got, bizResponse) {
+			t.Errorf("expected %v, got %v", bizResponse, &got)
+		}
+	})
+}
+
+func TestProcessDataHandlerSpans(t *testing.T) {
+	exporter := tracingtest.NewInMemory()
+
+	clients := &app.Clients{
+		LogClient: &mockLogClient{
+			LogDataGRPCFunc: func(ctx context.Context, entry *gen.LogEntry) (*gen.LogID, error) {
+				return &gen.LogID{Id: "log1"}, nil
+			},
+		},
+		BusinessClient: &mockBizClient{
+			ProcessFunc: func(ctx context.Context, req *gen.OperationRequest) (*gen.OperationResponse, error) {
+				return &gen.OperationResponse{}, nil
+			},
+		},
+	}
+
+	ctx, root := tracing.Start(context.Background(), "POST /process")
+	req := httptest.NewRequest(http.MethodPost, "/process", strings.NewReader("x = 1 + 2; print x")).WithContext(ctx)
+	req.Header.Set("Content-Type", "text/x-hasher")
+
+	ProcessDataHandler(clients)(httptest.NewRecorder(), req, httprouter.Params{})
+	root.End()
+
+	names := map[string]bool{}
+	for _, span := range exporter.GetSpans() {
+		if span.SpanContext.TraceID() != root.SpanContext().TraceID() {
+			t.Errorf("span %q belongs to another trace", span.Name)
+		}
+		names[span.Name] = true
+	}
+	for _, want := range []string{"validate request", "log request", "process operations"} {
+		if !names[want] {
+			t.Errorf("expected span %q, got %v", want, names)
+		}
+	}
+}
@@ -19,6 +19,11 @@ func ReadLogHandler(clients *app.Clients) httprouter.Handle {
 			return
 		}
 
+		if !serviceAvailable(clients.LogClient) {
+			writeJSON(w, http.StatusServiceUnavailable, "Log service unavailable: "+unavailableReason(clients.LogClient))
+			return
+		}
+
 		readLogResponse, err := clients.LogClient.ReadLogGRPC(id, filename)
 		if err != nil {
 			writeJSON(w, http.StatusInternalServerError, "Failed to retrieve log due to server mailfunction: "+err.Error())
@@ -74,6 +79,8 @@ func parseReadResponse(readLogResponse *gen.LogReadingResponse) ([]byte, error)
 		TimestampSend     int64       `json:"timestamp_send"`
 		TimestampReceived int64       `json:"timestamp_received"`
 		DeliveryDelayMs   string      `json:"deliveryDelayMs"`
+		RequestID         string      `json:"request_id,omitempty"`
+		ParentID          string      `json:"parent_id,omitempty"`
 	}
 
 	response := struct {
@@ -91,6 +98,8 @@ func parseReadResponse(readLogResponse *gen.LogReadingResponse) ([]byte, error)
 			TimestampSend:     logEntry.TimestampSend,
 			TimestampReceived: logEntry.TimestampRecv,
 			DeliveryDelayMs:   logEntry.DeliveryDelayMs,
+			RequestID:         logEntry.RequestID,
+			ParentID:          logEntry.ParentID,
 		},
 	}
 
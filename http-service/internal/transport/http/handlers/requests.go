@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/julienschmidt/httprouter"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"http-service/internal/app"
+	"net/http"
+)
+
+// RequestTimelineResponse объединяет лог входящего запроса, лог результата
+// и DOT-описание графа зависимостей. Результата нет, пока запрос не обработан.
+type RequestTimelineResponse struct {
+	RequestID string          `json:"request_id"`
+	Request   json.RawMessage `json:"request"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	Graph     string          `json:"graph,omitempty"`
+}
+
+func RequestTimelineHandler(clients *app.Clients) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		id := ps.ByName("id")
+
+		if !serviceAvailable(clients.LogClient) {
+			writeJSON(w, http.StatusServiceUnavailable, "Log service unavailable: "+unavailableReason(clients.LogClient))
+			return
+		}
+
+		timeline, err := clients.LogClient.GetRequestTimelineGRPC(r.Context(), id)
+		if status.Code(err) == codes.NotFound {
+			writeJSON(w, http.StatusNotFound, "Request "+id+" not found")
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, "Failed to retrieve request timeline: "+err.Error())
+			return
+		}
+
+		resp := RequestTimelineResponse{
+			RequestID: timeline.GetRequestId(),
+			Graph:     timeline.GetGraph(),
+		}
+		if resp.Request, err = expandLog(timeline.GetRequest()); err == nil {
+			resp.Result, err = expandLog(timeline.GetResult())
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, "Failed to parse request timeline: "+err.Error())
+			return
+		}
+
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// expandLog разворачивает строку лога в объект. Поле message лог-сервис
+// записывает строкой с JSON, оно тоже разворачивается.
+func expandLog(line string) (json.RawMessage, error) {
+	if line == "" {
+		return nil, nil
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse log entry: %w", err)
+	}
+
+	if encoded, ok := entry["message"].(string); ok {
+		var message any
+		if err := json.Unmarshal([]byte(encoded), &message); err == nil {
+			entry["message"] = message
+		}
+	}
+
+	return json.Marshal(entry)
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	gen "http-service/gen"
+	"http-service/internal/app"
+	"http-service/internal/client/grpc/resilience"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestTimelineHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockResponse   *gen.RequestTimeline
+		mockError      error
+		unavailable    bool
+		expectedStatus int
+		expectedBody   []string
+	}{
+		{
+			name: "request with result and graph",
+			mockResponse: &gen.RequestTimeline{
+				RequestId: "req123",
+				Request:   `{"id":"req123","request_id":"req123","message":"{\"method\":\"POST\",\"path\":\"/process\"}"}`,
+				Result:    `{"id":"res456","request_id":"req123","parent_id":"req123","message":"{\"result\":{}}"}`,
+				Graph:     "digraph G {}",
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: []string{
+				`"request_id":"req123"`,
+				`"message":{"method":"POST","path":"/process"}`,
+				`"parent_id":"req123"`,
+				`"graph":"digraph G {}"`,
+			},
+		},
+		{
+			name:           "request not processed yet",
+			mockResponse:   &gen.RequestTimeline{RequestId: "req123", Request: `{"id":"req123"}`},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{`"request":{"id":"req123"}`},
+		},
+		{
+			name:           "unknown request",
+			mockError:      status.Error(codes.NotFound, "request req123 not found"),
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   []string{"not found"},
+		},
+		{
+			name:           "gRPC call fails",
+			mockError:      errors.New("internal gRPC error"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   []string{"Failed to retrieve request timeline"},
+		},
+		{
+			name:           "log service unavailable",
+			unavailable:    true,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   []string{"Log service unavailable"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotID string
+			mockClient := &mockLogClient{
+				TimelineFunc: func(ctx context.Context, requestID string) (*gen.RequestTimeline, error) {
+					gotID = requestID
+					return tt.mockResponse, tt.mockError
+				},
+			}
+			if tt.unavailable {
+				mockClient.StatusFunc = func() resilience.Status {
+					return resilience.Status{Name: "log-service", Breaker: resilience.StateOpen}
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/requests/req123", nil)
+			w := httptest.NewRecorder()
+			RequestTimelineHandler(&app.Clients{LogClient: mockClient})(w, req, httprouter.Params{{Key: "id", Value: "req123"}})
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			for _, want := range tt.expectedBody {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("expected body to contain %q, got %q", want, w.Body.String())
+				}
+			}
+			if !tt.unavailable && gotID != "req123" {
+				t.Errorf("expected timeline for req123, got %q", gotID)
+			}
+		})
+	}
+}
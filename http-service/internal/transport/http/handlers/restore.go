@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/julienschmidt/httprouter"
+	"http-service/internal/app"
+	"http-service/internal/auth"
+	"net/http"
+)
+
+// RestoreLogHandler возвращает удалённый лог из корзины лог-сервиса, пока
+// срок восстановления не истёк.
+func RestoreLogHandler(clients *app.Clients) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		id := r.URL.Query().Get("id")
+		filename := r.URL.Query().Get("filename")
+
+		if id == "" || filename == "" {
+			writeJSON(w, http.StatusBadRequest, "Missing query parameters: id and filename are required")
+			return
+		}
+
+		if !serviceAvailable(clients.LogClient) {
+			writeJSON(w, http.StatusServiceUnavailable, "Log service unavailable: "+unavailableReason(clients.LogClient))
+			return
+		}
+
+		restoreResponse, err := clients.LogClient.RestoreLogGRPC(id, filename, auth.KeyName(r.Context()), r.URL.Query().Get("reason"))
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, "Failed to restore log due to server malfunction: "+err.Error())
+			return
+		}
+
+		if !restoreResponse.GetSuccess() {
+			writeJSON(w, http.StatusInternalServerError, "Failed to restore log: "+restoreResponse.Message)
+			return
+		}
+
+		restoreResponseJSON, err := json.Marshal(restoreResponse)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, "Failed to marshal response: "+err.Error())
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(restoreResponseJSON)
+	}
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	gen "http-service/gen"
+	"http-service/internal/app"
+	"http-service/internal/auth"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRestoreLogHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		queryParams    string
+		mockResponse   *gen.LogRestorationResponse
+		mockError      error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "missing query params",
+			queryParams:    "?id=123",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "id and filename are required",
+		},
+		{
+			name:           "gRPC call fails",
+			queryParams:    "?id=123&filename=http_logs.json",
+			mockError:      errors.New("internal gRPC error"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Failed to restore log due to server malfunction",
+		},
+		{
+			name:           "log is not in trash",
+			queryParams:    "?id=123&filename=http_logs.json",
+			mockResponse:   &gen.LogRestorationResponse{Success: false, Message: "log with id 123 is not in trash of http_logs.json"},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "is not in trash",
+		},
+		{
+			name:           "log restored",
+			queryParams:    "?id=123&filename=http_logs.json&reason=mistake",
+			mockResponse:   &gen.LogRestorationResponse{Success: true, Message: "Log with id 123 restored to http_logs.json"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"success":true`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotActor, gotReason string
+			clients := &app.Clients{LogClient: &mockLogClient{
+				RestoreLogFunc: func(id, filename, actor, reason string) (*gen.LogRestorationResponse, error) {
+					gotActor, gotReason = actor, reason
+					return tt.mockResponse, tt.mockError
+				},
+			}}
+
+			req := httptest.NewRequest(http.MethodPost, "/restoreLog"+tt.queryParams, nil)
+			req = req.WithContext(auth.WithKeyName(req.Context(), "ops"))
+			w := httptest.NewRecorder()
+			RestoreLogHandler(clients)(w, req, httprouter.Params{})
+
+			res := w.Result()
+			defer res.Body.Close()
+			body, _ := io.ReadAll(res.Body)
+
+			if res.StatusCode != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, res.StatusCode)
+			}
+			if !strings.Contains(string(body), tt.expectedBody) {
+				t.Errorf("expected body to contain %q, got %q", tt.expectedBody, string(body))
+			}
+			if tt.expectedStatus == http.StatusOK && (gotActor != "ops" || gotReason != "mistake") {
+				t.Errorf("expected key name and reason to be passed for the audit trail, got %q, %q", gotActor, gotReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/julienschmidt/httprouter"
+	gen "http-service/gen"
+	"http-service/internal/app"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Как часто в тихий поток пишется комментарий, чтобы прокси не закрыли соединение
+var tailKeepAlive = 15 * time.Second
+
+type LogTailEvent struct {
+	Filename string          `json:"filename"`
+	Log      json.RawMessage `json:"log"`
+	// Сколько записей пропущено перед этой, потому что клиент не успевал читать
+	Dropped int64 `json:"dropped,omitempty"`
+}
+
+// TailLogsHandler передаёт новые логи как Server-Sent Events: событие log
+// с id записи, так что EventSource после обрыва продолжит с Last-Event-ID.
+// Ошибка лог-сервиса после начала потока приходит событием error, и поток
+// закрывается.
+func TailLogsHandler(clients *app.Clients) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		query, err := parseLogTailQuery(r.URL.Query())
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, "Invalid tail query: "+err.Error())
+			return
+		}
+		if query.AfterId == "" && query.Since == 0 {
+			query.AfterId = r.Header.Get("Last-Event-ID")
+		}
+
+		if !serviceAvailable(clients.LogClient) {
+			writeJSON(w, http.StatusServiceUnavailable, "Log service unavailable: "+unavailableReason(clients.LogClient))
+			return
+		}
+
+		disableWriteDeadline(w)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		flusher, _ := w.(http.Flusher)
+		flush := func() {
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		flush()
+
+		// Поток читается в отдельной горутине, а пишет в ответ только эта,
+		// чтобы события и комментарии keepalive не перемешались
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		events := make(chan *gen.LogTailEvent)
+		errc := make(chan error, 1)
+		go func() {
+			errc <- clients.LogClient.TailLogsGRPC(ctx, query, func(e *gen.LogTailEvent) error {
+				select {
+				case events <- e:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			})
+		}()
+
+		keepAlive := time.NewTicker(tailKeepAlive)
+		defer keepAlive.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-errc:
+				if err != nil && ctx.Err() == nil {
+					data, _ := json.Marshal(map[string]string{"error": "Failed to tail logs: " + err.Error()})
+					fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+					flush()
+				}
+				return
+			case e := <-events:
+				entry, err := expandLog(e.GetLog())
+				if err != nil {
+					continue
+				}
+				data, _ := json.Marshal(LogTailEvent{Filename: e.GetFilename(), Log: entry, Dropped: e.GetDropped()})
+				fmt.Fprintf(w, "id: %s\nevent: log\ndata: %s\n\n", logEventID(entry), data)
+				flush()
+			case <-keepAlive.C:
+				fmt.Fprint(w, ": keepalive\n\n")
+				flush()
+			}
+		}
+	}
+}
+
+// logEventID возвращает id записи без переводов строк, которые разорвали бы поле SSE.
+func logEventID(entry json.RawMessage) string {
+	var fields struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(entry, &fields)
+	return strings.NewReplacer("\n", "", "\r", "").Replace(fields.ID)
+}
+
+// parseLogTailQuery принимает те же фильтры, что и поиск, и начало потока:
+// since (RFC 3339) или after_id.
+func parseLogTailQuery(values url.Values) (*gen.LogTailQuery, error) {
+	search, err := parseLogSearchQuery(values)
+	if err != nil {
+		return nil, err
+	}
+	query := &gen.LogTailQuery{
+		Sources:       search.Sources,
+		Levels:        search.Levels,
+		Path:          search.Path,
+		Variable:      search.Variable,
+		MinDelayMs:    search.MinDelayMs,
+		Text:          search.Text,
+		MetadataKey:   values.Get("metadata_key"),
+		MetadataValue: values.Get("metadata_value"),
+		AfterId:       values.Get("after_id"),
+	}
+	if query.Since, err = parseTimeParam(values, "since"); err != nil {
+		return nil, err
+	}
+	if query.Since != 0 && query.AfterId != "" {
+		return nil, fmt.Errorf("since and after_id are mutually exclusive")
+	}
+	return query, nil
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"github.com/julienschmidt/httprouter"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	gen "http-service/gen"
+	"http-service/internal/app"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTailLogsHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		lastEventID    string
+		events         []*gen.LogTailEvent
+		mockError      error
+		expectedQuery  *gen.LogTailQuery
+		expectedStatus int
+		expectedBody   []string
+	}{
+		{
+			name:  "filters are passed and entries streamed as events",
+			query: "?source=HTTP-server&level=info&path=/process&var=total&min_delay=250ms&q=timeout&metadata_key=tenant&metadata_value=acme&since=2025-06-01T00:00:00Z",
+			events: []*gen.LogTailEvent{
+				{Filename: "http_logs.json", Log: `{"id":"req1","message":"{\"path\":\"/process\"}"}`},
+				{Filename: "http_logs.json", Log: `{"id":"req2"}`, Dropped: 3},
+			},
+			expectedQuery: &gen.LogTailQuery{
+				Sources:       []string{"HTTP-server"},
+				Levels:        []string{"info"},
+				Path:          "/process",
+				Variable:      "total",
+				MinDelayMs:    250,
+				Text:          "timeout",
+				MetadataKey:   "tenant",
+				MetadataValue: "acme",
+				Since:         1748736000000,
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: []string{
+				"id: req1\nevent: log\ndata: {\"filename\":\"http_logs.json\",\"log\":{\"id\":\"req1\",\"message\":{\"path\":\"/process\"}}}\n\n",
+				"id: req2\nevent: log\ndata: {\"filename\":\"http_logs.json\",\"log\":{\"id\":\"req2\"},\"dropped\":3}\n\n",
+			},
+		},
+		{
+			name:           "reconnect resumes after Last-Event-ID",
+			lastEventID:    "req2",
+			expectedQuery:  &gen.LogTailQuery{AfterId: "req2"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "explicit after_id wins over Last-Event-ID",
+			query:          "?after_id=req1",
+			lastEventID:    "req2",
+			expectedQuery:  &gen.LogTailQuery{AfterId: "req1"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "log service error ends the stream with an error event",
+			events:         []*gen.LogTailEvent{{Filename: "http_logs.json", Log: `{"id":"req1"}`}},
+			mockError:      status.Error(codes.NotFound, "log with id missing not found"),
+			expectedQuery:  &gen.LogTailQuery{},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{"event: log", "event: error\ndata: {\"error\":\"Failed to tail logs: rpc error: code = NotFound desc = log with id missing not found\"}\n\n"},
+		},
+		{
+			name:           "invalid since",
+			query:          "?since=yesterday",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   []string{"since must be an RFC 3339 time"},
+		},
+		{
+			name:           "since with after_id",
+			query:          "?since=2025-06-01T00:00:00Z&after_id=req1",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   []string{"mutually exclusive"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotQuery *gen.LogTailQuery
+			mockClient := &mockLogClient{
+				TailFunc: func(ctx context.Context, query *gen.LogTailQuery, onEvent func(*gen.LogTailEvent) error) error {
+					gotQuery = query
+					for _, e := range tt.events {
+						if err := onEvent(e); err != nil {
+							return err
+						}
+					}
+					return tt.mockError
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/logs/tail"+tt.query, nil)
+			if tt.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastEventID)
+			}
+			w := httptest.NewRecorder()
+			TailLogsHandler(&app.Clients{LogClient: mockClient})(w, req, httprouter.Params{})
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if w.Code == http.StatusOK && w.Header().Get("Content-Type") != "text/event-stream" {
+				t.Errorf("expected event stream, got %q", w.Header().Get("Content-Type"))
+			}
+			for _, want := range tt.expectedBody {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("expected body to contain %q, got %q", want, w.Body.String())
+				}
+			}
+			if tt.expectedQuery != nil && !proto.Equal(gotQuery, tt.expectedQuery) {
+				t.Errorf("expected query %v, got %v", tt.expectedQuery, gotQuery)
+			}
+		})
+	}
+}
+
+func TestTailLogsHandlerKeepAlive(t *testing.T) {
+	defer func(d time.Duration) { tailKeepAlive = d }(tailKeepAlive)
+	tailKeepAlive = 5 * time.Millisecond
+
+	mockClient := &mockLogClient{
+		TailFunc: func(ctx context.Context, query *gen.LogTailQuery, onEvent func(*gen.LogTailEvent) error) error {
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/logs/tail", nil)
+	w := httptest.NewRecorder()
+	TailLogsHandler(&app.Clients{LogClient: mockClient})(w, req, httprouter.Params{})
+
+	if !strings.Contains(w.Body.String(), ": keepalive\n\n") {
+		t.Errorf("expected keepalive comments in a quiet stream, got %q", w.Body.String())
+	}
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/julienschmidt/httprouter"
+	"http-service/internal/app"
+	"http-service/internal/auth"
+	"http-service/internal/jobs"
+	"http-service/internal/webhook"
+	"log"
+	"net/http"
+)
+
+const (
+	EventProcessCompleted = "process.completed"
+	EventJobSucceeded     = "job.succeeded"
+	EventJobFailed        = "job.failed"
+)
+
+type WebhookResponse struct {
+	Success    bool                `json:"success"`
+	Status     int                 `json:"status"`
+	Message    string              `json:"message,omitempty"`
+	Error      string              `json:"error,omitempty"`
+	Delivery   *webhook.Delivery   `json:"delivery,omitempty"`
+	Deliveries []*webhook.Delivery `json:"deliveries,omitempty"`
+}
+
+// callbackURL достаёт необязательный параметр ?callback_url=. Он передаётся в
+// строке запроса, чтобы работать одинаково для всех форматов тела.
+func callbackURL(r *http.Request, clients *app.Clients) (string, error) {
+	raw := r.URL.Query().Get("callback_url")
+	if raw == "" {
+		return "", nil
+	}
+	if isNil(clients.Webhooks) {
+		return "", errors.New("callback_url is not supported: webhooks are not configured")
+	}
+	if err := clients.Webhooks.ValidateURL(r.Context(), raw); err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// notifyCallback ставит ответ /process в очередь на отправку от имени ключа
+// owner и записывает в него идентификатор доставки, по которому её можно
+// найти и повторить.
+func notifyCallback(clients *app.Clients, owner, url string, resp *CompositeResponse) {
+	if url == "" {
+		return
+	}
+	delivery, err := clients.Webhooks.Enqueue(owner, url, EventProcessCompleted, resp)
+	if err != nil {
+		resp.CallbackError = err.Error()
+		return
+	}
+	resp.CallbackDeliveryID = delivery.ID
+}
+
+// NewJobNotifier отправляет результат завершённой задачи на её callback_url
+// в том же виде, в каком его вернул бы /process.
+func NewJobNotifier(clients *app.Clients) func(job *jobs.Job) {
+	return func(job *jobs.Job) {
+		if job.CallbackURL == "" || isNil(clients.Webhooks) {
+			return
+		}
+
+		event := EventJobSucceeded
+		if job.Status != jobs.StatusSucceeded {
+			event = EventJobFailed
+		}
+		if _, err := clients.Webhooks.Enqueue(job.Owner, job.CallbackURL, event, jobCompositeResponse(job)); err != nil {
+			log.Printf("failed to enqueue webhook for job %s: %v", job.ID, err)
+		}
+	}
+}
+
+func jobCompositeResponse(job *jobs.Job) CompositeResponse {
+	if job.Status != jobs.StatusSucceeded {
+		return CompositeResponse{
+			Success:      false,
+			Status:       http.StatusInternalServerError,
+			Message:      fmt.Sprintf("Job %s", job.Status),
+			JobID:        job.ID,
+			ProcessError: job.Error,
+		}
+	}
+
+	resp := CompositeResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Job succeeded",
+		JobID:   job.ID,
+	}
+	if result := job.Result; result != nil {
+		resp.LogID = result.LogID
+		resp.ResultID = result.ResultID
+		resp.LogError = result.LogError
+		resp.Items = result.Items
+		resp.Warning = result.Warning
+		resp.ProcessingDuration = result.ProcessingDuration
+	}
+	return resp
+}
+
+func ListWebhookDeliveriesHandler(clients *app.Clients) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if isNil(clients.Webhooks) {
+			writeWebhookError(w, http.StatusServiceUnavailable, "Webhooks are not configured", nil)
+			return
+		}
+
+		status := webhook.Status(r.URL.Query().Get("status"))
+		switch status {
+		case "", webhook.StatusPending, webhook.StatusDelivered, webhook.StatusFailed:
+		default:
+			writeWebhookError(w, http.StatusBadRequest, "Invalid status filter", fmt.Errorf("unknown status %q", status))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, WebhookResponse{
+			Success:    true,
+			Status:     http.StatusOK,
+			Deliveries: clients.Webhooks.List(auth.KeyName(r.Context()), status),
+		})
+	}
+}
+
+func GetWebhookDeliveryHandler(clients *app.Clients) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if isNil(clients.Webhooks) {
+			writeWebhookError(w, http.StatusServiceUnavailable, "Webhooks are not configured", nil)
+			return
+		}
+
+		delivery, err := clients.Webhooks.Get(ps.ByName("id"), auth.KeyName(r.Context()))
+		if errors.Is(err, webhook.ErrNotFound) {
+			writeWebhookError(w, http.StatusNotFound, "Delivery not found", err)
+			return
+		}
+		if err != nil {
+			writeWebhookError(w, http.StatusInternalServerError, "Failed to read delivery", err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, WebhookResponse{Success: true, Status: http.StatusOK, Delivery: delivery})
+	}
+}
+
+func ReplayWebhookDeliveryHandler(clients *app.Clients) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if isNil(clients.Webhooks) {
+			writeWebhookError(w, http.StatusServiceUnavailable, "Webhooks are not configured", nil)
+			return
+		}
+
+		delivery, err := clients.Webhooks.Replay(ps.ByName("id"), auth.KeyName(r.Context()))
+		switch {
+		case errors.Is(err, webhook.ErrNotFound):
+			writeWebhookError(w, http.StatusNotFound, "Delivery not found", err)
+		case errors.Is(err, webhook.ErrNotFailed):
+			writeJSON(w, http.StatusConflict, WebhookResponse{
+				Success:  false,
+				Status:   http.StatusConflict,
+				Message:  "Delivery has not failed",
+				Error:    err.Error(),
+				Delivery: delivery,
+			})
+		case err != nil:
+			writeWebhookError(w, http.StatusInternalServerError, "Failed to replay delivery", err)
+		default:
+			writeJSON(w, http.StatusAccepted, WebhookResponse{Success: true, Status: http.StatusAccepted, Message: "Delivery queued", Delivery: delivery})
+		}
+	}
+}
+
+func writeWebhookError(w http.ResponseWriter, status int, message string, err error) {
+	resp := WebhookResponse{Success: false, Status: status, Message: message}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	writeJSON(w, status, resp)
+}
@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/julienschmidt/httprouter"
+	"http-service/gen"
+	"http-service/internal/app"
+	"http-service/internal/auth"
+	"http-service/internal/jobs"
+	"http-service/internal/webhook"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+var webhookSecret = []byte("test-secret")
+
+type callbackReceiver struct {
+	srv      *httptest.Server
+	requests chan callbackRequest
+}
+
+type callbackRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func newCallbackReceiver(t *testing.T, status int) *callbackReceiver {
+	t.Helper()
+	rc := &callbackReceiver{requests: make(chan callbackRequest, 10)}
+	rc.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rc.requests <- callbackRequest{header: r.Header, body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(rc.srv.Close)
+	return rc
+}
+
+func (rc *callbackReceiver) next(t *testing.T) callbackRequest {
+	t.Helper()
+	select {
+	case req := <-rc.requests:
+		if !webhook.Verify(webhookSecret, req.header.Get(webhook.HeaderTimestamp), req.body, req.header.Get(webhook.HeaderSignature)) {
+			t.Error("callback signature does not verify")
+		}
+		return req
+	case <-time.After(2 * time.Second):
+		t.Fatal("callback was not delivered")
+	}
+	return callbackRequest{}
+}
+
+func withWebhooks(t *testing.T, clients *app.Clients, maxAttempts int) *webhook.Dispatcher {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	deliveryLog, _ := webhook.OpenDeliveryLog("")
+	dispatcher := webhook.NewDispatcher(deliveryLog, webhook.Options{
+		Secret:      webhookSecret,
+		MaxAttempts: maxAttempts,
+		Backoff:     time.Millisecond,
+		// Получатели в тестах слушают на loopback
+		URLPolicy: webhook.URLPolicy{AllowedNetworks: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}},
+	})
+	dispatcher.Start(ctx)
+	clients.Webhooks = dispatcher
+	return dispatcher
+}
+
+func TestProcessCallback(t *testing.T) {
+	clients := &app.Clients{BusinessClient: &mockBizClient{
+		ProcessFunc: func(ctx context.Context, req *gen.OperationRequest) (*gen.OperationResponse, error) {
+			return &gen.OperationResponse{Items: []*gen.VariableValue{{Var: "x", Value: 3}}}, nil
+		},
+	}}
+	withWebhooks(t, clients, 1)
+	rc := newCallbackReceiver(t, http.StatusOK)
+
+	req := httptest.NewRequest(http.MethodPost, "/process?callback_url="+rc.srv.URL, strings.NewReader("x = 1 + 2; print x"))
+	req.Header.Set("Content-Type", "text/x-hasher")
+	w := httptest.NewRecorder()
+	ProcessDataHandler(clients)(w, req, httprouter.Params{})
+
+	var resp CompositeResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if w.Code != http.StatusOK || resp.CallbackDeliveryID == "" {
+		t.Fatalf("unexpected response %d: %+v", w.Code, resp)
+	}
+
+	callback := rc.next(t)
+	if callback.header.Get(webhook.HeaderEvent) != EventProcessCompleted || callback.header.Get(webhook.HeaderDelivery) != resp.CallbackDeliveryID {
+		t.Errorf("unexpected callback headers: %v", callback.header)
+	}
+	var payload CompositeResponse
+	if err := json.Unmarshal(callback.body, &payload); err != nil {
+		t.Fatalf("failed to decode callback: %v", err)
+	}
+	if !payload.Success || len(payload.Items) != 1 || payload.Items[0].GetValue() != 3 {
+		t.Errorf("unexpected callback payload: %s", callback.body)
+	}
+}
+
+func TestProcessInvalidCallbackURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		webhooks bool
+		url      string
+		wantMsg  string
+	}{
+		{"relative url", true, "/hook", "callback_url must be an absolute http or https URL"},
+		{"private address", true, "http://10.0.0.1/hook", "must not point to a loopback, private or link-local address"},
+		{"webhooks not configured", false, "http://example.com", "webhooks are not configured"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clients := &app.Clients{BusinessClient: &mockBizClient{}}
+			if tt.webhooks {
+				withWebhooks(t, clients, 1)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/process?callback_url="+tt.url, strings.NewReader("x = 1; print x"))
+			req.Header.Set("Content-Type", "text/x-hasher")
+			w := httptest.NewRecorder()
+			ProcessDataHandler(clients)(w, req, httprouter.Params{})
+
+			if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), tt.wantMsg) {
+				t.Errorf("expected 400 with %q, got %d: %s", tt.wantMsg, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestJobCallback(t *testing.T) {
+	clients := newJobClients(t, &mockBizClient{
+		ProcessStreamFunc: func(ctx context.Context, req *gen.OperationRequest, onProgress func(completed, total int32)) (*gen.OperationResponse, error) {
+			return &gen.OperationResponse{Items: []*gen.VariableValue{{Var: "x", Value: 3}}}, nil
+		},
+	})
+	withWebhooks(t, clients, 1)
+	clients.Jobs.(*jobs.Manager).OnFinish(NewJobNotifier(clients))
+	rc := newCallbackReceiver(t, http.StatusOK)
+
+	req := httptest.NewRequest(http.MethodPost, "/jobs?callback_url="+rc.srv.URL, strings.NewReader("x = 1 + 2; print x"))
+	req.Header.Set("Content-Type", "text/x-hasher")
+	w := httptest.NewRecorder()
+	CreateJobHandler(clients)(w, req, httprouter.Params{})
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var created JobResponse
+	_ = json.NewDecoder(w.Body).Decode(&created)
+
+	callback := rc.next(t)
+	var payload CompositeResponse
+	if err := json.Unmarshal(callback.body, &payload); err != nil {
+		t.Fatalf("failed to decode callback: %v", err)
+	}
+	if callback.header.Get(webhook.HeaderEvent) != EventJobSucceeded || payload.JobID != created.Job.ID || len(payload.Items) != 1 {
+		t.Errorf("unexpected callback %v: %s", callback.header, callback.body)
+	}
+}
+
+func TestReplayWebhookDeliveryHandler(t *testing.T) {
+	clients := &app.Clients{}
+	dispatcher := withWebhooks(t, clients, 1)
+	failing := newCallbackReceiver(t, http.StatusBadGateway)
+
+	delivery, err := dispatcher.Enqueue("", failing.srv.URL, EventProcessCompleted, CompositeResponse{Success: true})
+	if err != nil {
+		t.Fatalf("failed to enqueue: %v", err)
+	}
+	failing.next(t)
+
+	replay := func(id string) (int, WebhookResponse) {
+		w := httptest.NewRecorder()
+		ReplayWebhookDeliveryHandler(clients)(w, httptest.NewRequest(http.MethodPost, "/webhooks/deliveries/"+id+"/replay", nil), httprouter.Params{{Key: "id", Value: id}})
+		var resp WebhookResponse
+		_ = json.NewDecoder(w.Body).Decode(&resp)
+		return w.Code, resp
+	}
+
+	// Доставка ещё может быть pending, пока журнал не обновился после ответа
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		d, _ := dispatcher.Get(delivery.ID, "")
+		if d.Status == webhook.StatusFailed || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	w := httptest.NewRecorder()
+	ListWebhookDeliveriesHandler(clients)(w, httptest.NewRequest(http.MethodGet, "/webhooks/deliveries?status=failed", nil), httprouter.Params{})
+	if !strings.Contains(w.Body.String(), delivery.ID) {
+		t.Errorf("expected failed delivery in list: %s", w.Body.String())
+	}
+
+	if code, resp := replay(delivery.ID); code != http.StatusAccepted || resp.Delivery.Status != webhook.StatusPending {
+		t.Errorf("expected 202 with pending delivery, got %d: %+v", code, resp)
+	}
+	failing.next(t)
+
+	if code, _ := replay("missing"); code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", code)
+	}
+}
+
+func TestWebhookDeliveriesAreScopedToKey(t *testing.T) {
+	clients := &app.Clients{}
+	dispatcher := withWebhooks(t, clients, 1)
+	failing := newCallbackReceiver(t, http.StatusBadGateway)
+
+	delivery, err := dispatcher.Enqueue("alice", failing.srv.URL, EventProcessCompleted, CompositeResponse{Success: true})
+	if err != nil {
+		t.Fatalf("failed to enqueue: %v", err)
+	}
+	failing.next(t)
+
+	as := func(req *http.Request, key string) *http.Request {
+		return req.WithContext(auth.WithKeyName(req.Context(), key))
+	}
+	params := httprouter.Params{{Key: "id", Value: delivery.ID}}
+
+	w := httptest.NewRecorder()
+	ListWebhookDeliveriesHandler(clients)(w, as(httptest.NewRequest(http.MethodGet, "/webhooks/deliveries", nil), "bob"), httprouter.Params{})
+	if strings.Contains(w.Body.String(), delivery.ID) {
+		t.Errorf("expected another key's delivery to be hidden: %s", w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	GetWebhookDeliveryHandler(clients)(w, as(httptest.NewRequest(http.MethodGet, "/webhooks/deliveries/"+delivery.ID, nil), "bob"), params)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for another key's delivery, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	ReplayWebhookDeliveryHandler(clients)(w, as(httptest.NewRequest(http.MethodPost, "/webhooks/deliveries/"+delivery.ID+"/replay", nil), "bob"), params)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 when replaying another key's delivery, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	GetWebhookDeliveryHandler(clients)(w, as(httptest.NewRequest(http.MethodGet, "/webhooks/deliveries/"+delivery.ID, nil), "alice"), params)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected the owner to read the delivery, got %d", w.Code)
+	}
+}
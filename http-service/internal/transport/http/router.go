@@ -5,6 +5,9 @@ import (
 	"github.com/swaggo/http-swagger"
 	_ "http-service/cmd/docs"
 	"http-service/internal/app"
+	"http-service/internal/auth"
+	"http-service/internal/metrics"
+	"http-service/internal/tracing"
 	handlers "http-service/internal/transport/http/handlers"
 	"net/http"
 )
@@ -12,9 +15,31 @@ import (
 func NewRouter(app *app.Clients) *httprouter.Router {
 	router := httprouter.New()
 
-	router.POST("/process", handlers.ProcessDataHandler(app))
-	router.GET("/getLog", handlers.ReadLogHandler(app))
-	router.DELETE("/deleteLog", handlers.DeleteLogHandler(app))
+	// Все маршруты регистрируются через handle, чтобы попасть в метрики и trace под своим шаблоном
+	handle := func(method, path string, h httprouter.Handle) {
+		router.Handle(method, path, metrics.InstrumentRoute(path, tracing.Route(path, h)))
+	}
+
+	handle(http.MethodPost, "/process", handlers.RequireScope(app, auth.ScopeProcess, handlers.WithIdempotency(app, handlers.ProcessDataHandler(app))))
+	handle(http.MethodPost, "/jobs", handlers.RequireScope(app, auth.ScopeProcess, handlers.CreateJobHandler(app)))
+	handle(http.MethodGet, "/jobs/:id", handlers.RequireScope(app, auth.ScopeProcess, handlers.GetJobHandler(app)))
+	handle(http.MethodDelete, "/jobs/:id", handlers.RequireScope(app, auth.ScopeProcess, handlers.CancelJobHandler(app)))
+	handle(http.MethodGet, "/webhooks/deliveries", handlers.RequireScope(app, auth.ScopeProcess, handlers.ListWebhookDeliveriesHandler(app)))
+	handle(http.MethodGet, "/webhooks/deliveries/:id", handlers.RequireScope(app, auth.ScopeProcess, handlers.GetWebhookDeliveryHandler(app)))
+	handle(http.MethodPost, "/webhooks/deliveries/:id/replay", handlers.RequireScope(app, auth.ScopeProcess, handlers.ReplayWebhookDeliveryHandler(app)))
+	handle(http.MethodGet, "/getLog", handlers.RequireScope(app, auth.ScopeLogsRead, handlers.ReadLogHandler(app)))
+	handle(http.MethodDelete, "/deleteLog", handlers.RequireScope(app, auth.ScopeLogsDelete, handlers.DeleteLogHandler(app)))
+	handle(http.MethodPost, "/restoreLog", handlers.RequireScope(app, auth.ScopeLogsDelete, handlers.RestoreLogHandler(app)))
+	handle(http.MethodGet, "/logs", handlers.RequireScope(app, auth.ScopeLogsRead, handlers.SearchLogsHandler(app)))
+	handle(http.MethodDelete, "/logs", handlers.RequireScope(app, auth.ScopeLogsDelete, handlers.DeleteLogsHandler(app)))
+	handle(http.MethodGet, "/logs/tail", handlers.RequireScope(app, auth.ScopeLogsRead, handlers.TailLogsHandler(app)))
+	handle(http.MethodGet, "/requests/:id", handlers.RequireScope(app, auth.ScopeLogsRead, handlers.RequestTimelineHandler(app)))
+	handle(http.MethodGet, "/healthz", handlers.HealthzHandler())
+	handle(http.MethodGet, "/readyz", handlers.ReadyzHandler(app))
+	metricsHandler := metrics.Handler()
+	handle(http.MethodGet, "/metrics", func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		metricsHandler.ServeHTTP(w, r)
+	})
 	router.GET("/swagger/*any", func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 		httpSwagger.WrapHandler.ServeHTTP(w, r)
 	})
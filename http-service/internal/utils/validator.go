@@ -2,7 +2,6 @@ package utils
 
 import (
 	"bytes"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -27,10 +26,6 @@ func generalValidation(r *http.Request) ([]byte, error) {
 		return nil, errors.New("request body is empty")
 	}
 
-	if r.Header.Get("Content-Type") != "application/json" {
-		return nil, errors.New("Content-Type must be application/json")
-	}
-
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read request body: %w", err)
@@ -40,11 +35,6 @@ func generalValidation(r *http.Request) ([]byte, error) {
 		return nil, errors.New("request body is empty")
 	}
 
-	var tmp interface{}
-	if err := json.Unmarshal(body, &tmp); err != nil {
-		return nil, errors.New("invalid JSON")
-	}
-
 	r.Body = io.NopCloser(bytes.NewBuffer(body))
 
 	return body, nil
@@ -23,22 +23,22 @@ func TestGeneralValidation(t *testing.T) {
 			expectedError: "request body is empty",
 		},
 		{
-			name:          "invalid content type",
+			name:          "valid request",
 			body:          `{"key":"value"}`,
-			contentType:   "text/plain",
-			expectedError: "Content-Type must be application/json",
-		},
-		{
-			name:          "invalid JSON",
-			body:          `{"key":}`, // плохой JSON
 			contentType:   "application/json",
-			expectedError: "invalid JSON",
+			expectedError: "", // всё ок
 		},
 		{
-			name:          "valid request",
+			name:          "json with charset",
 			body:          `{"key":"value"}`,
-			contentType:   "application/json",
-			expectedError: "", // всё ок
+			contentType:   "application/json; charset=utf-8",
+			expectedError: "",
+		},
+		{
+			name:          "hasher program is not checked as JSON",
+			body:          "x = 1 + 2; print x",
+			contentType:   "text/x-hasher",
+			expectedError: "",
 		},
 	}
 
@@ -89,20 +89,6 @@ func TestValidateHttpRequest(t *testing.T) {
 			contentType:   "application/json",
 			expectedError: "only POST requests allowed",
 		},
-		{
-			name:          "invalid content type",
-			method:        http.MethodPost,
-			body:          `{"key":"value"}`,
-			contentType:   "text/plain",
-			expectedError: "Content-Type must be application/json",
-		},
-		{
-			name:          "invalid JSON",
-			method:        http.MethodPost,
-			body:          `{"key":}`,
-			contentType:   "application/json",
-			expectedError: "invalid JSON",
-		},
 		{
 			name:          "valid request",
 			method:        http.MethodPost,
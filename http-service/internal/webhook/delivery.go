@@ -0,0 +1,38 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusDelivered Status = "delivered"
+	StatusFailed    Status = "failed"
+)
+
+type Delivery struct {
+	ID          string          `json:"id"`
+	Event       string          `json:"event"`
+	URL         string          `json:"url"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      Status          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	LastError   string          `json:"last_error,omitempty"`
+	LastCode    int             `json:"last_status_code,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	DeliveredAt *time.Time      `json:"delivered_at,omitempty"`
+	// Имя API-ключа, запрос которого создал доставку; доставки других ключей ему не видны
+	Owner string `json:"owner,omitempty"`
+}
+
+func newDeliveryID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
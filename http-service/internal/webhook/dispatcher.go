@@ -0,0 +1,258 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var ErrNotFailed = errors.New("only failed deliveries can be replayed")
+
+type Options struct {
+	Secret      []byte
+	MaxAttempts int
+	// Backoff — пауза перед второй попыткой, дальше она удваивается до MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	Workers    int
+	// Куда разрешено отправлять; применяется в ValidateURL и в клиенте по умолчанию
+	URLPolicy URLPolicy
+	// Клиент, заданный явно, отвечает за проверку адресов сам
+	Client *http.Client
+}
+
+// Dispatcher отправляет подписанные POST-запросы на callback_url и повторяет
+// их с экспоненциальной задержкой. Любой ответ кроме 2xx считается неудачей.
+type Dispatcher struct {
+	log  *DeliveryLog
+	opts Options
+	sem  chan struct{}
+
+	mu  sync.Mutex
+	ctx context.Context
+}
+
+func NewDispatcher(deliveryLog *DeliveryLog, opts Options) *Dispatcher {
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = 1
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = time.Second
+	}
+	if opts.MaxBackoff < opts.Backoff {
+		opts.MaxBackoff = opts.Backoff
+	}
+	if opts.Workers < 1 {
+		opts.Workers = 1
+	}
+	if opts.Client == nil {
+		opts.Client = opts.URLPolicy.Client(10 * time.Second)
+	}
+	return &Dispatcher{
+		log:  deliveryLog,
+		opts: opts,
+		sem:  make(chan struct{}, opts.Workers),
+	}
+}
+
+// ValidateURL проверяет callback_url по URLPolicy до того, как задача будет принята.
+func (d *Dispatcher) ValidateURL(ctx context.Context, raw string) error {
+	return d.opts.URLPolicy.Validate(ctx, raw)
+}
+
+// Start возобновляет доставки, не завершённые до перезапуска.
+// Доставки останавливаются вместе с ctx и остаются в статусе pending.
+func (d *Dispatcher) Start(ctx context.Context) {
+	d.mu.Lock()
+	d.ctx = ctx
+	d.mu.Unlock()
+
+	for _, delivery := range d.log.List(StatusPending) {
+		go d.deliver(ctx, delivery)
+	}
+}
+
+// Enqueue сохраняет доставку владельца owner в журнал и отправляет её в фоне.
+func (d *Dispatcher) Enqueue(owner, url, event string, payload any) (*Delivery, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	now := time.Now().UTC()
+	delivery := &Delivery{
+		ID:        newDeliveryID(),
+		Owner:     owner,
+		Event:     event,
+		URL:       url,
+		Payload:   body,
+		Status:    StatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := d.log.Save(delivery); err != nil {
+		return nil, err
+	}
+
+	// Горутина доставки меняет delivery, вызывающему отдаём копию
+	queued := *delivery
+	d.launch(delivery)
+	return &queued, nil
+}
+
+// Replay заново отправляет доставку владельца owner, исчерпавшую все попытки.
+func (d *Dispatcher) Replay(id, owner string) (*Delivery, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delivery, err := d.Get(id, owner)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status != StatusFailed {
+		return delivery, ErrNotFailed
+	}
+
+	delivery.Status = StatusPending
+	delivery.Attempts = 0
+	delivery.UpdatedAt = time.Now().UTC()
+	if err := d.log.Save(delivery); err != nil {
+		return nil, err
+	}
+
+	queued := *delivery
+	if d.ctx != nil {
+		go d.deliver(d.ctx, delivery)
+	}
+	return &queued, nil
+}
+
+// Get возвращает доставку владельца owner. Доставка другого владельца не
+// отличается от несуществующей: ErrNotFound.
+func (d *Dispatcher) Get(id, owner string) (*Delivery, error) {
+	delivery, err := d.log.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Owner != owner {
+		return nil, ErrNotFound
+	}
+	return delivery, nil
+}
+
+// List возвращает доставки владельца owner с указанным статусом (все, если status пустой).
+func (d *Dispatcher) List(owner string, status Status) []*Delivery {
+	var list []*Delivery
+	for _, delivery := range d.log.List(status) {
+		if delivery.Owner == owner {
+			list = append(list, delivery)
+		}
+	}
+	return list
+}
+
+func (d *Dispatcher) launch(delivery *Delivery) {
+	d.mu.Lock()
+	ctx := d.ctx
+	d.mu.Unlock()
+
+	// До Start доставка только записывается в журнал, Start её подхватит.
+	if ctx != nil {
+		go d.deliver(ctx, delivery)
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery *Delivery) {
+	for delivery.Attempts < d.opts.MaxAttempts {
+		if delivery.Attempts > 0 {
+			timer := time.NewTimer(d.backoff(delivery.Attempts))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+
+		code, err := d.attempt(ctx, delivery)
+		if ctx.Err() != nil {
+			// Попытку прервала остановка сервиса, она не засчитывается
+			return
+		}
+
+		now := time.Now().UTC()
+		delivery.Attempts++
+		delivery.LastCode = code
+		delivery.UpdatedAt = now
+		if err == nil {
+			delivery.Status = StatusDelivered
+			delivery.LastError = ""
+			delivery.DeliveredAt = &now
+			d.save(delivery)
+			return
+		}
+
+		delivery.LastError = err.Error()
+		if delivery.Attempts >= d.opts.MaxAttempts {
+			delivery.Status = StatusFailed
+		}
+		d.save(delivery)
+	}
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, delivery *Delivery) (int, error) {
+	select {
+	case d.sem <- struct{}{}:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+	defer func() { <-d.sem }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "hasher-webhook/1.0")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(d.opts.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.opts.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff возвращает паузу перед попыткой attempts+1.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.opts.Backoff
+	for i := 1; i < attempts && delay < d.opts.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, d.opts.MaxBackoff)
+}
+
+func (d *Dispatcher) save(delivery *Delivery) {
+	if err := d.log.Save(delivery); err != nil {
+		log.Printf("failed to save webhook delivery %s: %v", delivery.ID, err)
+	}
+}
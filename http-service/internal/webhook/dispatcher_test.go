@@ -0,0 +1,297 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testSecret = []byte("s3cret")
+
+type receiver struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+	// failures — сколько первых запросов получат 500
+	failures atomic.Int32
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rc.mu.Lock()
+	rc.requests = append(rc.requests, r)
+	rc.bodies = append(rc.bodies, body)
+	rc.mu.Unlock()
+
+	if rc.failures.Add(-1) >= 0 {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rc *receiver) count() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return len(rc.requests)
+}
+
+func newTestDispatcher(t *testing.T, path string, maxAttempts int) *Dispatcher {
+	t.Helper()
+	deliveryLog, err := OpenDeliveryLog(path)
+	if err != nil {
+		t.Fatalf("failed to open delivery log: %v", err)
+	}
+	t.Cleanup(func() { deliveryLog.Close() })
+	return NewDispatcher(deliveryLog, Options{
+		Secret:      testSecret,
+		MaxAttempts: maxAttempts,
+		Backoff:     time.Millisecond,
+		MaxBackoff:  4 * time.Millisecond,
+		Workers:     2,
+		// Тестовые получатели слушают на loopback
+		URLPolicy: URLPolicy{AllowedNetworks: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}},
+	})
+}
+
+func waitForDelivery(t *testing.T, d *Dispatcher, id string, want Status) *Delivery {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		delivery, err := d.Get(id, "")
+		if err != nil {
+			t.Fatalf("failed to get delivery: %v", err)
+		}
+		if delivery.Status == want {
+			return delivery
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	delivery, _ := d.Get(id, "")
+	t.Fatalf("delivery %s: expected status %s, got %s", id, want, delivery.Status)
+	return nil
+}
+
+func TestDispatcherSignsPayload(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rc := &receiver{}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	d := newTestDispatcher(t, "", 3)
+	d.Start(ctx)
+
+	delivery, err := d.Enqueue("", srv.URL, "process.completed", map[string]any{"success": true})
+	if err != nil {
+		t.Fatalf("failed to enqueue: %v", err)
+	}
+	done := waitForDelivery(t, d, delivery.ID, StatusDelivered)
+	if done.Attempts != 1 || done.LastCode != http.StatusNoContent || done.DeliveredAt == nil {
+		t.Errorf("unexpected delivery: %+v", done)
+	}
+
+	req, body := rc.requests[0], rc.bodies[0]
+	if string(body) != `{"success":true}` {
+		t.Errorf("unexpected body: %s", body)
+	}
+	if req.Header.Get(HeaderEvent) != "process.completed" || req.Header.Get(HeaderDelivery) != delivery.ID {
+		t.Errorf("unexpected headers: %v", req.Header)
+	}
+	if !Verify(testSecret, req.Header.Get(HeaderTimestamp), body, req.Header.Get(HeaderSignature)) {
+		t.Error("signature does not verify")
+	}
+	if Verify([]byte("other"), req.Header.Get(HeaderTimestamp), body, req.Header.Get(HeaderSignature)) {
+		t.Error("signature verifies with a wrong secret")
+	}
+}
+
+func TestDispatcherRetriesWithBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rc := &receiver{}
+	rc.failures.Store(2)
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	d := newTestDispatcher(t, "", 5)
+	d.Start(ctx)
+
+	delivery, _ := d.Enqueue("", srv.URL, "job.succeeded", "done")
+	done := waitForDelivery(t, d, delivery.ID, StatusDelivered)
+	if done.Attempts != 3 || rc.count() != 3 {
+		t.Errorf("expected 3 attempts, got %d (receiver saw %d)", done.Attempts, rc.count())
+	}
+}
+
+func TestDispatcherBackoff(t *testing.T) {
+	d := NewDispatcher(&DeliveryLog{}, Options{Backoff: time.Second, MaxBackoff: 5 * time.Second})
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := d.backoff(i + 1); got != w {
+			t.Errorf("attempt %d: expected %v, got %v", i+1, w, got)
+		}
+	}
+}
+
+func TestDispatcherReplayFailedDelivery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rc := &receiver{}
+	rc.failures.Store(2)
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	d := newTestDispatcher(t, "", 2)
+	d.Start(ctx)
+
+	delivery, _ := d.Enqueue("", srv.URL, "process.completed", "payload")
+	failed := waitForDelivery(t, d, delivery.ID, StatusFailed)
+	if failed.Attempts != 2 || failed.LastCode != http.StatusInternalServerError || failed.LastError == "" {
+		t.Errorf("unexpected failed delivery: %+v", failed)
+	}
+	if list := d.List("", StatusFailed); len(list) != 1 || list[0].ID != delivery.ID {
+		t.Errorf("expected failed delivery in list, got %+v", list)
+	}
+
+	if _, err := d.Replay(delivery.ID, ""); err != nil {
+		t.Fatalf("failed to replay: %v", err)
+	}
+	waitForDelivery(t, d, delivery.ID, StatusDelivered)
+
+	if _, err := d.Replay(delivery.ID, ""); !errors.Is(err, ErrNotFailed) {
+		t.Errorf("expected ErrNotFailed, got %v", err)
+	}
+	if _, err := d.Replay("missing", ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDispatcherResumesPendingDeliveries(t *testing.T) {
+	rc := &receiver{}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "deliveries.jsonl")
+
+	// Без Start доставка только записывается в журнал
+	first := newTestDispatcher(t, path, 3)
+	delivery, err := first.Enqueue("", srv.URL, "process.completed", "payload")
+	if err != nil {
+		t.Fatalf("failed to enqueue: %v", err)
+	}
+	first.log.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	second := newTestDispatcher(t, path, 3)
+	second.Start(ctx)
+	waitForDelivery(t, second, delivery.ID, StatusDelivered)
+
+	// Итоговый статус тоже переживает перезапуск
+	second.log.Close()
+	reopened, err := OpenDeliveryLog(path)
+	if err != nil {
+		t.Fatalf("failed to reopen delivery log: %v", err)
+	}
+	defer reopened.Close()
+	stored, err := reopened.Get(delivery.ID)
+	if err != nil || stored.Status != StatusDelivered {
+		t.Errorf("expected delivered status after reopen, got %+v, %v", stored, err)
+	}
+}
+
+type fakeResolver map[string][]netip.Addr
+
+func (r fakeResolver) LookupNetIP(_ context.Context, _, host string) ([]netip.Addr, error) {
+	addrs, ok := r[strings.ToLower(host)]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return addrs, nil
+}
+
+func TestValidateURL(t *testing.T) {
+	resolver := fakeResolver{
+		"example.com":       {netip.MustParseAddr("93.184.215.14")},
+		"hooks.example.com": {netip.MustParseAddr("93.184.215.15")},
+		"localhost":         {netip.MustParseAddr("127.0.0.1"), netip.MustParseAddr("::1")},
+		"rebind.example":    {netip.MustParseAddr("93.184.215.14"), netip.MustParseAddr("10.0.0.5")},
+		"internal.corp":     {netip.MustParseAddr("10.1.2.3")},
+	}
+
+	tests := []struct {
+		name   string
+		policy URLPolicy
+		url    string
+		want   error
+	}{
+		{"public host", URLPolicy{}, "https://example.com/hook", nil},
+		{"public ip", URLPolicy{}, "http://93.184.215.14:9000", nil},
+		{"not http", URLPolicy{}, "ftp://example.com", ErrInvalidURL},
+		{"relative", URLPolicy{}, "/relative", ErrInvalidURL},
+		{"no host", URLPolicy{}, "https://", ErrInvalidURL},
+		{"unparsable", URLPolicy{}, "::", ErrInvalidURL},
+		{"localhost", URLPolicy{}, "http://localhost:9000", ErrForbiddenAddress},
+		{"loopback ip", URLPolicy{}, "http://127.0.0.1/hook", ErrForbiddenAddress},
+		{"loopback ipv6", URLPolicy{}, "http://[::1]/hook", ErrForbiddenAddress},
+		{"mapped loopback", URLPolicy{}, "http://[::ffff:127.0.0.1]/hook", ErrForbiddenAddress},
+		{"private ip", URLPolicy{}, "http://192.168.1.10/hook", ErrForbiddenAddress},
+		{"metadata endpoint", URLPolicy{}, "http://169.254.169.254/latest/meta-data", ErrForbiddenAddress},
+		{"unspecified", URLPolicy{}, "http://0.0.0.0/hook", ErrForbiddenAddress},
+		{"one private address", URLPolicy{}, "http://rebind.example/hook", ErrForbiddenAddress},
+		{"allowed network", URLPolicy{AllowedNetworks: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}}, "http://internal.corp/hook", nil},
+		{"allowed host", URLPolicy{AllowedHosts: []string{"example.com"}}, "https://EXAMPLE.com/hook", nil},
+		{"allowed subdomain", URLPolicy{AllowedHosts: []string{"*.example.com"}}, "https://hooks.example.com/hook", nil},
+		{"wildcard excludes apex", URLPolicy{AllowedHosts: []string{"*.example.com"}}, "https://example.com/hook", ErrHostNotAllowed},
+		{"host not allowed", URLPolicy{AllowedHosts: []string{"example.com"}}, "https://hooks.example.com/hook", ErrHostNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.policy.Resolver = resolver
+			if err := tt.policy.Validate(context.Background(), tt.url); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if err := (URLPolicy{Resolver: resolver}).Validate(context.Background(), "http://unknown.example/hook"); err == nil {
+		t.Error("expected unresolvable host to be rejected")
+	}
+}
+
+func TestDeliveryRechecksAddressOnDial(t *testing.T) {
+	rc := &receiver{}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	// Проверка при соединении срабатывает, даже если URL не прошёл через ValidateURL
+	deliveryLog, err := OpenDeliveryLog("")
+	if err != nil {
+		t.Fatalf("failed to open delivery log: %v", err)
+	}
+	d := NewDispatcher(deliveryLog, Options{Secret: testSecret, MaxAttempts: 1, Backoff: time.Millisecond})
+	d.Start(context.Background())
+
+	delivery, err := d.Enqueue("", srv.URL, "test", map[string]string{"a": "b"})
+	if err != nil {
+		t.Fatalf("failed to enqueue: %v", err)
+	}
+	failed := waitForDelivery(t, d, delivery.ID, StatusFailed)
+	if !strings.Contains(failed.LastError, ErrForbiddenAddress.Error()) || rc.count() != 0 {
+		t.Errorf("expected delivery to loopback to be refused at dial time, got %+v with %d requests", failed, rc.count())
+	}
+}
@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+var ErrNotFound = errors.New("delivery not found")
+
+// DeliveryLog — журнал доставок. Каждое изменение состояния дописывается
+// отдельной JSON-строкой, при загрузке последняя запись по id побеждает.
+type DeliveryLog struct {
+	mu         sync.RWMutex
+	file       *os.File
+	deliveries map[string]*Delivery
+}
+
+// OpenDeliveryLog открывает журнал по пути path. Пустой путь — журнал только в памяти.
+func OpenDeliveryLog(path string) (*DeliveryLog, error) {
+	l := &DeliveryLog{deliveries: make(map[string]*Delivery)}
+	if path == "" {
+		return l, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create delivery log directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open delivery log: %w", err)
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var d Delivery
+		if err := json.Unmarshal(scanner.Bytes(), &d); err != nil {
+			continue // Недописанная строка после падения
+		}
+		l.deliveries[d.ID] = &d
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read delivery log: %w", err)
+	}
+
+	l.file = file
+	return l, nil
+}
+
+func (l *DeliveryLog) Save(d *Delivery) error {
+	clone := *d
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file != nil {
+		line, err := json.Marshal(&clone)
+		if err != nil {
+			return err
+		}
+		if _, err := l.file.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("failed to append to delivery log: %w", err)
+		}
+	}
+	l.deliveries[d.ID] = &clone
+	return nil
+}
+
+func (l *DeliveryLog) Get(id string) (*Delivery, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	d, ok := l.deliveries[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	clone := *d
+	return &clone, nil
+}
+
+// List возвращает доставки с указанным статусом (все, если status пустой), старые первыми.
+func (l *DeliveryLog) List(status Status) []*Delivery {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var list []*Delivery
+	for _, d := range l.deliveries {
+		if status == "" || d.Status == status {
+			clone := *d
+			list = append(list, &clone)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+	return list
+}
+
+func (l *DeliveryLog) Close() error {
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
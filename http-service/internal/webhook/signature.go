@@ -0,0 +1,37 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+const (
+	HeaderSignature = "X-Hasher-Signature"
+	HeaderTimestamp = "X-Hasher-Timestamp"
+	HeaderEvent     = "X-Hasher-Event"
+	HeaderDelivery  = "X-Hasher-Delivery"
+
+	signaturePrefix = "sha256="
+)
+
+// Sign подписывает "<timestamp>.<body>", чтобы получатель мог отбросить повторно
+// отправленные злоумышленником старые запросы.
+func Sign(secret []byte, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись из заголовка X-Hasher-Signature.
+func Verify(secret []byte, timestamp string, body []byte, signature string) bool {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, ts, body)), []byte(signature))
+}
@@ -0,0 +1,141 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrInvalidURL       = errors.New("callback_url must be an absolute http or https URL")
+	ErrHostNotAllowed   = errors.New("callback_url host is not in the webhook allowlist")
+	ErrForbiddenAddress = errors.New("callback_url must not point to a loopback, private or link-local address")
+)
+
+// Resolver — часть net.Resolver, через которую URLPolicy узнаёт адреса хоста.
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// URLPolicy решает, куда можно отправлять webhook. По умолчанию разрешён любой
+// хост с публичными адресами; loopback, приватные и link-local адреса
+// запрещены, чтобы callback_url нельзя было направить во внутреннюю сеть.
+type URLPolicy struct {
+	// Если список не пуст, разрешены только эти хосты; "*.example.com"
+	// разрешает поддомены example.com
+	AllowedHosts []string
+	// Внутренние сети, куда отправлять можно, несмотря на запрет выше
+	AllowedNetworks []netip.Prefix
+	// По умолчанию net.DefaultResolver
+	Resolver Resolver
+}
+
+// Validate проверяет callback_url до того, как задача будет принята: схему,
+// список разрешённых хостов и все адреса, в которые разрешается хост.
+func (p URLPolicy) Validate(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ErrInvalidURL
+	}
+	if err := p.checkURL(u); err != nil {
+		return err
+	}
+
+	host := u.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return p.checkAddr(addr)
+	}
+	resolver := p.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	addrs, err := resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve callback_url host: %w", err)
+	}
+	for _, addr := range addrs {
+		if err := p.checkAddr(addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Client возвращает HTTP-клиент, который повторяет проверку адреса при каждом
+// соединении, включая переходы по редиректам: DNS может ответить иначе, чем
+// при Validate.
+func (p URLPolicy) Client(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		// Control получает уже разрешённый адрес прямо перед connect
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil {
+				return err
+			}
+			return p.checkAddr(addr)
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// Через прокси проверялся бы адрес прокси, а не получателя
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return p.checkURL(req.URL)
+		},
+	}
+}
+
+func (p URLPolicy) checkURL(u *url.URL) error {
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return ErrInvalidURL
+	}
+	if len(p.AllowedHosts) == 0 {
+		return nil
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	allowed := slices.ContainsFunc(p.AllowedHosts, func(pattern string) bool {
+		pattern = strings.ToLower(pattern)
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			return strings.HasSuffix(host, "."+suffix)
+		}
+		return host == pattern
+	})
+	if !allowed {
+		return ErrHostNotAllowed
+	}
+	return nil
+}
+
+func (p URLPolicy) checkAddr(addr netip.Addr) error {
+	addr = addr.Unmap()
+	if slices.ContainsFunc(p.AllowedNetworks, func(network netip.Prefix) bool { return network.Contains(addr) }) {
+		return nil
+	}
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() {
+		return ErrForbiddenAddress
+	}
+	return nil
+}
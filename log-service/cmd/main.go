@@ -3,9 +3,12 @@ package main
 import (
 	"context"
 	"fmt"
+	"log"
 	"log-service/internal/config"
 	"log-service/internal/logger/server"
+	"log-service/internal/metrics"
 	"log-service/internal/signals"
+	"log-service/internal/tracing"
 )
 
 func main() {
@@ -15,7 +18,15 @@ func main() {
 	cfg := config.Load()
 	fmt.Println("Route to logs: ", cfg.LogsDir)
 
-	server.RunLogServer(cfg)
+	shutdownTracing, err := tracing.Setup(ctx, cfg.TraceExporter)
+	if err != nil {
+		log.Fatalf("failed to set up tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
 
-	signals.WaitForShutdown(ctx, cancel)
+	go metrics.Serve(cfg.MetricsAddr)
+
+	go signals.WaitForShutdown(ctx, cancel)
+
+	server.RunLogServer(ctx, cfg)
 }
@@ -0,0 +1,50 @@
+// Команда migrate переносит записи логов из одного хранилища в другое, например
+// из JSON-файлов в bbolt:
+//
+//	migrate -from file -from-path ../log_files -to bolt -to-path ../log_files/logs.db
+//
+// Уже перенесённые записи пропускаются, поэтому прерванную миграцию можно
+// запустить повторно. Сервис логов на время миграции лучше остановить.
+package main
+
+import (
+	"flag"
+	"log"
+	"log-service/internal/store"
+)
+
+func main() {
+	from := flag.String("from", store.BackendFile, "исходное хранилище: file или bolt")
+	fromPath := flag.String("from-path", "../log_files", "каталог логов или файл базы исходного хранилища")
+	to := flag.String("to", store.BackendBolt, "целевое хранилище: file или bolt")
+	toPath := flag.String("to-path", "", "каталог логов или файл базы целевого хранилища; по умолчанию внутри -from-path")
+	flag.Parse()
+
+	if *from == store.BackendMemory || *to == store.BackendMemory {
+		log.Fatal("memory store lives only inside the service process and cannot be migrated")
+	}
+	if *toPath == "" {
+		*toPath = store.DefaultPath(*to, *fromPath)
+	}
+	if *from == *to && *fromPath == *toPath {
+		log.Fatal("source and destination are the same store")
+	}
+
+	src, err := store.Open(*from, *fromPath)
+	if err != nil {
+		log.Fatalf("failed to open source store: %v", err)
+	}
+	defer src.Close()
+
+	dst, err := store.Open(*to, *toPath)
+	if err != nil {
+		log.Fatalf("failed to open destination store: %v", err)
+	}
+	defer dst.Close()
+
+	copied, skipped, err := store.Migrate(src, dst)
+	if err != nil {
+		log.Fatalf("migration failed after %d records: %v", copied, err)
+	}
+	log.Printf("Migrated %d records from %s to %s, %d already present", copied, *fromPath, *toPath, skipped)
+}
@@ -3,63 +3,82 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/segmentio/kafka-go"
 	"google.golang.org/protobuf/proto"
 	"log"
 	"log-service/gen"
 	"log-service/internal/config"
-	"log-service/internal/logger/CRUD"
+	"log-service/internal/metrics"
+	"log-service/internal/outbox"
+	"log-service/internal/store"
+	"log-service/internal/tracing"
 	"strings"
 	"time"
 )
 
-func publishOperationResult(broker, topic string, payload []byte) {
-	writer := NewKafkaWriter(broker, topic)
+// publishOperationResult отправляет в Kafka результат операции из записи
+// business-server вместе с операциями запроса, которые находятся в логах
+// HTTP-сервиса по parent_id результата. Ошибка означает, что отправку нужно
+// повторить.
+func publishOperationResult(ctx context.Context, writer *kafka.Writer, st store.LogStore, data []byte) error {
+	var entry gen.LogEntry
+	if err := proto.Unmarshal(data, &entry); err != nil {
+		return fmt.Errorf("failed to decode log entry: %w", err)
+	}
+	// Публикация продолжает trace записи, пришедшей по gRPC
+	ctx, span := tracing.Start(tracing.ExtractMetadata(ctx, &entry), "publish operation")
+	defer span.End()
+
+	parent, err := st.Get(store.Collection("http"), entry.GetParentId())
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		// Запись запроса уже удалена: результат отправляется без операций
+		log.Printf("Parent log %s not found, publishing the result without operations", entry.GetParentId())
+	case err != nil:
+		return fmt.Errorf("failed to read parent log %s: %w", entry.GetParentId(), err)
+	}
+	operations, _ := extractOperations(string(parent))
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	payload, err := proto.Marshal(&gen.StructuredMessage{
+		Method: "POST",
+		Path:   "from log-service",
+		Body:   operations,
+		Result: entry.GetMessage().GetResult(),
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	err := writer.WriteMessages(ctx, kafka.Message{
+	msg := kafka.Message{
 		Key:   []byte("operation"),
 		Value: payload,
-	})
-
-	if err != nil {
-		log.Printf("Failed to publish operation: %v\n", err)
-	} else {
-		log.Println("Operation send successfully.")
 	}
+	tracing.InjectKafka(ctx, &msg)
 
-	writer.Close()
-
+	if err := writer.WriteMessages(ctx, msg); err != nil {
+		metrics.KafkaPublishFailures.Inc()
+		return fmt.Errorf("failed to publish operation: %w", err)
+	}
+	log.Println("Operation send successfully.")
+	return nil
 }
 
-func StartKafka(ch chan *gen.LogEntry) {
-
+// StartKafka доставляет результаты операций из outbox одним писателем Kafka,
+// пока outbox не закрыт.
+func StartKafka(ob *outbox.Outbox, st store.LogStore) {
 	cfg := config.Load()
 
-	file, err := CRUD.OpenFile("http_logs.json")
-	if err != nil {
-		fmt.Println("Failed to open file: ", err)
-	}
-	for msg := range ch {
-		log, err := CRUD.FindLog(file, msg.Message.Path)
-		if err != nil {
-			fmt.Println("Failed to fing log: ", err)
-		}
-
-		operations, err := extractOperations(log)
-		result := msg.Message.Result
+	writer := NewKafkaWriter(cfg.KafkaBroker, cfg.KafkaTopic)
+	defer writer.Close()
 
-		payload, err := proto.Marshal(&gen.StructuredMessage{
-			Method: "POST",
-			Path:   "from log-service",
-			Body:   operations,
-			Result: result,
-		})
-		publishOperationResult(cfg.KafkaBroker, cfg.KafkaTopic, payload)
-	}
+	ob.Run(func(ctx context.Context, payload []byte) error {
+		return publishOperationResult(ctx, writer, st, payload)
+	})
 }
 
 func extractBody(log string) string {
@@ -1,6 +1,9 @@
 package kafka
 
-import "github.com/segmentio/kafka-go"
+import (
+	"github.com/segmentio/kafka-go"
+	"time"
+)
 
 func NewKafkaWriter(broker, topic string) *kafka.Writer {
 	return &kafka.Writer{
@@ -8,5 +11,8 @@ func NewKafkaWriter(broker, topic string) *kafka.Writer {
 		Topic:        topic,
 		Balancer:     &kafka.LeastBytes{},
 		RequiredAcks: kafka.RequireAll,
+		// Сообщения отправляются по одному: без этого каждое ждёт
+		// наполнения пачки до секунды
+		BatchTimeout: 10 * time.Millisecond,
 	}
 }
@@ -11,6 +11,32 @@ type Config struct {
 	LogsDir     string
 	KafkaBroker string
 	KafkaTopic  string
+	MetricsAddr string
+	// none или otlp; адрес коллектора задаётся OTEL_EXPORTER_OTLP_ENDPOINT
+	TraceExporter string
+	// file (по умолчанию), bolt или memory
+	LogStore string
+	// Каталог для file, файл базы для bolt; по умолчанию внутри LogsDir
+	LogStorePath string
+	// Ротация файлов логгеров: размер (64MB) и возраст (24h); 0 отключает
+	LogRotateSize     string
+	LogRotateInterval string
+	// Сжимать ротированные сегменты gzip, по умолчанию true
+	LogCompress string
+	// Правила хранения сегментов, например "http=720h:1GB,*=:10GB"; пусто — хранить всё
+	LogRetention string
+	// Сколько удалённая запись хранится в корзине и может быть восстановлена, по умолчанию 720h
+	LogDeleteGrace string
+	// Номер узла 0–65535 в id записей, чтобы id разных экземпляров не совпадали; пусто — без номера
+	LogNodeID string
+	// YAML-файл правил маршрутизации записей в приёмники (см. routes.example.yaml); пусто — только хранилище
+	LogRoutesFile string
+	// Попытки отправки результата операции в Kafka, после которых он пишется
+	// в LogsDir/outbox/dead_letter.jsonl, по умолчанию 10
+	OutboxMaxAttempts string
+	// Пауза после первой неудачной отправки (1s), удваивается до OUTBOX_MAX_BACKOFF (5m)
+	OutboxBackoff    string
+	OutboxMaxBackoff string
 }
 
 func Load() *Config {
@@ -20,9 +46,31 @@ func Load() *Config {
 	}
 
 	return &Config{
-		LoggerAddr:  os.Getenv("LOGGER_ADDR"),
-		LogsDir:     os.Getenv("LOGS_DIR"),
-		KafkaBroker: os.Getenv("KAFKA_BROKER"),
-		KafkaTopic:  os.Getenv("KAFKA_TOPIC"),
+		LoggerAddr:    os.Getenv("LOGGER_ADDR"),
+		LogsDir:       os.Getenv("LOGS_DIR"),
+		KafkaBroker:   os.Getenv("KAFKA_BROKER"),
+		KafkaTopic:    os.Getenv("KAFKA_TOPIC"),
+		MetricsAddr:   os.Getenv("METRICS_ADDR"),
+		TraceExporter: os.Getenv("TRACE_EXPORTER"),
+		LogStore:      os.Getenv("LOG_STORE"),
+		LogStorePath:  os.Getenv("LOG_STORE_PATH"),
+
+		LogRotateSize:     getEnv("LOG_ROTATE_SIZE", "64MB"),
+		LogRotateInterval: getEnv("LOG_ROTATE_INTERVAL", "24h"),
+		LogCompress:       getEnv("LOG_COMPRESS", "true"),
+		LogRetention:      os.Getenv("LOG_RETENTION"),
+		LogDeleteGrace:    getEnv("LOG_DELETE_GRACE", "720h"),
+		LogNodeID:         os.Getenv("LOG_NODE_ID"),
+		LogRoutesFile:     os.Getenv("LOG_ROUTES_FILE"),
+		OutboxMaxAttempts: getEnv("OUTBOX_MAX_ATTEMPTS", "10"),
+		OutboxBackoff:     getEnv("OUTBOX_BACKOFF", "1s"),
+		OutboxMaxBackoff:  getEnv("OUTBOX_MAX_BACKOFF", "5m"),
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
 	}
+	return fallback
 }
@@ -0,0 +1,279 @@
+package index
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Суффикс временной копии файла, которую пишет Compact
+const compactSuffix = ".compact.tmp"
+
+// burial — запись, скрытая надгробием.
+type burial struct {
+	// Файл с надгробием
+	tombstone string
+	// Файл, где ещё лежит сама запись; пусто — её уже нет на диске, и
+	// надгробие можно убрать
+	record string
+}
+
+// Tombstone возвращает строку надгробия для записи id, удалённой в момент deleted.
+func Tombstone(id string, deleted time.Time) []byte {
+	line, _ := json.Marshal(struct {
+		Tombstone         string `json:"tombstone"`
+		TimestampReceived int64  `json:"timestamp_received"`
+	}{id, deleted.UnixMilli()})
+	return append(line, '\n')
+}
+
+// applyTombstone скрывает запись, на которую указывает надгробие t файла f.
+// Надгробие действует на записи, проиндексированные до него.
+func (idx *Index) applyTombstone(f *logFile, t Entry) {
+	f.garbage += t.Length + 1
+
+	b := burial{tombstone: t.File}
+	if e, ok := idx.byID[t.ID]; ok {
+		delete(idx.byID, t.ID)
+		idx.files[e.File].garbage += e.Length + 1
+		b.record = e.File
+	} else if old, ok := idx.dead[t.ID]; ok {
+		b.record = old.record
+	}
+	idx.dead[t.ID] = b
+}
+
+// Garbage возвращает, сколько байт файла name занимают удалённые записи и
+// надгробия, и сколько байт файла проиндексировано.
+func (idx *Index) Garbage(name string) (garbage, size int64) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	f, ok := idx.files[name]
+	if !ok {
+		return 0, 0
+	}
+	return f.garbage, f.covered
+}
+
+// compactFault прерывает Compact после шага step; тесты имитируют так сбой.
+var compactFault = func(step string) error { return nil }
+
+// Compact переписывает файл name без удалённых и перезаписанных записей и без
+// надгробий, чьих записей уже нет на диске. Новая версия пишется во временный
+// файл и заменяет старую переименованием, поэтому читатели и сбой видят файл
+// целиком в одной из версий. Писатель активного файла должен быть закрыт на
+// время уплотнения. Возвращает число освобождённых байт (у сжатого сегмента —
+// несжатых).
+func (idx *Index) Compact(name string) (int64, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	f, ok := idx.files[name]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	if err := f.journal.Flush(); err != nil {
+		return 0, err
+	}
+
+	path := filepath.Join(idx.dir, name)
+	records, dropped, size, err := idx.rewrite(name, f, path+compactSuffix)
+	if err != nil {
+		os.Remove(path + compactSuffix)
+		return 0, fmt.Errorf("failed to compact %s: %w", name, err)
+	}
+	if err := compactFault("rewritten"); err != nil {
+		return 0, err
+	}
+
+	// Журнал удаляется до замены файла: после сбоя между шагами индекс
+	// строится заново по той версии файла, которая оказалась на месте
+	if err := f.journalFile.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Remove(path + JournalSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+	if err := compactFault("journal removed"); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(path+compactSuffix, path); err != nil {
+		return 0, fmt.Errorf("failed to replace %s: %w", name, err)
+	}
+	if err := syncDir(idx.dir); err != nil {
+		return 0, err
+	}
+	if err := compactFault("renamed"); err != nil {
+		return 0, err
+	}
+
+	reader, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	journal, err := os.OpenFile(path+JournalSuffix, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		reader.Close()
+		return 0, fmt.Errorf("failed to open index journal: %w", err)
+	}
+	f.reader.Close()
+	f.reader, f.journalFile, f.journal = reader, journal, bufio.NewWriter(journal)
+
+	for id, e := range idx.byID {
+		if e.File == name {
+			delete(idx.byID, id)
+		}
+	}
+	idx.prune()
+	for id, b := range idx.dead {
+		if b.record == name {
+			b.record = ""
+			idx.dead[id] = b
+		}
+	}
+	for _, id := range dropped {
+		if idx.dead[id].tombstone == name {
+			delete(idx.dead, id)
+		}
+	}
+
+	freed := f.covered - size
+	f.covered, f.first, f.garbage = size, 0, 0
+	for _, r := range records {
+		f.journal.WriteString(r.String())
+		if r.tombstone {
+			f.garbage += r.Length + 1
+			continue
+		}
+		if f.first == 0 {
+			f.first = r.Timestamp
+		}
+		idx.add(r.Entry)
+	}
+	if err := f.journal.Flush(); err != nil {
+		return 0, fmt.Errorf("failed to write index journal: %w", err)
+	}
+	return freed, nil
+}
+
+// rewrite копирует в tmp живые строки файла name и возвращает их строки
+// журнала с новыми смещениями, id убранных надгробий и размер новой версии.
+// Вызывается под блокировкой индекса.
+func (idx *Index) rewrite(name string, f *logFile, tmp string) ([]journalRecord, []string, int64, error) {
+	var data io.Reader = io.NewSectionReader(f.reader, 0, f.covered)
+	if f.compressed {
+		zr, err := gunzip(f.reader)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		data = zr
+	}
+
+	out, err := os.Create(tmp)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	defer out.Close()
+	var dst io.Writer = out
+	var zw *gzip.Writer
+	if f.compressed {
+		zw = gzip.NewWriter(out)
+		dst = zw
+	}
+	w := bufio.NewWriterSize(dst, 64*1024)
+
+	var records []journalRecord
+	var dropped []string
+	var offset, size int64
+	reader := bufio.NewReaderSize(data, 64*1024)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF && len(line) == 0 {
+			break
+		}
+		if err != nil && err != io.EOF {
+			return nil, nil, 0, err
+		}
+		r, keep := idx.survives(name, offset, line)
+		offset += int64(len(line))
+		if !keep {
+			if r.tombstone {
+				dropped = append(dropped, r.ID)
+			}
+			continue
+		}
+		if r.ID != "" {
+			r.Offset = size
+			records = append(records, r)
+		}
+		if _, err := w.Write(line); err != nil {
+			return nil, nil, 0, err
+		}
+		size += int64(len(line))
+	}
+
+	if err := w.Flush(); err != nil {
+		return nil, nil, 0, err
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return nil, nil, 0, err
+		}
+	}
+	return records, dropped, size, out.Sync()
+}
+
+// survives решает, остаётся ли строка line, начинающаяся с offset, после
+// уплотнения файла name. Невалидные строки сохраняются как есть, строки,
+// затёртые пробелами старым удалением, — нет.
+func (idx *Index) survives(name string, offset int64, line []byte) (journalRecord, bool) {
+	if len(bytes.TrimSpace(line)) == 0 {
+		return journalRecord{}, false
+	}
+	var fields indexedFields
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return journalRecord{}, true
+	}
+	length := int64(len(bytes.TrimSuffix(line, []byte{'\n'})))
+
+	if fields.Tombstone != "" {
+		r := journalRecord{Entry: Entry{ID: clean(fields.Tombstone), File: name, Length: length}, tombstone: true}
+		b, ok := idx.dead[r.ID]
+		// Надгробие нужно, пока скрытая им запись лежит в другом файле
+		return r, ok && b.record != "" && b.record != name
+	}
+	if fields.ID == "" {
+		return journalRecord{}, true
+	}
+	e, ok := idx.byID[clean(fields.ID)]
+	if !ok || e.File != name || e.Offset != offset {
+		return journalRecord{}, false
+	}
+	return journalRecord{Entry: e}, true
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// dropUnfinishedCompaction удаляет временную копию файла path, оставшуюся от
+// прерванного уплотнения: файл на месте ещё не заменён.
+func dropUnfinishedCompaction(path string) error {
+	if err := os.Remove(path + compactSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package index
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fileIDs возвращает id записей и надгробий файла name в порядке строк.
+func fileIDs(t *testing.T, idx *Index, name string) []string {
+	t.Helper()
+	reader, err := idx.OpenReader(name)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", name, err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", name, err)
+	}
+	out := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var fields indexedFields
+		json.Unmarshal([]byte(line), &fields)
+		if fields.Tombstone != "" {
+			out = append(out, "x"+fields.Tombstone)
+		} else if fields.ID != "" {
+			out = append(out, fields.ID)
+		}
+	}
+	return out
+}
+
+func TestCompactDropsDeletedRecordsAndTombstones(t *testing.T) {
+	dir := t.TempDir()
+	idx, err := Open(dir)
+	if err != nil {
+		t.Fatalf("failed to open index: %v", err)
+	}
+	defer idx.Close()
+
+	writeLogs(t, idx, "http_logs.json", logLine("a", "HTTP-server", 100), logLine("b", "HTTP-server", 200), logLine("c", "HTTP-server", 300))
+	segment, err := idx.Rotate("http_logs.json", time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("failed to rotate: %v", err)
+	}
+	compressed, err := idx.Compress(segment)
+	if err != nil {
+		t.Fatalf("failed to compress: %v", err)
+	}
+	writeLogs(t, idx, "http_logs.json", logLine("d", "HTTP-server", 400), logLine("e", "HTTP-server", 500))
+	bury(t, idx, "http_logs.json", "a", "d")
+
+	// Надгробие a остаётся, пока запись лежит в сегменте
+	if _, err := idx.Compact("http_logs.json"); err != nil {
+		t.Fatalf("failed to compact active file: %v", err)
+	}
+	if got := fileIDs(t, idx, "http_logs.json"); !reflect.DeepEqual(got, []string{"e", "xa"}) {
+		t.Errorf("expected d and its tombstone to be dropped, got %v", got)
+	}
+
+	freed, err := idx.Compact(compressed)
+	if err != nil || freed != int64(len(logLine("a", "HTTP-server", 100))) {
+		t.Fatalf("expected record a to be freed from compressed segment, got %d, %v", freed, err)
+	}
+	if got := fileIDs(t, idx, compressed); !reflect.DeepEqual(got, []string{"b", "c"}) {
+		t.Errorf("expected a to be dropped from segment, got %v", got)
+	}
+
+	if _, err := idx.Compact("http_logs.json"); err != nil {
+		t.Fatalf("failed to compact active file: %v", err)
+	}
+	if got := fileIDs(t, idx, "http_logs.json"); !reflect.DeepEqual(got, []string{"e"}) {
+		t.Errorf("expected tombstone of a to be dropped once a is gone, got %v", got)
+	}
+	for _, name := range []string{compressed, "http_logs.json"} {
+		if garbage, _ := idx.Garbage(name); garbage != 0 {
+			t.Errorf("expected no garbage left in %s, got %d", name, garbage)
+		}
+	}
+
+	check := func(t *testing.T, idx *Index, want ...string) {
+		t.Helper()
+		if got := ids(idx.Select(nil, 0, 0)); !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v to remain, got %v", want, got)
+		}
+		for _, id := range want {
+			if line, err := idx.Find(id); err != nil || !strings.Contains(line, `"id":"`+id+`"`) {
+				t.Errorf("expected %s to be read at its new offset, got %q, %v", id, line, err)
+			}
+		}
+	}
+	check(t, idx, "b", "c", "e")
+
+	writeLogs(t, idx, "http_logs.json", logLine("f", "HTTP-server", 600))
+	if line, err := idx.Find("f"); err != nil || !strings.Contains(line, `"id":"f"`) {
+		t.Errorf("expected record appended after compaction to be indexed, got %q, %v", line, err)
+	}
+	idx.Close()
+
+	reopened, err := Open(dir)
+	if err != nil {
+		t.Fatalf("failed to reopen index: %v", err)
+	}
+	defer reopened.Close()
+	check(t, reopened, "b", "c", "e", "f")
+}
+
+func TestCompactCrashRecovery(t *testing.T) {
+	for _, step := range []string{"rewritten", "journal removed", "renamed"} {
+		t.Run(step, func(t *testing.T) {
+			dir := t.TempDir()
+			idx, err := Open(dir)
+			if err != nil {
+				t.Fatalf("failed to open index: %v", err)
+			}
+			writeLogs(t, idx, "http_logs.json", logLine("a", "HTTP-server", 100), logLine("b", "HTTP-server", 200), logLine("c", "HTTP-server", 300))
+			bury(t, idx, "http_logs.json", "b")
+
+			crash := errors.New("crash")
+			compactFault = func(s string) error {
+				if s == step {
+					return crash
+				}
+				return nil
+			}
+			_, err = idx.Compact("http_logs.json")
+			compactFault = func(string) error { return nil }
+			if !errors.Is(err, crash) {
+				t.Fatalf("expected compaction to stop after %s, got %v", step, err)
+			}
+			// Процесс «упал»: индекс не закрывается штатно
+			idx.mu.Lock()
+			for _, f := range idx.files {
+				f.reader.Close()
+				f.journalFile.Close()
+			}
+			idx.mu.Unlock()
+
+			reopened, err := Open(dir)
+			if err != nil {
+				t.Fatalf("failed to reopen index: %v", err)
+			}
+			defer reopened.Close()
+
+			if _, err := os.Stat(filepath.Join(dir, "http_logs.json"+compactSuffix)); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("expected temporary copy to be removed, got %v", err)
+			}
+			if got := ids(reopened.Select(nil, 0, 0)); !reflect.DeepEqual(got, []string{"a", "c"}) {
+				t.Errorf("expected a and c after recovery, got %v", got)
+			}
+			for _, id := range []string{"a", "c"} {
+				if line, err := reopened.Find(id); err != nil || !strings.Contains(line, `"id":"`+id+`"`) {
+					t.Errorf("expected %s to be readable after recovery, got %q, %v", id, line, err)
+				}
+			}
+
+			if _, err := reopened.Compact("http_logs.json"); err != nil {
+				t.Fatalf("failed to compact after recovery: %v", err)
+			}
+			if got := fileIDs(t, reopened, "http_logs.json"); !reflect.DeepEqual(got, []string{"a", "c"}) {
+				t.Errorf("expected compacted file to hold a and c, got %v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,588 @@
+package index
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// JournalSuffix дописывается к имени файла логов, рядом с ним лежит журнал индекса
+const JournalSuffix = ".idx"
+
+var (
+	ErrNotFound = errors.New("log not found in index")
+	// Журнал записан до появления индекса по запросу и строится заново
+	errOutdatedJournal = errors.New("index journal has no request ids")
+)
+
+// Entry описывает положение записи в файле логов. Length не включает перевод строки.
+type Entry struct {
+	ID        string
+	File      string
+	Offset    int64
+	Length    int64
+	Timestamp int64
+	Source    string
+	// request_id записи; у записей, сделанных до его появления, — path, куда
+	// раньше писался id запроса
+	Request string
+}
+
+type timeRef struct {
+	timestamp int64
+	id        string
+}
+
+// Index хранит положение каждой записи файлов *_logs.json каталога: id → файл,
+// смещение и длина, а также вторичные индексы по времени получения, источнику
+// и запросу.
+// Изменения дописываются в журнал <файл>.idx; при открытии журнал читается,
+// а записи, появившиеся в файле логов после него, доиндексируются. Удалённый
+// журнал поэтому просто строится заново.
+//
+// Запись удаляется надгробием — строкой {"tombstone":"<id>"}, дописанной в
+// активный файл (см. Tombstone); саму запись и ненужные больше надгробия
+// убирает с диска Compact.
+type Index struct {
+	dir string
+
+	mu       sync.RWMutex
+	byID     map[string]Entry
+	byTime   []timeRef
+	bySource map[string][]string
+	// Запрос → id его записей, см. Entry.Request
+	byRequest map[string][]string
+	files     map[string]*logFile
+	dead      map[string]burial
+}
+
+type logFile struct {
+	journalFile *os.File
+	// Записи журнала сбрасываются на диск после каждой пачки строк
+	journal *bufio.Writer
+	reader  *os.File
+	// Сколько байт файла логов покрыто индексом; у сжатого сегмента — несжатых
+	covered int64
+	// Сжатый сегмент читается потоком, смещения записей указаны в несжатых данных
+	compressed bool
+	// timestamp_received первой записи файла, 0 — записей ещё нет
+	first int64
+	// Сколько байт занимают удалённые или перезаписанные записи и надгробия
+	garbage int64
+}
+
+// Open загружает индексы всех файлов логов каталога.
+func Open(dir string) (*Index, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	idx := &Index{
+		dir:       dir,
+		byID:      make(map[string]Entry),
+		bySource:  make(map[string][]string),
+		byRequest: make(map[string][]string),
+		files:     make(map[string]*logFile),
+		dead:      make(map[string]burial),
+	}
+
+	var paths []string
+	for _, pattern := range []string{"*_logs.json", "*_logs.*" + segmentSuffix, "*_logs.*" + compressedSuffix} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+	// Надгробие применяется к уже загруженным записям, поэтому файлы читаются
+	// в порядке записи: сегменты от старых к новым, затем активные файлы
+	sort.SliceStable(paths, func(i, j int) bool {
+		_, ti, si := ParseName(filepath.Base(paths[i]))
+		_, tj, sj := ParseName(filepath.Base(paths[j]))
+		if si != sj {
+			return si
+		}
+		return ti.Before(tj)
+	})
+	for _, path := range paths {
+		name := filepath.Base(path)
+		if err := dropUnfinishedCompaction(path); err != nil {
+			return nil, err
+		}
+		if !IsCompressed(name) {
+			if err := dropUnfinishedCompression(path); err != nil {
+				return nil, err
+			}
+		} else if _, err := os.Stat(strings.TrimSuffix(path, ".gz")); err == nil {
+			continue // Сжатие прервано, сегмент остаётся несжатым
+		}
+		if err := idx.load(name); err != nil {
+			idx.Close()
+			return nil, err
+		}
+	}
+	return idx, nil
+}
+
+func (idx *Index) Close() error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	var errs []error
+	for _, f := range idx.files {
+		errs = append(errs, f.journal.Flush(), f.journalFile.Close(), f.reader.Close())
+	}
+	idx.files = map[string]*logFile{}
+	return errors.Join(errs...)
+}
+
+// load читает журнал файла name и доиндексирует его хвост. Вызывается под
+// блокировкой или до того, как индекс стал доступен другим горутинам.
+func (idx *Index) load(name string) error {
+	if _, ok := idx.files[name]; ok {
+		return nil
+	}
+
+	logPath := filepath.Join(idx.dir, name)
+	reader, err := os.OpenFile(logPath, os.O_RDONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	info, err := reader.Stat()
+	if err != nil {
+		reader.Close()
+		return err
+	}
+
+	journalPath := logPath + JournalSuffix
+	records, deleted, covered, err := readJournal(journalPath, name)
+	if errors.Is(err, errOutdatedJournal) {
+		err = truncateJournal(journalPath)
+	}
+	if err != nil {
+		reader.Close()
+		return err
+	}
+	if IsCompressed(name) {
+		return idx.loadCompressed(name, reader, journalPath, records, deleted, covered)
+	}
+	if covered > info.Size() {
+		// Файл логов короче журнала — его переписали, журнал устарел
+		records, deleted, covered = nil, nil, 0
+		if err := truncateJournal(journalPath); err != nil {
+			reader.Close()
+			return err
+		}
+	}
+
+	journal, err := os.OpenFile(journalPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		reader.Close()
+		return fmt.Errorf("failed to open index journal: %w", err)
+	}
+
+	f := &logFile{journalFile: journal, journal: bufio.NewWriter(journal), reader: reader, covered: covered}
+	idx.files[name] = f
+	idx.restore(f, records, deleted)
+
+	if info.Size() > covered {
+		data := io.NewSectionReader(reader, covered, info.Size()-covered)
+		if err := idx.catchUp(name, f, data); err != nil {
+			return fmt.Errorf("failed to rebuild index of %s: %w", name, err)
+		}
+	}
+	return f.journal.Flush()
+}
+
+// journalRecord — строка журнала: запись файла логов или надгробие.
+type journalRecord struct {
+	Entry
+	tombstone bool
+}
+
+func (r journalRecord) String() string {
+	if r.tombstone {
+		return fmt.Sprintf("x\t%s\t%d\t%d\n", r.ID, r.Offset, r.Length)
+	}
+	return fmt.Sprintf("+\t%s\t%d\t%d\t%d\t%s\t%s\n", r.ID, r.Offset, r.Length, r.Timestamp, r.Source, r.Request)
+}
+
+// restore применяет строки журнала по порядку. Записи, затёртые удалением
+// до появления надгробий, в индекс не попадают.
+func (idx *Index) restore(f *logFile, records []journalRecord, deleted map[string]bool) {
+	for _, r := range records {
+		switch {
+		case r.tombstone:
+			idx.applyTombstone(f, r.Entry)
+		case deleted[r.ID]:
+			f.garbage += r.Length + 1
+		default:
+			if f.first == 0 {
+				f.first = r.Timestamp
+			}
+			idx.add(r.Entry)
+		}
+	}
+}
+
+// readJournal возвращает строки журнала, затёртые удалением id и покрытый
+// журналом размер файла логов. Журнал без запросов записей возвращает
+// errOutdatedJournal.
+func readJournal(path, name string) ([]journalRecord, map[string]bool, int64, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, 0, nil
+	}
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	defer file.Close()
+
+	var records []journalRecord
+	deleted := map[string]bool{}
+	var covered int64
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		switch {
+		case len(fields) == 2 && fields[0] == "-":
+			// Так отмечались записи, затёртые пробелами до появления надгробий
+			deleted[fields[1]] = true
+		case len(fields) == 6 && fields[0] == "+":
+			return nil, nil, 0, errOutdatedJournal
+		case len(fields) == 7 && fields[0] == "+":
+			e, ok := parseRecord(fields[1:], name)
+			if !ok {
+				continue // Недописанная при сбое строка журнала
+			}
+			delete(deleted, e.ID)
+			records = append(records, journalRecord{Entry: e})
+			covered = max(covered, e.Offset+e.Length+1)
+		case len(fields) == 4 && fields[0] == "x":
+			e, ok := parseRecord(append(fields[1:], "0", ""), name)
+			if !ok {
+				continue
+			}
+			records = append(records, journalRecord{Entry: e, tombstone: true})
+			covered = max(covered, e.Offset+e.Length+1)
+		}
+	}
+	return records, deleted, covered, scanner.Err()
+}
+
+func parseRecord(fields []string, name string) (Entry, bool) {
+	offset, err1 := strconv.ParseInt(fields[1], 10, 64)
+	length, err2 := strconv.ParseInt(fields[2], 10, 64)
+	timestamp, err3 := strconv.ParseInt(fields[3], 10, 64)
+	if err := errors.Join(err1, err2, err3); err != nil {
+		return Entry{}, false
+	}
+	e := Entry{ID: fields[0], File: name, Offset: offset, Length: length, Timestamp: timestamp, Source: fields[4]}
+	if len(fields) > 5 {
+		e.Request = fields[5]
+	}
+	return e, true
+}
+
+// truncateJournal очищает журнал, чтобы индекс файла построился заново.
+func truncateJournal(path string) error {
+	if err := os.Truncate(path, 0); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// catchUp индексирует строки data — файла логов от покрытой журналом границы.
+func (idx *Index) catchUp(name string, f *logFile, data io.Reader) error {
+	reader := bufio.NewReaderSize(data, 64*1024)
+
+	offset := f.covered
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// Последняя строка без перевода строки ещё дописывается
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := idx.appendLine(name, f, offset, line); err != nil {
+			return err
+		}
+		offset += int64(len(line))
+	}
+}
+
+// indexLines индексирует записи, только что дописанные в файл с позиции offset.
+func (idx *Index) indexLines(name string, offset int64, p []byte) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	f, ok := idx.files[name]
+	if !ok {
+		return fmt.Errorf("file %s is not indexed", name)
+	}
+	for len(p) > 0 {
+		end := bytes.IndexByte(p, '\n')
+		if end < 0 {
+			break
+		}
+		if err := idx.appendLine(name, f, offset, p[:end+1]); err != nil {
+			return err
+		}
+		offset += int64(end + 1)
+		p = p[end+1:]
+	}
+	return f.journal.Flush()
+}
+
+// indexedFields — поля записи, по которым строится индекс (см. CRUD.WriteLogToFile),
+// и id, который скрывает надгробие
+type indexedFields struct {
+	ID                string `json:"id"`
+	Source            string `json:"source"`
+	TimestampReceived int64  `json:"timestamp_received"`
+	RequestID         string `json:"request_id"`
+	Path              string `json:"path"`
+	Tombstone         string `json:"tombstone"`
+}
+
+func (f indexedFields) request() string {
+	if f.RequestID != "" {
+		return f.RequestID
+	}
+	return f.Path
+}
+
+// appendLine индексирует строку line, начинающуюся с offset и заканчивающуюся переводом строки.
+func (idx *Index) appendLine(name string, f *logFile, offset int64, line []byte) error {
+	f.covered = offset + int64(len(line))
+
+	var fields indexedFields
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return nil // Невалидные и затёртые удалением строки не индексируются
+	}
+	if fields.Tombstone != "" {
+		r := journalRecord{Entry: Entry{ID: clean(fields.Tombstone), File: name, Offset: offset, Length: int64(len(line) - 1)}, tombstone: true}
+		if _, err := f.journal.WriteString(r.String()); err != nil {
+			return fmt.Errorf("failed to write index journal: %w", err)
+		}
+		idx.applyTombstone(f, r.Entry)
+		return nil
+	}
+	if fields.ID == "" {
+		return nil
+	}
+	if f.first == 0 {
+		f.first = fields.TimestampReceived
+	}
+
+	e := Entry{
+		ID:        clean(fields.ID),
+		File:      name,
+		Offset:    offset,
+		Length:    int64(len(line) - 1),
+		Timestamp: fields.TimestampReceived,
+		Source:    clean(fields.Source),
+		Request:   clean(fields.request()),
+	}
+	if _, err := f.journal.WriteString(journalRecord{Entry: e}.String()); err != nil {
+		return fmt.Errorf("failed to write index journal: %w", err)
+	}
+	idx.add(e)
+	return nil
+}
+
+func clean(s string) string {
+	return strings.NewReplacer("\t", " ", "\n", " ").Replace(s)
+}
+
+func (idx *Index) add(e Entry) {
+	if old, ok := idx.byID[e.ID]; ok && old != e {
+		// Перезаписанная строка остаётся в файле до уплотнения
+		idx.files[old.File].garbage += old.Length + 1
+	}
+	idx.byID[e.ID] = e
+
+	ref := timeRef{timestamp: e.Timestamp, id: e.ID}
+	n := len(idx.byTime)
+	if n == 0 || !ref.less(idx.byTime[n-1]) {
+		idx.byTime = append(idx.byTime, ref)
+	} else {
+		// Записи почти всегда приходят по порядку, вставка в середину — редкость
+		i := sort.Search(n, func(i int) bool { return ref.less(idx.byTime[i]) })
+		idx.byTime = append(idx.byTime, timeRef{})
+		copy(idx.byTime[i+1:], idx.byTime[i:])
+		idx.byTime[i] = ref
+	}
+
+	source := strings.ToLower(e.Source)
+	idx.bySource[source] = append(idx.bySource[source], e.ID)
+	if e.Request != "" {
+		idx.byRequest[e.Request] = append(idx.byRequest[e.Request], e.ID)
+	}
+}
+
+func (r timeRef) less(other timeRef) bool {
+	if r.timestamp != other.timestamp {
+		return r.timestamp < other.timestamp
+	}
+	return r.id < other.id
+}
+
+// Lookup возвращает положение записи id в любом из файлов.
+func (idx *Index) Lookup(id string) (Entry, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	e, ok := idx.byID[id]
+	return e, ok
+}
+
+// Read читает запись id из файла name одним чтением по смещению.
+func (idx *Index) Read(name, id string) (string, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	e, ok := idx.byID[id]
+	if !ok || e.File != name {
+		return "", ErrNotFound
+	}
+	return idx.readEntry(e)
+}
+
+// Find читает запись id, не зная файла.
+func (idx *Index) Find(id string) (string, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	e, ok := idx.byID[id]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return idx.readEntry(e)
+}
+
+// ReadEntry читает запись по положению, полученному из Lookup или Select.
+func (idx *Index) ReadEntry(e Entry) (string, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.readEntry(e)
+}
+
+func (idx *Index) readEntry(e Entry) (string, error) {
+	f, ok := idx.files[e.File]
+	if !ok {
+		return "", ErrNotFound
+	}
+	if f.compressed {
+		return readCompressed(f, e)
+	}
+	buf := make([]byte, e.Length)
+	if _, err := f.reader.ReadAt(buf, e.Offset); err != nil {
+		return "", fmt.Errorf("failed to read %s at %d: %w", e.File, e.Offset, err)
+	}
+	return string(buf), nil
+}
+
+// Select возвращает записи, полученные в [from, to] (нулевая граница не
+// ограничивает) от любого из sources без учёта регистра (пустой список — от всех), по времени.
+func (idx *Index) Select(sources []string, from, to int64) []Entry {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var entries []Entry
+	// Ссылки вторичных индексов на удалённые или перезаписанные id пропускаются
+	seen := map[string]bool{}
+	live := func(id string) (Entry, bool) {
+		e, ok := idx.byID[id]
+		inRange := (from == 0 || e.Timestamp >= from) && (to == 0 || e.Timestamp <= to)
+		return e, ok && inRange && !seen[id]
+	}
+
+	if len(sources) > 0 {
+		for _, source := range sources {
+			for _, id := range idx.bySource[strings.ToLower(source)] {
+				if e, ok := live(id); ok && strings.EqualFold(e.Source, source) {
+					seen[id] = true
+					entries = append(entries, e)
+				}
+			}
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return timeRef{entries[i].Timestamp, entries[i].ID}.less(timeRef{entries[j].Timestamp, entries[j].ID})
+		})
+		return entries
+	}
+
+	start := 0
+	if from != 0 {
+		start = sort.Search(len(idx.byTime), func(i int) bool { return idx.byTime[i].timestamp >= from })
+	}
+	for _, ref := range idx.byTime[start:] {
+		if to != 0 && ref.timestamp > to {
+			break
+		}
+		if e, ok := live(ref.id); ok && e.Timestamp == ref.timestamp {
+			seen[ref.id] = true
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// SelectRequest возвращает записи запроса request (см. Entry.Request) по времени.
+func (idx *Index) SelectRequest(request string) []Entry {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var entries []Entry
+	seen := map[string]bool{}
+	for _, id := range idx.byRequest[request] {
+		// Запись могла быть перезаписана с другим запросом
+		if e, ok := idx.byID[id]; ok && e.Request == request && !seen[id] {
+			seen[id] = true
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return timeRef{entries[i].Timestamp, entries[i].ID}.less(timeRef{entries[j].Timestamp, entries[j].ID})
+	})
+	return entries
+}
+
+// Attach подключает к индексу существующий файл name, не создавая его.
+func (idx *Index) Attach(name string) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if _, ok := idx.files[name]; ok {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(idx.dir, name)); err != nil {
+		return err
+	}
+	return idx.load(name)
+}
+
+// Files возвращает имена проиндексированных файлов по алфавиту.
+func (idx *Index) Files() []string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	names := make([]string, 0, len(idx.files))
+	for name := range idx.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,324 @@
+package index
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func logLine(id, source string, received int64) string {
+	return fmt.Sprintf(`{"level":"info","id":%q,"message":"{}","source":%q,"timestamp_received":%d}`+"\n", id, source, received)
+}
+
+func writeLogs(t *testing.T, idx *Index, name string, lines ...string) {
+	t.Helper()
+	w, err := idx.Writer(name)
+	if err != nil {
+		t.Fatalf("failed to open writer: %v", err)
+	}
+	defer w.Close()
+	for _, line := range lines {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatalf("failed to write log: %v", err)
+		}
+	}
+}
+
+// bury дописывает в активный файл name надгробия записей ids.
+func bury(t *testing.T, idx *Index, name string, ids ...string) {
+	t.Helper()
+	var lines []string
+	for _, id := range ids {
+		lines = append(lines, string(Tombstone(id, time.Now())))
+	}
+	writeLogs(t, idx, name, lines...)
+}
+
+func ids(entries []Entry) []string {
+	out := []string{}
+	for _, e := range entries {
+		out = append(out, e.ID)
+	}
+	return out
+}
+
+func TestIndexReadSelectDelete(t *testing.T) {
+	dir := t.TempDir()
+	idx, err := Open(dir)
+	if err != nil {
+		t.Fatalf("failed to open index: %v", err)
+	}
+	defer idx.Close()
+
+	writeLogs(t, idx, "http_logs.json", logLine("a", "HTTP-server", 100), logLine("b", "HTTP-server", 300))
+	writeLogs(t, idx, "business_logs.json", logLine("c", "business-server", 200))
+
+	line, err := idx.Read("http_logs.json", "b")
+	if err != nil || line != strings.TrimSuffix(logLine("b", "HTTP-server", 300), "\n") {
+		t.Fatalf("expected line of b, got %q, %v", line, err)
+	}
+	if _, err := idx.Read("business_logs.json", "b"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a record in another file, got %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		sources  []string
+		from, to int64
+		want     []string
+	}{
+		{name: "all by time", want: []string{"a", "c", "b"}},
+		{name: "time range", from: 150, to: 300, want: []string{"c", "b"}},
+		{name: "source ignoring case", sources: []string{"http-SERVER"}, want: []string{"a", "b"}},
+		{name: "source and time", sources: []string{"HTTP-server", "business-server"}, to: 200, want: []string{"a", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ids(idx.Select(tt.sources, tt.from, tt.to)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	bury(t, idx, "http_logs.json", "a")
+	if _, err := idx.Read("http_logs.json", "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected deleted record to be gone, got %v", err)
+	}
+	if got := ids(idx.Select(nil, 0, 0)); !reflect.DeepEqual(got, []string{"c", "b"}) {
+		t.Errorf("expected deleted record to leave secondary indexes, got %v", got)
+	}
+	if line, err := idx.Read("http_logs.json", "b"); err != nil || !strings.Contains(line, `"id":"b"`) {
+		t.Errorf("expected b to stay readable at its offset, got %q, %v", line, err)
+	}
+	if garbage, _ := idx.Garbage("http_logs.json"); garbage != int64(len(logLine("a", "HTTP-server", 100))+len(Tombstone("a", time.Now()))) {
+		t.Errorf("expected deleted record and its tombstone to count as garbage, got %d", garbage)
+	}
+}
+
+func TestOpenRestoresAndRebuildsIndex(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, dir string)
+	}{
+		{name: "journal is reused", prepare: func(t *testing.T, dir string) {}},
+		{name: "missing journal is rebuilt", prepare: func(t *testing.T, dir string) {
+			os.Remove(filepath.Join(dir, "http_logs.json"+JournalSuffix))
+		}},
+		{name: "records written without index are caught up", prepare: func(t *testing.T, dir string) {
+			file, _ := os.OpenFile(filepath.Join(dir, "http_logs.json"), os.O_APPEND|os.O_WRONLY, 0644)
+			file.WriteString(logLine("late", "HTTP-server", 400))
+			file.Close()
+		}},
+		{name: "journal of rewritten file is discarded", prepare: func(t *testing.T, dir string) {
+			os.WriteFile(filepath.Join(dir, "http_logs.json"), []byte(logLine("b", "HTTP-server", 300)), 0644)
+		}},
+		{name: "journal without request ids is rebuilt", prepare: func(t *testing.T, dir string) {
+			stripRequests(t, filepath.Join(dir, "http_logs.json"+JournalSuffix))
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			idx, err := Open(dir)
+			if err != nil {
+				t.Fatalf("failed to open index: %v", err)
+			}
+			writeLogs(t, idx, "http_logs.json", logLine("a", "HTTP-server", 100), logLine("b", "HTTP-server", 300))
+			bury(t, idx, "http_logs.json", "a")
+			idx.Close()
+
+			tt.prepare(t, dir)
+
+			reopened, err := Open(dir)
+			if err != nil {
+				t.Fatalf("failed to reopen index: %v", err)
+			}
+			defer reopened.Close()
+
+			if _, ok := reopened.Lookup("a"); ok {
+				t.Errorf("expected deleted record to stay deleted")
+			}
+			line, err := reopened.Read("http_logs.json", "b")
+			if err != nil || !strings.Contains(line, `"id":"b"`) {
+				t.Errorf("expected b to be readable, got %q, %v", line, err)
+			}
+
+			// После открытия индекс продолжает пополняться новыми записями
+			writeLogs(t, reopened, "http_logs.json", logLine("new", "HTTP-server", 500))
+			if line, err := reopened.Find("new"); err != nil || !strings.Contains(line, `"id":"new"`) {
+				t.Errorf("expected appended record to be indexed, got %q, %v", line, err)
+			}
+		})
+	}
+}
+
+// stripRequests переписывает журнал path в формат без запросов записей.
+func stripRequests(t *testing.T, path string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read journal: %v", err)
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "+\t") {
+			lines[i] = line[:strings.LastIndex(line, "\t")] + "\n"
+		}
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "")), 0644); err != nil {
+		t.Fatalf("failed to write journal: %v", err)
+	}
+}
+
+func TestSelectRequest(t *testing.T) {
+	dir := t.TempDir()
+	idx, err := Open(dir)
+	if err != nil {
+		t.Fatalf("failed to open index: %v", err)
+	}
+	writeLogs(t, idx, "http_logs.json",
+		`{"id":"req","request_id":"req","path":"/process","timestamp_received":100}`+"\n",
+		`{"id":"other","request_id":"other","path":"/process","timestamp_received":150}`+"\n",
+		// Старая запись без request_id относится к запросу через path
+		`{"id":"legacy","path":"req","timestamp_received":50}`+"\n",
+	)
+	writeLogs(t, idx, "business_logs.json", `{"id":"res","request_id":"req","parent_id":"req","timestamp_received":200}`+"\n")
+	idx.Close()
+
+	// Журнал старого формата строится заново вместе с индексом запросов
+	stripRequests(t, filepath.Join(dir, "http_logs.json"+JournalSuffix))
+	reopened, err := Open(dir)
+	if err != nil {
+		t.Fatalf("failed to reopen index: %v", err)
+	}
+	defer reopened.Close()
+
+	if got := ids(reopened.SelectRequest("req")); !reflect.DeepEqual(got, []string{"legacy", "req", "res"}) {
+		t.Errorf("expected records of req by time, got %v", got)
+	}
+	bury(t, reopened, "business_logs.json", "res")
+	if got := ids(reopened.SelectRequest("req")); !reflect.DeepEqual(got, []string{"legacy", "req"}) {
+		t.Errorf("expected deleted record to leave the request index, got %v", got)
+	}
+	if got := ids(reopened.SelectRequest("missing")); len(got) != 0 {
+		t.Errorf("expected no records of an unknown request, got %v", got)
+	}
+}
+
+func TestRotateAndCompressSegments(t *testing.T) {
+	dir := t.TempDir()
+	idx, err := Open(dir)
+	if err != nil {
+		t.Fatalf("failed to open index: %v", err)
+	}
+	writeLogs(t, idx, "http_logs.json", logLine("a", "HTTP-server", 100), logLine("b", "HTTP-server", 200), logLine("c", "HTTP-server", 300))
+
+	segment, err := idx.Rotate("http_logs.json", time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC))
+	if err != nil || segment != "http_logs.20261019T120000.000.json" {
+		t.Fatalf("expected segment named by rotation time, got %q, %v", segment, err)
+	}
+	if active, rotated, ok := ParseName(segment + ".gz"); !ok || active != "http_logs.json" || rotated.Hour() != 12 {
+		t.Errorf("expected compressed segment to parse back, got %q, %v, %v", active, rotated, ok)
+	}
+	writeLogs(t, idx, "http_logs.json", logLine("d", "HTTP-server", 400))
+
+	bury(t, idx, "http_logs.json", "a")
+	compressed, err := idx.Compress(segment)
+	if err != nil {
+		t.Fatalf("failed to compress: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, segment)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected plain segment to be replaced, got %v", err)
+	}
+	bury(t, idx, "http_logs.json", "b")
+
+	check := func(t *testing.T, idx *Index) {
+		t.Helper()
+		if got := ids(idx.Select(nil, 0, 0)); !reflect.DeepEqual(got, []string{"c", "d"}) {
+			t.Errorf("expected c and d across segments, got %v", got)
+		}
+		if line, err := idx.Read(compressed, "c"); err != nil || !strings.Contains(line, `"id":"c"`) {
+			t.Errorf("expected c to be read from compressed segment, got %q, %v", line, err)
+		}
+		var read []string
+		if err := idx.ReadEntries(idx.Select(nil, 0, 0), func(e Entry, line string) bool {
+			read = append(read, e.ID)
+			return true
+		}); err != nil || !reflect.DeepEqual(read, []string{"c", "d"}) {
+			t.Errorf("expected entries of both files to be read, got %v, %v", read, err)
+		}
+	}
+	check(t, idx)
+	idx.Close()
+
+	for _, journal := range []bool{true, false} {
+		if !journal {
+			// Без журнала сегмент индексируется заново, а надгробия активного
+			// файла снова скрывают удалённые из него записи
+			os.Remove(filepath.Join(dir, compressed+JournalSuffix))
+		}
+		reopened, err := Open(dir)
+		if err != nil {
+			t.Fatalf("failed to reopen index: %v", err)
+		}
+		check(t, reopened)
+
+		removed, err := reopened.Remove(compressed)
+		if err != nil || removed == 0 {
+			t.Errorf("expected segment records to be removed, got %d, %v", removed, err)
+		}
+		if got := ids(reopened.Select([]string{"http-server"}, 0, 0)); !reflect.DeepEqual(got, []string{"d"}) {
+			t.Errorf("expected only the active file to remain, got %v", got)
+		}
+		reopened.Close()
+		if journal {
+			// Вернём сегмент для проверки без журнала
+			writeCompressedCopy(t, dir, compressed)
+		}
+	}
+}
+
+// writeCompressedCopy пишет сжатый сегмент с записями a, b и c без журнала.
+func writeCompressedCopy(t *testing.T, dir, name string) {
+	t.Helper()
+	file, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatalf("failed to create segment: %v", err)
+	}
+	defer file.Close()
+	zw := gzip.NewWriter(file)
+	for _, line := range []string{logLine("a", "HTTP-server", 100), logLine("b", "HTTP-server", 200), logLine("c", "HTTP-server", 300)} {
+		zw.Write([]byte(line))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to write segment: %v", err)
+	}
+}
+
+func TestOpenDropsUnfinishedCompression(t *testing.T) {
+	dir := t.TempDir()
+	segment := "http_logs.20261019T120000.000.json"
+	os.WriteFile(filepath.Join(dir, segment), []byte(logLine("a", "HTTP-server", 100)), 0644)
+	// Сжатая копия появилась, но несжатый сегмент ещё не удалён
+	writeCompressedCopy(t, dir, segment+".gz")
+
+	idx, err := Open(dir)
+	if err != nil {
+		t.Fatalf("failed to open index: %v", err)
+	}
+	defer idx.Close()
+
+	if got := idx.Files(); !reflect.DeepEqual(got, []string{segment}) {
+		t.Errorf("expected only the plain segment to be indexed, got %v", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, segment+".gz")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected unfinished compressed copy to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,429 @@
+package index
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Ротированный сегмент файла <service>_logs.json называется
+// <service>_logs.<время ротации>.json, после сжатия — с суффиксом .gz.
+const (
+	segmentSuffix    = ".json"
+	compressedSuffix = ".json.gz"
+	segmentTime      = "20060102T150405.000"
+)
+
+// IsCompressed сообщает, что файл — сжатый сегмент.
+func IsCompressed(name string) bool {
+	return strings.HasSuffix(name, compressedSuffix)
+}
+
+// SegmentName возвращает имя сегмента, в который файл name ротируется в момент rotated.
+func SegmentName(name string, rotated time.Time) string {
+	return strings.TrimSuffix(name, segmentSuffix) + "." + rotated.UTC().Format(segmentTime) + segmentSuffix
+}
+
+// ParseName возвращает файл логгера, к которому относится name, и для
+// сегментов — время ротации.
+func ParseName(name string) (active string, rotated time.Time, segment bool) {
+	base := strings.TrimSuffix(strings.TrimSuffix(name, ".gz"), segmentSuffix)
+	i := strings.LastIndex(base, "_logs.")
+	if i < 0 {
+		return name, time.Time{}, false
+	}
+	t, err := time.Parse(segmentTime, base[i+len("_logs."):])
+	if err != nil {
+		return name, time.Time{}, false
+	}
+	return base[:i] + "_logs" + segmentSuffix, t, true
+}
+
+// loadCompressed подключает сжатый сегмент. Сегмент не меняется, поэтому
+// журнал, если он есть, покрывает его целиком; без журнала индекс строится заново.
+func (idx *Index) loadCompressed(name string, reader *os.File, journalPath string, records []journalRecord, deleted map[string]bool, covered int64) error {
+	journal, err := os.OpenFile(journalPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		reader.Close()
+		return fmt.Errorf("failed to open index journal: %w", err)
+	}
+
+	f := &logFile{journalFile: journal, journal: bufio.NewWriter(journal), reader: reader, covered: covered, compressed: true}
+	idx.files[name] = f
+	idx.restore(f, records, deleted)
+
+	if covered == 0 {
+		data, err := gunzip(reader)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if err == nil {
+			if err := idx.catchUp(name, f, data); err != nil {
+				return fmt.Errorf("failed to rebuild index of %s: %w", name, err)
+			}
+		}
+	}
+	return f.journal.Flush()
+}
+
+// gunzip читает сжатый файл через ReadAt, не сдвигая общую позицию file,
+// поэтому сегмент можно читать из нескольких горутин.
+func gunzip(file *os.File) (*gzip.Reader, error) {
+	return gzip.NewReader(io.NewSectionReader(file, 0, math.MaxInt64))
+}
+
+// readCompressed распаковывает сегмент до записи e.
+func readCompressed(f *logFile, e Entry) (string, error) {
+	data, err := gunzip(f.reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", e.File, err)
+	}
+	if _, err := io.CopyN(io.Discard, data, e.Offset); err != nil {
+		return "", fmt.Errorf("failed to read %s at %d: %w", e.File, e.Offset, err)
+	}
+	buf := make([]byte, e.Length)
+	if _, err := io.ReadFull(data, buf); err != nil {
+		return "", fmt.Errorf("failed to read %s at %d: %w", e.File, e.Offset, err)
+	}
+	return string(buf), nil
+}
+
+// dropUnfinishedCompression удаляет сжатую копию сегмента path, оставшуюся
+// от прерванного сжатия: несжатый сегмент ещё на месте, значит копия не подключена.
+func dropUnfinishedCompression(path string) error {
+	for _, p := range []string{path + ".gz.tmp", path + ".gz", path + ".gz" + JournalSuffix} {
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// First возвращает timestamp_received первой записи файла name.
+func (idx *Index) First(name string) (int64, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	f, ok := idx.files[name]
+	if !ok || f.first == 0 {
+		return 0, false
+	}
+	return f.first, true
+}
+
+// Rotate переименовывает файл name вместе с журналом в сегмент и возвращает
+// его имя. Писатели файла должны быть закрыты: следующий Writer(name) начнёт
+// новый файл.
+func (idx *Index) Rotate(name string, rotated time.Time) (string, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	f, ok := idx.files[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	segment := SegmentName(name, rotated)
+	for idx.exists(segment) || idx.exists(segment+".gz") {
+		// Две ротации за одну миллисекунду
+		rotated = rotated.Add(time.Millisecond)
+		segment = SegmentName(name, rotated)
+	}
+
+	if err := f.journal.Flush(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(filepath.Join(idx.dir, name), filepath.Join(idx.dir, segment)); err != nil {
+		return "", fmt.Errorf("failed to rename %s: %w", name, err)
+	}
+	if err := os.Rename(filepath.Join(idx.dir, name+JournalSuffix), filepath.Join(idx.dir, segment+JournalSuffix)); err != nil {
+		return "", fmt.Errorf("failed to rename index journal of %s: %w", name, err)
+	}
+	idx.retarget(name, segment)
+	return segment, nil
+}
+
+func (idx *Index) exists(name string) bool {
+	_, err := os.Stat(filepath.Join(idx.dir, name))
+	return err == nil
+}
+
+// retarget переносит файл from индекса и его записи на имя to.
+func (idx *Index) retarget(from, to string) {
+	idx.files[to] = idx.files[from]
+	delete(idx.files, from)
+	for id, e := range idx.byID {
+		if e.File == from {
+			e.File = to
+			idx.byID[id] = e
+		}
+	}
+	for id, b := range idx.dead {
+		if b.tombstone == from {
+			b.tombstone = to
+		}
+		if b.record == from {
+			b.record = to
+		}
+		idx.dead[id] = b
+	}
+}
+
+// Compress сжимает сегмент gzip и заменяет им несжатый. Сегмент больше не
+// дописывается: удаления из него — надгробия в активном файле.
+func (idx *Index) Compress(segment string) (string, error) {
+	idx.mu.RLock()
+	_, ok := idx.files[segment]
+	idx.mu.RUnlock()
+	if !ok || IsCompressed(segment) {
+		return "", ErrNotFound
+	}
+
+	// Сегмент больше не дописывается, поэтому сжимается без блокировки индекса
+	path := filepath.Join(idx.dir, segment)
+	compressed := segment + ".gz"
+	if err := gzipFile(path, path+".gz.tmp"); err != nil {
+		os.Remove(path + ".gz.tmp")
+		return "", err
+	}
+	if err := os.Rename(path+".gz.tmp", path+".gz"); err != nil {
+		return "", err
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	f, ok := idx.files[segment]
+	if !ok {
+		// Сегмент удалили, пока он сжимался
+		os.Remove(path + ".gz")
+		return "", ErrNotFound
+	}
+	reader, err := os.Open(path + ".gz")
+	if err != nil {
+		return "", err
+	}
+	if err := f.journal.Flush(); err != nil {
+		reader.Close()
+		return "", err
+	}
+	if err := os.Rename(path+JournalSuffix, path+".gz"+JournalSuffix); err != nil {
+		reader.Close()
+		return "", fmt.Errorf("failed to rename index journal of %s: %w", segment, err)
+	}
+
+	f.reader.Close()
+	f.reader = reader
+	f.compressed = true
+	idx.retarget(segment, compressed)
+	return compressed, os.Remove(path)
+}
+
+func gzipFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		return fmt.Errorf("failed to compress %s: %w", filepath.Base(src), err)
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return out.Sync()
+}
+
+// Remove удаляет файл name с журналом и его записи из индекса. Возвращает
+// число удалённых записей.
+func (idx *Index) Remove(name string) (int, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	f, ok := idx.files[name]
+	if !ok {
+		return 0, ErrNotFound
+	}
+
+	removed := 0
+	for id, e := range idx.byID {
+		if e.File == name {
+			delete(idx.byID, id)
+			removed++
+		}
+	}
+	idx.prune()
+	for id, b := range idx.dead {
+		if b.tombstone == name {
+			delete(idx.dead, id)
+		} else if b.record == name {
+			b.record = ""
+			idx.dead[id] = b
+		}
+	}
+
+	delete(idx.files, name)
+	errs := []error{f.journal.Flush(), f.journalFile.Close(), f.reader.Close()}
+	for _, p := range []string{name, name + JournalSuffix} {
+		if err := os.Remove(filepath.Join(idx.dir, p)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return removed, errors.Join(errs...)
+}
+
+// prune убирает из вторичных индексов ссылки на удалённые записи.
+func (idx *Index) prune() {
+	byTime := idx.byTime[:0]
+	for _, ref := range idx.byTime {
+		if e, ok := idx.byID[ref.id]; ok && e.Timestamp == ref.timestamp {
+			byTime = append(byTime, ref)
+		}
+	}
+	idx.byTime = byTime
+
+	idx.pruneRefs(idx.bySource)
+	idx.pruneRefs(idx.byRequest)
+}
+
+func (idx *Index) pruneRefs(refs map[string][]string) {
+	for key, ids := range refs {
+		live := ids[:0]
+		for _, id := range ids {
+			if _, ok := idx.byID[id]; ok {
+				live = append(live, id)
+			}
+		}
+		if len(live) == 0 {
+			delete(refs, key)
+		} else {
+			refs[key] = live
+		}
+	}
+}
+
+// OpenReader открывает файл name для последовательного чтения, сжатый
+// сегмент — распакованным.
+func (idx *Index) OpenReader(name string) (io.ReadCloser, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	f, ok := idx.files[name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	file, err := os.Open(filepath.Join(idx.dir, name))
+	if err != nil {
+		return nil, err
+	}
+	if !f.compressed {
+		return file, nil
+	}
+	data, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return &gzipFileReader{Reader: data, file: file}, nil
+}
+
+type gzipFileReader struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (r *gzipFileReader) Close() error {
+	return errors.Join(r.Reader.Close(), r.file.Close())
+}
+
+// ReadEntries читает записи entries пофайлово в порядке смещений, так что
+// сжатый сегмент распаковывается один раз, и передаёт их fn, пока она
+// возвращает true. fn вызывается без блокировки индекса.
+func (idx *Index) ReadEntries(entries []Entry, fn func(Entry, string) bool) error {
+	byFile := map[string][]Entry{}
+	var files []string
+	for _, e := range entries {
+		if _, ok := byFile[e.File]; !ok {
+			files = append(files, e.File)
+		}
+		byFile[e.File] = append(byFile[e.File], e)
+	}
+
+	for _, name := range files {
+		group := byFile[name]
+		sort.Slice(group, func(i, j int) bool { return group[i].Offset < group[j].Offset })
+
+		lines, err := idx.readGroup(name, group)
+		if err != nil {
+			return err
+		}
+		for i, line := range lines {
+			if line != "" && !fn(group[i], line) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// readGroup читает записи одного файла; записи, которых уже нет, остаются пустыми строками.
+func (idx *Index) readGroup(name string, group []Entry) ([]string, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	lines := make([]string, len(group))
+	f, ok := idx.files[name]
+	if !ok {
+		return lines, nil // Сегмент удалён или сжат после выборки
+	}
+	// Удалённые после выборки записи не читаются
+	live := func(e Entry) bool { return idx.byID[e.ID] == e }
+	if !f.compressed {
+		for i, e := range group {
+			if !live(e) {
+				continue
+			}
+			line, err := idx.readEntry(e)
+			if err != nil {
+				return nil, err
+			}
+			lines[i] = line
+		}
+		return lines, nil
+	}
+
+	data, err := gunzip(f.reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	var pos int64
+	for i, e := range group {
+		if _, err := io.CopyN(io.Discard, data, e.Offset-pos); err != nil {
+			return nil, fmt.Errorf("failed to read %s at %d: %w", name, e.Offset, err)
+		}
+		buf := make([]byte, e.Length)
+		if _, err := io.ReadFull(data, buf); err != nil {
+			return nil, fmt.Errorf("failed to read %s at %d: %w", name, e.Offset, err)
+		}
+		pos = e.Offset + e.Length
+		if live(e) {
+			lines[i] = string(buf)
+		}
+	}
+	return lines, nil
+}
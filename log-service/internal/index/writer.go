@@ -0,0 +1,69 @@
+package index
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Writer дописывает записи в файл логов и сразу индексирует их. Подходит
+// как zapcore.WriteSyncer: zap передаёт в Write целые строки.
+type Writer struct {
+	idx  *Index
+	name string
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// Writer открывает файл name каталога индекса на дозапись.
+func (idx *Index) Writer(name string) (*Writer, error) {
+	idx.mu.Lock()
+	err := idx.load(name)
+	idx.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(filepath.Join(idx.dir, name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &Writer{idx: idx, name: name, file: file, size: info.Size()}, nil
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	offset := w.size
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	if indexErr := w.idx.indexLines(w.name, offset, p[:n]); err == nil {
+		err = indexErr
+	}
+	return n, err
+}
+
+func (w *Writer) Sync() error {
+	return w.file.Sync()
+}
+
+func (w *Writer) Close() error {
+	return w.file.Close()
+}
+
+// Size возвращает текущий размер файла.
+func (w *Writer) Size() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.size
+}
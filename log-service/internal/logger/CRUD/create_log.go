@@ -5,19 +5,50 @@ import (
 	"encoding/json"
 	"fmt"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
-	"log"
+	"google.golang.org/protobuf/proto"
 	"log-service/gen"
+	"log-service/internal/metrics"
+	"log-service/internal/outbox"
+	"log-service/internal/policy"
+	"log-service/internal/routing"
+	"log-service/internal/store"
+	"log-service/internal/tail"
+	"log-service/internal/tracing"
+	"log-service/internal/trash"
 	"log-service/internal/utils"
 	"time"
 )
 
 type LogManager struct {
 	gen.UnimplementedLoggerServer
-	Loggers   map[string]*zap.Logger
-	LogChanel chan *gen.LogEntry
+	// Логгеры по имени сервиса, если Router не задан; неизвестные сервисы
+	// пишутся логгером undefined-server
+	Loggers map[string]*zap.Logger
+	// Результаты операций business-server для отправки в Kafka; без него не отправляются
+	Outbox *outbox.Outbox
+	// Хранилище записей логгеров; без него чтение и удаление просматривают
+	// файл в ../log_files целиком
+	Store store.LogStore
+	// Корзина делает DeleteLog обратимым; без неё запись удаляется из Store сразу
+	Trash *trash.Trash
+	// Раздаёт записи логгеров подписчикам TailLogs; без него живой поток недоступен
+	Tail *tail.Hub
+	// Выдаёт id новых записей; по умолчанию ULID без номера узла
+	IDs utils.IDGenerator
+	// Выбирает коллекцию и приёмники записи по правилам маршрутизации
+	Router *routing.Router
+	// Минимальный уровень и выборка записей по сервисам; без них сохраняется всё.
+	// Записи с операциями или результатом политика не отбрасывает
+	Policies *policy.Policies
 }
 
+var defaultIDs = utils.NewULIDGenerator()
+
+// HandleIncomingLog записывает одну запись. Sync логгера фиксирует её вместе
+// с записями параллельных вызовов (см. store.Committer).
 func (lm *LogManager) HandleIncomingLog(ctx context.Context, entry *gen.LogEntry) (*gen.LogCreationResponse, error) {
 	select {
 	case <-ctx.Done():
@@ -25,27 +56,136 @@ func (lm *LogManager) HandleIncomingLog(ctx context.Context, entry *gen.LogEntry
 	default:
 	}
 
-	id := gen.LogID{Id: utils.GenerateID(10)}
-	logger, ok := lm.Loggers[entry.ServiceName]
-	if !ok {
-		logger = lm.Loggers["undefined-server"]
+	a, err := lm.accept(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	if err := lm.enqueue([]*accepted{a}); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to queue log %s for Kafka: %v", a.id.GetId(), err)
+	}
+	if a.logger == nil {
+		return &gen.LogCreationResponse{Id: a.id, Dropped: true}, nil
+	}
+	lm.write(ctx, a)
+	if err := a.logger.Sync(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to write log %s: %v", a.id.GetId(), err)
+	}
+
+	return &gen.LogCreationResponse{Id: a.id}, nil
+
+}
+
+// accepted — запись, которой accept выдал id, ещё не поставленная в outbox
+// и не переданная логгеру.
+type accepted struct {
+	id     *gen.LogID
+	entry  *gen.LogEntry
+	source string
+	// Логгер источника; nil, если политика сервиса запись отбросила
+	logger *zap.Logger
+	// Сообщение для Kafka; nil, если результат операции не отправляется
+	payload []byte
+}
+
+// accept выдаёт записи id, выбирает её логгер и готовит сообщение для outbox.
+// Записи нужно поставить в outbox (enqueue) раньше, чем передать логгеру
+// (write): результат операции business-server отправляется в Kafka, даже
+// если политика сервиса запись отбросила. id отброшенной записи всё равно
+// выдаётся, чтобы на него могли ссылаться следующие записи запроса.
+func (lm *LogManager) accept(ctx context.Context, entry *gen.LogEntry) (*accepted, error) {
+	entry.Level = policy.NormalizeLevel(entry.GetLevel())
+
+	ids := lm.IDs
+	if ids == nil {
+		ids = defaultIDs
+	}
+	id := &gen.LogID{Id: ids.NewID()}
+	if entry.GetRequestId() == "" {
+		// Запись без request_id сама начинает запрос
+		entry.RequestId = id.GetId()
+	}
+	a := &accepted{id: id, entry: entry}
+	a.source, a.logger = lm.logger(entry)
+
+	// Политика хранения не касается отправки в Kafka
+	if entry.ServiceName == "business-server" && lm.Outbox != nil {
+		payload, err := outboxPayload(ctx, entry)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to queue log %s for Kafka: %v", id.GetId(), err)
+		}
+		a.payload = payload
 	}
+	if lm.Policies != nil && !pinned(entry) {
+		if reason := lm.Policies.Admit(entry.GetServiceName(), entry.GetLevel()); reason != "" {
+			metrics.PolicyDropped.WithLabelValues(a.source, reason).Inc()
+			a.logger = nil
+		}
+	}
+	return a, nil
+}
 
-	WriteLogToFile(logger, entry.GetLevel(), id.GetId(), entry, lm.LogChanel)
+// enqueue ставит сообщения принятых записей в outbox одной транзакцией.
+func (lm *LogManager) enqueue(batch []*accepted) error {
+	var payloads [][]byte
+	for _, a := range batch {
+		if a.payload != nil {
+			payloads = append(payloads, a.payload)
+		}
+	}
+	if len(payloads) == 0 {
+		return nil
+	}
+	return lm.Outbox.EnqueueBatch(payloads)
+}
+
+// write передаёт принятую запись её логгеру. Запись сохранена после Sync
+// логгера.
+func (lm *LogManager) write(ctx context.Context, a *accepted) {
+	WriteLogToFile(ctx, a.logger, a.entry.GetLevel(), a.id.GetId(), a.entry)
+	metrics.LogsIngested.WithLabelValues(a.source).Inc()
+}
 
-	_ = logger.Sync()
+// pinned — записи, которые сохраняются при любой политике: операции запроса
+// нужны отправке результата в Kafka и хронологии запроса, а результат — самой
+// хронологии.
+func pinned(entry *gen.LogEntry) bool {
+	return len(entry.GetMessage().GetBody()) > 0 || entry.GetMessage().GetResult() != nil
+}
 
-	return &gen.LogCreationResponse{Id: &id}, nil
+// outboxPayload готовит сообщение outbox вместе с контекстом trace из ctx:
+// по нему отправка в Kafka продолжает trace записи, пришедшей по gRPC.
+func outboxPayload(ctx context.Context, entry *gen.LogEntry) ([]byte, error) {
+	msg := proto.Clone(entry).(*gen.LogEntry)
+	tracing.InjectMetadata(ctx, msg)
+	return proto.Marshal(msg)
+}
 
+// logger выбирает логгер записи и источник для метрики LogsIngested.
+func (lm *LogManager) logger(entry *gen.LogEntry) (string, *zap.Logger) {
+	if lm.Router != nil {
+		return lm.Router.Logger(entry)
+	}
+	source := entry.ServiceName
+	logger, ok := lm.Loggers[source]
+	if !ok {
+		// Неизвестные источники не попадают в метку, чтобы не плодить ряды
+		source = routing.UndefinedSource
+		logger = lm.Loggers[source]
+	}
+	return source, logger
 }
 
-func WriteLogToFile(logger *zap.Logger, level string, id string, entry *gen.LogEntry, logChan chan *gen.LogEntry) {
-	log.Println("Хотим записать лог:", level, id, entry.String())
+// WriteLogToFile пишет запись вместе с trace_id и span_id вызова из ctx,
+// по ним запись находится в трассировке запроса.
+func WriteLogToFile(ctx context.Context, logger *zap.Logger, level string, id string, entry *gen.LogEntry) {
 	sendTs := entry.TimestampSend
 	receiveTs := time.Now().UnixMilli()
 	delay := float64(receiveTs - sendTs)
 
 	formattedDelay := fmt.Sprintf("%.3f ms", delay)
+	if sendTs > 0 && delay >= 0 {
+		metrics.DeliveryDelay.Observe(delay / 1000)
+	}
 
 	msgJSON, err := protojson.Marshal(entry.Message)
 	if err != nil {
@@ -56,13 +196,18 @@ func WriteLogToFile(logger *zap.Logger, level string, id string, entry *gen.LogE
 		zap.String("id", id),
 		zap.Any("message", json.RawMessage(msgJSON)),
 		zap.String("path", entry.Message.GetPath()),
+		zap.String("request_id", entry.GetRequestId()),
+		zap.String("parent_id", entry.GetParentId()),
+		zap.Any("metadata", entry.GetMetadata()),
 		zap.String("source", entry.ServiceName),
 		zap.Int64("timestamp_send", sendTs),
 		zap.Int64("timestamp_received", receiveTs),
 		zap.String("deliveryDelayMs", formattedDelay),
+		zap.String("trace_id", tracing.TraceID(ctx)),
+		zap.String("span_id", tracing.SpanID(ctx)),
 	}
 
-	switch level {
+	switch policy.NormalizeLevel(level) {
 	case "debug":
 		logger.Debug("New log entry", logFields...)
 	case "info":
@@ -74,13 +219,4 @@ func WriteLogToFile(logger *zap.Logger, level string, id string, entry *gen.LogE
 	default:
 		logger.Info("New log entry", logFields...)
 	}
-
-	if entry.ServiceName == "business-server" {
-		select {
-		case logChan <- entry:
-			fmt.Printf("Log %s successfully send to chanel\n", id)
-		default:
-			log.Println("LogChanel is full, dropping message")
-		}
-	}
 }
@@ -2,9 +2,15 @@ package CRUD
 
 import (
 	"bytes"
+	"context"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"log-service/gen"
+	"log-service/internal/metrics"
+	"log-service/internal/routing"
+	"log-service/internal/tracing"
+	"log-service/internal/tracing/tracingtest"
 	"strings"
 	"testing"
 	"time"
@@ -19,19 +25,16 @@ func (bws bufWriteSyncer) Sync() error { return nil }
 
 func TestWriteLogToFile(t *testing.T) {
 	tests := []struct {
-		name           string
-		level          string
-		serviceName    string
-		expectInChan   bool
-		expectChanFull bool
+		name        string
+		level       string
+		serviceName string
 	}{
-		{"debug level", "debug", "TestService", false, false},
-		{"info level", "info", "TestService", false, false},
-		{"warn level", "warn", "TestService", false, false},
-		{"error level", "error", "TestService", false, false},
-		{"default level", "unknown", "TestService", false, false},
-		{"business-server ok", "info", "business-server", true, false},
-		{"business-server full", "info", "business-server", false, true},
+		{"debug level", "debug", "TestService"},
+		{"info level", "info", "TestService"},
+		{"warn level", "warn", "TestService"},
+		{"error level", "error", "TestService"},
+		{"default level", "unknown", "TestService"},
+		{"business-server", "info", "business-server"},
 	}
 
 	for _, tt := range tests {
@@ -44,11 +47,6 @@ func TestWriteLogToFile(t *testing.T) {
 			core := zapcore.NewCore(encoder, writer, zapcore.DebugLevel)
 			logger := zap.New(core)
 
-			logChan := make(chan *gen.LogEntry, 1)
-			if tt.expectChanFull {
-				logChan <- &gen.LogEntry{}
-			}
-
 			entry := &gen.LogEntry{
 				ServiceName:   tt.serviceName,
 				TimestampSend: time.Now().Add(-50 * time.Millisecond).UnixMilli(),
@@ -66,7 +64,7 @@ func TestWriteLogToFile(t *testing.T) {
 				},
 			}
 
-			WriteLogToFile(logger, tt.level, "test-id", entry, logChan)
+			WriteLogToFile(context.Background(), logger, tt.level, "test-id", entry)
 
 			logOutput := buf.String()
 
@@ -82,21 +80,82 @@ func TestWriteLogToFile(t *testing.T) {
 			if !strings.Contains(logOutput, "/test/path") {
 				t.Errorf("log output missing path: %s", logOutput)
 			}
+		})
+	}
+}
 
-			initialLen := 0
-			if tt.expectChanFull {
-				initialLen = 1
-			}
-
-			finalLen := len(logChan)
+func TestHandleIncomingLogMetrics(t *testing.T) {
+	lm := &LogManager{
+		Loggers: map[string]*zap.Logger{
+			"business-server":  zap.NewNop(),
+			"undefined-server": zap.NewNop(),
+		},
+	}
 
-			if tt.expectInChan && finalLen != initialLen+1 {
-				t.Error("expected log to be added to channel, but it wasn't")
-			}
-			if !tt.expectInChan && finalLen != initialLen {
-				t.Error("log was unexpectedly added to channel")
-			}
+	known := metrics.LogsIngested.WithLabelValues("business-server")
+	undefined := metrics.LogsIngested.WithLabelValues("undefined-server")
+	knownBefore, undefinedBefore := testutil.ToFloat64(known), testutil.ToFloat64(undefined)
 
+	for _, service := range []string{"business-server", "some-new-service", "another-one"} {
+		_, err := lm.HandleIncomingLog(context.Background(), &gen.LogEntry{
+			ServiceName:   service,
+			TimestampSend: time.Now().UnixMilli(),
+			Message:       &gen.StructuredMessage{},
 		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if got := testutil.ToFloat64(known) - knownBefore; got != 1 {
+		t.Errorf("expected 1 entry from business-server, got %v", got)
+	}
+	if got := testutil.ToFloat64(undefined) - undefinedBefore; got != 2 {
+		t.Errorf("expected unknown sources to be counted as undefined-server, got %v", got)
+	}
+	if n := testutil.CollectAndCount(metrics.DeliveryDelay); n != 1 {
+		t.Errorf("expected delivery delay histogram to be exported, got %d series", n)
+	}
+}
+
+func TestWriteLogToFileTraceContext(t *testing.T) {
+	tracingtest.NewInMemory()
+	ctx, span := tracing.Start(context.Background(), "HandleIncomingLog")
+	defer span.End()
+
+	var buf bytes.Buffer
+	logger := zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), bufWriteSyncer{&buf}, zapcore.DebugLevel))
+
+	WriteLogToFile(ctx, logger, "info", "test-id", &gen.LogEntry{
+		ServiceName: "business-server",
+		Message:     &gen.StructuredMessage{Path: "req-id"},
+	})
+
+	traceID := tracing.TraceID(ctx)
+	if !strings.Contains(buf.String(), `"trace_id":"`+traceID+`"`) {
+		t.Errorf("expected trace_id %s in log record, got %s", traceID, buf.String())
+	}
+	if !strings.Contains(buf.String(), `"span_id":"`+tracing.SpanID(ctx)+`"`) {
+		t.Errorf("expected span_id in log record, got %s", buf.String())
+	}
+}
+
+func TestHandleIncomingLogRouter(t *testing.T) {
+	st := newFileStore(t, t.TempDir())
+	router, err := routing.NewRouter(&routing.Config{}, "", func(collection string, sinks []zapcore.WriteSyncer) *zap.Logger {
+		return storeLogger(st, collection)
+	})
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	lm := &LogManager{Router: router, Store: st}
+
+	// Новый сервис получает свою коллекцию без настройки
+	resp, err := lm.HandleIncomingLog(context.Background(), &gen.LogEntry{ServiceName: "payment-server", Message: &gen.StructuredMessage{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := st.Get("payment_logs.json", resp.GetId().GetId()); err != nil {
+		t.Errorf("expected entry in payment_logs.json, got %v", err)
 	}
 }
@@ -4,18 +4,55 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log-service/gen"
+	"log-service/internal/store"
 	"path/filepath"
 
 	"os"
 	"strings"
+	"time"
 )
 
-func (*LogManager) DeleteLog(ctx context.Context, logInfo *gen.LogInfo) (*gen.LogDeletionResponse, error) {
+func (lm *LogManager) DeleteLog(ctx context.Context, logInfo *gen.LogInfo) (*gen.LogDeletionResponse, error) {
 	filename := logInfo.GetFilename()
 	id := logInfo.GetId()
 
+	if lm.Trash != nil {
+		purgeAt, err := lm.Trash.Delete(filename, id, actor(logInfo), logInfo.GetReason())
+		if errors.Is(err, store.ErrNotFound) {
+			return writeWrongDeleteResponse(fmt.Sprintf("log with id %s not found", id)), nil
+		}
+		if errors.Is(err, store.ErrUnknownCollection) {
+			return writeWrongDeleteResponse("failed to open file: " + err.Error()), nil
+		}
+		if err != nil {
+			return writeWrongDeleteResponse("failed to delete log: " + err.Error()), nil
+		}
+		return &gen.LogDeletionResponse{
+			Success: true,
+			Message: "Log with id " + id + " moved to trash of " + filename + " until " + purgeAt.UTC().Format(time.RFC3339),
+			PurgeAt: purgeAt.UnixMilli(),
+		}, nil
+	}
+
+	if lm.Store != nil {
+		err := lm.Store.Delete(filename, id)
+		if errors.Is(err, store.ErrNotFound) {
+			return writeWrongDeleteResponse(fmt.Sprintf("log with id %s not found", id)), nil
+		}
+		if errors.Is(err, store.ErrUnknownCollection) {
+			return writeWrongDeleteResponse("failed to open file: " + err.Error()), nil
+		}
+		if err != nil {
+			return writeWrongDeleteResponse("failed to delete log: " + err.Error()), nil
+		}
+		return &gen.LogDeletionResponse{
+			Success: true,
+			Message: "Log with id " + id + " successfully deleted from " + filename}, nil
+	}
+
 	filePath := filepath.Join("../log_files/", filename)
 
 	file, err := OpenFile(filename)
@@ -45,6 +82,14 @@ func (*LogManager) DeleteLog(ctx context.Context, logInfo *gen.LogInfo) (*gen.Lo
 
 }
 
+// actor возвращает, от чьего имени удаляется или восстанавливается запись.
+func actor(logInfo *gen.LogInfo) string {
+	if a := logInfo.GetActor(); a != "" {
+		return a
+	}
+	return "unknown"
+}
+
 func writeWrongDeleteResponse(err string) *gen.LogDeletionResponse {
 	return &gen.LogDeletionResponse{
 		Success: false,
@@ -84,10 +129,14 @@ func updateLines(lines []string, id string) (updatedLines []string, err error) {
 	return updatedLines, err
 }
 
+// rewriteFile заменяет файл новой версией через временный файл и
+// переименование, чтобы читатели и сбой не застали файл недописанным.
+// Используется только без хранилища: FileStore удаляет надгробиями.
 func rewriteFile(filePath string, updatedLines []string) (err error) {
-	err = os.WriteFile(filePath, []byte(strings.Join(updatedLines, "\n")+"\n"), 0644)
-	if err != nil {
+	tmp := filePath + ".tmp"
+	if err = os.WriteFile(tmp, []byte(strings.Join(updatedLines, "\n")+"\n"), 0644); err != nil {
+		os.Remove(tmp)
 		return err
 	}
-	return nil
+	return os.Rename(tmp, filePath)
 }
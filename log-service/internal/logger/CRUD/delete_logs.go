@@ -0,0 +1,153 @@
+package CRUD
+
+import (
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log-service/gen"
+	"log-service/internal/store"
+	"log-service/internal/trash"
+	"time"
+)
+
+const (
+	defaultDeleteBatch = 500
+	maxDeleteBatch     = 5000
+	// Сколько id найденных записей показывается до удаления
+	deleteSampleSize = 20
+)
+
+// DeleteLogs удаляет все записи, подходящие под фильтр, пачками по batch_size.
+// Первое сообщение потока — число найденных записей и образец их id; при
+// dry_run на нём поток и заканчивается. Затем после каждой пачки
+// отправляется накопленный прогресс. Если настроена корзина, записи
+// переносятся в неё и их можно восстановить до purge_at.
+func (lm *LogManager) DeleteLogs(query *gen.LogDeleteQuery, stream gen.Logger_DeleteLogsServer) error {
+	filter, batchSize, err := newDeleteFilter(query)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if lm.Store == nil {
+		return status.Error(codes.FailedPrecondition, "bulk delete requires a log store")
+	}
+	ctx := stream.Context()
+	// Заголовки сообщают клиенту, что удаление принято, до долгого поиска записей
+	if err := stream.SendHeader(nil); err != nil {
+		return err
+	}
+
+	opts := store.ScanOptions{From: filter.from, To: filter.to}
+	for source := range filter.sources {
+		opts.Sources = append(opts.Sources, source)
+	}
+
+	var (
+		refs    []trash.Ref
+		scanned int
+	)
+	err = lm.Store.Scan(opts, func(r store.Record) bool {
+		if scanned++; scanned%1000 == 0 && ctx.Err() != nil {
+			return false
+		}
+		if hit, ok := filter.hit(r.Collection, string(r.Data)); ok {
+			refs = append(refs, trash.Ref{Collection: r.Collection, ID: hit.key.id})
+		}
+		return true
+	})
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to find logs: %v", err)
+	}
+
+	progress := &gen.LogDeleteProgress{Matched: int64(len(refs))}
+	for _, ref := range refs[:min(len(refs), deleteSampleSize)] {
+		progress.SampleIds = append(progress.SampleIds, ref.ID)
+	}
+	progress.Done = query.GetDryRun() || len(refs) == 0
+	if err := stream.Send(progress); err != nil {
+		return err
+	}
+	if progress.Done {
+		return nil
+	}
+	progress.SampleIds = nil
+
+	by := actor(&gen.LogInfo{Actor: query.GetActor()})
+	for start := 0; start < len(refs); start += batchSize {
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		batch := refs[start:min(start+batchSize, len(refs))]
+
+		deleted, purgeAt, err := lm.deleteBatch(batch, by, query.GetReason())
+		progress.Deleted += int64(deleted)
+		progress.Missing += int64(len(batch) - deleted)
+		if !purgeAt.IsZero() {
+			progress.PurgeAt = purgeAt.UnixMilli()
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to delete logs after %d of %d: %v", progress.Deleted, progress.Matched, err)
+		}
+
+		progress.Done = start+batchSize >= len(refs)
+		if err := stream.Send(progress); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteBatch удаляет пачку записей через корзину или, без неё, из хранилища
+// безвозвратно. Записи, исчезнувшие после поиска, не считаются удалёнными.
+func (lm *LogManager) deleteBatch(refs []trash.Ref, actor, reason string) (int, time.Time, error) {
+	if lm.Trash != nil {
+		return lm.Trash.DeleteBatch(refs, actor, reason)
+	}
+
+	deleted := 0
+	for _, ref := range refs {
+		err := lm.Store.Delete(ref.Collection, ref.ID)
+		if errors.Is(err, store.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return deleted, time.Time{}, err
+		}
+		deleted++
+	}
+	return deleted, time.Time{}, nil
+}
+
+func newDeleteFilter(query *gen.LogDeleteQuery) (*searchFilter, int, error) {
+	f := &searchFilter{
+		sources:       lowerSet(query.GetSources()),
+		from:          query.GetFrom(),
+		to:            query.GetTo(),
+		variable:      query.GetVariable(),
+		metadataKey:   query.GetMetadataKey(),
+		metadataValue: query.GetMetadataValue(),
+	}
+
+	// Пустой фильтр удалил бы все логи разом
+	if f.sources == nil && f.from == 0 && f.to == 0 && f.variable == "" && f.metadataKey == "" {
+		return nil, 0, fmt.Errorf("at least one filter is required: sources, from, to, metadata_key or variable")
+	}
+	if f.metadataValue != "" && f.metadataKey == "" {
+		return nil, 0, fmt.Errorf("metadata_value requires metadata_key")
+	}
+	if f.to != 0 && f.from > f.to {
+		return nil, 0, fmt.Errorf("from is after to")
+	}
+
+	batchSize := int(query.GetBatchSize())
+	if batchSize < 0 || batchSize > maxDeleteBatch {
+		return nil, 0, fmt.Errorf("batch_size must be between 1 and %d", maxDeleteBatch)
+	}
+	if batchSize == 0 {
+		batchSize = defaultDeleteBatch
+	}
+	return f, batchSize, nil
+}
@@ -0,0 +1,134 @@
+package CRUD
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"log-service/gen"
+	"log-service/internal/store"
+	"log-service/internal/trash"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type deleteStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*gen.LogDeleteProgress
+}
+
+func (s *deleteStream) Context() context.Context { return s.ctx }
+
+func (s *deleteStream) SendHeader(metadata.MD) error { return nil }
+
+func (s *deleteStream) Send(p *gen.LogDeleteProgress) error {
+	// DeleteLogs переиспользует сообщение между отправками
+	s.sent = append(s.sent, proto.Clone(p).(*gen.LogDeleteProgress))
+	return nil
+}
+
+func TestDeleteLogsDryRun(t *testing.T) {
+	lm := newSearchManager(t)
+	stream := &deleteStream{ctx: context.Background()}
+
+	err := lm.DeleteLogs(&gen.LogDeleteQuery{Variable: "total", DryRun: true}, stream)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stream.sent) != 1 || !stream.sent[0].GetDone() || stream.sent[0].GetMatched() != 3 || len(stream.sent[0].GetSampleIds()) != 3 {
+		t.Fatalf("expected a single report of 3 matches, got %v", stream.sent)
+	}
+
+	result, _ := lm.SearchLogs(context.Background(), &gen.LogSearchQuery{})
+	if got := hitIDs(t, result); len(got) != 5 {
+		t.Errorf("expected dry run to keep all logs, got %v", got)
+	}
+}
+
+func TestDeleteLogsInBatches(t *testing.T) {
+	lm := newSearchManager(t)
+	tr, err := trash.New(lm.Store, store.NewMemoryStore(), filepath.Join(t.TempDir(), "audit.jsonl"), time.Hour)
+	if err != nil {
+		t.Fatalf("failed to open trash: %v", err)
+	}
+	defer tr.Close()
+	lm.Trash = tr
+
+	stream := &deleteStream{ctx: context.Background()}
+	err = lm.DeleteLogs(&gen.LogDeleteQuery{Sources: []string{"http-server"}, BatchSize: 2, Actor: "ops"}, stream)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var deleted []int64
+	for _, p := range stream.sent {
+		deleted = append(deleted, p.GetDeleted())
+	}
+	if want := []int64{0, 2, 3}; !reflect.DeepEqual(deleted, want) {
+		t.Errorf("expected progress %v, got %v", want, deleted)
+	}
+	last := stream.sent[len(stream.sent)-1]
+	if !last.GetDone() || last.GetMatched() != 3 || last.GetPurgeAt() == 0 {
+		t.Errorf("expected final report with purge time, got %v", last)
+	}
+
+	result, _ := lm.SearchLogs(context.Background(), &gen.LogSearchQuery{})
+	if got, want := hitIDs(t, result), []string{"res3", "res1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v to remain, got %v", want, got)
+	}
+	if err := tr.Restore("http_logs.json", "req2", "ops", ""); err != nil {
+		t.Errorf("expected bulk deleted log to be restorable, got %v", err)
+	}
+}
+
+func TestDeleteLogsByMetadata(t *testing.T) {
+	lm := newSearchManager(t)
+	for _, line := range []string{
+		`{"id":"m1","source":"HTTP-server","metadata":{"tenant":"acme"},"timestamp_received":4000}`,
+		`{"id":"m2","source":"HTTP-server","metadata":{"tenant":"other"},"timestamp_received":4001}`,
+	} {
+		if err := lm.Store.Append("http_logs.json", []byte(line)); err != nil {
+			t.Fatalf("failed to append log: %v", err)
+		}
+	}
+
+	stream := &deleteStream{ctx: context.Background()}
+	if err := lm.DeleteLogs(&gen.LogDeleteQuery{MetadataKey: "tenant", MetadataValue: "acme"}, stream); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	last := stream.sent[len(stream.sent)-1]
+	if last.GetDeleted() != 1 || last.GetPurgeAt() != 0 || !reflect.DeepEqual(stream.sent[0].GetSampleIds(), []string{"m1"}) {
+		t.Errorf("expected only m1 to be deleted permanently, got %v", stream.sent)
+	}
+	if _, err := lm.Store.Get("http_logs.json", "m2"); err != nil {
+		t.Errorf("expected m2 to be kept, got %v", err)
+	}
+}
+
+func TestDeleteLogsInvalidQuery(t *testing.T) {
+	lm := newSearchManager(t)
+
+	tests := []struct {
+		name  string
+		query *gen.LogDeleteQuery
+	}{
+		{name: "no filter", query: &gen.LogDeleteQuery{DryRun: true}},
+		{name: "value without key", query: &gen.LogDeleteQuery{MetadataValue: "acme"}},
+		{name: "inverted range", query: &gen.LogDeleteQuery{From: 10, To: 5}},
+		{name: "batch too large", query: &gen.LogDeleteQuery{Variable: "x", BatchSize: maxDeleteBatch + 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := lm.DeleteLogs(tt.query, &deleteStream{ctx: context.Background()})
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package CRUD
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log-service/gen"
+	"log-service/internal/index"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadAndDeleteLogWithStore(t *testing.T) {
+	dir := t.TempDir()
+	appendLine(t, filepath.Join(dir, "http_logs.json"), `{"id":"abc123","msg":"hello world"}`)
+	appendLine(t, filepath.Join(dir, "http_logs.json"), `{"id":"def456","msg":"another log"}`)
+
+	lm := &LogManager{Store: newFileStore(t, dir)}
+	ctx := context.Background()
+
+	read, _ := lm.ReadLog(ctx, &gen.LogInfo{Filename: "http_logs.json", Id: "def456"})
+	if !read.GetSuccess() || read.GetLog() != `{"id":"def456","msg":"another log"}` {
+		t.Fatalf("expected def456 to be read, got %v", read)
+	}
+
+	deleted, _ := lm.DeleteLog(ctx, &gen.LogInfo{Filename: "http_logs.json", Id: "abc123"})
+	if !deleted.GetSuccess() {
+		t.Fatalf("expected abc123 to be deleted, got %v", deleted)
+	}
+
+	tests := []struct {
+		name      string
+		id        string
+		expectOk  bool
+		expectMsg string
+	}{
+		{name: "deleted log", id: "abc123", expectMsg: "not found"},
+		{name: "remaining log", id: "def456", expectOk: true},
+		{name: "unknown log", id: "zzz999", expectMsg: "not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			read, _ := lm.ReadLog(ctx, &gen.LogInfo{Filename: "http_logs.json", Id: tt.id})
+			if read.GetSuccess() != tt.expectOk || !strings.Contains(read.GetError(), tt.expectMsg) {
+				t.Errorf("expected success %v and error containing %q, got %v", tt.expectOk, tt.expectMsg, read)
+			}
+		})
+	}
+
+	again, _ := lm.DeleteLog(ctx, &gen.LogInfo{Filename: "http_logs.json", Id: "abc123"})
+	if again.GetSuccess() || again.GetMessage() != "log with id abc123 not found" {
+		t.Errorf("expected repeated delete to report not found, got %v", again)
+	}
+}
+
+const benchRecords = 1_000_000
+
+func benchID(i int) string {
+	return fmt.Sprintf("id%08d", i)
+}
+
+// writeBenchLogs создаёт файл логов из benchRecords записей в формате WriteLogToFile.
+func writeBenchLogs(b *testing.B) (dir string) {
+	b.Helper()
+	dir = b.TempDir()
+	file, err := os.Create(filepath.Join(dir, "http_logs.json"))
+	if err != nil {
+		b.Fatalf("failed to create log file: %v", err)
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	for i := 0; i < benchRecords; i++ {
+		fmt.Fprintf(w, `{"level":"info","msg":"New log entry","id":%q,"message":"{\"method\":\"POST\",\"path\":\"/process\",\"body\":[{\"type\":\"calc\",\"op\":\"+\",\"var\":\"x\",\"left\":\"1\",\"right\":\"2\"}]}","path":"/process","request_id":%q,"source":"HTTP-server","timestamp_send":%d,"timestamp_received":%d}`+"\n",
+			benchID(i), benchID(i), 1_700_000_000_000+int64(i), 1_700_000_000_005+int64(i))
+	}
+	if err := w.Flush(); err != nil {
+		b.Fatalf("failed to write log file: %v", err)
+	}
+	return dir
+}
+
+func BenchmarkReadLogLinearScan(b *testing.B) {
+	file, err := os.Open(filepath.Join(writeBenchLogs(b), "http_logs.json"))
+	if err != nil {
+		b.Fatalf("failed to open log file: %v", err)
+	}
+	defer file.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		file.Seek(0, 0)
+		if _, err := FindLog(file, benchID((i*7919+benchRecords/2)%benchRecords)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadLogIndexed(b *testing.B) {
+	dir := writeBenchLogs(b)
+	lm := &LogManager{Store: newFileStore(b, dir)}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resp, _ := lm.ReadLog(context.Background(), &gen.LogInfo{Filename: "http_logs.json", Id: benchID((i * 7919) % benchRecords)})
+		if !resp.GetSuccess() {
+			b.Fatal(resp.GetError())
+		}
+	}
+}
+
+func BenchmarkDeleteLogRewrite(b *testing.B) {
+	path := filepath.Join(writeBenchLogs(b), "http_logs.json")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		file, err := os.Open(path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		lines, err := readLines(file)
+		file.Close()
+		if err != nil {
+			b.Fatal(err)
+		}
+		updated, err := updateLines(lines, benchID(i%benchRecords))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := rewriteFile(path, updated); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDeleteLogIndexed(b *testing.B) {
+	dir := writeBenchLogs(b)
+	lm := &LogManager{Store: newFileStore(b, dir)}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resp, _ := lm.DeleteLog(context.Background(), &gen.LogInfo{Filename: "http_logs.json", Id: benchID(i % benchRecords)})
+		if !resp.GetSuccess() {
+			b.Fatal(resp.GetMessage())
+		}
+	}
+}
+
+// BenchmarkOpenIndex — запуск сервиса: построение индекса без журнала и загрузка из журнала.
+func BenchmarkOpenIndex(b *testing.B) {
+	dir := writeBenchLogs(b)
+	journal := filepath.Join(dir, "http_logs.json"+index.JournalSuffix)
+
+	b.Run("rebuild", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			os.Remove(journal)
+			idx, err := index.Open(dir)
+			if err != nil {
+				b.Fatal(err)
+			}
+			idx.Close()
+		}
+	})
+	b.Run("journal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			idx, err := index.Open(dir)
+			if err != nil {
+				b.Fatal(err)
+			}
+			idx.Close()
+		}
+	})
+}
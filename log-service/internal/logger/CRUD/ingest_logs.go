@@ -0,0 +1,71 @@
+package CRUD
+
+import (
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"log-service/gen"
+)
+
+// Сколько записей потока IngestLogs фиксируется одним Sync
+const ingestBatch = 500
+
+// IngestLogs принимает поток записей и фиксирует их пачками по ingestBatch:
+// одна транзакция outbox, одна дозапись и один Sync на пачку вместо
+// фиксации каждой записи. Ответ содержит id записей в порядке отправки,
+// включая отброшенные политикой. Если фиксация или постановка в outbox не
+// удалась, поток завершается ошибкой и id не возвращаются; записи до
+// последней успешной пачки уже сохранены.
+func (lm *LogManager) IngestLogs(stream gen.Logger_IngestLogsServer) error {
+	ctx := stream.Context()
+
+	var (
+		ids   []*gen.LogID
+		batch []*accepted
+	)
+	commit := func() error {
+		if err := lm.enqueue(batch); err != nil {
+			return status.Errorf(codes.Internal, "failed to queue logs for Kafka after %d entries: %v", len(ids)-len(batch), err)
+		}
+		pending := make(map[*zap.Logger]bool)
+		for _, a := range batch {
+			if a.logger != nil {
+				lm.write(ctx, a)
+				pending[a.logger] = true
+			}
+		}
+		for logger := range pending {
+			if err := logger.Sync(); err != nil {
+				return status.Errorf(codes.Internal, "failed to write logs after %d entries: %v", len(ids)-len(batch), err)
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		entry, err := stream.Recv()
+		if err == io.EOF {
+			if err := commit(); err != nil {
+				return err
+			}
+			return stream.SendAndClose(&gen.LogIngestResponse{Ids: ids})
+		}
+		if err != nil {
+			return err
+		}
+
+		a, err := lm.accept(ctx, entry)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, a.id)
+		batch = append(batch, a)
+		if len(batch) == ingestBatch {
+			if err := commit(); err != nil {
+				return err
+			}
+		}
+	}
+}
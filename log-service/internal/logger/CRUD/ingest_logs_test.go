@@ -0,0 +1,98 @@
+package CRUD
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"io"
+	"log-service/gen"
+	"log-service/internal/outbox"
+	"log-service/internal/store"
+	"testing"
+)
+
+type ingestStream struct {
+	grpc.ServerStream
+	entries  []*gen.LogEntry
+	response *gen.LogIngestResponse
+}
+
+func (s *ingestStream) Context() context.Context { return context.Background() }
+
+func (s *ingestStream) Recv() (*gen.LogEntry, error) {
+	if len(s.entries) == 0 {
+		return nil, io.EOF
+	}
+	entry := s.entries[0]
+	s.entries = s.entries[1:]
+	return entry, nil
+}
+
+func (s *ingestStream) SendAndClose(resp *gen.LogIngestResponse) error {
+	s.response = resp
+	return nil
+}
+
+func TestIngestLogs(t *testing.T) {
+	st := newFileStore(t, t.TempDir())
+	committer := store.NewCommitter(st)
+	logger := func(collection string) *zap.Logger {
+		return zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), committer.Writer(collection), zapcore.DebugLevel))
+	}
+	ob, err := outbox.Open(t.TempDir(), outbox.Options{})
+	if err != nil {
+		t.Fatalf("failed to open outbox: %v", err)
+	}
+	defer ob.Close()
+	lm := &LogManager{
+		Loggers: map[string]*zap.Logger{
+			"HTTP-server":      logger(requestLogFile),
+			"business-server":  logger(resultLogFile),
+			"undefined-server": logger("undefined_logs.json"),
+		},
+		Store:  st,
+		Outbox: ob,
+	}
+
+	stream := &ingestStream{}
+	for i := 0; i < ingestBatch+2; i++ {
+		service := "HTTP-server"
+		if i%2 == 1 {
+			service = "business-server"
+		}
+		stream.entries = append(stream.entries, &gen.LogEntry{ServiceName: service, Message: &gen.StructuredMessage{Path: "/process"}})
+	}
+	stream.entries = append(stream.entries, &gen.LogEntry{ServiceName: "unknown", Message: &gen.StructuredMessage{}})
+
+	if err := lm.IngestLogs(stream); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Результаты business-server поставлены в outbox до ответа
+	if stats, _ := ob.Stats(); stats.Pending != (ingestBatch+2)/2 {
+		t.Errorf("expected %d pending outbox messages, got %+v", (ingestBatch+2)/2, stats)
+	}
+
+	ids := stream.response.GetIds()
+	if len(ids) != ingestBatch+3 {
+		t.Fatalf("expected %d ids, got %d", ingestBatch+3, len(ids))
+	}
+	for i, id := range ids {
+		collection := requestLogFile
+		switch {
+		case i == len(ids)-1:
+			collection = "undefined_logs.json"
+		case i%2 == 1:
+			collection = resultLogFile
+		}
+		// Ответ приходит после фиксации: записи уже читаются
+		data, err := st.Get(collection, id.GetId())
+		if err != nil {
+			t.Fatalf("expected entry %d to be stored in %s, got %v", i, collection, err)
+		}
+		if got := logID(t, string(data)); got != id.GetId() {
+			t.Fatalf("expected ids in the order entries were sent, entry %d has %s", i, got)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package CRUD
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log-service/gen"
+	"log-service/internal/policy"
+)
+
+// SetLogPolicy меняет политику приёма записей сервиса без перезапуска и
+// возвращает все действующие политики.
+func (lm *LogManager) SetLogPolicy(ctx context.Context, req *gen.LogPolicy) (*gen.LogPolicies, error) {
+	if lm.Policies == nil {
+		return nil, status.Error(codes.FailedPrecondition, "log policies are not configured")
+	}
+	err := lm.Policies.Set(policy.Policy{
+		Service:         req.GetService(),
+		MinLevel:        req.GetMinLevel(),
+		DebugSampleRate: req.GetDebugSampleRate(),
+		InfoSampleRate:  req.GetInfoSampleRate(),
+	})
+	if errors.Is(err, policy.ErrInvalidPolicy) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to set log policy: %v", err)
+	}
+	return lm.GetLogPolicies(ctx, &gen.Nothing{})
+}
+
+// GetLogPolicies возвращает политики сервисов со счётчиками отброшенных записей.
+func (lm *LogManager) GetLogPolicies(ctx context.Context, _ *gen.Nothing) (*gen.LogPolicies, error) {
+	resp := &gen.LogPolicies{}
+	if lm.Policies == nil {
+		return resp, nil
+	}
+	for _, s := range lm.Policies.List() {
+		resp.Policies = append(resp.Policies, &gen.LogPolicy{
+			Service:           s.Service,
+			MinLevel:          s.MinLevel,
+			DebugSampleRate:   s.DebugSampleRate,
+			InfoSampleRate:    s.InfoSampleRate,
+			DroppedByLevel:    s.DroppedByLevel,
+			DroppedBySampling: s.DroppedBySampling,
+		})
+	}
+	return resp, nil
+}
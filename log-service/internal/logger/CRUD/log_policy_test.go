@@ -0,0 +1,154 @@
+package CRUD
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log-service/gen"
+	"log-service/internal/metrics"
+	"log-service/internal/outbox"
+	"log-service/internal/policy"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newPolicyManager(t *testing.T) (*LogManager, string) {
+	t.Helper()
+	st := newFileStore(t, t.TempDir())
+	policies, err := policy.Open(filepath.Join(t.TempDir(), "log_policies.json"))
+	if err != nil {
+		t.Fatalf("failed to open policies: %v", err)
+	}
+	return &LogManager{
+		Loggers: map[string]*zap.Logger{
+			"business-server":  storeLogger(st, resultLogFile),
+			"undefined-server": zap.NewNop(),
+		},
+		Store:    st,
+		Policies: policies,
+	}, resultLogFile
+}
+
+func TestHandleIncomingLogPolicy(t *testing.T) {
+	lm, collection := newPolicyManager(t)
+	ctx := context.Background()
+
+	if _, err := lm.SetLogPolicy(ctx, &gen.LogPolicy{Service: "business-server", MinLevel: "warn"}); err != nil {
+		t.Fatalf("failed to set policy: %v", err)
+	}
+	droppedBefore := testutil.ToFloat64(metrics.PolicyDropped.WithLabelValues("business-server", policy.ReasonLevel))
+
+	tests := []struct {
+		level   string
+		dropped bool
+		stored  string
+	}{
+		{level: "DEBUG", dropped: true},
+		{level: "Info", dropped: true},
+		{level: "WARNING", stored: `"level":"warn"`},
+		{level: "fatal", stored: `"level":"error"`},
+	}
+	for _, tt := range tests {
+		resp, err := lm.HandleIncomingLog(ctx, &gen.LogEntry{ServiceName: "business-server", Level: tt.level, Message: &gen.StructuredMessage{}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.GetId().GetId() == "" || resp.GetDropped() != tt.dropped {
+			t.Errorf("%s: expected an id and dropped=%v, got %v", tt.level, tt.dropped, resp)
+		}
+		data, err := lm.Store.Get(collection, resp.GetId().GetId())
+		if tt.dropped {
+			if err == nil {
+				t.Errorf("%s: expected dropped entry not to be stored", tt.level)
+			}
+			continue
+		}
+		if err != nil || !strings.Contains(string(data), tt.stored) {
+			t.Errorf("%s: expected stored entry with %s, got %s (%v)", tt.level, tt.stored, data, err)
+		}
+	}
+
+	if got := testutil.ToFloat64(metrics.PolicyDropped.WithLabelValues("business-server", policy.ReasonLevel)) - droppedBefore; got != 2 {
+		t.Errorf("expected 2 entries counted as dropped by level, got %v", got)
+	}
+	resp, err := lm.GetLogPolicies(ctx, &gen.Nothing{})
+	if err != nil {
+		t.Fatalf("failed to get policies: %v", err)
+	}
+	if len(resp.GetPolicies()) != 1 || resp.GetPolicies()[0].GetDroppedByLevel() != 2 {
+		t.Errorf("expected business-server policy with 2 dropped entries, got %v", resp.GetPolicies())
+	}
+}
+
+func TestHandleIncomingLogPolicyKeepsOperationsAndResults(t *testing.T) {
+	lm, collection := newPolicyManager(t)
+	lm.Loggers["HTTP-server"] = storeLogger(lm.Store, requestLogFile)
+	ob, err := outbox.Open(t.TempDir(), outbox.Options{})
+	if err != nil {
+		t.Fatalf("failed to open outbox: %v", err)
+	}
+	defer ob.Close()
+	lm.Outbox = ob
+	ctx := context.Background()
+
+	for _, service := range []string{"HTTP-server", "business-server"} {
+		if _, err := lm.SetLogPolicy(ctx, &gen.LogPolicy{Service: service, MinLevel: "error"}); err != nil {
+			t.Fatalf("failed to set policy: %v", err)
+		}
+	}
+
+	// Операции запроса нужны отправке результата в Kafka и хронологии
+	request, err := lm.HandleIncomingLog(ctx, &gen.LogEntry{
+		ServiceName: "HTTP-server",
+		Level:       "info",
+		Message:     &gen.StructuredMessage{Body: []*gen.Operation{{Type: "print", Var: "x"}}},
+	})
+	if err != nil || request.GetDropped() {
+		t.Fatalf("expected request with operations to be kept, got %v (%v)", request, err)
+	}
+	if _, err := lm.Store.Get(requestLogFile, request.GetId().GetId()); err != nil {
+		t.Errorf("expected request with operations to be stored: %v", err)
+	}
+
+	result, err := lm.HandleIncomingLog(ctx, &gen.LogEntry{
+		ServiceName: "business-server",
+		Level:       "info",
+		ParentId:    request.GetId().GetId(),
+		Message:     &gen.StructuredMessage{Result: &gen.OperationResponse{}},
+	})
+	if err != nil || result.GetDropped() {
+		t.Fatalf("expected result to be kept, got %v (%v)", result, err)
+	}
+	if _, err := lm.Store.Get(collection, result.GetId().GetId()); err != nil {
+		t.Errorf("expected result to be stored: %v", err)
+	}
+
+	// Отброшенная политикой запись business-server всё равно уходит в Kafka
+	dropped, err := lm.HandleIncomingLog(ctx, &gen.LogEntry{ServiceName: "business-server", Level: "info", Message: &gen.StructuredMessage{}})
+	if err != nil || !dropped.GetDropped() {
+		t.Fatalf("expected plain info entry to be dropped, got %v (%v)", dropped, err)
+	}
+	if stats, _ := ob.Stats(); stats.Pending != 2 {
+		t.Errorf("expected both business-server entries in the outbox, got %+v", stats)
+	}
+}
+
+func TestSetLogPolicyErrors(t *testing.T) {
+	ctx := context.Background()
+	if _, err := (&LogManager{}).SetLogPolicy(ctx, &gen.LogPolicy{MinLevel: "warn"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition without policies, got %v", err)
+	}
+
+	lm, _ := newPolicyManager(t)
+	for _, req := range []*gen.LogPolicy{
+		{Service: "business-server", MinLevel: "loud"},
+		{Service: "business-server", InfoSampleRate: 2},
+	} {
+		if _, err := lm.SetLogPolicy(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("expected InvalidArgument for %v, got %v", req, err)
+		}
+	}
+}
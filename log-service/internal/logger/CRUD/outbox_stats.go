@@ -0,0 +1,35 @@
+package CRUD
+
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log-service/gen"
+)
+
+// GetOutboxStats возвращает глубину очереди отправки в Kafka и состояние
+// доставки её первого сообщения.
+func (lm *LogManager) GetOutboxStats(ctx context.Context, _ *gen.Nothing) (*gen.OutboxStats, error) {
+	if lm.Outbox == nil {
+		return nil, status.Error(codes.FailedPrecondition, "outbox is not configured")
+	}
+	stats, err := lm.Outbox.Stats()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read outbox: %v", err)
+	}
+
+	resp := &gen.OutboxStats{
+		Pending:      stats.Pending,
+		Attempts:     uint32(stats.Attempts),
+		LastError:    stats.LastError,
+		Delivered:    stats.Delivered,
+		DeadLettered: stats.DeadLettered,
+	}
+	if !stats.OldestEnqueuedAt.IsZero() {
+		resp.OldestEnqueuedAt = stats.OldestEnqueuedAt.UnixMilli()
+	}
+	if !stats.NextAttempt.IsZero() {
+		resp.NextAttemptAt = stats.NextAttempt.UnixMilli()
+	}
+	return resp, nil
+}
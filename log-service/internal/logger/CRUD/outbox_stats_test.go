@@ -0,0 +1,77 @@
+package CRUD
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"log-service/gen"
+	"log-service/internal/outbox"
+	"log-service/internal/tracing"
+	"log-service/internal/tracing/tracingtest"
+	"testing"
+	"time"
+)
+
+func TestHandleIncomingLogOutbox(t *testing.T) {
+	ob, err := outbox.Open(t.TempDir(), outbox.Options{})
+	if err != nil {
+		t.Fatalf("failed to open outbox: %v", err)
+	}
+	defer ob.Close()
+	lm := &LogManager{
+		Loggers: map[string]*zap.Logger{
+			"HTTP-server":      zap.NewNop(),
+			"business-server":  zap.NewNop(),
+			"undefined-server": zap.NewNop(),
+		},
+		Outbox: ob,
+	}
+
+	tracingtest.NewInMemory()
+	ctx, span := tracing.Start(context.Background(), "HandleIncomingLog")
+	defer span.End()
+
+	// В outbox попадают только результаты business-server
+	for _, service := range []string{"HTTP-server", "business-server"} {
+		if _, err := lm.HandleIncomingLog(ctx, &gen.LogEntry{ServiceName: service, ParentId: "parent", Message: &gen.StructuredMessage{}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	stats, err := lm.GetOutboxStats(ctx, &gen.Nothing{})
+	if err != nil {
+		t.Fatalf("failed to get outbox stats: %v", err)
+	}
+	if stats.GetPending() != 1 || stats.GetOldestEnqueuedAt() == 0 {
+		t.Errorf("expected one pending entry, got %v", stats)
+	}
+
+	queued := make(chan *gen.LogEntry, 1)
+	go ob.Run(func(_ context.Context, payload []byte) error {
+		var entry gen.LogEntry
+		if err := proto.Unmarshal(payload, &entry); err != nil {
+			return err
+		}
+		queued <- &entry
+		return nil
+	})
+	select {
+	case entry := <-queued:
+		if entry.GetServiceName() != "business-server" || entry.GetParentId() != "parent" {
+			t.Errorf("expected the business-server entry, got %v", entry)
+		}
+		// Контекст trace доходит до отправки в Kafka вместе с записью
+		if got := tracing.TraceID(tracing.ExtractMetadata(context.Background(), entry)); got != tracing.TraceID(ctx) {
+			t.Errorf("expected trace context to travel with the entry to Kafka, got %q", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the entry to be delivered")
+	}
+}
+
+func TestGetOutboxStatsWithoutOutbox(t *testing.T) {
+	if _, err := (&LogManager{}).GetOutboxStats(context.Background(), &gen.Nothing{}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition without outbox, got %v", err)
+	}
+}
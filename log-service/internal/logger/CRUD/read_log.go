@@ -4,17 +4,30 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log-service/gen"
+	"log-service/internal/store"
 	"os"
 	"path/filepath"
 )
 
-func (*LogManager) ReadLog(ctx context.Context, logInfo *gen.LogInfo) (*gen.LogReadingResponse, error) {
+func (lm *LogManager) ReadLog(ctx context.Context, logInfo *gen.LogInfo) (*gen.LogReadingResponse, error) {
 
 	filename := logInfo.GetFilename()
 	fmt.Println(filename)
 
+	if lm.Store != nil {
+		log, err := lm.Store.Get(filename, logInfo.GetId())
+		if errors.Is(err, store.ErrUnknownCollection) {
+			return writeWrongReadResponse("failed to open file", err), nil
+		}
+		if err != nil {
+			return writeWrongReadResponse(fmt.Sprintf("failed to find log in %s", filename), err), nil
+		}
+		return &gen.LogReadingResponse{Success: true, Log: string(log)}, nil
+	}
+
 	file, err := OpenFile(filename)
 	if err != nil {
 		return writeWrongReadResponse("failed to open file", err), nil
@@ -50,7 +63,6 @@ func FindLog(file *os.File, id string) (log string, err error) {
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := scanner.Text()
-		fmt.Println("	New line starts here: ", line)
 		var logId struct {
 			ID string `json:"id"`
 		}
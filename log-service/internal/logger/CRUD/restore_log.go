@@ -0,0 +1,40 @@
+package CRUD
+
+import (
+	"context"
+	"errors"
+	"log-service/gen"
+	"log-service/internal/store"
+	"log-service/internal/trash"
+)
+
+func (lm *LogManager) RestoreLog(ctx context.Context, logInfo *gen.LogInfo) (*gen.LogRestorationResponse, error) {
+	filename := logInfo.GetFilename()
+	id := logInfo.GetId()
+
+	if lm.Trash == nil {
+		return writeWrongRestoreResponse("restore is not available: deleted logs are not kept"), nil
+	}
+
+	err := lm.Trash.Restore(filename, id, actor(logInfo), logInfo.GetReason())
+	if errors.Is(err, trash.ErrNotDeleted) {
+		return writeWrongRestoreResponse("log with id " + id + " is not in trash of " + filename), nil
+	}
+	if errors.Is(err, store.ErrUnknownCollection) {
+		return writeWrongRestoreResponse("failed to open file: " + err.Error()), nil
+	}
+	if err != nil {
+		return writeWrongRestoreResponse("failed to restore log: " + err.Error()), nil
+	}
+	return &gen.LogRestorationResponse{
+		Success: true,
+		Message: "Log with id " + id + " restored to " + filename,
+	}, nil
+}
+
+func writeWrongRestoreResponse(err string) *gen.LogRestorationResponse {
+	return &gen.LogRestorationResponse{
+		Success: false,
+		Message: err,
+	}
+}
@@ -0,0 +1,51 @@
+package CRUD
+
+import (
+	"context"
+	"log-service/gen"
+	"log-service/internal/store"
+	"log-service/internal/trash"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSoftDeleteAndRestoreLog(t *testing.T) {
+	dir := t.TempDir()
+	appendLine(t, filepath.Join(dir, "http_logs.json"), `{"id":"abc123","msg":"hello world"}`)
+	st := newFileStore(t, dir)
+	tr, err := trash.New(st, store.NewMemoryStore(), filepath.Join(dir, "audit.jsonl"), time.Hour)
+	if err != nil {
+		t.Fatalf("failed to open trash: %v", err)
+	}
+	defer tr.Close()
+
+	lm := &LogManager{Store: st, Trash: tr}
+	ctx := context.Background()
+	info := &gen.LogInfo{Filename: "http_logs.json", Id: "abc123", Actor: "ops", Reason: "cleanup"}
+
+	deleted, _ := lm.DeleteLog(ctx, info)
+	if !deleted.GetSuccess() || deleted.GetPurgeAt() <= time.Now().UnixMilli() {
+		t.Fatalf("expected abc123 to be moved to trash, got %v", deleted)
+	}
+	if read, _ := lm.ReadLog(ctx, info); read.GetSuccess() {
+		t.Errorf("expected soft-deleted log to be hidden, got %v", read)
+	}
+
+	restored, _ := lm.RestoreLog(ctx, info)
+	if !restored.GetSuccess() {
+		t.Fatalf("expected abc123 to be restored, got %v", restored)
+	}
+	if read, _ := lm.ReadLog(ctx, info); read.GetLog() != `{"id":"abc123","msg":"hello world"}` {
+		t.Errorf("expected restored log to be readable, got %v", read)
+	}
+
+	again, _ := lm.RestoreLog(ctx, info)
+	if again.GetSuccess() || !strings.Contains(again.GetMessage(), "is not in trash") {
+		t.Errorf("expected repeated restore to fail, got %v", again)
+	}
+	if noTrash, _ := (&LogManager{Store: st}).RestoreLog(ctx, info); noTrash.GetSuccess() {
+		t.Errorf("expected restore without trash to fail, got %v", noTrash)
+	}
+}
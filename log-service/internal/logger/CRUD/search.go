@@ -0,0 +1,411 @@
+package CRUD
+
+import (
+	"container/heap"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"log-service/gen"
+	"log-service/internal/store"
+	"log-service/internal/utils"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 500
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// SearchLogs ищет записи во всех коллекциях хранилища. Страницы задаются
+// курсором по ключу сортировки и id последней записи, поэтому дописанные
+// между запросами строки не сдвигают уже выданные страницы.
+func (lm *LogManager) SearchLogs(ctx context.Context, query *gen.LogSearchQuery) (*gen.LogSearchResult, error) {
+	filter, err := newSearchFilter(query)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// Лишняя запись показывает, что есть следующая страница
+	var hits []searchHit
+	err = lm.eachHit(ctx, filter, filter.limit+1, func(hit searchHit) bool {
+		hits = append(hits, hit)
+		return len(hits) <= filter.limit
+	})
+	if ctx.Err() != nil {
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to search logs: %v", err)
+	}
+
+	return filter.page(hits), nil
+}
+
+// eachHit передаёт fn записи, подходящие под фильтр, после курсора f.after в
+// порядке выдачи, пока fn возвращает true. Хранилище с индексом времени
+// отдаёт записи по порядку само, и обход останавливается вместе с fn. Иначе
+// записи выбираются проходами по хранилищу, каждый из которых держит в куче
+// не больше batch ближайших к курсору записей.
+func (lm *LogManager) eachHit(ctx context.Context, f *searchFilter, batch int, fn func(searchHit) bool) error {
+	scanned := 0
+	cancelled := func() bool {
+		scanned++
+		return scanned%1000 == 0 && ctx.Err() != nil
+	}
+
+	opts := f.scanOptions()
+	if scanner, ok := lm.Store.(store.TimeScanner); ok && !f.byDelay {
+		if f.after != nil {
+			// Курсор по времени сужает выборку индекса
+			if f.ascending {
+				opts.From = max(opts.From, f.after.value)
+			} else if opts.To == 0 || f.after.value < opts.To {
+				opts.To = f.after.value
+			}
+		}
+		return scanner.ScanByTime(opts, !f.ascending, func(r store.Record) bool {
+			if cancelled() {
+				return false
+			}
+			hit, ok := f.hit(r.Collection, string(r.Data))
+			return !ok || !f.afterCursor(hit.key) || fn(hit)
+		})
+	}
+
+	pass := *f
+	for {
+		hits := &hitHeap{filter: &pass}
+		err := lm.Store.Scan(opts, func(r store.Record) bool {
+			if cancelled() {
+				return false
+			}
+			if hit, ok := pass.hit(r.Collection, string(r.Data)); ok && pass.afterCursor(hit.key) {
+				heap.Push(hits, hit)
+				if hits.Len() > batch {
+					heap.Pop(hits)
+				}
+			}
+			return true
+		})
+		if err != nil || ctx.Err() != nil {
+			return err
+		}
+
+		found := hits.hits
+		sort.Slice(found, func(i, j int) bool { return pass.before(found[i].key, found[j].key) })
+		for _, hit := range found {
+			if !fn(hit) {
+				return nil
+			}
+		}
+		if len(found) < batch {
+			return nil
+		}
+		pass.after = &found[len(found)-1].key
+	}
+}
+
+// hitHeap держит ближайшие к курсору записи; на вершине — самая дальняя из них.
+type hitHeap struct {
+	filter *searchFilter
+	hits   []searchHit
+}
+
+func (h *hitHeap) Len() int           { return len(h.hits) }
+func (h *hitHeap) Less(i, j int) bool { return h.filter.before(h.hits[j].key, h.hits[i].key) }
+func (h *hitHeap) Swap(i, j int)      { h.hits[i], h.hits[j] = h.hits[j], h.hits[i] }
+func (h *hitHeap) Push(x any)         { h.hits = append(h.hits, x.(searchHit)) }
+func (h *hitHeap) Pop() any {
+	last := h.hits[len(h.hits)-1]
+	h.hits = h.hits[:len(h.hits)-1]
+	return last
+}
+
+type searchFilter struct {
+	sources  map[string]bool
+	levels   map[string]bool
+	from, to int64
+	path     string
+	variable string
+	// Ключ метаданных и, если не пусто, его значение
+	metadataKey   string
+	metadataValue string
+	minDelay      int64
+	text          string
+	limit         int
+	byDelay       bool
+	ascending     bool
+	after         *searchKey
+	// Только записи с id в формате ULID больше этого
+	afterID string
+}
+
+func newSearchFilter(query *gen.LogSearchQuery) (*searchFilter, error) {
+	f := &searchFilter{
+		sources:  lowerSet(query.GetSources()),
+		levels:   lowerSet(query.GetLevels()),
+		from:     query.GetFrom(),
+		to:       query.GetTo(),
+		path:     query.GetPath(),
+		variable: query.GetVariable(),
+		minDelay: query.GetMinDelayMs(),
+		text:     strings.ToLower(query.GetText()),
+		limit:    int(query.GetLimit()),
+		afterID:  query.GetAfterId(),
+	}
+
+	switch query.GetSort() {
+	case "", "timestamp":
+	case "delay":
+		f.byDelay = true
+	default:
+		return nil, fmt.Errorf("unknown sort %q: expected timestamp or delay", query.GetSort())
+	}
+
+	switch query.GetOrder() {
+	case "", "desc":
+	case "asc":
+		f.ascending = true
+	default:
+		return nil, fmt.Errorf("unknown order %q: expected asc or desc", query.GetOrder())
+	}
+
+	if f.limit < 0 || f.limit > maxSearchLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxSearchLimit)
+	}
+	if f.limit == 0 {
+		f.limit = defaultSearchLimit
+	}
+	if f.to != 0 && f.from > f.to {
+		return nil, fmt.Errorf("from is after to")
+	}
+	if f.afterID != "" && !utils.IsULID(f.afterID) {
+		return nil, fmt.Errorf("after_id %q is not a time-sortable id", f.afterID)
+	}
+
+	if query.GetCursor() != "" {
+		key, err := f.decodeCursor(query.GetCursor())
+		if err != nil {
+			return nil, err
+		}
+		f.after = &key
+	}
+
+	return f, nil
+}
+
+func lowerSet(values []string) map[string]bool {
+	if len(values) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[strings.ToLower(v)] = true
+	}
+	return set
+}
+
+// storedLog — поля записи, по которым идёт поиск (см. WriteLogToFile)
+type storedLog struct {
+	ID                string            `json:"id"`
+	Level             string            `json:"level"`
+	Source            string            `json:"source"`
+	Path              string            `json:"path"`
+	Message           json.RawMessage   `json:"message"`
+	Metadata          map[string]string `json:"metadata"`
+	TimestampSend     int64             `json:"timestamp_send"`
+	TimestampReceived int64             `json:"timestamp_received"`
+}
+
+// delay возвращает задержку доставки в миллисекундах или -1, если время отправки неизвестно.
+func (l storedLog) delay() int64 {
+	if l.TimestampSend <= 0 || l.TimestampReceived < l.TimestampSend {
+		return -1
+	}
+	return l.TimestampReceived - l.TimestampSend
+}
+
+// structured разбирает сообщение, записанное строкой с JSON или вложенным объектом.
+func (l storedLog) structured() *gen.StructuredMessage {
+	raw := []byte(l.Message)
+	var encoded string
+	if err := json.Unmarshal(raw, &encoded); err == nil {
+		raw = []byte(encoded)
+	}
+
+	var msg gen.StructuredMessage
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(raw, &msg); err != nil {
+		return nil
+	}
+	return &msg
+}
+
+func (f *searchFilter) match(entry storedLog, line string) bool {
+	if f.sources != nil && !f.sources[strings.ToLower(entry.Source)] {
+		return false
+	}
+	if f.levels != nil && !f.levels[strings.ToLower(entry.Level)] {
+		return false
+	}
+	if f.from != 0 && entry.TimestampReceived < f.from {
+		return false
+	}
+	if f.to != 0 && entry.TimestampReceived > f.to {
+		return false
+	}
+	// Id в формате ULID сравниваются как строки в порядке выдачи
+	if f.afterID != "" && (!utils.IsULID(entry.ID) || entry.ID <= f.afterID) {
+		return false
+	}
+	if f.minDelay > 0 && entry.delay() < f.minDelay {
+		return false
+	}
+	if f.text != "" && !strings.Contains(strings.ToLower(line), f.text) {
+		return false
+	}
+	if f.metadataKey != "" {
+		value, ok := entry.Metadata[f.metadataKey]
+		if !ok || (f.metadataValue != "" && value != f.metadataValue) {
+			return false
+		}
+	}
+	if f.path == "" && f.variable == "" {
+		return true
+	}
+
+	msg := entry.structured()
+	if f.path != "" && entry.Path != f.path && msg.GetPath() != f.path {
+		return false
+	}
+	return f.variable == "" || mentionsVariable(msg, f.variable)
+}
+
+func mentionsVariable(msg *gen.StructuredMessage, variable string) bool {
+	for _, op := range msg.GetBody() {
+		if op.GetVar() == variable || op.GetLeft() == variable || op.GetRight() == variable {
+			return true
+		}
+	}
+	for _, item := range msg.GetResult().GetItems() {
+		if item.GetVar() == variable {
+			return true
+		}
+	}
+	return false
+}
+
+type searchKey struct {
+	value int64
+	id    string
+}
+
+func (k searchKey) less(other searchKey) bool {
+	if k.value != other.value {
+		return k.value < other.value
+	}
+	return k.id < other.id
+}
+
+type searchHit struct {
+	key      searchKey
+	filename string
+	line     string
+}
+
+func (f *searchFilter) hit(filename, line string) (searchHit, bool) {
+	var entry storedLog
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		return searchHit{}, false // Пропускаем невалидные JSON строки
+	}
+	if !f.match(entry, line) {
+		return searchHit{}, false
+	}
+
+	key := searchKey{value: entry.TimestampReceived, id: entry.ID}
+	if f.byDelay {
+		key.value = entry.delay()
+	}
+	return searchHit{key: key, filename: filename, line: line}, true
+}
+
+// before сообщает, что запись с ключом a выдаётся раньше записи с ключом b.
+func (f *searchFilter) before(a, b searchKey) bool {
+	if f.ascending {
+		return a.less(b)
+	}
+	return b.less(a)
+}
+
+// afterCursor сообщает, что запись ещё не выдана на предыдущих страницах.
+func (f *searchFilter) afterCursor(key searchKey) bool {
+	return f.after == nil || f.before(*f.after, key)
+}
+
+func (f *searchFilter) scanOptions() store.ScanOptions {
+	opts := store.ScanOptions{From: f.from, To: f.to}
+	for source := range f.sources {
+		opts.Sources = append(opts.Sources, source)
+	}
+	return opts
+}
+
+// page отрезает страницу от найденного по порядку выдачи.
+func (f *searchFilter) page(hits []searchHit) *gen.LogSearchResult {
+	result := &gen.LogSearchResult{}
+	if len(hits) > f.limit {
+		hits = hits[:f.limit]
+		result.NextCursor = f.encodeCursor(hits[len(hits)-1].key)
+	}
+	for _, hit := range hits {
+		result.Hits = append(result.Hits, &gen.LogSearchHit{Filename: hit.filename, Log: hit.line})
+	}
+	return result
+}
+
+// ordering — сортировка и порядок выдачи, для которых выдан курсор.
+func (f *searchFilter) ordering() (string, string) {
+	sortBy, order := "timestamp", "desc"
+	if f.byDelay {
+		sortBy = "delay"
+	}
+	if f.ascending {
+		order = "asc"
+	}
+	return sortBy, order
+}
+
+func (f *searchFilter) encodeCursor(key searchKey) string {
+	sortBy, order := f.ordering()
+	raw := strings.Join([]string{sortBy, order, strconv.FormatInt(key.value, 10), key.id}, ":")
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor разбирает курсор и проверяет, что он выдан для тех же
+// сортировки и порядка: ключ другой сортировки указал бы не на ту страницу.
+func (f *searchFilter) decodeCursor(cursor string) (searchKey, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return searchKey{}, errInvalidCursor
+	}
+	parts := strings.SplitN(string(raw), ":", 4)
+	if len(parts) != 4 {
+		return searchKey{}, errInvalidCursor
+	}
+	if sortBy, order := f.ordering(); parts[0] != sortBy || parts[1] != order {
+		return searchKey{}, fmt.Errorf("cursor was issued for sort=%s order=%s, got sort=%s order=%s", parts[0], parts[1], sortBy, order)
+	}
+	n, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return searchKey{}, errInvalidCursor
+	}
+	return searchKey{value: n, id: parts[3]}, nil
+}
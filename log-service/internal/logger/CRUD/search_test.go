@@ -0,0 +1,232 @@
+package CRUD
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log-service/gen"
+	"log-service/internal/store"
+	"log-service/internal/utils"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// storedLine повторяет формат WriteLogToFile: сообщение записано строкой с JSON
+func storedLine(t *testing.T, id, source, level, message string, send, received int64) string {
+	t.Helper()
+	var path struct {
+		Path string `json:"path"`
+	}
+	_ = json.Unmarshal([]byte(message), &path)
+
+	line, err := json.Marshal(map[string]any{
+		"level":              level,
+		"msg":                "New log entry",
+		"id":                 id,
+		"message":            message,
+		"path":               path.Path,
+		"source":             source,
+		"timestamp_send":     send,
+		"timestamp_received": received,
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal log: %v", err)
+	}
+	return string(line)
+}
+
+func newSearchManager(t *testing.T) *LogManager {
+	t.Helper()
+	dir := t.TempDir()
+	httpLogs := filepath.Join(dir, "http_logs.json")
+	businessLogs := filepath.Join(dir, "business_logs.json")
+
+	appendLine(t, httpLogs, storedLine(t, "req1", "HTTP-server", "info",
+		`{"method":"POST","path":"/process","body":[{"type":"calc","op":"+","var":"total","left":"1","right":"2"}]}`, 1000, 1010))
+	appendLine(t, httpLogs, storedLine(t, "req2", "HTTP-server", "info",
+		`{"method":"POST","path":"/jobs","body":[{"type":"calc","op":"*","var":"x","left":"total","right":"3"}]}`, 2000, 2500))
+	appendLine(t, httpLogs, `not a json line`)
+	appendLine(t, httpLogs, storedLine(t, "req3", "HTTP-server", "warn",
+		`{"method":"POST","path":"/process","body":[{"type":"print","var":"y"}]}`, 3000, 3020))
+	appendLine(t, businessLogs, storedLine(t, "res1", "business-server", "info",
+		`{"result":{"items":[{"var":"total","value":"3"}]}}`, 0, 1100))
+	appendLine(t, businessLogs, storedLine(t, "res3", "business-server", "error",
+		`{"result":{"warning":"WARNING: variable(s) y called for print before calculation"}}`, 0, 3100))
+
+	// Хранилище открывается после записи файлов и индексирует их целиком
+	return &LogManager{Store: newFileStore(t, dir)}
+}
+
+func hitIDs(t *testing.T, result *gen.LogSearchResult) []string {
+	t.Helper()
+	ids := []string{}
+	for _, hit := range result.GetHits() {
+		ids = append(ids, logID(t, hit.GetLog()))
+	}
+	return ids
+}
+
+func TestSearchLogsFilters(t *testing.T) {
+	lm := newSearchManager(t)
+
+	tests := []struct {
+		name  string
+		query *gen.LogSearchQuery
+		want  []string
+	}{
+		{name: "everything newest first", query: &gen.LogSearchQuery{}, want: []string{"res3", "req3", "req2", "res1", "req1"}},
+		{name: "oldest first", query: &gen.LogSearchQuery{Order: "asc"}, want: []string{"req1", "res1", "req2", "req3", "res3"}},
+		{name: "by source ignoring case", query: &gen.LogSearchQuery{Sources: []string{"business-SERVER"}}, want: []string{"res3", "res1"}},
+		{name: "by levels", query: &gen.LogSearchQuery{Levels: []string{"warn", "error"}}, want: []string{"res3", "req3"}},
+		{name: "by time range", query: &gen.LogSearchQuery{From: 1100, To: 3020}, want: []string{"req3", "req2", "res1"}},
+		{name: "by path", query: &gen.LogSearchQuery{Path: "/process"}, want: []string{"req3", "req1"}},
+		{name: "by variable in operations and results", query: &gen.LogSearchQuery{Variable: "total"}, want: []string{"req2", "res1", "req1"}},
+		{name: "by delivery delay", query: &gen.LogSearchQuery{MinDelayMs: 20}, want: []string{"req3", "req2"}},
+		{name: "by free text", query: &gen.LogSearchQuery{Text: "before CALCULATION"}, want: []string{"res3"}},
+		{name: "sorted by delay", query: &gen.LogSearchQuery{Sources: []string{"HTTP-server"}, Sort: "delay"}, want: []string{"req2", "req3", "req1"}},
+		{name: "nothing matches", query: &gen.LogSearchQuery{Variable: "missing"}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := lm.SearchLogs(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := hitIDs(t, result); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+			if result.GetNextCursor() != "" {
+				t.Errorf("expected no next page, got cursor %q", result.GetNextCursor())
+			}
+		})
+	}
+}
+
+// withBoltStore переносит записи в bbolt, у которого нет индекса времени:
+// поиск по нему выбирает записи проходами с кучей.
+func withBoltStore(t *testing.T, lm *LogManager) *LogManager {
+	t.Helper()
+	bolt, err := store.NewBoltStore(filepath.Join(t.TempDir(), "logs.db"))
+	if err != nil {
+		t.Fatalf("failed to open bolt store: %v", err)
+	}
+	t.Cleanup(func() { bolt.Close() })
+	if _, _, err := store.Migrate(lm.Store, bolt); err != nil {
+		t.Fatalf("failed to migrate logs: %v", err)
+	}
+	return &LogManager{Store: bolt}
+}
+
+func TestSearchLogsPagination(t *testing.T) {
+	for name, newManager := range map[string]func(t *testing.T) *LogManager{
+		"time index": newSearchManager,
+		"heap":       func(t *testing.T) *LogManager { return withBoltStore(t, newSearchManager(t)) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			for _, tt := range []struct {
+				order string
+				// Время получения записи, которая появляется до курсора
+				late int64
+				want [][]string
+			}{
+				{order: "asc", late: 0, want: [][]string{{"req1", "res1"}, {"req2", "req3"}, {"res3"}}},
+				{order: "desc", late: 9999, want: [][]string{{"res3", "req3"}, {"req2", "res1"}, {"req1"}}},
+			} {
+				lm := newManager(t)
+				var pages [][]string
+				query := &gen.LogSearchQuery{Limit: 2, Order: tt.order, To: 10000}
+				for {
+					result, err := lm.SearchLogs(context.Background(), query)
+					if err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+					pages = append(pages, hitIDs(t, result))
+					if result.GetNextCursor() == "" {
+						break
+					}
+					query.Cursor = result.GetNextCursor()
+
+					// Записи, появившиеся между страницами до курсора, не сдвигают их
+					late := storedLine(t, fmt.Sprintf("late%d", len(pages)), "HTTP-server", "info", `{}`, 0, tt.late)
+					if err := lm.Store.Append("http_logs.json", []byte(late)); err != nil {
+						t.Fatalf("failed to append log: %v", err)
+					}
+				}
+
+				if !reflect.DeepEqual(pages, tt.want) {
+					t.Errorf("%s: expected pages %v, got %v", tt.order, tt.want, pages)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchLogsCursorOrdering(t *testing.T) {
+	lm := newSearchManager(t)
+
+	result, err := lm.SearchLogs(context.Background(), &gen.LogSearchQuery{Limit: 1, Order: "asc"})
+	if err != nil || result.GetNextCursor() == "" {
+		t.Fatalf("expected a next page, got %v (%v)", result, err)
+	}
+	for _, query := range []*gen.LogSearchQuery{
+		{Limit: 1, Cursor: result.GetNextCursor()},
+		{Limit: 1, Order: "asc", Sort: "delay", Cursor: result.GetNextCursor()},
+	} {
+		if _, err := lm.SearchLogs(context.Background(), query); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("expected InvalidArgument for a cursor of another ordering with %v, got %v", query, err)
+		}
+	}
+}
+
+func TestSearchLogsInvalidQuery(t *testing.T) {
+	lm := newSearchManager(t)
+
+	tests := []struct {
+		name  string
+		query *gen.LogSearchQuery
+	}{
+		{name: "unknown sort", query: &gen.LogSearchQuery{Sort: "level"}},
+		{name: "unknown order", query: &gen.LogSearchQuery{Order: "random"}},
+		{name: "limit too large", query: &gen.LogSearchQuery{Limit: maxSearchLimit + 1}},
+		{name: "inverted range", query: &gen.LogSearchQuery{From: 10, To: 5}},
+		{name: "broken cursor", query: &gen.LogSearchQuery{Cursor: "!!!"}},
+		{name: "after legacy id", query: &gen.LogSearchQuery{AfterId: "req1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := lm.SearchLogs(context.Background(), tt.query)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSearchLogsAfterID(t *testing.T) {
+	lm := newSearchManager(t)
+	ids := utils.NewULIDGenerator()
+
+	var issued []string
+	for i := 0; i < 4; i++ {
+		id := ids.NewID()
+		issued = append(issued, id)
+		// Время получения не совпадает с порядком id: важен только id
+		if err := lm.Store.Append("http_logs.json", []byte(storedLine(t, id, "HTTP-server", "info", `{}`, 0, int64(5000-i)))); err != nil {
+			t.Fatalf("failed to append log: %v", err)
+		}
+	}
+
+	result, err := lm.SearchLogs(context.Background(), &gen.LogSearchQuery{AfterId: issued[1], Order: "asc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Записи со старыми id не упорядочены по времени и не попадают в выборку
+	if got, want := hitIDs(t, result), []string{issued[3], issued[2]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,171 @@
+package CRUD
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log-service/gen"
+	"log-service/internal/store"
+	"log-service/internal/tail"
+	"strings"
+	"time"
+)
+
+const (
+	// Сколько записей за проход выбирает из хранилища без индекса времени
+	tailBacklogBatch = 1000
+	// Запись попадает в подписку позже, чем получает timestamp_received, но
+	// не настолько
+	tailDedupWindow = time.Minute
+)
+
+// TailLogs передаёт новые записи, подходящие под фильтр, по мере того как
+// HandleIncomingLog их принимает. С since или after_id сначала досылаются уже
+// записанные, от старых к новым. Подписчик, который не успевает читать,
+// пропускает записи сверх своего буфера; их число приходит в dropped
+// следующего сообщения.
+func (lm *LogManager) TailLogs(query *gen.LogTailQuery, stream gen.Logger_TailLogsServer) error {
+	filter, err := newTailFilter(query)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if lm.Tail == nil {
+		return status.Error(codes.FailedPrecondition, "live tail is not enabled")
+	}
+	ctx := stream.Context()
+
+	// Подписка оформляется до чтения хранилища, чтобы не потерять записи,
+	// пришедшие между ними; повторы отсеиваются по id
+	subscribed := time.Now()
+	sub := lm.Tail.Subscribe(func(collection string, line []byte) bool {
+		_, ok := filter.hit(collection, string(line))
+		return ok
+	}, tail.DefaultBuffer)
+	defer sub.Unsubscribe()
+	// Заголовки сообщают клиенту, что подписка принята, ещё до первой записи
+	if err := stream.SendHeader(nil); err != nil {
+		return err
+	}
+
+	// Записи, пришедшие после подписки, могут оказаться и в хранилище, и в
+	// подписке. Запоминаются только id выданных записей, полученных не раньше
+	// чем за tailDedupWindow до подписки, чтобы память не росла с историей
+	sent := map[string]bool{}
+	if query.GetSince() != 0 || query.GetAfterId() != "" {
+		recent := subscribed.Add(-tailDedupWindow).UnixMilli()
+		err := lm.tailBacklog(ctx, filter, query, func(hit searchHit) error {
+			if hit.key.value >= recent {
+				sent[hit.key.id] = true
+			}
+			return stream.Send(&gen.LogTailEvent{Filename: hit.filename, Log: hit.line})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case entry, ok := <-sub.Entries():
+			if !ok {
+				return nil // Сервис останавливается
+			}
+			hit, _ := filter.hit(entry.Collection, string(entry.Line))
+			if sent[hit.key.id] {
+				delete(sent, hit.key.id) // В подписку запись приходит один раз
+				continue
+			}
+			event := &gen.LogTailEvent{Filename: entry.Collection, Log: hit.line, Dropped: sub.Dropped()}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// tailBacklog передаёт send уже записанные записи, с которых начинается
+// поток, от старых к новым, не собирая их в памяти.
+func (lm *LogManager) tailBacklog(ctx context.Context, filter *searchFilter, query *gen.LogTailQuery, send func(searchHit) error) error {
+	if lm.Store == nil {
+		return status.Error(codes.FailedPrecondition, "since and after_id require a log store")
+	}
+
+	backlog := *filter
+	backlog.ascending = true
+	if id := query.GetAfterId(); id != "" {
+		after, err := lm.findKey(id)
+		if err != nil {
+			return err
+		}
+		backlog.after = after
+	}
+
+	var sendErr error
+	err := lm.eachHit(ctx, &backlog, tailBacklogBatch, func(hit searchHit) bool {
+		sendErr = send(hit)
+		return sendErr == nil
+	})
+	switch {
+	case sendErr != nil:
+		return sendErr
+	case ctx.Err() != nil:
+		return status.FromContextError(ctx.Err()).Err()
+	case err != nil:
+		return status.Errorf(codes.Internal, "failed to read logs: %v", err)
+	}
+	return nil
+}
+
+// findKey находит запись id по индексу id хранилища и возвращает её ключ
+// в порядке времени получения.
+func (lm *LogManager) findKey(id string) (*searchKey, error) {
+	collections, err := lm.Store.Collections()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to find log %s: %v", id, err)
+	}
+	for _, collection := range collections {
+		data, err := lm.Store.Get(collection, id)
+		if errors.Is(err, store.ErrNotFound) || errors.Is(err, store.ErrUnknownCollection) {
+			continue
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to find log %s: %v", id, err)
+		}
+		var entry storedLog
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to parse log %s: %v", id, err)
+		}
+		return &searchKey{value: entry.TimestampReceived, id: id}, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "log with id %s not found", id)
+}
+
+func newTailFilter(query *gen.LogTailQuery) (*searchFilter, error) {
+	f := &searchFilter{
+		sources:       lowerSet(query.GetSources()),
+		levels:        lowerSet(query.GetLevels()),
+		from:          query.GetSince(),
+		path:          query.GetPath(),
+		variable:      query.GetVariable(),
+		metadataKey:   query.GetMetadataKey(),
+		metadataValue: query.GetMetadataValue(),
+		minDelay:      query.GetMinDelayMs(),
+		text:          strings.ToLower(query.GetText()),
+	}
+
+	if f.metadataValue != "" && f.metadataKey == "" {
+		return nil, fmt.Errorf("metadata_value requires metadata_key")
+	}
+	if f.from < 0 {
+		return nil, fmt.Errorf("since must not be negative")
+	}
+	if f.from != 0 && query.GetAfterId() != "" {
+		return nil, fmt.Errorf("since and after_id are mutually exclusive")
+	}
+	return f, nil
+}
@@ -0,0 +1,136 @@
+package CRUD
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"log-service/gen"
+	"log-service/internal/store"
+	"log-service/internal/tail"
+	"testing"
+	"time"
+)
+
+type tailStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *gen.LogTailEvent
+}
+
+func (s *tailStream) Context() context.Context { return s.ctx }
+
+func (s *tailStream) SendHeader(metadata.MD) error { return nil }
+
+func (s *tailStream) Send(e *gen.LogTailEvent) error {
+	s.events <- e
+	return nil
+}
+
+func (s *tailStream) next(t *testing.T) *gen.LogTailEvent {
+	t.Helper()
+	select {
+	case e := <-s.events:
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for tail event")
+		return nil
+	}
+}
+
+func tailLogger(st store.LogStore, hub *tail.Hub, collection string) *zap.Logger {
+	out := zapcore.NewMultiWriteSyncer(store.NewWriter(st, collection), hub.Writer(collection))
+	return zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), out, zapcore.DebugLevel))
+}
+
+func TestTailLogsBacklogThenLive(t *testing.T) {
+	st := newFileStore(t, t.TempDir())
+	hub := tail.NewHub()
+	defer hub.Close()
+	lm := &LogManager{
+		Loggers: map[string]*zap.Logger{
+			"HTTP-server":      tailLogger(st, hub, requestLogFile),
+			"business-server":  tailLogger(st, hub, resultLogFile),
+			"undefined-server": zap.NewNop(),
+		},
+		Store: st,
+		Tail:  hub,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	write := func(service, path string) string {
+		t.Helper()
+		// Записи одной миллисекунды упорядочены по id, а не по времени записи
+		time.Sleep(2 * time.Millisecond)
+		resp, err := lm.HandleIncomingLog(ctx, &gen.LogEntry{ServiceName: service, Message: &gen.StructuredMessage{Path: path}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp.GetId().GetId()
+	}
+	first := write("HTTP-server", "/process")
+	second := write("HTTP-server", "/process")
+
+	stream := &tailStream{ctx: ctx, events: make(chan *gen.LogTailEvent, 10)}
+	done := make(chan error, 1)
+	go func() {
+		done <- lm.TailLogs(&gen.LogTailQuery{Sources: []string{"http-server"}, AfterId: first}, stream)
+	}()
+
+	if e := stream.next(t); logID(t, e.GetLog()) != second || e.GetFilename() != requestLogFile {
+		t.Fatalf("expected backlog to start after %s with %s, got %v", first, second, e)
+	}
+
+	write("business-server", "/process")
+	third := write("HTTP-server", "/jobs")
+	if e := stream.next(t); logID(t, e.GetLog()) != third {
+		t.Fatalf("expected live entry %s, got %v", third, e)
+	}
+
+	cancel()
+	if err := <-done; status.Code(err) != codes.Canceled {
+		t.Errorf("expected stream to end with Canceled, got %v", err)
+	}
+	if len(stream.events) != 0 {
+		t.Errorf("expected no other events, got %d", len(stream.events))
+	}
+}
+
+func TestTailLogsInvalidQuery(t *testing.T) {
+	lm := &LogManager{Tail: tail.NewHub()}
+	defer lm.Tail.Close()
+
+	tests := []struct {
+		name  string
+		query *gen.LogTailQuery
+		code  codes.Code
+	}{
+		{name: "value without key", query: &gen.LogTailQuery{MetadataValue: "acme"}, code: codes.InvalidArgument},
+		{name: "since and after_id", query: &gen.LogTailQuery{Since: 10, AfterId: "abc"}, code: codes.InvalidArgument},
+		{name: "negative since", query: &gen.LogTailQuery{Since: -1}, code: codes.InvalidArgument},
+		{name: "backlog without store", query: &gen.LogTailQuery{Since: 10}, code: codes.FailedPrecondition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := lm.TailLogs(tt.query, &tailStream{ctx: context.Background()})
+			if status.Code(err) != tt.code {
+				t.Errorf("expected %v, got %v", tt.code, err)
+			}
+		})
+	}
+}
+
+func TestTailLogsAfterUnknownID(t *testing.T) {
+	lm := &LogManager{Store: newFileStore(t, t.TempDir()), Tail: tail.NewHub()}
+	defer lm.Tail.Close()
+
+	err := lm.TailLogs(&gen.LogTailQuery{AfterId: "missing"}, &tailStream{ctx: context.Background()})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+}
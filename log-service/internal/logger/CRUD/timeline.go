@@ -0,0 +1,124 @@
+package CRUD
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log-service/gen"
+	"log-service/internal/store"
+)
+
+// Файлы логгеров HTTP- и бизнес-сервиса, см. server.NewLogManager
+const (
+	requestLogFile = "http_logs.json"
+	resultLogFile  = "business_logs.json"
+)
+
+// GetRequestTimeline собирает входящий запрос, результат его обработки и граф
+// зависимостей по request_id. Записи, сделанные до появления request_id,
+// находятся по id запроса и по path результата, куда раньше писался id запроса.
+func (lm *LogManager) GetRequestTimeline(ctx context.Context, query *gen.RequestTimelineQuery) (*gen.RequestTimeline, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	requestID := query.GetRequestId()
+	if requestID == "" {
+		return nil, status.Error(codes.InvalidArgument, "request_id is required")
+	}
+
+	request, err := lm.findRequestLog(requestLogFile, requestID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read request log: %v", err)
+	}
+	if request == "" {
+		return nil, status.Errorf(codes.NotFound, "request %s not found", requestID)
+	}
+
+	result, err := lm.findRequestLog(resultLogFile, requestID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read result log: %v", err)
+	}
+
+	return &gen.RequestTimeline{
+		RequestId: requestID,
+		Request:   request,
+		Result:    result,
+		Graph:     graphFromLog(result),
+	}, nil
+}
+
+type correlatedLog struct {
+	ID        string `json:"id"`
+	RequestID string `json:"request_id"`
+	Path      string `json:"path"`
+}
+
+func (l correlatedLog) belongsTo(requestID string) bool {
+	if l.RequestID != "" {
+		return l.RequestID == requestID
+	}
+	return l.ID == requestID || l.Path == requestID
+}
+
+// findRequestLog возвращает запись коллекции, относящуюся к запросу, или
+// пустую строку, если такой нет. Отсутствующая коллекция означает, что записей нет.
+func (lm *LogManager) findRequestLog(collection, requestID string) (string, error) {
+	// Входящий запрос записан под своим же id, его достаточно прочитать
+	data, err := lm.Store.Get(collection, requestID)
+	switch {
+	case err == nil:
+		var entry correlatedLog
+		if json.Unmarshal(data, &entry) == nil && entry.belongsTo(requestID) {
+			return string(data), nil
+		}
+	case errors.Is(err, store.ErrUnknownCollection):
+		return "", nil
+	case !errors.Is(err, store.ErrNotFound):
+		return "", err
+	}
+
+	// Файловое хранилище отбирает записи запроса по индексу, не читая коллекцию
+	var found string
+	err = lm.Store.Scan(store.ScanOptions{Collections: []string{collection}, RequestID: requestID}, func(r store.Record) bool {
+		var entry correlatedLog
+		if err := json.Unmarshal(r.Data, &entry); err != nil || !entry.belongsTo(requestID) {
+			return true
+		}
+		found = string(r.Data)
+		return false
+	})
+	return found, err
+}
+
+// graphFromLog достаёт граф из сообщения записи. Сообщение записывается
+// строкой с JSON внутри, но допускается и вложенный объект.
+func graphFromLog(line string) string {
+	if line == "" {
+		return ""
+	}
+	var entry struct {
+		Message json.RawMessage `json:"message"`
+	}
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		return ""
+	}
+
+	message := []byte(entry.Message)
+	var encoded string
+	if err := json.Unmarshal(message, &encoded); err == nil {
+		message = []byte(encoded)
+	}
+
+	var structured struct {
+		Graph string `json:"graph"`
+	}
+	if err := json.Unmarshal(message, &structured); err != nil {
+		return ""
+	}
+	return structured.Graph
+}
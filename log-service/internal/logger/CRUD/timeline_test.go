@@ -0,0 +1,129 @@
+package CRUD
+
+import (
+	"context"
+	"encoding/json"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log-service/gen"
+	"log-service/internal/store"
+	"os"
+	"testing"
+)
+
+func newFileStore(t testing.TB, dir string) store.LogStore {
+	t.Helper()
+	st, err := store.NewFileStore(dir, store.FileOptions{})
+	if err != nil {
+		t.Fatalf("failed to open log store: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+	return st
+}
+
+func storeLogger(st store.LogStore, collection string) *zap.Logger {
+	return zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), store.NewWriter(st, collection), zapcore.DebugLevel))
+}
+
+func TestGetRequestTimeline(t *testing.T) {
+	st := newFileStore(t, t.TempDir())
+	lm := &LogManager{
+		Loggers: map[string]*zap.Logger{
+			"HTTP-server":      storeLogger(st, requestLogFile),
+			"business-server":  storeLogger(st, resultLogFile),
+			"undefined-server": zap.NewNop(),
+		},
+		Store: st,
+	}
+	ctx := context.Background()
+
+	request, err := lm.HandleIncomingLog(ctx, &gen.LogEntry{
+		ServiceName: "HTTP-server",
+		Message:     &gen.StructuredMessage{Method: "POST", Path: "/process"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	requestID := request.GetId().GetId()
+
+	result, err := lm.HandleIncomingLog(ctx, &gen.LogEntry{
+		ServiceName: "business-server",
+		RequestId:   requestID,
+		ParentId:    requestID,
+		Message: &gen.StructuredMessage{
+			Result: &gen.OperationResponse{Items: []*gen.VariableValue{{Var: "x", Value: 3}}},
+			Graph:  "digraph G {}",
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Записи в старом формате: результат ссылается на запрос только через path
+	if err := st.Append(requestLogFile, []byte(`{"id":"legacyReq","message":{"method":"POST","path":"/process"}}`)); err != nil {
+		t.Fatalf("failed to append log: %v", err)
+	}
+	if err := st.Append(resultLogFile, []byte(`{"id":"legacyRes","path":"legacyReq","message":{"path":"legacyReq"}}`)); err != nil {
+		t.Fatalf("failed to append log: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		requestID  string
+		wantCode   codes.Code
+		wantResult string
+		wantGraph  string
+	}{
+		{name: "correlated by request_id", requestID: requestID, wantResult: result.GetId().GetId(), wantGraph: "digraph G {}"},
+		{name: "legacy entries correlated by path", requestID: "legacyReq", wantResult: "legacyRes"},
+		{name: "unknown request", requestID: "missing", wantCode: codes.NotFound},
+		{name: "empty request_id", wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeline, err := lm.GetRequestTimeline(ctx, &gen.RequestTimelineQuery{RequestId: tt.requestID})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("expected code %v, got %v", tt.wantCode, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if got := logID(t, timeline.GetRequest()); got != tt.requestID {
+				t.Errorf("expected request log %s, got %s", tt.requestID, got)
+			}
+			if got := logID(t, timeline.GetResult()); got != tt.wantResult {
+				t.Errorf("expected result log %s, got %s", tt.wantResult, got)
+			}
+			if timeline.GetGraph() != tt.wantGraph {
+				t.Errorf("expected graph %q, got %q", tt.wantGraph, timeline.GetGraph())
+			}
+		})
+	}
+}
+
+func appendLine(t *testing.T, path, line string) {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("failed to open log file: %v", err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(line + "\n"); err != nil {
+		t.Fatalf("failed to append log: %v", err)
+	}
+}
+
+func logID(t *testing.T, line string) string {
+	t.Helper()
+	var entry struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		t.Fatalf("invalid log line %q: %v", line, err)
+	}
+	return entry.ID
+}
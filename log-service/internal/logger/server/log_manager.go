@@ -1,40 +1,112 @@
 package server
 
 import (
-	"fmt"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"log"
-	"log-service/gen"
 	"log-service/internal/config"
 	lm "log-service/internal/logger/CRUD"
-	"os"
+	"log-service/internal/outbox"
+	"log-service/internal/policy"
+	"log-service/internal/routing"
+	"log-service/internal/store"
+	"log-service/internal/tail"
+	"log-service/internal/trash"
+	"log-service/internal/utils"
+	"path/filepath"
+	"strconv"
+	"time"
 )
 
+// NewLogManager открывает хранилище, выбранное в LOG_STORE, и направляет
+// записи каждого сервиса в его коллекцию, а копии — в приёмники по правилам
+// из LOG_ROUTES_FILE. Удалённые записи хранятся в корзине того же типа в
+// LogsDir/trash в течение LOG_DELETE_GRACE. Id записей помечаются номером
+// узла из LOG_NODE_ID, если он задан. Политики приёма записей сервисов
+// хранятся в LogsDir/log_policies.json, очередь отправки в Kafka — в
+// LogsDir/outbox.
 func NewLogManager(cfg *config.Config) *lm.LogManager {
+	st, err := store.New(cfg)
+	if err != nil {
+		log.Fatalf("Failed to open log store: %v", err)
+	}
+	grace, err := time.ParseDuration(cfg.LogDeleteGrace)
+	if err != nil {
+		log.Fatalf("Invalid LOG_DELETE_GRACE: %v", err)
+	}
+	bin, err := store.Open(cfg.LogStore, store.DefaultPath(cfg.LogStore, filepath.Join(cfg.LogsDir, "trash")))
+	if err != nil {
+		log.Fatalf("Failed to open trash: %v", err)
+	}
+	tr, err := trash.New(st, bin, filepath.Join(cfg.LogsDir, "audit.jsonl"), grace)
+	if err != nil {
+		log.Fatalf("Failed to open trash: %v", err)
+	}
+
+	var ids utils.IDGenerator = utils.NewULIDGenerator()
+	if cfg.LogNodeID != "" {
+		node, err := strconv.ParseUint(cfg.LogNodeID, 10, 16)
+		if err != nil {
+			log.Fatalf("Invalid LOG_NODE_ID: %v", err)
+		}
+		ids = utils.NewNodeULIDGenerator(uint16(node))
+	}
+
+	routes := &routing.Config{}
+	if cfg.LogRoutesFile != "" {
+		if routes, err = routing.Load(cfg.LogRoutesFile); err != nil {
+			log.Fatalf("Failed to load log routes: %v", err)
+		}
+	}
+	policies, err := policy.Open(filepath.Join(cfg.LogsDir, "log_policies.json"))
+	if err != nil {
+		log.Fatalf("Failed to load log policies: %v", err)
+	}
+	maxAttempts, err := strconv.Atoi(cfg.OutboxMaxAttempts)
+	if err != nil {
+		log.Fatalf("Invalid OUTBOX_MAX_ATTEMPTS: %v", err)
+	}
+	backoff, err := time.ParseDuration(cfg.OutboxBackoff)
+	if err != nil {
+		log.Fatalf("Invalid OUTBOX_BACKOFF: %v", err)
+	}
+	maxBackoff, err := time.ParseDuration(cfg.OutboxMaxBackoff)
+	if err != nil {
+		log.Fatalf("Invalid OUTBOX_MAX_BACKOFF: %v", err)
+	}
+	ob, err := outbox.Open(filepath.Join(cfg.LogsDir, "outbox"), outbox.Options{MaxAttempts: maxAttempts, MinBackoff: backoff, MaxBackoff: maxBackoff})
+	if err != nil {
+		log.Fatalf("Failed to open outbox: %v", err)
+	}
+	hub := tail.NewHub()
+	committer := store.NewCommitter(st)
+	router, err := routing.NewRouter(routes, cfg.KafkaBroker, func(collection string, sinks []zapcore.WriteSyncer) *zap.Logger {
+		return createLogger(collection, committer, hub, sinks)
+	})
+	if err != nil {
+		log.Fatalf("Failed to open log sinks: %v", err)
+	}
+
 	return &lm.LogManager{
-		Loggers: map[string]*zap.Logger{
-			"HTTP-server":      createLogger("http", cfg),
-			"business-server":  createLogger("business", cfg),
-			"undefined-server": createLogger("undefined", cfg),
-		},
-		LogChanel: make(chan *gen.LogEntry, 500),
+		Router:   router,
+		Outbox:   ob,
+		Store:    st,
+		Trash:    tr,
+		Tail:     hub,
+		IDs:      ids,
+		Policies: policies,
 	}
 }
 
-func createLogger(serviceName string, cfg *config.Config) *zap.Logger {
+// createLogger пишет записи в коллекцию хранилища через общую групповую
+// фиксацию, раздаёт их подписчикам живого потока и копирует в приёмники
+// правил маршрутизации.
+func createLogger(collection string, committer *store.Committer, hub *tail.Hub, sinks []zapcore.WriteSyncer) *zap.Logger {
 	cfgZap := zap.NewProductionEncoderConfig()
 	cfgZap.TimeKey = ""
 	encoder := zapcore.NewJSONEncoder(cfgZap)
 
-	logDir := cfg.LogsDir
-
-	if err := os.MkdirAll(logDir, 0755); err != nil {
-		log.Fatalf("Failed to create log directory: %v", err)
-	}
-
-	logFile, _ := os.OpenFile(logDir+"/"+serviceName+"_logs.json", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	fmt.Println(logDir + "/" + serviceName + "_logs.json")
-	core := zapcore.NewCore(encoder, zapcore.AddSync(logFile), zapcore.DebugLevel)
+	out := zapcore.NewMultiWriteSyncer(append([]zapcore.WriteSyncer{committer.Writer(collection), hub.Writer(collection)}, sinks...)...)
+	core := zapcore.NewCore(encoder, out, zapcore.DebugLevel)
 	return zap.New(core)
 }
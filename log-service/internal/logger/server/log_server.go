@@ -1,15 +1,27 @@
 package server
 
 import (
+	"context"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"log"
 	"log-service/gen"
 	"log-service/internal/clients/kafka"
 	"log-service/internal/config"
+	"log-service/internal/metrics"
 	"net"
+	"time"
 )
 
-func RunLogServer(cfg *config.Config) {
+// Сколько после сигнала остановки ждать завершения вызовов, прежде чем
+// оборвать оставшиеся потоки
+const shutdownTimeout = 10 * time.Second
+
+// RunLogServer обслуживает вызовы до отмены ctx. После остановки gRPC-сервера
+// записи больше не поступают, и приёмники маршрутизации дописывают очереди.
+func RunLogServer(ctx context.Context, cfg *config.Config) {
 	lis, err := net.Listen("tcp", cfg.LoggerAddr)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
@@ -19,15 +31,49 @@ func RunLogServer(cfg *config.Config) {
 
 	logManager := NewLogManager(cfg)
 
-	go kafka.StartKafka(logManager.LogChanel)
+	go kafka.StartKafka(logManager.Outbox, logManager.Store)
 
-	if err := StartGRPCServer(lis, logManager); err != nil {
+	s := newGRPCServer(logManager)
+	go func() {
+		<-ctx.Done()
+		stopped := make(chan struct{})
+		go func() {
+			s.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(shutdownTimeout):
+			// Подписки TailLogs сами не завершаются
+			s.Stop()
+		}
+	}()
+	if err := s.Serve(lis); err != nil {
 		log.Fatalf("failed to serve: %v", err)
 	}
+
+	if err := logManager.Router.Close(); err != nil {
+		log.Printf("failed to close log sinks: %v", err)
+	}
 }
 
+// StartGRPCServer регистрирует Logger и стандартный grpc.health.v1: пустое имя
+// сервиса отвечает за процесс целиком, gen.Logger — за сам сервис логов.
+// Контекст trace принимается из метаданных вызова (traceparent).
 func StartGRPCServer(listener net.Listener, loggerServer gen.LoggerServer) error {
-	s := grpc.NewServer()
+	return newGRPCServer(loggerServer).Serve(listener)
+}
+
+func newGRPCServer(loggerServer gen.LoggerServer) *grpc.Server {
+	s := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(metrics.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(metrics.StreamServerInterceptor()),
+	)
 	gen.RegisterLoggerServer(s, loggerServer)
-	return s.Serve(listener)
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(s, healthServer)
+	healthServer.SetServingStatus(gen.Logger_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	return s
 }
@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"strings"
+	"time"
+)
+
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observeServer(info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observeServer(info.FullMethod, start, err)
+		return err
+	}
+}
+
+func observeServer(fullMethod string, start time.Time, err error) {
+	service, method := splitMethod(fullMethod)
+	GRPCServerDuration.WithLabelValues(service, method).Observe(time.Since(start).Seconds())
+	GRPCServerHandled.WithLabelValues(service, method, status.Code(err).String()).Inc()
+}
+
+// splitMethod разбирает "/gen.Logger/HandleIncomingLog" на сервис и метод.
+func splitMethod(fullMethod string) (string, string) {
+	name := strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "unknown", name
+}
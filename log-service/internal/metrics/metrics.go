@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"log"
+	"net/http"
+)
+
+// Метрики регистрируются в стандартном реестре Prometheus, поэтому вместе с ними
+// отдаются go_* и process_* коллекторы.
+var (
+	LogsIngested = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "log_entries_ingested_total",
+		Help: "Log entries written, by source logger.",
+	}, []string{"source"})
+
+	DeliveryDelay = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "log_delivery_delay_seconds",
+		Help:    "Delay between timestamp_send of an entry and its arrival at the log service.",
+		Buckets: prometheus.ExponentialBuckets(0.001, 4, 8),
+	})
+
+	KafkaPublishFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "log_kafka_publish_failures_total",
+		Help: "Failed attempts to publish an operation result to Kafka.",
+	})
+
+	OutboxDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "log_outbox_pending",
+		Help: "Operation results waiting in the outbox for delivery to Kafka.",
+	})
+
+	OutboxDeadLettered = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "log_outbox_dead_lettered_total",
+		Help: "Operation results moved to the dead letter file after repeated delivery failures.",
+	})
+
+	SegmentsRotated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "log_segments_rotated_total",
+		Help: "Log files rotated into segments, by collection.",
+	}, []string{"collection"})
+
+	RetentionRemoved = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "log_retention_removed_segments_total",
+		Help: "Log segments removed by retention rules, by collection and reason.",
+	}, []string{"collection", "reason"})
+
+	RetentionRemovedBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "log_retention_removed_bytes_total",
+		Help: "Bytes of log segments removed by retention rules, by collection.",
+	}, []string{"collection"})
+
+	CompactedBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "log_compacted_bytes_total",
+		Help: "Bytes of deleted records and tombstones freed by compaction, by collection.",
+	}, []string{"collection"})
+
+	LogsSoftDeleted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "log_soft_deleted_total",
+		Help: "Log records moved to trash, by collection.",
+	}, []string{"collection"})
+
+	LogsRestored = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "log_restored_total",
+		Help: "Log records restored from trash, by collection.",
+	}, []string{"collection"})
+
+	LogsPurged = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "log_purged_total",
+		Help: "Deleted log records purged from trash after the grace period, by collection.",
+	}, []string{"collection"})
+
+	CommitBatchSize = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "log_commit_batch_records",
+		Help:    "Records appended and synced together by one group commit.",
+		Buckets: prometheus.ExponentialBuckets(1, 4, 8),
+	})
+
+	TailSubscribers = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "log_tail_subscribers",
+		Help: "Open live tail streams.",
+	})
+
+	TailDropped = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "log_tail_dropped_total",
+		Help: "Entries skipped for live tail subscribers that fell behind.",
+	})
+
+	PolicyDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "log_policy_dropped_total",
+		Help: "Entries not stored because of the source service log policy, by source and reason (level or sampling).",
+	}, []string{"source", "reason"})
+
+	RoutedEntries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "log_routed_entries_total",
+		Help: "Entries copied to a routing sink, by sink.",
+	}, []string{"sink"})
+
+	SinkErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "log_sink_errors_total",
+		Help: "Entries a routing sink failed to write or deliver, by sink.",
+	}, []string{"sink"})
+
+	SinkDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "log_sink_dropped_total",
+		Help: "Entries dropped because a routing sink queue was full, by sink.",
+	}, []string{"sink"})
+
+	GRPCServerHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_handled_total",
+		Help: "Completed incoming gRPC calls by service, method and status code.",
+	}, []string{"grpc_service", "grpc_method", "grpc_code"})
+
+	GRPCServerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_handling_seconds",
+		Help:    "Incoming gRPC call latency until the handler returns.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"grpc_service", "grpc_method"})
+)
+
+// Serve отдаёт /metrics на отдельном адресе, так как сам сервис работает только по gRPC.
+// Пустой адрес отключает эндпоинт.
+func Serve(addr string) {
+	if addr == "" {
+		log.Println("METRICS_ADDR is not set — metrics endpoint is disabled")
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	log.Printf("metrics server started on %s", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Printf("metrics server stopped: %v", err)
+	}
+}
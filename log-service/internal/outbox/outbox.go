@@ -0,0 +1,385 @@
+package outbox
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.etcd.io/bbolt"
+	"log"
+	"log-service/internal/metrics"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	bucketPending = []byte("pending")
+	bucketMeta    = []byte("meta")
+	keyDead       = []byte("dead_lettered")
+)
+
+// Deliver отправляет сообщение; ошибка означает, что его нужно отправить повторно.
+type Deliver func(ctx context.Context, payload []byte) error
+
+type Options struct {
+	// После стольких неудачных попыток сообщение уходит в dead letter, по умолчанию 10
+	MaxAttempts int
+	// Пауза после первой неудачи, удваивается с каждой следующей до MaxBackoff;
+	// по умолчанию 1s и 5m
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = 10
+	}
+	if o.MinBackoff <= 0 {
+		o.MinBackoff = time.Second
+	}
+	if o.MaxBackoff < o.MinBackoff {
+		o.MaxBackoff = max(5*time.Minute, o.MinBackoff)
+	}
+	return o
+}
+
+// message — сообщение очереди в бакете pending под порядковым номером.
+type message struct {
+	Payload     []byte `json:"payload"`
+	EnqueuedAt  int64  `json:"enqueued_at"`
+	Attempts    int    `json:"attempts,omitempty"`
+	NextAttempt int64  `json:"next_attempt,omitempty"`
+	LastError   string `json:"last_error,omitempty"`
+}
+
+// DeadLetter — строка файла dead letter: сообщение, которое не удалось
+// доставить за MaxAttempts попыток.
+type DeadLetter struct {
+	Time       time.Time `json:"time"`
+	Seq        uint64    `json:"seq"`
+	EnqueuedAt time.Time `json:"enqueued_at"`
+	Attempts   int       `json:"attempts"`
+	Error      string    `json:"error"`
+	// Исходное сообщение, в JSON — base64
+	Payload []byte `json:"payload"`
+}
+
+// Stats — состояние очереди. Сообщения доставляются по порядку, поэтому
+// повторные попытки относятся к первому из них.
+type Stats struct {
+	Pending uint64
+	// Время постановки в очередь первого сообщения; ноль, если очередь пуста
+	OldestEnqueuedAt time.Time
+	Attempts         int
+	NextAttempt      time.Time
+	LastError        string
+	// Доставлено с запуска сервиса
+	Delivered uint64
+	// Отправлено в dead letter за всё время
+	DeadLettered uint64
+}
+
+// Outbox — очередь сообщений на диске. Enqueue возвращается, когда
+// сообщение сброшено на диск, а Run удаляет его только после успешной
+// доставки, поэтому сообщение доставляется хотя бы один раз и после
+// перезапуска. Неудачная попытка повторяется с растущей паузой, следующие
+// сообщения её ждут.
+type Outbox struct {
+	db         *bbolt.DB
+	deadLetter *os.File
+	opts       Options
+	now        func() time.Time
+
+	pending   atomic.Int64
+	delivered atomic.Uint64
+	// Будит Run после Enqueue
+	notify chan struct{}
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+	closed  bool
+	running sync.WaitGroup
+}
+
+// Open открывает очередь в dir: базу outbox.db и файл dead_letter.jsonl.
+func Open(dir string, opts Options) (*Outbox, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+	db, err := bbolt.Open(filepath.Join(dir, "outbox.db"), 0644, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open outbox: %w", err)
+	}
+	var pending int
+	err = db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(bucketPending)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucketIfNotExists(bucketMeta); err != nil {
+			return err
+		}
+		pending = b.Stats().KeyN
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open outbox: %w", err)
+	}
+	deadLetter, err := os.OpenFile(filepath.Join(dir, "dead_letter.jsonl"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open dead letter file: %w", err)
+	}
+
+	o := &Outbox{
+		db:         db,
+		deadLetter: deadLetter,
+		opts:       opts.withDefaults(),
+		now:        time.Now,
+		notify:     make(chan struct{}, 1),
+	}
+	o.ctx, o.cancel = context.WithCancel(context.Background())
+	o.pending.Store(int64(pending))
+	metrics.OutboxDepth.Set(float64(pending))
+	return o, nil
+}
+
+func seqKey(seq uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, seq)
+}
+
+// Enqueue ставит сообщение в очередь и возвращается после записи на диск.
+func (o *Outbox) Enqueue(payload []byte) error {
+	return o.EnqueueBatch([][]byte{payload})
+}
+
+// EnqueueBatch ставит сообщения в очередь по порядку одной транзакцией и
+// возвращается после записи на диск: одна синхронизация на все сообщения.
+func (o *Outbox) EnqueueBatch(payloads [][]byte) error {
+	if len(payloads) == 0 {
+		return nil
+	}
+	enqueuedAt := o.now().UnixMilli()
+	err := o.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(bucketPending)
+		for _, payload := range payloads {
+			data, err := json.Marshal(message{Payload: payload, EnqueuedAt: enqueuedAt})
+			if err != nil {
+				return err
+			}
+			seq, err := b.NextSequence()
+			if err != nil {
+				return err
+			}
+			if err := b.Put(seqKey(seq), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enqueue messages: %w", err)
+	}
+
+	metrics.OutboxDepth.Set(float64(o.pending.Add(int64(len(payloads)))))
+	select {
+	case o.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run доставляет сообщения по порядку, пока не вызван Close. Сообщение,
+// доставка которого прервана Close, остаётся в очереди.
+func (o *Outbox) Run(deliver Deliver) {
+	o.mu.Lock()
+	if o.closed {
+		o.mu.Unlock()
+		return
+	}
+	o.running.Add(1)
+	o.mu.Unlock()
+	defer o.running.Done()
+
+	for {
+		seq, msg, err := o.head()
+		if err != nil {
+			log.Printf("Failed to read outbox: %v", err)
+		}
+
+		var wait <-chan time.Time
+		switch {
+		case err != nil:
+			wait = time.After(o.opts.MinBackoff)
+		case msg == nil:
+			// Очередь пуста: ждём Enqueue
+		case msg.NextAttempt > o.now().UnixMilli():
+			wait = time.After(time.Duration(msg.NextAttempt-o.now().UnixMilli()) * time.Millisecond)
+		default:
+			deliverErr := deliver(o.ctx, msg.Payload)
+			if o.ctx.Err() != nil {
+				return
+			}
+			if err := o.settle(seq, msg, deliverErr); err != nil {
+				log.Printf("Failed to update outbox: %v", err)
+				wait = time.After(o.opts.MinBackoff)
+				break
+			}
+			continue
+		}
+
+		select {
+		case <-o.ctx.Done():
+			return
+		case <-o.notify:
+		case <-wait:
+		}
+	}
+}
+
+// head возвращает первое сообщение очереди или nil, если она пуста.
+func (o *Outbox) head() (uint64, *message, error) {
+	var (
+		seq uint64
+		msg *message
+	)
+	err := o.db.View(func(tx *bbolt.Tx) error {
+		k, v := tx.Bucket(bucketPending).Cursor().First()
+		if k == nil {
+			return nil
+		}
+		seq, msg = binary.BigEndian.Uint64(k), &message{}
+		return json.Unmarshal(v, msg)
+	})
+	return seq, msg, err
+}
+
+func (o *Outbox) backoff(attempts int) time.Duration {
+	d := o.opts.MinBackoff
+	for i := 1; i < attempts && d < o.opts.MaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, o.opts.MaxBackoff)
+}
+
+// settle удаляет доставленное сообщение, а недоставленное откладывает до
+// следующей попытки или переносит в dead letter.
+func (o *Outbox) settle(seq uint64, msg *message, deliverErr error) error {
+	if deliverErr == nil {
+		if err := o.remove(seq, false); err != nil {
+			return err
+		}
+		o.delivered.Add(1)
+		return nil
+	}
+
+	msg.Attempts++
+	msg.LastError = deliverErr.Error()
+	if msg.Attempts >= o.opts.MaxAttempts {
+		log.Printf("Outbox message %d failed %d times, moving to dead letter: %v", seq, msg.Attempts, deliverErr)
+		if err := o.writeDeadLetter(seq, msg); err != nil {
+			return err
+		}
+		return o.remove(seq, true)
+	}
+
+	msg.NextAttempt = o.now().Add(o.backoff(msg.Attempts)).UnixMilli()
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return o.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucketPending).Put(seqKey(seq), data)
+	})
+}
+
+func (o *Outbox) remove(seq uint64, dead bool) error {
+	err := o.db.Update(func(tx *bbolt.Tx) error {
+		if dead {
+			meta := tx.Bucket(bucketMeta)
+			count := uint64(0)
+			if v := meta.Get(keyDead); v != nil {
+				count = binary.BigEndian.Uint64(v)
+			}
+			if err := meta.Put(keyDead, seqKey(count+1)); err != nil {
+				return err
+			}
+		}
+		return tx.Bucket(bucketPending).Delete(seqKey(seq))
+	})
+	if err != nil {
+		return err
+	}
+	if dead {
+		metrics.OutboxDeadLettered.Inc()
+	}
+	metrics.OutboxDepth.Set(float64(o.pending.Add(-1)))
+	return nil
+}
+
+// writeDeadLetter дописывает сообщение в файл dead letter и сбрасывает его
+// на диск до удаления сообщения из очереди.
+func (o *Outbox) writeDeadLetter(seq uint64, msg *message) error {
+	line, err := json.Marshal(DeadLetter{
+		Time:       o.now(),
+		Seq:        seq,
+		EnqueuedAt: time.UnixMilli(msg.EnqueuedAt),
+		Attempts:   msg.Attempts,
+		Error:      msg.LastError,
+		Payload:    msg.Payload,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := o.deadLetter.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write dead letter: %w", err)
+	}
+	return o.deadLetter.Sync()
+}
+
+// Stats возвращает глубину очереди и состояние доставки её первого сообщения.
+func (o *Outbox) Stats() (Stats, error) {
+	stats := Stats{Pending: uint64(o.pending.Load()), Delivered: o.delivered.Load()}
+	err := o.db.View(func(tx *bbolt.Tx) error {
+		if v := tx.Bucket(bucketMeta).Get(keyDead); v != nil {
+			stats.DeadLettered = binary.BigEndian.Uint64(v)
+		}
+		_, v := tx.Bucket(bucketPending).Cursor().First()
+		if v == nil {
+			return nil
+		}
+		var msg message
+		if err := json.Unmarshal(v, &msg); err != nil {
+			return err
+		}
+		stats.OldestEnqueuedAt = time.UnixMilli(msg.EnqueuedAt)
+		stats.Attempts = msg.Attempts
+		stats.LastError = msg.LastError
+		if msg.NextAttempt > 0 {
+			stats.NextAttempt = time.UnixMilli(msg.NextAttempt)
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// Close прерывает доставку, дожидается выхода из Run и закрывает очередь.
+func (o *Outbox) Close() error {
+	o.mu.Lock()
+	if o.closed {
+		o.mu.Unlock()
+		return nil
+	}
+	o.closed = true
+	o.mu.Unlock()
+
+	o.cancel()
+	o.running.Wait()
+	return errors.Join(o.db.Close(), o.deadLetter.Close())
+}
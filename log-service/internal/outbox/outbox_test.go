@@ -0,0 +1,255 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func openOutbox(t *testing.T, dir string, opts Options) *Outbox {
+	t.Helper()
+	o, err := Open(dir, opts)
+	if err != nil {
+		t.Fatalf("failed to open outbox: %v", err)
+	}
+	t.Cleanup(func() { o.Close() })
+	return o
+}
+
+// run запускает доставку и возвращает канал доставленных сообщений.
+func run(o *Outbox, deliver Deliver) <-chan string {
+	delivered := make(chan string, 100)
+	go o.Run(func(ctx context.Context, payload []byte) error {
+		if err := deliver(ctx, payload); err != nil {
+			return err
+		}
+		delivered <- string(payload)
+		return nil
+	})
+	return delivered
+}
+
+func receive(t *testing.T, delivered <-chan string, n int) []string {
+	t.Helper()
+	var got []string
+	for len(got) < n {
+		select {
+		case payload := <-delivered:
+			got = append(got, payload)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected %d deliveries, got %v", n, got)
+		}
+	}
+	return got
+}
+
+func waitStats(t *testing.T, o *Outbox, done func(Stats) bool) Stats {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		stats, err := o.Stats()
+		if err != nil {
+			t.Fatalf("failed to read stats: %v", err)
+		}
+		if done(stats) {
+			return stats
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("outbox did not reach the expected state, got %+v", stats)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestDeliversInOrder(t *testing.T) {
+	o := openOutbox(t, t.TempDir(), Options{})
+	for _, payload := range []string{"a", "b", "c"} {
+		if err := o.Enqueue([]byte(payload)); err != nil {
+			t.Fatalf("failed to enqueue: %v", err)
+		}
+	}
+	if stats, _ := o.Stats(); stats.Pending != 3 || stats.OldestEnqueuedAt.IsZero() {
+		t.Errorf("expected 3 pending messages, got %+v", stats)
+	}
+
+	delivered := run(o, func(context.Context, []byte) error { return nil })
+	if got := receive(t, delivered, 3); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("expected messages in order, got %v", got)
+	}
+	// Сообщение после опустошения очереди будит доставку
+	o.Enqueue([]byte("d"))
+	receive(t, delivered, 1)
+
+	stats := waitStats(t, o, func(s Stats) bool { return s.Delivered == 4 })
+	if stats.Pending != 0 || !stats.OldestEnqueuedAt.IsZero() {
+		t.Errorf("expected empty outbox, got %+v", stats)
+	}
+}
+
+func TestEnqueueBatchKeepsOrder(t *testing.T) {
+	o := openOutbox(t, t.TempDir(), Options{})
+	if err := o.Enqueue([]byte("a")); err != nil {
+		t.Fatalf("failed to enqueue: %v", err)
+	}
+	if err := o.EnqueueBatch([][]byte{[]byte("b"), []byte("c")}); err != nil {
+		t.Fatalf("failed to enqueue batch: %v", err)
+	}
+	if err := o.EnqueueBatch(nil); err != nil {
+		t.Fatalf("failed to enqueue empty batch: %v", err)
+	}
+	if stats, _ := o.Stats(); stats.Pending != 3 {
+		t.Errorf("expected 3 pending messages, got %+v", stats)
+	}
+
+	delivered := run(o, func(context.Context, []byte) error { return nil })
+	if got := receive(t, delivered, 3); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("expected messages in order, got %v", got)
+	}
+}
+
+func TestRetriesWithBackoff(t *testing.T) {
+	o := openOutbox(t, t.TempDir(), Options{MinBackoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond})
+	o.Enqueue([]byte("a"))
+	o.Enqueue([]byte("b"))
+
+	var (
+		mu       sync.Mutex
+		attempts []string
+	)
+	delivered := run(o, func(_ context.Context, payload []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts = append(attempts, string(payload))
+		if len(attempts) <= 3 {
+			return errors.New("broker unavailable")
+		}
+		return nil
+	})
+
+	// Следующее сообщение ждёт, пока первое не доставлено
+	if got := receive(t, delivered, 2); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("expected messages in order, got %v", got)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []string{"a", "a", "a", "a", "b"}; !reflect.DeepEqual(attempts, want) {
+		t.Errorf("expected attempts %v, got %v", want, attempts)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	o := &Outbox{opts: Options{MinBackoff: time.Second, MaxBackoff: 10 * time.Second}}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for i, d := range want {
+		if got := o.backoff(i + 1); got != d {
+			t.Errorf("backoff after %d attempts: expected %v, got %v", i+1, d, got)
+		}
+	}
+}
+
+func TestDeadLetter(t *testing.T) {
+	dir := t.TempDir()
+	o := openOutbox(t, dir, Options{MaxAttempts: 3, MinBackoff: time.Millisecond})
+	o.Enqueue([]byte("poison"))
+	o.Enqueue([]byte("ok"))
+
+	delivered := run(o, func(_ context.Context, payload []byte) error {
+		if string(payload) == "poison" {
+			return errors.New("message too large")
+		}
+		return nil
+	})
+	if got := receive(t, delivered, 1); got[0] != "ok" {
+		t.Errorf("expected the next message to be delivered, got %v", got)
+	}
+	stats := waitStats(t, o, func(s Stats) bool { return s.Pending == 0 })
+	if stats.DeadLettered != 1 || stats.Delivered != 1 {
+		t.Errorf("expected 1 dead lettered and 1 delivered message, got %+v", stats)
+	}
+
+	f, err := os.Open(filepath.Join(dir, "dead_letter.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to open dead letter file: %v", err)
+	}
+	defer f.Close()
+	var letters []DeadLetter
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var d DeadLetter
+		if err := json.Unmarshal(scanner.Bytes(), &d); err != nil {
+			t.Fatalf("failed to parse dead letter: %v", err)
+		}
+		letters = append(letters, d)
+	}
+	if len(letters) != 1 || string(letters[0].Payload) != "poison" || letters[0].Attempts != 3 || letters[0].Error != "message too large" {
+		t.Errorf("expected the poison message in dead letter, got %+v", letters)
+	}
+}
+
+func TestPendingSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	o, err := Open(dir, Options{MinBackoff: time.Hour})
+	if err != nil {
+		t.Fatalf("failed to open outbox: %v", err)
+	}
+	o.Enqueue([]byte("a"))
+	o.Enqueue([]byte("b"))
+
+	// Первая попытка неудачна, следующая отложена на час: оба сообщения остаются
+	failed := make(chan struct{})
+	go o.Run(func(ctx context.Context, payload []byte) error {
+		close(failed)
+		return errors.New("broker unavailable")
+	})
+	<-failed
+	waitStats(t, o, func(s Stats) bool { return s.Attempts == 1 })
+	if err := o.Close(); err != nil {
+		t.Fatalf("failed to close outbox: %v", err)
+	}
+
+	reopened := openOutbox(t, dir, Options{})
+	stats, err := reopened.Stats()
+	if err != nil || stats.Pending != 2 || stats.Attempts != 1 || stats.LastError != "broker unavailable" {
+		t.Fatalf("expected both messages and the failed attempt to survive restart, got %+v (%v)", stats, err)
+	}
+	// Следующая попытка по-прежнему отложена
+	if time.Until(stats.NextAttempt) < 30*time.Minute {
+		t.Errorf("expected the next attempt to stay delayed, got %v", stats.NextAttempt)
+	}
+}
+
+func TestCloseInterruptsDelivery(t *testing.T) {
+	dir := t.TempDir()
+	o, err := Open(dir, Options{})
+	if err != nil {
+		t.Fatalf("failed to open outbox: %v", err)
+	}
+	o.Enqueue([]byte("a"))
+
+	started := make(chan struct{})
+	go o.Run(func(ctx context.Context, _ []byte) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	<-started
+	if err := o.Close(); err != nil {
+		t.Fatalf("failed to close outbox: %v", err)
+	}
+
+	reopened := openOutbox(t, dir, Options{})
+	if stats, _ := reopened.Stats(); stats.Pending != 1 || stats.Attempts != 0 {
+		t.Errorf("expected an interrupted delivery not to count as an attempt, got %+v", stats)
+	}
+	delivered := run(reopened, func(context.Context, []byte) error { return nil })
+	if got := receive(t, delivered, 1); got[0] != "a" {
+		t.Errorf("expected the interrupted message to be delivered after restart, got %v", got)
+	}
+}
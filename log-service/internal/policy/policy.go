@@ -0,0 +1,247 @@
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	LevelDebug = "debug"
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+	LevelError = "error"
+)
+
+// Причины, по которым запись не принята; метки метрики log_policy_dropped_total
+const (
+	ReasonLevel    = "level"
+	ReasonSampling = "sampling"
+)
+
+var ErrInvalidPolicy = errors.New("invalid log policy")
+
+var levelRank = map[string]int{LevelDebug: 0, LevelInfo: 1, LevelWarn: 2, LevelError: 3}
+
+// NormalizeLevel приводит уровень записи к debug, info, warn или error без
+// учёта регистра и пробелов. Распространённые синонимы (trace, warning,
+// fatal и т. п.) сводятся к ближайшему уровню, пустой и неизвестный — к info.
+func NormalizeLevel(level string) string {
+	switch l := strings.ToLower(strings.TrimSpace(level)); l {
+	case LevelDebug, LevelInfo, LevelWarn, LevelError:
+		return l
+	case "trace":
+		return LevelDebug
+	case "information", "notice":
+		return LevelInfo
+	case "warning":
+		return LevelWarn
+	case "err", "critical", "fatal", "panic":
+		return LevelError
+	default:
+		return LevelInfo
+	}
+}
+
+// Policy ограничивает приём записей сервиса: записи ниже MinLevel
+// отбрасываются, из записей debug и info сохраняется доля DebugSampleRate и
+// InfoSampleRate (0 — все). Пустой Service — политика сервисов без своей.
+type Policy struct {
+	Service         string  `json:"service"`
+	MinLevel        string  `json:"min_level,omitempty"`
+	DebugSampleRate float64 `json:"debug_sample_rate,omitempty"`
+	InfoSampleRate  float64 `json:"info_sample_rate,omitempty"`
+}
+
+func (p Policy) empty() bool {
+	return p.MinLevel == "" && p.DebugSampleRate == 0 && p.InfoSampleRate == 0
+}
+
+func (p Policy) validate() error {
+	if p.MinLevel != "" {
+		if _, ok := levelRank[p.MinLevel]; !ok {
+			return fmt.Errorf("unknown min_level %q: expected debug, info, warn or error", p.MinLevel)
+		}
+	}
+	for name, rate := range map[string]float64{"debug_sample_rate": p.DebugSampleRate, "info_sample_rate": p.InfoSampleRate} {
+		if math.IsNaN(rate) || rate < 0 || rate > 1 {
+			return fmt.Errorf("%s must be between 0 and 1", name)
+		}
+	}
+	return nil
+}
+
+// Status — политика и сколько записей она отбросила с запуска сервиса.
+type Status struct {
+	Policy
+	DroppedByLevel    uint64
+	DroppedBySampling uint64
+}
+
+type state struct {
+	Status
+	// Сколько записей уровня прошло через выборку; по нему выбирается каждая 1/rate-я
+	seen map[string]uint64
+}
+
+// Policies хранит политики сервисов в JSON-файле и применяет их к входящим
+// записям. Изменения сохраняются в файл сразу и переживают перезапуск.
+type Policies struct {
+	path string
+
+	mu        sync.Mutex
+	byService map[string]*state
+}
+
+type policiesFile struct {
+	Policies []Policy `json:"policies"`
+}
+
+// Open читает политики из path; отсутствующий файл означает политик нет.
+func Open(path string) (*Policies, error) {
+	p := &Policies{path: path, byService: make(map[string]*state)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log policies: %w", err)
+	}
+	var file policiesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse log policies: %w", err)
+	}
+	for _, policy := range file.Policies {
+		policy, err := normalize(policy)
+		if err != nil {
+			return nil, fmt.Errorf("log policy for %q: %w", policy.Service, err)
+		}
+		p.byService[policy.Service] = &state{Status: Status{Policy: policy}, seen: map[string]uint64{}}
+	}
+	return p, nil
+}
+
+func normalize(policy Policy) (Policy, error) {
+	policy.Service = strings.ToLower(strings.TrimSpace(policy.Service))
+	policy.MinLevel = strings.ToLower(strings.TrimSpace(policy.MinLevel))
+	if err := policy.validate(); err != nil {
+		return policy, fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+	}
+	return policy, nil
+}
+
+// Admit решает, принять ли запись сервиса service с уровнем level (см.
+// NormalizeLevel). Возвращает пустую строку или причину отказа.
+func (p *Policies) Admit(service, level string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s, ok := p.byService[strings.ToLower(service)]
+	if !ok {
+		if s, ok = p.byService[""]; !ok {
+			return ""
+		}
+	}
+
+	if s.MinLevel != "" && levelRank[level] < levelRank[s.MinLevel] {
+		s.DroppedByLevel++
+		return ReasonLevel
+	}
+
+	rate := 0.0
+	switch level {
+	case LevelDebug:
+		rate = s.DebugSampleRate
+	case LevelInfo:
+		rate = s.InfoSampleRate
+	}
+	if rate == 0 || rate == 1 {
+		return ""
+	}
+	// Сохраняется запись, на которой целая часть n*rate увеличивается: ровно
+	// доля rate и равномерно, без случайных серий
+	n := s.seen[level]
+	s.seen[level] = n + 1
+	if math.Floor(float64(n+1)*rate) > math.Floor(float64(n)*rate) {
+		return ""
+	}
+	s.DroppedBySampling++
+	return ReasonSampling
+}
+
+// Set заменяет политику сервиса и сохраняет политики в файл. Политика без
+// ограничений удаляет прежнюю. Счётчики отброшенных записей сбрасываются.
+func (p *Policies) Set(policy Policy) error {
+	policy, err := normalize(policy)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	previous, existed := p.byService[policy.Service]
+	if policy.empty() {
+		delete(p.byService, policy.Service)
+	} else {
+		p.byService[policy.Service] = &state{Status: Status{Policy: policy}, seen: map[string]uint64{}}
+	}
+	if err := p.save(); err != nil {
+		// Политика в памяти не должна расходиться с файлом
+		if existed {
+			p.byService[policy.Service] = previous
+		} else {
+			delete(p.byService, policy.Service)
+		}
+		return err
+	}
+	return nil
+}
+
+// save записывает политики во временный файл и переименовывает его, чтобы
+// сбой не оставил файл наполовину записанным. Вызывается под p.mu.
+func (p *Policies) save() error {
+	file := policiesFile{Policies: []Policy{}}
+	for _, s := range p.list() {
+		file.Policies = append(file.Policies, s.Policy)
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p.path), 0755); err != nil {
+		return fmt.Errorf("failed to save log policies: %w", err)
+	}
+	tmp := p.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to save log policies: %w", err)
+	}
+	if err := os.Rename(tmp, p.path); err != nil {
+		return fmt.Errorf("failed to save log policies: %w", err)
+	}
+	return nil
+}
+
+// List возвращает политики по имени сервиса, политика по умолчанию первая.
+func (p *Policies) List() []Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.list()
+}
+
+func (p *Policies) list() []Status {
+	statuses := make([]Status, 0, len(p.byService))
+	for _, s := range p.byService {
+		statuses = append(statuses, s.Status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Service < statuses[j].Service })
+	return statuses
+}
@@ -0,0 +1,135 @@
+package policy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeLevel(t *testing.T) {
+	tests := map[string]string{
+		"debug":    LevelDebug,
+		"TRACE":    LevelDebug,
+		" Info ":   LevelInfo,
+		"notice":   LevelInfo,
+		"WARNING":  LevelWarn,
+		"warn":     LevelWarn,
+		"Error":    LevelError,
+		"fatal":    LevelError,
+		"critical": LevelError,
+		"":         LevelInfo,
+		"verbose":  LevelInfo,
+	}
+	for level, want := range tests {
+		if got := NormalizeLevel(level); got != want {
+			t.Errorf("NormalizeLevel(%q): expected %q, got %q", level, want, got)
+		}
+	}
+}
+
+func openPolicies(t *testing.T) (*Policies, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "log_policies.json")
+	p, err := Open(path)
+	if err != nil {
+		t.Fatalf("failed to open policies: %v", err)
+	}
+	return p, path
+}
+
+func TestAdmitMinLevel(t *testing.T) {
+	p, _ := openPolicies(t)
+	if err := p.Set(Policy{Service: "HTTP-server", MinLevel: "WARN"}); err != nil {
+		t.Fatalf("failed to set policy: %v", err)
+	}
+
+	tests := []struct {
+		service string
+		level   string
+		want    string
+	}{
+		{service: "http-server", level: LevelDebug, want: ReasonLevel},
+		{service: "HTTP-server", level: LevelInfo, want: ReasonLevel},
+		{service: "HTTP-server", level: LevelWarn},
+		{service: "HTTP-server", level: LevelError},
+		{service: "business-server", level: LevelDebug},
+	}
+	for _, tt := range tests {
+		if got := p.Admit(tt.service, tt.level); got != tt.want {
+			t.Errorf("Admit(%q, %q): expected %q, got %q", tt.service, tt.level, tt.want, got)
+		}
+	}
+	if got := p.List()[0]; got.DroppedByLevel != 2 || got.DroppedBySampling != 0 {
+		t.Errorf("expected 2 entries dropped by level, got %+v", got)
+	}
+}
+
+func TestAdmitSampling(t *testing.T) {
+	p, _ := openPolicies(t)
+	p.Set(Policy{MinLevel: LevelDebug, DebugSampleRate: 0.25, InfoSampleRate: 0.5})
+
+	kept := map[string]int{}
+	for i := 0; i < 100; i++ {
+		for _, level := range []string{LevelDebug, LevelInfo, LevelWarn} {
+			if p.Admit("business-server", level) == "" {
+				kept[level]++
+			}
+		}
+	}
+	if want := map[string]int{LevelDebug: 25, LevelInfo: 50, LevelWarn: 100}; !reflect.DeepEqual(kept, want) {
+		t.Errorf("expected %v kept by the default policy, got %v", want, kept)
+	}
+	if got := p.List()[0]; got.DroppedBySampling != 125 {
+		t.Errorf("expected 125 entries dropped by sampling, got %+v", got)
+	}
+}
+
+func TestSetPersists(t *testing.T) {
+	p, path := openPolicies(t)
+	p.Set(Policy{Service: "business-server", MinLevel: "info"})
+	p.Set(Policy{Service: "HTTP-server", InfoSampleRate: 0.1})
+	p.Set(Policy{Service: "payment-server", MinLevel: "error"})
+	// Политика без ограничений удаляет прежнюю
+	p.Set(Policy{Service: "payment-server"})
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("failed to reopen policies: %v", err)
+	}
+	want := []Status{
+		{Policy: Policy{Service: "business-server", MinLevel: "info"}},
+		{Policy: Policy{Service: "http-server", InfoSampleRate: 0.1}},
+	}
+	if got := reopened.List(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v after reopen, got %+v", want, got)
+	}
+}
+
+func TestSetInvalid(t *testing.T) {
+	p, path := openPolicies(t)
+	for _, policy := range []Policy{
+		{Service: "business-server", MinLevel: "loud"},
+		{Service: "business-server", DebugSampleRate: 1.5},
+		{Service: "business-server", InfoSampleRate: -0.1},
+	} {
+		if err := p.Set(policy); !errors.Is(err, ErrInvalidPolicy) {
+			t.Errorf("expected %+v to be rejected, got %v", policy, err)
+		}
+	}
+	if len(p.List()) != 0 {
+		t.Errorf("expected invalid policies not to be applied, got %+v", p.List())
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected invalid policies not to be saved, got %v", err)
+	}
+}
+
+func TestOpenInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log_policies.json")
+	os.WriteFile(path, []byte(`{"policies":[{"service":"x","min_level":"loud"}]}`), 0644)
+	if _, err := Open(path); !errors.Is(err, ErrInvalidPolicy) {
+		t.Errorf("expected invalid policy file to be rejected, got %v", err)
+	}
+}
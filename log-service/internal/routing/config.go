@@ -0,0 +1,157 @@
+package routing
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"log-service/gen"
+	"log-service/internal/policy"
+	"os"
+	"strings"
+)
+
+const (
+	SinkFile         = "file"
+	SinkRotatingFile = "rotating_file"
+	SinkStdout       = "stdout"
+	SinkKafka        = "kafka"
+	SinkWebhook      = "webhook"
+)
+
+// Config — правила маршрутизации из YAML-файла. Каждая запись сохраняется в
+// коллекцию своего сервиса; правила добавляют приёмники, в которые уходят
+// копии подходящих записей.
+type Config struct {
+	Sinks  map[string]SinkConfig `yaml:"sinks"`
+	Routes []Route               `yaml:"routes"`
+}
+
+// SinkConfig описывает приёмник; нужные поля зависят от типа.
+type SinkConfig struct {
+	Type string `yaml:"type"`
+	// file и rotating_file: путь к JSON-lines файлу
+	Path string `yaml:"path"`
+	// rotating_file: размер (64MB) и возраст (24h) файла до ротации, сколько
+	// ротированных файлов хранить; 0 не ограничивает
+	MaxSize  string `yaml:"max_size"`
+	MaxAge   string `yaml:"max_age"`
+	MaxFiles int    `yaml:"max_files"`
+	// kafka: топик и брокер, по умолчанию KAFKA_BROKER
+	Topic  string `yaml:"topic"`
+	Broker string `yaml:"broker"`
+	// webhook: адрес для POST каждой записи и таймаут запроса, по умолчанию 5s
+	URL     string `yaml:"url"`
+	Timeout string `yaml:"timeout"`
+}
+
+// Route отправляет записи, подходящие под Match, во все приёмники Sinks.
+// Применяются все подходящие правила по порядку; Stop прекращает разбор после
+// этого правила.
+type Route struct {
+	Name  string   `yaml:"name"`
+	Match Match    `yaml:"match"`
+	Sinks []string `yaml:"sinks"`
+	Stop  bool     `yaml:"stop"`
+}
+
+// Match — условия правила; пустое условие не ограничивает.
+type Match struct {
+	// Имена сервисов без учёта регистра, * — любой
+	Services []string `yaml:"services"`
+	// Уровни после policy.NormalizeLevel: запись без уровня или с неизвестным уровнем считается info
+	Levels []string `yaml:"levels"`
+	// Значения метаданных; пустое значение требует только наличия ключа
+	Metadata map[string]string `yaml:"metadata"`
+}
+
+// Load читает правила из YAML-файла вида:
+//
+//	sinks:
+//	  alerts:
+//	    type: webhook
+//	    url: http://alerts:9000/logs
+//	routes:
+//	  - name: errors
+//	    match:
+//	      levels: [error]
+//	    sinks: [alerts]
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read routes file: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse routes file: %w", err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func (c *Config) validate() error {
+	for name, sink := range c.Sinks {
+		switch sink.Type {
+		case SinkFile, SinkRotatingFile:
+			if sink.Path == "" {
+				return fmt.Errorf("sink %q: path is required", name)
+			}
+		case SinkStdout:
+		case SinkKafka:
+			if sink.Topic == "" {
+				return fmt.Errorf("sink %q: topic is required", name)
+			}
+		case SinkWebhook:
+			if !strings.HasPrefix(sink.URL, "http://") && !strings.HasPrefix(sink.URL, "https://") {
+				return fmt.Errorf("sink %q: url must be an http or https URL", name)
+			}
+		default:
+			return fmt.Errorf("sink %q: unknown type %q: expected file, rotating_file, stdout, kafka or webhook", name, sink.Type)
+		}
+		if sink.MaxFiles < 0 {
+			return fmt.Errorf("sink %q: max_files must not be negative", name)
+		}
+	}
+
+	for i, route := range c.Routes {
+		name := route.Name
+		if name == "" {
+			name = fmt.Sprintf("%d", i+1)
+		}
+		if len(route.Sinks) == 0 {
+			return fmt.Errorf("route %s: at least one sink is required", name)
+		}
+		for _, sink := range route.Sinks {
+			if _, ok := c.Sinks[sink]; !ok {
+				return fmt.Errorf("route %s: unknown sink %q", name, sink)
+			}
+		}
+	}
+	return nil
+}
+
+func (m Match) matches(entry *gen.LogEntry) bool {
+	if len(m.Services) > 0 && !containsFold(m.Services, entry.GetServiceName()) {
+		return false
+	}
+	if len(m.Levels) > 0 && !containsFold(m.Levels, policy.NormalizeLevel(entry.GetLevel())) {
+		return false
+	}
+	for key, want := range m.Metadata {
+		value, ok := entry.GetMetadata()[key]
+		if !ok || (want != "" && value != want) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if v == "*" || strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package routing
+
+import (
+	"log-service/gen"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeRoutes(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "routes.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write routes file: %v", err)
+	}
+	return path
+}
+
+func TestLoadExample(t *testing.T) {
+	cfg, err := Load("../../routes.example.yaml")
+	if err != nil {
+		t.Fatalf("expected example routes to load, got %v", err)
+	}
+	if len(cfg.Sinks) != 5 || len(cfg.Routes) != 3 {
+		t.Errorf("expected 5 sinks and 3 routes, got %d and %d", len(cfg.Sinks), len(cfg.Routes))
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "unknown field",
+			content: "sinks:\n  out:\n    type: stdout\n    colour: red\n",
+			wantErr: "failed to parse",
+		},
+		{
+			name:    "unknown sink type",
+			content: "sinks:\n  out:\n    type: syslog\n",
+			wantErr: `unknown type "syslog"`,
+		},
+		{
+			name:    "file without path",
+			content: "sinks:\n  out:\n    type: file\n",
+			wantErr: "path is required",
+		},
+		{
+			name:    "kafka without topic",
+			content: "sinks:\n  out:\n    type: kafka\n",
+			wantErr: "topic is required",
+		},
+		{
+			name:    "webhook without scheme",
+			content: "sinks:\n  out:\n    type: webhook\n    url: alerts:9000\n",
+			wantErr: "http or https",
+		},
+		{
+			name:    "route without sinks",
+			content: "routes:\n  - name: errors\n    match:\n      levels: [error]\n",
+			wantErr: "route errors: at least one sink",
+		},
+		{
+			name:    "route to unknown sink",
+			content: "sinks:\n  out:\n    type: stdout\nroutes:\n  - sinks: [out, missing]\n",
+			wantErr: `route 1: unknown sink "missing"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeRoutes(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	entry := &gen.LogEntry{ServiceName: "HTTP-server", Level: "warn", Metadata: map[string]string{"tenant": "acme"}}
+
+	tests := []struct {
+		name  string
+		match Match
+		entry *gen.LogEntry
+		want  bool
+	}{
+		{name: "empty match", entry: entry, want: true},
+		{name: "service ignores case", match: Match{Services: []string{"http-server"}}, entry: entry, want: true},
+		{name: "any service", match: Match{Services: []string{"*"}}, entry: entry, want: true},
+		{name: "other service", match: Match{Services: []string{"business-server"}}, entry: entry},
+		{name: "level", match: Match{Levels: []string{"error", "WARN"}}, entry: entry, want: true},
+		{name: "other level", match: Match{Levels: []string{"error"}}, entry: entry},
+		{name: "entry without level is info", match: Match{Levels: []string{"info"}}, entry: &gen.LogEntry{}, want: true},
+		{name: "unknown level is info", match: Match{Levels: []string{"info"}}, entry: &gen.LogEntry{Level: "verbose"}, want: true},
+		{name: "level synonym", match: Match{Levels: []string{"error"}}, entry: &gen.LogEntry{Level: "FATAL"}, want: true},
+		{name: "metadata value", match: Match{Metadata: map[string]string{"tenant": "acme"}}, entry: entry, want: true},
+		{name: "other metadata value", match: Match{Metadata: map[string]string{"tenant": "globex"}}, entry: entry},
+		{name: "metadata key present", match: Match{Metadata: map[string]string{"tenant": ""}}, entry: entry, want: true},
+		{name: "metadata key missing", match: Match{Metadata: map[string]string{"region": ""}}, entry: entry},
+		{
+			name:  "all conditions",
+			match: Match{Services: []string{"HTTP-server"}, Levels: []string{"warn"}, Metadata: map[string]string{"tenant": "acme"}},
+			entry: entry,
+			want:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.match.matches(tt.entry); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
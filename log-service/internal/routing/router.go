@@ -0,0 +1,158 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"log-service/gen"
+	"log-service/internal/metrics"
+	"log-service/internal/store"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	// Источник записей, для которых не заведена своя коллекция
+	UndefinedSource = "undefined-server"
+	// Сколько сервисов получают свою коллекцию; записи остальных идут в undefined
+	maxServices = 64
+	// Длина имени коллекции, полученного из имени сервиса
+	maxServiceName = 48
+)
+
+// Build создаёт логгер, который пишет записи в коллекцию хранилища и
+// дополнительно в sinks.
+type Build func(collection string, sinks []zapcore.WriteSyncer) *zap.Logger
+
+// Router выбирает логгер записи: коллекцию по имени сервиса (новый сервис
+// получает свою коллекцию без изменения кода) и приёмники по правилам. Логгеры
+// создаются при первой записи с таким сочетанием и переиспользуются.
+type Router struct {
+	routes []Route
+	sinks  map[string]*routedSink
+	build  Build
+
+	mu       sync.Mutex
+	services map[string]bool
+	loggers  map[string]*zap.Logger
+}
+
+// NewRouter открывает приёмники cfg; kafkaBroker используется приёмниками
+// kafka без своего брокера. Пустой cfg сохраняет записи только в хранилище.
+func NewRouter(cfg *Config, kafkaBroker string, build Build) (*Router, error) {
+	r := &Router{
+		routes:   cfg.Routes,
+		sinks:    make(map[string]*routedSink, len(cfg.Sinks)),
+		build:    build,
+		services: make(map[string]bool),
+		loggers:  make(map[string]*zap.Logger),
+	}
+	for name, sinkCfg := range cfg.Sinks {
+		sink, err := openSink(name, sinkCfg, kafkaBroker)
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("sink %q: %w", name, err)
+		}
+		r.sinks[name] = &routedSink{name: name, sink: sink}
+	}
+	return r, nil
+}
+
+var undefinedCollection = store.Collection("undefined")
+
+// ServiceCollection возвращает коллекцию сервиса: имя в нижнем регистре без
+// -server, символы кроме букв, цифр, - и _ заменяются на _. HTTP-server пишет
+// в http_logs.json, business-server — в business_logs.json.
+func ServiceCollection(service string) string {
+	name := strings.TrimSuffix(strings.ToLower(service), "-server")
+	var b strings.Builder
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '_':
+			b.WriteRune(c)
+		default:
+			b.WriteByte('_')
+		}
+		if b.Len() >= maxServiceName {
+			break
+		}
+	}
+	if strings.Trim(b.String(), "_-") == "" {
+		return undefinedCollection
+	}
+	return store.Collection(b.String())
+}
+
+// Logger возвращает логгер записи и источник для метрик: имя коллекции или
+// UndefinedSource, если сервису не досталась своя коллекция. Имя сервиса в
+// метку не попадает: число коллекций ограничено maxServices, а имён — нет.
+func (r *Router) Logger(entry *gen.LogEntry) (string, *zap.Logger) {
+	var names []string
+	for _, route := range r.routes {
+		if !route.Match.matches(entry) {
+			continue
+		}
+		names = append(names, route.Sinks...)
+		if route.Stop {
+			break
+		}
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	collection := ServiceCollection(entry.GetServiceName())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.services[collection] && len(r.services) >= maxServices {
+		collection = undefinedCollection
+	}
+	r.services[collection] = true
+	source := collection
+	if collection == undefinedCollection {
+		source = UndefinedSource
+	}
+
+	key := collection + "|" + strings.Join(names, ",")
+	logger, ok := r.loggers[key]
+	if !ok {
+		sinks := make([]zapcore.WriteSyncer, 0, len(names))
+		for _, name := range names {
+			sinks = append(sinks, r.sinks[name])
+		}
+		logger = r.build(collection, sinks)
+		r.loggers[key] = logger
+	}
+	return source, logger
+}
+
+// Close закрывает приёмники, дождавшись отправки очередей. Вызывается, когда
+// записи больше не поступают.
+func (r *Router) Close() error {
+	var errs []error
+	for _, s := range r.sinks {
+		errs = append(errs, s.sink.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// routedSink не даёт сбою приёмника сорвать запись в хранилище: ошибки
+// считаются в метрике, а Sync логгера копии на диск не сбрасывает.
+type routedSink struct {
+	name string
+	sink Sink
+}
+
+func (s *routedSink) Write(p []byte) (int, error) {
+	if _, err := s.sink.Write(p); err != nil {
+		metrics.SinkErrors.WithLabelValues(s.name).Inc()
+	} else {
+		metrics.RoutedEntries.WithLabelValues(s.name).Inc()
+	}
+	return len(p), nil
+}
+
+func (s *routedSink) Sync() error { return nil }
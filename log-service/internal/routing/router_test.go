@@ -0,0 +1,150 @@
+package routing
+
+import (
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"log-service/gen"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type built struct {
+	collection string
+	sinks      int
+}
+
+// newTestRouter пишет копии в файлы каталога dir: приёмник name — в dir/name.jsonl.
+func newTestRouter(t *testing.T, dir string, sinks []string, routes []Route) (*Router, *[]built) {
+	t.Helper()
+	cfg := &Config{Sinks: map[string]SinkConfig{}, Routes: routes}
+	for _, name := range sinks {
+		cfg.Sinks[name] = SinkConfig{Type: SinkFile, Path: filepath.Join(dir, name+".jsonl")}
+	}
+
+	var loggers []built
+	r, err := NewRouter(cfg, "", func(collection string, sinks []zapcore.WriteSyncer) *zap.Logger {
+		loggers = append(loggers, built{collection: collection, sinks: len(sinks)})
+		out := zapcore.NewMultiWriteSyncer(sinks...)
+		return zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), out, zapcore.DebugLevel))
+	})
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	t.Cleanup(func() { r.Close() })
+	return r, &loggers
+}
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return strings.Fields(strings.TrimSpace(string(data)))
+}
+
+func TestRouterSendsCopiesToMatchingSinks(t *testing.T) {
+	dir := t.TempDir()
+	r, _ := newTestRouter(t, dir, []string{"errors", "acme", "all", "never"}, []Route{
+		{Match: Match{Levels: []string{"error"}}, Sinks: []string{"errors", "all"}},
+		{Match: Match{Metadata: map[string]string{"tenant": "acme"}}, Sinks: []string{"acme"}, Stop: true},
+		{Sinks: []string{"all"}},
+		{Match: Match{Services: []string{"nobody"}}, Sinks: []string{"never"}},
+	})
+
+	entries := []*gen.LogEntry{
+		{ServiceName: "HTTP-server", Level: "error"},
+		{ServiceName: "business-server", Metadata: map[string]string{"tenant": "acme"}},
+		{ServiceName: "business-server", Level: "debug"},
+	}
+	for i, entry := range entries {
+		_, logger := r.Logger(entry)
+		logger.Info(fmt.Sprintf("entry%d", i))
+	}
+
+	want := map[string]int{"errors": 1, "acme": 1, "all": 2, "never": 0}
+	for name, count := range want {
+		lines := []string{}
+		if data, _ := os.ReadFile(filepath.Join(dir, name+".jsonl")); len(data) > 0 {
+			lines = readLines(t, filepath.Join(dir, name+".jsonl"))
+		}
+		if len(lines) != count {
+			t.Errorf("expected %d entries in %s, got %v", count, name, lines)
+		}
+	}
+	// Запись, подходящая под два правила с одним приёмником, копируется один раз
+	if lines := readLines(t, filepath.Join(dir, "all.jsonl")); !strings.Contains(lines[0], "entry0") || !strings.Contains(lines[1], "entry2") {
+		t.Errorf("expected entry0 and entry2 in all, got %v", lines)
+	}
+}
+
+func TestRouterCollections(t *testing.T) {
+	r, loggers := newTestRouter(t, t.TempDir(), []string{"errors"}, []Route{
+		{Match: Match{Levels: []string{"error"}}, Sinks: []string{"errors"}},
+	})
+
+	tests := []struct {
+		service    string
+		level      string
+		source     string
+		collection string
+	}{
+		{service: "HTTP-server", source: "http_logs.json", collection: "http_logs.json"},
+		{service: "business-server", source: "business_logs.json", collection: "business_logs.json"},
+		{service: "Payment-Server", source: "payment_logs.json", collection: "payment_logs.json"},
+		{service: "../etc/passwd", source: "___etc_passwd_logs.json", collection: "___etc_passwd_logs.json"},
+		{service: "", source: UndefinedSource, collection: "undefined_logs.json"},
+		{service: "undefined-server", source: UndefinedSource, collection: "undefined_logs.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.service, func(t *testing.T) {
+			source, _ := r.Logger(&gen.LogEntry{ServiceName: tt.service, Level: tt.level})
+			if source != tt.source {
+				t.Errorf("expected source %q, got %q", tt.source, source)
+			}
+			if got := (*loggers)[len(*loggers)-1].collection; got != tt.collection {
+				t.Errorf("expected collection %q, got %q", tt.collection, got)
+			}
+		})
+	}
+
+	// Логгер создаётся один раз на сочетание коллекции и приёмников
+	before := len(*loggers)
+	r.Logger(&gen.LogEntry{ServiceName: "HTTP-server"})
+	r.Logger(&gen.LogEntry{ServiceName: "http-server"})
+	r.Logger(&gen.LogEntry{ServiceName: "HTTP-server", Level: "error"})
+	r.Logger(&gen.LogEntry{ServiceName: "HTTP-server", Level: "error"})
+	if got := (*loggers)[before:]; !reflect.DeepEqual(got, []built{{collection: "http_logs.json", sinks: 1}}) {
+		t.Errorf("expected one new logger for errors of HTTP-server, got %v", got)
+	}
+}
+
+func TestRouterLimitsServices(t *testing.T) {
+	r, loggers := newTestRouter(t, t.TempDir(), nil, nil)
+
+	for i := 0; i < maxServices; i++ {
+		r.Logger(&gen.LogEntry{ServiceName: fmt.Sprintf("service%d", i)})
+	}
+	source, _ := r.Logger(&gen.LogEntry{ServiceName: "one-too-many"})
+	if source != UndefinedSource || (*loggers)[len(*loggers)-1].collection != "undefined_logs.json" {
+		t.Errorf("expected services over the limit to go to undefined, got %s in %s", source, (*loggers)[len(*loggers)-1].collection)
+	}
+	if source, _ := r.Logger(&gen.LogEntry{ServiceName: "service0"}); source != "service0_logs.json" {
+		t.Errorf("expected known services to keep their collection, got %s", source)
+	}
+}
+
+func TestNewRouterReportsBadSink(t *testing.T) {
+	cfg := &Config{Sinks: map[string]SinkConfig{"archive": {Type: SinkRotatingFile, Path: filepath.Join(t.TempDir(), "a.jsonl"), MaxSize: "lots"}}}
+	if _, err := NewRouter(cfg, "", nil); err == nil || !strings.Contains(err.Error(), `sink "archive": max_size`) {
+		t.Errorf("expected invalid max_size to be reported, got %v", err)
+	}
+	cfg = &Config{Sinks: map[string]SinkConfig{"events": {Type: SinkKafka, Topic: "logs"}}}
+	if _, err := NewRouter(cfg, "", nil); err == nil || !strings.Contains(err.Error(), "broker is required") {
+		t.Errorf("expected kafka sink without broker to be rejected, got %v", err)
+	}
+}
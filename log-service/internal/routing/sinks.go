@@ -0,0 +1,291 @@
+package routing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	kafkago "github.com/segmentio/kafka-go"
+	"log-service/internal/clients/kafka"
+	"log-service/internal/metrics"
+	"log-service/internal/store"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Sink принимает записи логгера строками JSON. Write вызывается параллельно
+// из логгеров разных сервисов.
+type Sink interface {
+	Write(p []byte) (int, error)
+	Sync() error
+	Close() error
+}
+
+// Сколько записей ждут отправки в webhook, прежде чем новые отбрасываются
+const webhookQueue = 1024
+
+var errSinkClosed = errors.New("sink is closed")
+
+// openSink открывает приёмник name; name попадает в метки метрик.
+func openSink(name string, cfg SinkConfig, kafkaBroker string) (Sink, error) {
+	switch cfg.Type {
+	case SinkFile:
+		return openFileSink(cfg.Path, 0, 0, 0)
+	case SinkRotatingFile:
+		var (
+			maxSize int64
+			maxAge  time.Duration
+			err     error
+		)
+		if cfg.MaxSize != "" {
+			if maxSize, err = store.ParseSize(cfg.MaxSize); err != nil {
+				return nil, fmt.Errorf("max_size: %w", err)
+			}
+		}
+		if cfg.MaxAge != "" {
+			if maxAge, err = time.ParseDuration(cfg.MaxAge); err != nil {
+				return nil, fmt.Errorf("max_age: %w", err)
+			}
+		}
+		return openFileSink(cfg.Path, maxSize, maxAge, cfg.MaxFiles)
+	case SinkStdout:
+		return &stdoutSink{}, nil
+	case SinkKafka:
+		broker := cfg.Broker
+		if broker == "" {
+			broker = kafkaBroker
+		}
+		if broker == "" {
+			return nil, errors.New("broker is required when KAFKA_BROKER is not set")
+		}
+		return newKafkaSink(name, broker, cfg.Topic), nil
+	case SinkWebhook:
+		timeout := 5 * time.Second
+		if cfg.Timeout != "" {
+			var err error
+			if timeout, err = time.ParseDuration(cfg.Timeout); err != nil {
+				return nil, fmt.Errorf("timeout: %w", err)
+			}
+		}
+		return newWebhookSink(name, cfg.URL, timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown sink type %q", cfg.Type)
+	}
+}
+
+// fileSink дописывает записи в JSON-lines файл. Файл переименовывается в
+// <path>.<время ротации>, когда превышает maxSize или старше maxAge; из
+// ротированных хранятся последние maxFiles. Нулевые значения не ограничивают.
+type fileSink struct {
+	path     string
+	maxSize  int64
+	maxAge   time.Duration
+	maxFiles int
+
+	mu      sync.Mutex
+	file    *os.File
+	size    int64
+	started time.Time
+}
+
+func openFileSink(path string, maxSize int64, maxAge time.Duration, maxFiles int) (*fileSink, error) {
+	s := &fileSink{path: path, maxSize: maxSize, maxAge: maxAge, maxFiles: maxFiles}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	s.file, s.size, s.started = file, info.Size(), time.Now()
+	return nil
+}
+
+func (s *fileSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.size > 0 && ((s.maxSize > 0 && s.size+int64(len(p)) > s.maxSize) || (s.maxAge > 0 && time.Since(s.started) >= s.maxAge)) {
+		if err := s.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := s.file.Write(p)
+	s.size += int64(n)
+	return n, err
+}
+
+// rotate переименовывает текущий файл и открывает новый. Вызывается под s.mu.
+func (s *fileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	rotated := s.path + "." + time.Now().UTC().Format("20060102T150405.000000000")
+	if err := os.Rename(s.path, rotated); err != nil {
+		return err
+	}
+	if err := s.open(); err != nil {
+		return err
+	}
+	if s.maxFiles == 0 {
+		return nil
+	}
+
+	// Время в имени сортируется как строка, старые файлы идут первыми
+	old, err := filepath.Glob(s.path + ".*")
+	if err != nil {
+		return err
+	}
+	sort.Strings(old)
+	for len(old) > s.maxFiles {
+		if err := os.Remove(old[0]); err != nil {
+			return err
+		}
+		old = old[1:]
+	}
+	return nil
+}
+
+func (s *fileSink) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Sync()
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+type stdoutSink struct {
+	mu sync.Mutex
+}
+
+func (s *stdoutSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return os.Stdout.Write(p)
+}
+
+func (s *stdoutSink) Sync() error  { return nil }
+func (s *stdoutSink) Close() error { return nil }
+
+// kafkaSink публикует каждую запись сообщением в топик. Писатель асинхронный:
+// Write не ждёт брокера, ошибки доставки только считаются.
+type kafkaSink struct {
+	writer *kafkago.Writer
+}
+
+func newKafkaSink(name, broker, topic string) *kafkaSink {
+	writer := kafka.NewKafkaWriter(broker, topic)
+	writer.Async = true
+	writer.Completion = func(messages []kafkago.Message, err error) {
+		if err != nil {
+			metrics.SinkErrors.WithLabelValues(name).Add(float64(len(messages)))
+		}
+	}
+	return &kafkaSink{writer: writer}
+}
+
+func (s *kafkaSink) Write(p []byte) (int, error) {
+	if err := s.writer.WriteMessages(context.Background(), kafkago.Message{Value: bytes.TrimSpace(bytes.Clone(p))}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (s *kafkaSink) Sync() error  { return nil }
+func (s *kafkaSink) Close() error { return s.writer.Close() }
+
+// webhookSink отправляет каждую запись POST-запросом с JSON в теле. Запросы
+// идут из очереди в отдельной горутине, при переполнении очереди записи
+// отбрасываются.
+type webhookSink struct {
+	name   string
+	url    string
+	client *http.Client
+	queue  chan []byte
+	done   chan struct{}
+
+	// closed защищает queue от записи после Close
+	mu     sync.RWMutex
+	closed bool
+}
+
+func newWebhookSink(name, url string, timeout time.Duration) *webhookSink {
+	s := &webhookSink{
+		name:   name,
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+		queue:  make(chan []byte, webhookQueue),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *webhookSink) Write(p []byte) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return 0, errSinkClosed
+	}
+	select {
+	case s.queue <- bytes.TrimSpace(bytes.Clone(p)):
+	default:
+		metrics.SinkDropped.WithLabelValues(s.name).Inc()
+	}
+	return len(p), nil
+}
+
+func (s *webhookSink) run() {
+	defer close(s.done)
+	for body := range s.queue {
+		if err := s.post(body); err != nil {
+			metrics.SinkErrors.WithLabelValues(s.name).Inc()
+		}
+	}
+}
+
+func (s *webhookSink) post(body []byte) error {
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+func (s *webhookSink) Sync() error { return nil }
+
+// Close отправляет записи, оставшиеся в очереди, и останавливает горутину.
+func (s *webhookSink) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+	<-s.done
+	return nil
+}
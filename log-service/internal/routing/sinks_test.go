@@ -0,0 +1,93 @@
+package routing
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestRotatingFileSink(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "archive.jsonl")
+	sink, err := openSink("archive", SinkConfig{Type: SinkRotatingFile, Path: path, MaxSize: "20B", MaxFiles: 2}, "")
+	if err != nil {
+		t.Fatalf("failed to open sink: %v", err)
+	}
+	defer sink.Close()
+
+	// Каждая строка — 12 байт, в файл помещается одна
+	for _, line := range []string{"{\"n\":\"one\"}\n", "{\"n\":\"two\"}\n", "{\"n\":\"thr\"}\n", "{\"n\":\"fou\"}\n"} {
+		if _, err := sink.Write([]byte(line)); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+	}
+
+	if lines := readLines(t, path); len(lines) != 1 || lines[0] != `{"n":"fou"}` {
+		t.Errorf("expected the last entry in the active file, got %v", lines)
+	}
+	rotated, _ := filepath.Glob(path + ".*")
+	sort.Strings(rotated)
+	if len(rotated) != 2 {
+		t.Fatalf("expected 2 rotated files to be kept, got %v", rotated)
+	}
+	if lines := readLines(t, rotated[0]); len(lines) != 1 || lines[0] != `{"n":"two"}` {
+		t.Errorf("expected the oldest rotated file to be removed, got %v", lines)
+	}
+}
+
+func TestFileSinkAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "audit.jsonl")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, []byte("{\"n\":1}\n"), 0644)
+
+	sink, err := openSink("audit", SinkConfig{Type: SinkFile, Path: path}, "")
+	if err != nil {
+		t.Fatalf("failed to open sink: %v", err)
+	}
+	sink.Write([]byte("{\"n\":2}\n"))
+	sink.Close()
+
+	if lines := readLines(t, path); len(lines) != 2 {
+		t.Errorf("expected existing file to be appended to, got %v", lines)
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, r.Header.Get("Content-Type")+" "+string(body))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	sink, err := openSink("alerts", SinkConfig{Type: SinkWebhook, URL: server.URL}, "")
+	if err != nil {
+		t.Fatalf("failed to open sink: %v", err)
+	}
+	sink.Write([]byte("{\"id\":\"a\"}\n"))
+	sink.Write([]byte("{\"id\":\"b\"}\n"))
+	// Close дожидается отправки очереди
+	sink.Close()
+	// Запись после Close не паникует на закрытой очереди
+	if _, err := sink.Write([]byte("{\"id\":\"c\"}\n")); err == nil {
+		t.Error("expected write after close to fail")
+	}
+	sink.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{`application/json {"id":"a"}`, `application/json {"id":"b"}`}
+	if len(bodies) != 2 || bodies[0] != want[0] || bodies[1] != want[1] {
+		t.Errorf("expected %v, got %v", want, bodies)
+	}
+}
@@ -0,0 +1,308 @@
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"go.etcd.io/bbolt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	bucketRecords = []byte("records")
+	bucketIDs     = []byte("ids")
+	bucketTime    = []byte("time")
+	bucketSource  = []byte("source")
+)
+
+// Сколько записей Scan читает за одну транзакцию
+const boltScanBatch = 1000
+
+// BoltStore хранит записи во встроенной базе bbolt. Каждая коллекция — бакет
+// с вложенными бакетами: records (порядковый номер → запись), ids (id → номер),
+// time (timestamp|номер) и source (источник в нижнем регистре|0|номер).
+type BoltStore struct {
+	db *bbolt.DB
+}
+
+func NewBoltStore(path string) (*BoltStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+	db, err := bbolt.Open(path, 0644, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	return &BoltStore{db: db}, nil
+}
+
+func uint64Key(v uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, v)
+}
+
+func timeKey(ts int64, seq []byte) []byte {
+	// Сдвиг знакового бита сохраняет порядок отрицательных значений
+	return append(uint64Key(uint64(ts)^(1<<63)), seq...)
+}
+
+func sourcePrefix(source string) []byte {
+	return append([]byte(strings.ToLower(source)), 0)
+}
+
+func (s *BoltStore) Append(collection string, data []byte) error {
+	return s.AppendBatch(collection, [][]byte{data})
+}
+
+// AppendBatch пишет записи одной транзакцией: bbolt сбрасывает её на диск
+// один раз на всю пачку.
+func (s *BoltStore) AppendBatch(collection string, records [][]byte) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		parsed, err := parseBatch(collection, records, func(id string) bool {
+			return tx.ForEach(func(_ []byte, b *bbolt.Bucket) error {
+				if ids := b.Bucket(bucketIDs); ids != nil && ids.Get([]byte(id)) != nil {
+					return ErrDuplicateID
+				}
+				return nil
+			}) != nil
+		})
+		if err != nil {
+			return err
+		}
+
+		b, err := tx.CreateBucketIfNotExists([]byte(collection))
+		if err != nil {
+			return err
+		}
+		for _, name := range [][]byte{bucketRecords, bucketIDs, bucketTime, bucketSource} {
+			if _, err := b.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		for i, r := range parsed {
+			if err := putRecord(b, r, records[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// putRecord дописывает запись в бакет коллекции. Id записи проверен parseBatch.
+func putRecord(b *bbolt.Bucket, r Record, data []byte) error {
+	records := b.Bucket(bucketRecords)
+	n, err := records.NextSequence()
+	if err != nil {
+		return err
+	}
+	seq := uint64Key(n)
+	if err := records.Put(seq, data); err != nil {
+		return err
+	}
+	if err := b.Bucket(bucketIDs).Put([]byte(r.ID), seq); err != nil {
+		return err
+	}
+	if err := b.Bucket(bucketTime).Put(timeKey(r.Timestamp, seq), nil); err != nil {
+		return err
+	}
+	return b.Bucket(bucketSource).Put(append(sourcePrefix(r.Source), seq...), nil)
+}
+
+// deleteRecord удаляет запись id и её ключи во вторичных индексах.
+func deleteRecord(b *bbolt.Bucket, id string) error {
+	ids := b.Bucket(bucketIDs)
+	seq := ids.Get([]byte(id))
+	if seq == nil {
+		return ErrNotFound
+	}
+	seq = bytes.Clone(seq)
+
+	records := b.Bucket(bucketRecords)
+	if data := records.Get(seq); data != nil {
+		if r, err := parseRecord("", data); err == nil {
+			if err := b.Bucket(bucketTime).Delete(timeKey(r.Timestamp, seq)); err != nil {
+				return err
+			}
+			if err := b.Bucket(bucketSource).Delete(append(sourcePrefix(r.Source), seq...)); err != nil {
+				return err
+			}
+		}
+	}
+	if err := records.Delete(seq); err != nil {
+		return err
+	}
+	return ids.Delete([]byte(id))
+}
+
+func (s *BoltStore) Get(collection, id string) ([]byte, error) {
+	var data []byte
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(collection))
+		if b == nil {
+			return ErrUnknownCollection
+		}
+		seq := b.Bucket(bucketIDs).Get([]byte(id))
+		if seq == nil {
+			return ErrNotFound
+		}
+		// Данные bbolt действительны только внутри транзакции
+		data = bytes.Clone(b.Bucket(bucketRecords).Get(seq))
+		return nil
+	})
+	return data, err
+}
+
+func (s *BoltStore) Delete(collection, id string) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(collection))
+		if b == nil {
+			return ErrUnknownCollection
+		}
+		return deleteRecord(b, id)
+	})
+}
+
+// boltRange — диапазон ключей бакета, который обходит Scan. Ключи индексов
+// заканчиваются порядковым номером записи, ключи records — это он сам.
+type boltRange struct {
+	bucket []byte
+	prefix []byte
+	start  []byte
+	// Ключ, после которого диапазон заканчивается; nil — до конца префикса
+	end []byte
+}
+
+func (o ScanOptions) ranges() []boltRange {
+	if o.From != 0 || o.To != 0 {
+		r := boltRange{bucket: bucketTime}
+		if o.From != 0 {
+			r.start = timeKey(o.From, nil)
+		}
+		if o.To != 0 {
+			r.end = timeKey(o.To, bytes.Repeat([]byte{0xff}, 8))
+		}
+		return []boltRange{r}
+	}
+	if len(o.Sources) > 0 {
+		var ranges []boltRange
+		seen := map[string]bool{}
+		for _, source := range o.Sources {
+			prefix := sourcePrefix(source)
+			if seen[string(prefix)] {
+				continue
+			}
+			seen[string(prefix)] = true
+			ranges = append(ranges, boltRange{bucket: bucketSource, prefix: prefix, start: prefix})
+		}
+		return ranges
+	}
+	return []boltRange{{bucket: bucketRecords}}
+}
+
+// Scan читает записи пачками в отдельных транзакциях и вызывает fn вне их,
+// поэтому fn может писать в то же хранилище.
+func (s *BoltStore) Scan(opts ScanOptions, fn func(Record) bool) error {
+	collections, err := s.Collections()
+	if err != nil {
+		return err
+	}
+	for _, name := range collections {
+		if !opts.matchCollection(name) {
+			continue
+		}
+		for _, rng := range opts.ranges() {
+			more, err := s.scanRange(name, rng, opts, fn)
+			if err != nil || !more {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *BoltStore) scanRange(collection string, rng boltRange, opts ScanOptions, fn func(Record) bool) (bool, error) {
+	var after []byte
+	for {
+		var batch []Record
+		err := s.db.View(func(tx *bbolt.Tx) error {
+			b := tx.Bucket([]byte(collection))
+			if b == nil {
+				return nil
+			}
+			records := b.Bucket(bucketRecords)
+			c := b.Bucket(rng.bucket).Cursor()
+
+			var k []byte
+			if after == nil {
+				if rng.start == nil {
+					k, _ = c.First()
+				} else {
+					k, _ = c.Seek(rng.start)
+				}
+			} else if k, _ = c.Seek(after); bytes.Equal(k, after) {
+				k, _ = c.Next()
+			}
+
+			for ; k != nil && len(batch) < boltScanBatch; k, _ = c.Next() {
+				if !bytes.HasPrefix(k, rng.prefix) || (rng.end != nil && bytes.Compare(k, rng.end) > 0) {
+					after = nil
+					return nil
+				}
+				after = bytes.Clone(k)
+
+				data := records.Get(k[len(k)-8:])
+				if data == nil {
+					continue
+				}
+				r, err := parseRecord(collection, bytes.Clone(data))
+				if err != nil {
+					continue
+				}
+				if opts.match(r) {
+					batch = append(batch, r)
+				}
+			}
+			if k == nil {
+				after = nil
+			}
+			return nil
+		})
+		if err != nil {
+			return false, err
+		}
+
+		for _, r := range batch {
+			if !fn(r) {
+				return false, nil
+			}
+		}
+		if after == nil {
+			return true, nil
+		}
+	}
+}
+
+func (s *BoltStore) Collections() ([]string, error) {
+	var names []string
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.ForEach(func(name []byte, _ *bbolt.Bucket) error {
+			names = append(names, string(name))
+			return nil
+		})
+	})
+	return names, err
+}
+
+func (s *BoltStore) Sync() error {
+	return s.db.Sync()
+}
+
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
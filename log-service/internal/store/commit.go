@@ -0,0 +1,133 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+	"log-service/internal/metrics"
+	"sync"
+)
+
+// Committer собирает записи параллельных писателей и сохраняет их групповой
+// фиксацией: записи, накопившиеся, пока шла предыдущая фиксация, дописываются
+// одной пачкой на коллекцию и сбрасываются на диск одним Sync хранилища.
+// Писатель, вызвавший Sync, становится ведущим, если фиксация не идёт, иначе
+// ждёт её и, если его записи в неё не попали, следующую.
+type Committer struct {
+	store LogStore
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	open *commitBatch
+	// Пачка, которую сейчас фиксирует ведущий; nil, если фиксация не идёт
+	flushing *commitBatch
+}
+
+type commitBatch struct {
+	collections []string
+	records     map[string][][]byte
+	size        int
+	done        bool
+	err         error
+}
+
+func newCommitBatch() *commitBatch {
+	return &commitBatch{records: make(map[string][][]byte)}
+}
+
+func NewCommitter(s LogStore) *Committer {
+	c := &Committer{store: s, open: newCommitBatch()}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Add ставит запись в очередь на фиксацию. Запись сохранена, только когда
+// следующий за Add вызов Sync вернул nil.
+func (c *Committer) Add(collection string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b := c.open
+	if _, ok := b.records[collection]; !ok {
+		b.collections = append(b.collections, collection)
+	}
+	b.records[collection] = append(b.records[collection], data)
+	b.size++
+}
+
+// Sync ждёт, пока все записи, поставленные в очередь до вызова, будут
+// дописаны и сброшены на диск, и возвращает ошибку их фиксации.
+func (c *Committer) Sync() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	target := c.open
+	if target.size == 0 {
+		// Всё поставленное в очередь уже фиксируется
+		if c.flushing == nil {
+			return nil
+		}
+		target = c.flushing
+	}
+	for !target.done {
+		if c.flushing != nil {
+			c.cond.Wait()
+			continue
+		}
+		b := c.open
+		c.open = newCommitBatch()
+		c.flushing = b
+		c.mu.Unlock()
+
+		b.err = c.flush(b)
+
+		c.mu.Lock()
+		b.done = true
+		c.flushing = nil
+		c.cond.Broadcast()
+	}
+	return target.err
+}
+
+func (c *Committer) flush(b *commitBatch) error {
+	metrics.CommitBatchSize.Observe(float64(b.size))
+
+	var errs []error
+	for _, collection := range b.collections {
+		err := c.store.AppendBatch(collection, b.records[collection])
+		if errors.Is(err, ErrDuplicateID) {
+			// Повторённый id не должен отменять остальные записи пачки
+			err = nil
+			for _, data := range b.records[collection] {
+				errs = append(errs, c.store.Append(collection, data))
+			}
+		}
+		errs = append(errs, err)
+	}
+	errs = append(errs, c.store.Sync())
+	return errors.Join(errs...)
+}
+
+// Writer возвращает вывод zap в коллекцию через групповую фиксацию: Write
+// ставит строки в очередь, Sync фиксирует их.
+func (c *Committer) Writer(collection string) *CommitWriter {
+	return &CommitWriter{committer: c, collection: collection}
+}
+
+type CommitWriter struct {
+	committer  *Committer
+	collection string
+}
+
+func (w *CommitWriter) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(p, []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		w.committer.Add(w.collection, bytes.Clone(line))
+	}
+	return len(p), nil
+}
+
+func (w *CommitWriter) Sync() error {
+	return w.committer.Sync()
+}
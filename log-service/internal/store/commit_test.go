@@ -0,0 +1,143 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// countingStore считает пачки и сбросы на диск и может задерживать Sync,
+// чтобы параллельные писатели успели накопиться.
+type countingStore struct {
+	LogStore
+	batches atomic.Int64
+	syncs   atomic.Int64
+	syncErr error
+	gate    chan struct{}
+}
+
+func (s *countingStore) AppendBatch(collection string, records [][]byte) error {
+	s.batches.Add(1)
+	return s.LogStore.AppendBatch(collection, records)
+}
+
+func (s *countingStore) Sync() error {
+	s.syncs.Add(1)
+	if s.gate != nil {
+		<-s.gate
+	}
+	if s.syncErr != nil {
+		return s.syncErr
+	}
+	return s.LogStore.Sync()
+}
+
+func TestAppendBatch(t *testing.T) {
+	for _, backend := range backends {
+		t.Run(backend, func(t *testing.T) {
+			s := openStore(t, backend)
+			batch := [][]byte{record("a", "HTTP-server", 100), record("b", "HTTP-server", 200)}
+			if err := s.AppendBatch("http_logs.json", batch); err != nil {
+				t.Fatalf("failed to append batch: %v", err)
+			}
+			if got := scanIDs(t, s, ScanOptions{}); !reflect.DeepEqual(got, []string{"a", "b"}) {
+				t.Errorf("expected a and b, got %v", got)
+			}
+			if data, err := s.Get("http_logs.json", "b"); err != nil || string(data) != string(batch[1]) {
+				t.Errorf("expected record b, got %q, %v", data, err)
+			}
+
+			invalid := [][]byte{record("c", "HTTP-server", 400), []byte(`{"msg":"no id"}`)}
+			if err := s.AppendBatch("http_logs.json", invalid); err == nil {
+				t.Errorf("expected batch with an invalid record to be rejected")
+			}
+			if _, err := s.Get("http_logs.json", "c"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("expected rejected batch not to be written, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCommitterGroupsConcurrentWriters(t *testing.T) {
+	st := &countingStore{LogStore: NewMemoryStore(), gate: make(chan struct{})}
+	c := NewCommitter(st)
+
+	// Первый писатель занимает фиксацию, остальные копятся за ним
+	first := c.Writer("http_logs.json")
+	first.Write(append(record("w0", "HTTP-server", 1), '\n'))
+	firstDone := make(chan error)
+	go func() { firstDone <- first.Sync() }()
+	// Ждём, пока ведущий дойдёт до Sync хранилища
+	for st.syncs.Load() == 0 {
+		runtime.Gosched()
+	}
+
+	const writers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 1; i <= writers; i++ {
+		c.Add("business_logs.json", record(fmt.Sprintf("w%d", i), "business-server", int64(i)))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- c.Sync()
+		}()
+	}
+	close(st.gate)
+	if err := <-firstDone; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if got := len(scanIDs(t, st, ScanOptions{})); got != writers+1 {
+		t.Errorf("expected %d records, got %d", writers+1, got)
+	}
+	if st.syncs.Load() != 2 || st.batches.Load() != 2 {
+		t.Errorf("expected the waiting writers to share one commit, got %d syncs and %d batches", st.syncs.Load(), st.batches.Load())
+	}
+	if err := c.Sync(); err != nil || st.syncs.Load() != 2 {
+		t.Errorf("expected Sync with nothing queued to return at once, got %v after %d syncs", err, st.syncs.Load())
+	}
+}
+
+func TestCommitterReportsFailedSync(t *testing.T) {
+	st := &countingStore{LogStore: NewMemoryStore(), syncErr: errors.New("disk full")}
+	c := NewCommitter(st)
+
+	c.Add("http_logs.json", record("a", "HTTP-server", 1))
+	if err := c.Sync(); err == nil || err.Error() != "disk full" {
+		t.Errorf("expected sync error to be returned, got %v", err)
+	}
+
+	st.syncErr = nil
+	c.Add("http_logs.json", record("b", "HTTP-server", 2))
+	if err := c.Sync(); err != nil {
+		t.Errorf("expected the next commit to succeed, got %v", err)
+	}
+}
+
+func TestCommitterKeepsBatchWithDuplicateID(t *testing.T) {
+	st := NewMemoryStore()
+	st.Append("http_logs.json", record("a", "HTTP-server", 1))
+	c := NewCommitter(st)
+
+	c.Add("http_logs.json", record("b", "HTTP-server", 2))
+	c.Add("http_logs.json", record("a", "HTTP-server", 3))
+	c.Add("http_logs.json", record("c", "HTTP-server", 4))
+	if err := c.Sync(); !errors.Is(err, ErrDuplicateID) {
+		t.Errorf("expected duplicate id to be reported, got %v", err)
+	}
+	if got := scanIDs(t, st, ScanOptions{}); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("expected the other records of the batch to be written, got %v", got)
+	}
+}
@@ -0,0 +1,363 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"log-service/internal/index"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileStore хранит каждую коллекцию в JSON-lines файле каталога и ищет записи
+// по индексу (см. пакет index). Файл ротируется в сегменты по FileOptions,
+// сегменты сжимаются и удаляются по правилам хранения; чтение и поиск
+// охватывают все сегменты коллекции. Удаление дописывает надгробие в активный
+// файл, место освобождает фоновое уплотнение (см. compact).
+type FileStore struct {
+	dir  string
+	idx  *index.Index
+	opts FileOptions
+
+	mu      sync.Mutex
+	writers map[string]*activeFile
+
+	// Сжатие, уплотнение и правила хранения применяются в фоне, см. maintain
+	maintenance sync.Mutex
+	kick        chan struct{}
+	background  sync.WaitGroup
+	stop        chan struct{}
+	closeOnce   sync.Once
+}
+
+type activeFile struct {
+	*index.Writer
+	started time.Time
+}
+
+func NewFileStore(dir string, opts FileOptions) (*FileStore, error) {
+	idx, err := index.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	s := &FileStore{
+		dir:     dir,
+		idx:     idx,
+		opts:    opts,
+		writers: make(map[string]*activeFile),
+		kick:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+	}
+	s.background.Add(1)
+	go s.maintainLoop()
+	return s, nil
+}
+
+// validCollection пропускает только файлы логгеров самого каталога (см. Collection).
+func validCollection(name string) bool {
+	return filepath.Base(name) == name && !strings.HasPrefix(name, ".") && strings.HasSuffix(name, Collection(""))
+}
+
+// collectionOf возвращает коллекцию файла: у сегмента — файл, из которого он ротирован.
+func collectionOf(file string) string {
+	active, _, _ := index.ParseName(file)
+	return active
+}
+
+func (s *FileStore) attach(collection string) error {
+	if !validCollection(collection) {
+		return ErrUnknownCollection
+	}
+	err := s.idx.Attach(collection)
+	if errors.Is(err, os.ErrNotExist) {
+		// После ротации коллекция живёт в сегментах до следующей записи
+		if len(s.files(collection)) > 0 {
+			return nil
+		}
+		return ErrUnknownCollection
+	}
+	return err
+}
+
+func (s *FileStore) Append(collection string, data []byte) error {
+	return s.AppendBatch(collection, [][]byte{data})
+}
+
+func (s *FileStore) AppendBatch(collection string, records [][]byte) error {
+	if !validCollection(collection) {
+		return ErrUnknownCollection
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Индекс общий для всех файлов каталога, поэтому id проверяется во всех коллекциях
+	_, err := parseBatch(collection, records, func(id string) bool {
+		_, ok := s.idx.Lookup(id)
+		return ok
+	})
+	if err != nil {
+		return err
+	}
+	var lines []byte
+	for _, data := range records {
+		lines = append(append(lines, data...), '\n')
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return s.write(collection, lines)
+}
+
+// write дописывает строку в активный файл коллекции, ротируя его по
+// RotationPolicy. Вызывается под s.mu.
+func (s *FileStore) write(collection string, line []byte) error {
+	if w, ok := s.writers[collection]; ok && s.opts.Rotation.dueByAge(w.started, time.Now()) {
+		if err := s.rotate(collection); err != nil {
+			return err
+		}
+	}
+	w, err := s.writer(collection)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(line); err != nil {
+		return err
+	}
+	if s.opts.Rotation.dueBySize(w.Size()) {
+		return s.rotate(collection)
+	}
+	return nil
+}
+
+// writer возвращает писателя активного файла коллекции. Вызывается под s.mu.
+func (s *FileStore) writer(collection string) (*activeFile, error) {
+	if w, ok := s.writers[collection]; ok {
+		return w, nil
+	}
+	w, err := s.idx.Writer(collection)
+	if err != nil {
+		return nil, err
+	}
+	// Файл, начатый до запуска, отсчитывает возраст от первой записи
+	started := time.Now()
+	if first, ok := s.idx.First(collection); ok {
+		started = time.UnixMilli(first)
+	}
+	s.writers[collection] = &activeFile{Writer: w, started: started}
+	return s.writers[collection], nil
+}
+
+func (s *FileStore) Get(collection, id string) ([]byte, error) {
+	if err := s.attach(collection); err != nil {
+		return nil, err
+	}
+	e, ok := s.idx.Lookup(id)
+	if !ok || collectionOf(e.File) != collection {
+		return nil, ErrNotFound
+	}
+	line, err := s.idx.ReadEntry(e)
+	if errors.Is(err, index.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	return []byte(line), err
+}
+
+// Delete дописывает в активный файл коллекции надгробие записи: оно скрывает
+// запись сразу, а сама запись остаётся в файле до уплотнения.
+func (s *FileStore) Delete(collection, id string) error {
+	if err := s.attach(collection); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.idx.Lookup(id)
+	if !ok || collectionOf(e.File) != collection {
+		return ErrNotFound
+	}
+	if err := s.write(collection, index.Tombstone(id, time.Now())); err != nil {
+		return err
+	}
+	s.wake()
+	return nil
+}
+
+// Scan с фильтром по источнику, времени или запросу читает только отобранные
+// индексом записи, без фильтра — файлы целиком.
+func (s *FileStore) Scan(opts ScanOptions, fn func(Record) bool) error {
+	if opts.indexed() {
+		return s.idx.ReadEntries(s.selectEntries(opts), func(e index.Entry, line string) bool {
+			r, err := parseRecord(collectionOf(e.File), []byte(line))
+			if err != nil {
+				return true // Строка без id, например затёртая старым удалением
+			}
+			return fn(r)
+		})
+	}
+
+	collections, _ := s.Collections()
+	for _, collection := range collections {
+		if !opts.matchCollection(collection) {
+			continue
+		}
+		for _, name := range s.files(collection) {
+			more, err := s.scanFile(collection, name, fn)
+			if err != nil || !more {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Сколько записей ScanByTime читает из файлов за раз
+const fileScanBatch = 1000
+
+// ScanByTime обходит записи по индексу времени. Записи читаются пачками,
+// сгруппированными по файлам, и передаются fn в порядке индекса.
+func (s *FileStore) ScanByTime(opts ScanOptions, descending bool, fn func(Record) bool) error {
+	entries := s.selectEntries(opts)
+	if descending {
+		slices.Reverse(entries)
+	}
+
+	for len(entries) > 0 {
+		batch := entries[:min(len(entries), fileScanBatch)]
+		entries = entries[len(batch):]
+
+		lines := make(map[string]string, len(batch))
+		err := s.idx.ReadEntries(batch, func(e index.Entry, line string) bool {
+			lines[e.ID] = line
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		for _, e := range batch {
+			line, ok := lines[e.ID]
+			if !ok {
+				continue // Удалена после выборки
+			}
+			r, err := parseRecord(collectionOf(e.File), []byte(line))
+			if err != nil {
+				continue
+			}
+			if !fn(r) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// selectEntries отбирает по индексу записи opts в порядке времени: записи
+// запроса — по индексу запросов, остальные — по индексам времени и источника.
+func (s *FileStore) selectEntries(opts ScanOptions) []index.Entry {
+	var candidates []index.Entry
+	if opts.RequestID != "" {
+		candidates = s.idx.SelectRequest(opts.RequestID)
+	} else {
+		candidates = s.idx.Select(opts.Sources, opts.From, opts.To)
+	}
+
+	var entries []index.Entry
+	for _, e := range candidates {
+		r := Record{Collection: collectionOf(e.File), ID: e.ID, Timestamp: e.Timestamp, Source: e.Source, RequestID: e.Request}
+		if opts.match(r) {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// files возвращает файлы коллекции: сегменты от старых к новым, затем активный файл.
+func (s *FileStore) files(collection string) []string {
+	var files []string
+	for _, name := range s.idx.Files() {
+		if collectionOf(name) == collection {
+			files = append(files, name)
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		_, ti, si := index.ParseName(files[i])
+		_, tj, sj := index.ParseName(files[j])
+		if si != sj {
+			return si
+		}
+		return ti.Before(tj)
+	})
+	return files
+}
+
+func (s *FileStore) scanFile(collection, name string, fn func(Record) bool) (bool, error) {
+	file, err := s.idx.OpenReader(name)
+	if errors.Is(err, index.ErrNotFound) {
+		return true, nil // Файл ротирован или удалён после начала обхода
+	}
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		r, err := parseRecord(collection, bytes.Clone(scanner.Bytes()))
+		if err != nil {
+			continue // Невалидные и затёртые удалением строки пропускаются
+		}
+		if e, ok := s.idx.Lookup(r.ID); !ok || e.File != name {
+			continue // Скрыта надгробием или перезаписана позже
+		}
+		if !fn(r) {
+			return false, nil
+		}
+	}
+	return true, scanner.Err()
+}
+
+func (s *FileStore) Collections() ([]string, error) {
+	seen := map[string]bool{}
+	var collections []string
+	for _, name := range s.idx.Files() {
+		if collection := collectionOf(name); !seen[collection] {
+			seen[collection] = true
+			collections = append(collections, collection)
+		}
+	}
+	sort.Strings(collections)
+	return collections, nil
+}
+
+func (s *FileStore) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	for _, w := range s.writers {
+		errs = append(errs, w.Sync())
+	}
+	return errors.Join(errs...)
+}
+
+func (s *FileStore) Close() error {
+	s.closeOnce.Do(func() { close(s.stop) })
+	s.background.Wait()
+
+	s.mu.Lock()
+	var errs []error
+	for _, w := range s.writers {
+		errs = append(errs, w.Close())
+	}
+	s.writers = map[string]*activeFile{}
+	s.mu.Unlock()
+
+	return errors.Join(append(errs, s.idx.Close())...)
+}
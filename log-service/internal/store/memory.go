@@ -0,0 +1,151 @@
+package store
+
+import (
+	"sort"
+	"sync"
+)
+
+// MemoryStore держит записи в памяти процесса, для тестов и локального запуска.
+type MemoryStore struct {
+	mu          sync.RWMutex
+	collections map[string]*memoryCollection
+}
+
+type memoryCollection struct {
+	records []*Record
+	byID    map[string]int
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{collections: make(map[string]*memoryCollection)}
+}
+
+func (s *MemoryStore) Append(collection string, data []byte) error {
+	return s.AppendBatch(collection, [][]byte{data})
+}
+
+func (s *MemoryStore) AppendBatch(collection string, records [][]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	parsed, err := parseBatch(collection, records, func(id string) bool {
+		for _, c := range s.collections {
+			if _, ok := c.byID[id]; ok {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return err
+	}
+
+	c, ok := s.collections[collection]
+	if !ok {
+		c = &memoryCollection{byID: make(map[string]int)}
+		s.collections[collection] = c
+	}
+	for i := range parsed {
+		r := &parsed[i]
+		c.byID[r.ID] = len(c.records)
+		c.records = append(c.records, r)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Get(collection, id string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.collections[collection]
+	if !ok {
+		return nil, ErrUnknownCollection
+	}
+	i, ok := c.byID[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return c.records[i].Data, nil
+}
+
+func (s *MemoryStore) Delete(collection, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.collections[collection]
+	if !ok {
+		return ErrUnknownCollection
+	}
+	i, ok := c.byID[id]
+	if !ok {
+		return ErrNotFound
+	}
+	c.records[i] = nil
+	delete(c.byID, id)
+	return nil
+}
+
+// Scan проходит по снимку записей, поэтому fn может обращаться к хранилищу.
+func (s *MemoryStore) Scan(opts ScanOptions, fn func(Record) bool) error {
+	for _, r := range s.snapshot(opts) {
+		if !fn(r) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) snapshot(opts ScanOptions) []Record {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var snapshot []Record
+	for name, c := range s.collections {
+		if !opts.matchCollection(name) {
+			continue
+		}
+		for _, r := range c.records {
+			if r != nil && opts.match(*r) {
+				snapshot = append(snapshot, *r)
+			}
+		}
+	}
+	return snapshot
+}
+
+// ScanByTime сортирует снимок записей по времени получения.
+func (s *MemoryStore) ScanByTime(opts ScanOptions, descending bool, fn func(Record) bool) error {
+	snapshot := s.snapshot(opts)
+
+	sort.Slice(snapshot, func(i, j int) bool {
+		a, b := snapshot[i], snapshot[j]
+		if descending {
+			a, b = b, a
+		}
+		if a.Timestamp != b.Timestamp {
+			return a.Timestamp < b.Timestamp
+		}
+		return a.ID < b.ID
+	})
+	for _, r := range snapshot {
+		if !fn(r) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) Collections() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make([]string, 0, len(s.collections))
+	for name := range s.collections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *MemoryStore) Sync() error  { return nil }
+func (s *MemoryStore) Close() error { return nil }
@@ -0,0 +1,365 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"log-service/internal/index"
+	"log-service/internal/metrics"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Как часто FileStore проверяет возраст активных файлов и правила хранения
+const maintainInterval = time.Minute
+
+// Файл уплотняется, когда удалённые записи и надгробия занимают такую долю его размера
+const compactRatio = 0.25
+
+// RotationPolicy задаёт, когда активный файл коллекции переименовывается в
+// сегмент и начинается новый. Нулевые границы не ограничивают.
+type RotationPolicy struct {
+	MaxSize int64
+	MaxAge  time.Duration
+	// Сжимать ротированные сегменты gzip
+	Compress bool
+}
+
+func (p RotationPolicy) dueBySize(size int64) bool {
+	return p.MaxSize > 0 && size >= p.MaxSize
+}
+
+func (p RotationPolicy) dueByAge(started, now time.Time) bool {
+	return p.MaxAge > 0 && now.Sub(started) >= p.MaxAge
+}
+
+// RetentionPolicy задаёт, сколько хранить сегменты коллекции: старше MaxAge
+// (по времени ротации) и сверх MaxSize на всю коллекцию удаляются, начиная
+// со старых. Активный файл не удаляется.
+type RetentionPolicy struct {
+	MaxAge  time.Duration
+	MaxSize int64
+}
+
+// DefaultRetention — ключ правила для коллекций без своего правила
+const DefaultRetention = "*"
+
+type FileOptions struct {
+	Rotation RotationPolicy
+	// Правила хранения по коллекциям, DefaultRetention — для остальных
+	Retention map[string]RetentionPolicy
+	// JSON-lines файл с удалёнными по правилам сегментами; по умолчанию
+	// retention.jsonl в каталоге логов
+	RetentionLog string
+}
+
+func (o FileOptions) retention(collection string) (RetentionPolicy, bool) {
+	if p, ok := o.Retention[collection]; ok {
+		return p, true
+	}
+	p, ok := o.Retention[DefaultRetention]
+	return p, ok
+}
+
+// ParseSize разбирает размер в байтах с необязательным суффиксом KB, MB или GB
+// (степени 1024).
+func ParseSize(value string) (int64, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(value, unit.suffix) {
+			value, multiplier = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix)), unit.size
+			break
+		}
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", value)
+	}
+	return n * multiplier, nil
+}
+
+// ParseRetention разбирает правила хранения вида
+// "http=720h:1GB,business=168h,*=:10GB": сервис (или * для остальных),
+// максимальный возраст и максимальный размер; любую часть можно опустить.
+func ParseRetention(spec string) (map[string]RetentionPolicy, error) {
+	rules := map[string]RetentionPolicy{}
+	for _, rule := range strings.Split(spec, ",") {
+		if strings.TrimSpace(rule) == "" {
+			continue
+		}
+		service, limits, ok := strings.Cut(rule, "=")
+		service = strings.TrimSpace(service)
+		if !ok || service == "" {
+			return nil, fmt.Errorf("invalid retention rule %q: expected service=max_age:max_size", rule)
+		}
+		age, size, _ := strings.Cut(limits, ":")
+
+		var policy RetentionPolicy
+		if age = strings.TrimSpace(age); age != "" {
+			d, err := time.ParseDuration(age)
+			if err != nil || d < 0 {
+				return nil, fmt.Errorf("invalid max age in retention rule %q", rule)
+			}
+			policy.MaxAge = d
+		}
+		if size = strings.TrimSpace(size); size != "" {
+			n, err := ParseSize(size)
+			if err != nil {
+				return nil, fmt.Errorf("invalid max size in retention rule %q: %w", rule, err)
+			}
+			policy.MaxSize = n
+		}
+
+		key := DefaultRetention
+		if service != DefaultRetention {
+			key = Collection(service)
+		}
+		rules[key] = policy
+	}
+	return rules, nil
+}
+
+// RetentionRecord — удалённый по правилам хранения сегмент.
+type RetentionRecord struct {
+	Time       time.Time `json:"time"`
+	Collection string    `json:"collection"`
+	Segment    string    `json:"segment"`
+	Reason     string    `json:"reason"`
+	Bytes      int64     `json:"bytes"`
+	Records    int       `json:"records"`
+}
+
+// rotate закрывает писателя коллекции и переименовывает активный файл в
+// сегмент. Вызывается под s.mu.
+func (s *FileStore) rotate(collection string) error {
+	if w, ok := s.writers[collection]; ok {
+		delete(s.writers, collection)
+		if err := w.Close(); err != nil {
+			return err
+		}
+	}
+	segment, err := s.idx.Rotate(collection, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to rotate %s: %w", collection, err)
+	}
+	metrics.SegmentsRotated.WithLabelValues(collection).Inc()
+	log.Printf("Rotated %s to %s", collection, segment)
+
+	// Сжатие и правила хранения выполняет фоновое обслуживание
+	s.wake()
+	return nil
+}
+
+// wake запускает фоновое обслуживание, не дожидаясь интервала.
+func (s *FileStore) wake() {
+	select {
+	case s.kick <- struct{}{}:
+	default:
+	}
+}
+
+func (s *FileStore) maintainLoop() {
+	defer s.background.Done()
+
+	ticker := time.NewTicker(maintainInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		case <-s.kick:
+		}
+		if _, err := s.maintain(time.Now()); err != nil {
+			log.Printf("Log maintenance failed: %v", err)
+		}
+	}
+}
+
+// maintain ротирует устаревшие по возрасту активные файлы, сжимает и
+// уплотняет сегменты и удаляет сегменты по правилам хранения. Возвращает
+// удалённые сегменты.
+func (s *FileStore) maintain(now time.Time) ([]RetentionRecord, error) {
+	s.maintenance.Lock()
+	defer s.maintenance.Unlock()
+
+	var errs []error
+	collections, _ := s.Collections()
+	for _, collection := range collections {
+		errs = append(errs, s.rotateStale(collection, now))
+	}
+
+	if s.opts.Rotation.Compress {
+		for _, name := range s.idx.Files() {
+			if _, _, segment := index.ParseName(name); segment && !index.IsCompressed(name) {
+				if _, err := s.idx.Compress(name); err != nil && !errors.Is(err, index.ErrNotFound) {
+					errs = append(errs, fmt.Errorf("failed to compress %s: %w", name, err))
+				}
+			}
+		}
+	}
+
+	for _, collection := range collections {
+		errs = append(errs, s.compact(collection))
+	}
+
+	removed, err := s.applyRetention(now)
+	return removed, errors.Join(append(errs, err)...)
+}
+
+// compact уплотняет файлы коллекции, в которых удалённые записи и надгробия
+// занимают не меньше compactRatio. Файлы обходятся от старых к новым, чтобы надгробия в новых файлах
+// освобождались в том же проходе, что и скрытые ими записи.
+func (s *FileStore) compact(collection string) error {
+	var errs []error
+	for _, name := range s.files(collection) {
+		garbage, size := s.idx.Garbage(name)
+		if garbage == 0 || float64(garbage) < compactRatio*float64(size) {
+			continue
+		}
+
+		var freed int64
+		var err error
+		if name == collection {
+			freed, err = s.compactActive(name)
+		} else {
+			freed, err = s.idx.Compact(name)
+		}
+		if errors.Is(err, index.ErrNotFound) {
+			continue // Сегмент удалён или сжат, пока собирался список
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		metrics.CompactedBytes.WithLabelValues(collection).Add(float64(freed))
+		log.Printf("Compacted %s, freed %d bytes", name, freed)
+	}
+	return errors.Join(errs...)
+}
+
+// compactActive уплотняет активный файл: запись в коллекцию ждёт на s.mu,
+// а писатель закрывается, чтобы не дописывать в заменённый файл.
+func (s *FileStore) compactActive(name string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if w, ok := s.writers[name]; ok {
+		delete(s.writers, name)
+		if err := w.Close(); err != nil {
+			return 0, err
+		}
+	}
+	return s.idx.Compact(name)
+}
+
+// rotateStale ротирует активный файл коллекции, если он старше MaxAge, даже
+// когда в него давно не пишут.
+func (s *FileStore) rotateStale(collection string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	first, ok := s.idx.First(collection)
+	if !ok {
+		return nil // Активного файла нет или он пуст
+	}
+	started := time.UnixMilli(first)
+	if w, ok := s.writers[collection]; ok {
+		started = w.started
+	}
+	if !s.opts.Rotation.dueByAge(started, now) {
+		return nil
+	}
+	return s.rotate(collection)
+}
+
+// applyRetention удаляет сегменты по правилам хранения и записывает их в журнал хранения.
+func (s *FileStore) applyRetention(now time.Time) ([]RetentionRecord, error) {
+	var removed []RetentionRecord
+	var errs []error
+
+	collections, _ := s.Collections()
+	for _, collection := range collections {
+		policy, ok := s.opts.retention(collection)
+		if !ok {
+			continue
+		}
+
+		files := s.files(collection)
+		sizes := make(map[string]int64, len(files))
+		var total int64
+		for _, name := range files {
+			if info, err := os.Stat(filepath.Join(s.dir, name)); err == nil {
+				sizes[name] = info.Size()
+				total += info.Size()
+			}
+		}
+
+		for _, name := range files {
+			_, rotated, segment := index.ParseName(name)
+			if !segment {
+				continue
+			}
+			var reason string
+			switch {
+			case policy.MaxAge > 0 && now.Sub(rotated) > policy.MaxAge:
+				reason = "max_age"
+			case policy.MaxSize > 0 && total > policy.MaxSize:
+				reason = "max_size"
+			default:
+				continue
+			}
+
+			records, err := s.idx.Remove(name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to remove %s: %w", name, err))
+				continue
+			}
+			total -= sizes[name]
+			removed = append(removed, RetentionRecord{
+				Time:       now.UTC(),
+				Collection: collection,
+				Segment:    name,
+				Reason:     reason,
+				Bytes:      sizes[name],
+				Records:    records,
+			})
+		}
+	}
+
+	if len(removed) > 0 {
+		errs = append(errs, s.recordRetention(removed))
+	}
+	return removed, errors.Join(errs...)
+}
+
+func (s *FileStore) recordRetention(removed []RetentionRecord) error {
+	path := s.opts.RetentionLog
+	if path == "" {
+		path = filepath.Join(s.dir, "retention.jsonl")
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open retention log: %w", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	for _, r := range removed {
+		metrics.RetentionRemoved.WithLabelValues(r.Collection, r.Reason).Inc()
+		metrics.RetentionRemovedBytes.WithLabelValues(r.Collection).Add(float64(r.Bytes))
+		log.Printf("Retention removed %s (%s, %d bytes, %d records)", r.Segment, r.Reason, r.Bytes, r.Records)
+		if err := encoder.Encode(r); err != nil {
+			return fmt.Errorf("failed to write retention log: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log-service/internal/index"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func openFileStore(t *testing.T, dir string, opts FileOptions) *FileStore {
+	t.Helper()
+	s, err := NewFileStore(dir, opts)
+	if err != nil {
+		t.Fatalf("failed to open file store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// stopMaintenance останавливает фоновое обслуживание, чтобы тест вызывал
+// maintain сам и видел все удаления
+func stopMaintenance(s *FileStore) {
+	s.closeOnce.Do(func() { close(s.stop) })
+	s.background.Wait()
+}
+
+func segments(t *testing.T, dir string) (plain, compressed int) {
+	t.Helper()
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if _, _, ok := index.ParseName(e.Name()); !ok || strings.HasSuffix(e.Name(), index.JournalSuffix) {
+			continue
+		}
+		if index.IsCompressed(e.Name()) {
+			compressed++
+		} else {
+			plain++
+		}
+	}
+	return plain, compressed
+}
+
+func TestFileStoreRotatesAndReadsSegments(t *testing.T) {
+	dir := t.TempDir()
+	// Каждая запись занимает больше 80 байт, поэтому ротируется после каждой второй
+	s := openFileStore(t, dir, FileOptions{Rotation: RotationPolicy{MaxSize: 160, Compress: true}})
+
+	for i := 0; i < 7; i++ {
+		if err := s.Append("http_logs.json", record(fmt.Sprintf("id%d", i), "HTTP-server", int64(100*(i+1)))); err != nil {
+			t.Fatalf("failed to append: %v", err)
+		}
+	}
+	if _, err := s.maintain(time.Now()); err != nil {
+		t.Fatalf("failed to maintain: %v", err)
+	}
+	if plain, compressed := segments(t, dir); plain != 0 || compressed != 3 {
+		t.Fatalf("expected 3 compressed segments, got %d plain and %d compressed", plain, compressed)
+	}
+
+	if err := s.Delete("http_logs.json", "id1"); err != nil {
+		t.Fatalf("failed to delete from compressed segment: %v", err)
+	}
+	want := []string{"id0", "id2", "id3", "id4", "id5", "id6"}
+
+	check := func(t *testing.T, s LogStore) {
+		t.Helper()
+		for _, id := range want {
+			if data, err := s.Get("http_logs.json", id); err != nil || !strings.Contains(string(data), id) {
+				t.Errorf("expected %s to be readable, got %q, %v", id, data, err)
+			}
+		}
+		if _, err := s.Get("http_logs.json", "id1"); err != ErrNotFound {
+			t.Errorf("expected deleted record to stay deleted, got %v", err)
+		}
+		if got := scanIDs(t, s, ScanOptions{}); !reflect.DeepEqual(got, want) {
+			t.Errorf("expected full scan over segments to return %v, got %v", want, got)
+		}
+		if got := scanIDs(t, s, ScanOptions{From: 150, To: 450}); !reflect.DeepEqual(got, []string{"id2", "id3"}) {
+			t.Errorf("expected indexed scan over segments, got %v", got)
+		}
+		if got, _ := s.Collections(); !reflect.DeepEqual(got, []string{"http_logs.json"}) {
+			t.Errorf("expected segments to belong to one collection, got %v", got)
+		}
+	}
+	check(t, s)
+
+	s.Close()
+	check(t, openFileStore(t, dir, FileOptions{}))
+}
+
+func TestFileStoreRotatesByAge(t *testing.T) {
+	dir := t.TempDir()
+	s := openFileStore(t, dir, FileOptions{Rotation: RotationPolicy{MaxAge: time.Hour}})
+	now := time.Now()
+	s.Append("business_logs.json", record("a", "business-server", now.UnixMilli()))
+
+	if _, err := s.maintain(now.Add(30 * time.Minute)); err != nil {
+		t.Fatalf("failed to maintain: %v", err)
+	}
+	if plain, _ := segments(t, dir); plain != 0 {
+		t.Fatalf("expected young file not to be rotated, got %d segments", plain)
+	}
+
+	// Файл ротируется по возрасту, даже если в него больше не пишут
+	if _, err := s.maintain(now.Add(2 * time.Hour)); err != nil {
+		t.Fatalf("failed to maintain: %v", err)
+	}
+	if plain, compressed := segments(t, dir); plain != 1 || compressed != 0 {
+		t.Fatalf("expected one uncompressed segment, got %d plain and %d compressed", plain, compressed)
+	}
+	if _, err := s.Get("business_logs.json", "a"); err != nil {
+		t.Errorf("expected record to be read from the segment, got %v", err)
+	}
+	if err := s.Append("business_logs.json", record("b", "business-server", now.UnixMilli())); err != nil {
+		t.Fatalf("failed to append after rotation: %v", err)
+	}
+	if got := scanIDs(t, s, ScanOptions{}); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("expected both records, got %v", got)
+	}
+}
+
+func TestFileStoreRetention(t *testing.T) {
+	dir := t.TempDir()
+	s := openFileStore(t, dir, FileOptions{
+		Rotation: RotationPolicy{MaxSize: 1},
+		Retention: map[string]RetentionPolicy{
+			"http_logs.json": {MaxAge: time.Hour},
+			DefaultRetention: {MaxSize: 250},
+		},
+	})
+	stopMaintenance(s)
+
+	// Каждая запись попадает в свой сегмент
+	for i := 0; i < 4; i++ {
+		s.Append("http_logs.json", record(fmt.Sprintf("h%d", i), "HTTP-server", int64(i+1)))
+		s.Append("business_logs.json", record(fmt.Sprintf("b%d", i), "business-server", int64(i+1)))
+	}
+
+	removed, err := s.maintain(time.Now().Add(2 * time.Hour))
+	if err != nil {
+		t.Fatalf("failed to maintain: %v", err)
+	}
+
+	reasons := map[string]int{}
+	for _, r := range removed {
+		reasons[r.Collection+" "+r.Reason]++
+		if r.Records != 1 || r.Bytes == 0 {
+			t.Errorf("expected removed segment to report its record and size, got %+v", r)
+		}
+	}
+	// Сегменты business по ~85 байт: в 250 байт помещаются два последних
+	if want := map[string]int{"http_logs.json max_age": 4, "business_logs.json max_size": 2}; !reflect.DeepEqual(reasons, want) {
+		t.Errorf("expected removals %v, got %v", want, reasons)
+	}
+	if got := scanIDs(t, s, ScanOptions{}); !reflect.DeepEqual(got, []string{"b2", "b3"}) {
+		t.Errorf("expected only the newest business records to remain, got %v", got)
+	}
+
+	file, err := os.Open(filepath.Join(dir, "retention.jsonl"))
+	if err != nil {
+		t.Fatalf("expected retention log, got %v", err)
+	}
+	defer file.Close()
+	var logged []RetentionRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var r RetentionRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("invalid retention log line %q: %v", scanner.Text(), err)
+		}
+		logged = append(logged, r)
+	}
+	if !reflect.DeepEqual(logged, removed) {
+		t.Errorf("expected retention log to record %v, got %v", removed, logged)
+	}
+}
+
+func TestFileStoreCompactsDeletes(t *testing.T) {
+	dir := t.TempDir()
+	s := openFileStore(t, dir, FileOptions{})
+	for i := 0; i < 100; i++ {
+		s.Append("http_logs.json", record(fmt.Sprintf("old%02d", i), "HTTP-server", int64(i+1)))
+	}
+	// Запись продолжается, пока записи удаляются и файл уплотняется
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			if err := s.Append("http_logs.json", record(fmt.Sprintf("new%03d", i), "HTTP-server", int64(1000+i))); err != nil {
+				t.Errorf("failed to append: %v", err)
+				return
+			}
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if err := s.Delete("http_logs.json", fmt.Sprintf("old%02d", i)); err != nil {
+			t.Fatalf("failed to delete: %v", err)
+		}
+		if _, err := s.Get("http_logs.json", fmt.Sprintf("old%02d", i)); err != ErrNotFound {
+			t.Fatalf("expected deleted record to be hidden at once, got %v", err)
+		}
+		if i%25 == 0 {
+			if _, err := s.maintain(time.Now()); err != nil {
+				t.Fatalf("failed to maintain: %v", err)
+			}
+		}
+	}
+	<-done
+	// Последние удаления не набирают compactRatio, поэтому уплотняем явно
+	if _, err := s.compactActive("http_logs.json"); err != nil {
+		t.Fatalf("failed to compact: %v", err)
+	}
+
+	var want []string
+	for i := 0; i < 200; i++ {
+		want = append(want, fmt.Sprintf("new%03d", i))
+	}
+	check := func(t *testing.T, s LogStore) {
+		t.Helper()
+		if got := scanIDs(t, s, ScanOptions{}); !reflect.DeepEqual(got, want) {
+			t.Errorf("expected only records appended during compaction to remain, got %d records", len(got))
+		}
+		if got := scanIDs(t, s, ScanOptions{From: 1}); !reflect.DeepEqual(got, want) {
+			t.Errorf("expected indexed scan to agree, got %d records", len(got))
+		}
+	}
+	check(t, s)
+
+	data, _ := os.ReadFile(filepath.Join(dir, "http_logs.json"))
+	if lines := strings.Count(string(data), "\n"); lines != len(want) || strings.Contains(string(data), "tombstone") {
+		t.Errorf("expected deleted records and tombstones to be compacted away, got %d lines", lines)
+	}
+
+	s.Close()
+	check(t, openFileStore(t, dir, FileOptions{}))
+}
+
+func TestParseRetention(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    map[string]RetentionPolicy
+		wantErr bool
+	}{
+		{spec: "", want: map[string]RetentionPolicy{}},
+		{spec: "http=720h:1GB, business=168h,*=:10mb", want: map[string]RetentionPolicy{
+			"http_logs.json":     {MaxAge: 720 * time.Hour, MaxSize: 1 << 30},
+			"business_logs.json": {MaxAge: 168 * time.Hour},
+			DefaultRetention:     {MaxSize: 10 << 20},
+		}},
+		{spec: "http", wantErr: true},
+		{spec: "http=forever", wantErr: true},
+		{spec: "http=:lots", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseRetention(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log-service/internal/config"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound          = errors.New("log not found")
+	ErrUnknownCollection = errors.New("unknown collection")
+	ErrUnknownBackend    = errors.New("unknown log store backend")
+	ErrInvalidRecord     = errors.New("log record has no id")
+	ErrDuplicateID       = errors.New("log id already exists")
+)
+
+const (
+	BackendFile   = "file"
+	BackendBolt   = "bolt"
+	BackendMemory = "memory"
+)
+
+// Record — запись лога в JSON, как её формирует CRUD.WriteLogToFile. Поля
+// ID, Timestamp, Source и RequestID хранилище берёт из самой записи при добавлении.
+type Record struct {
+	Collection string
+	ID         string
+	Timestamp  int64
+	Source     string
+	// request_id записи; у записей, сделанных до его появления, — path, куда
+	// раньше писался id запроса
+	RequestID string
+	Data      []byte
+}
+
+// ScanOptions ограничивает Scan; пустые поля не ограничивают. Хранилище
+// может использовать для них свои индексы.
+type ScanOptions struct {
+	Collections []string
+	// Источники без учёта регистра
+	Sources []string
+	// Границы по timestamp_received, unix ms, включительно
+	From, To int64
+	// Записи одного запроса, см. Record.RequestID
+	RequestID string
+}
+
+// LogStore хранит записи логов по коллекциям. Коллекция — имя файла логгера
+// (<service>_logs.json), оно же используется и остальными хранилищами, чтобы
+// ReadLog и DeleteLog не зависели от выбранного хранилища. Id записи уникален
+// во всём хранилище: запись с id, который уже есть в любой коллекции,
+// отклоняется с ErrDuplicateID.
+type LogStore interface {
+	Append(collection string, data []byte) error
+	// AppendBatch дописывает записи коллекции за один проход: одной записью
+	// в файл или одной транзакцией. Если хотя бы одна запись невалидна или
+	// повторяет id, не дописывается ни одна.
+	AppendBatch(collection string, records [][]byte) error
+	Get(collection, id string) ([]byte, error)
+	Delete(collection, id string) error
+	// Scan передаёт fn записи, подходящие под opts, пока fn возвращает true.
+	// Порядок записей не гарантируется.
+	Scan(opts ScanOptions, fn func(Record) bool) error
+	Collections() ([]string, error)
+	Sync() error
+	Close() error
+}
+
+// TimeScanner — хранилище с индексом времени получения: ScanByTime передаёт
+// fn записи по (Timestamp, ID) по возрастанию или, с descending, по убыванию,
+// поэтому обход можно прервать, как только найдено достаточно записей.
+// Реализуют FileStore и MemoryStore.
+type TimeScanner interface {
+	ScanByTime(opts ScanOptions, descending bool, fn func(Record) bool) error
+}
+
+// Collection возвращает коллекцию логгера сервиса.
+func Collection(service string) string {
+	return service + "_logs.json"
+}
+
+// New открывает хранилище, выбранное в LOG_STORE. Ротация и правила хранения
+// применяются только к файловому хранилищу.
+func New(cfg *config.Config) (LogStore, error) {
+	path := cfg.LogStorePath
+	if path == "" {
+		path = DefaultPath(cfg.LogStore, cfg.LogsDir)
+	}
+	if cfg.LogStore != "" && cfg.LogStore != BackendFile {
+		return Open(cfg.LogStore, path)
+	}
+
+	opts, err := fileOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewFileStore(path, opts)
+}
+
+func fileOptions(cfg *config.Config) (FileOptions, error) {
+	opts := FileOptions{Rotation: RotationPolicy{Compress: cfg.LogCompress != "false"}}
+
+	var err error
+	if opts.Rotation.MaxSize, err = ParseSize(cfg.LogRotateSize); err != nil {
+		return opts, fmt.Errorf("LOG_ROTATE_SIZE: %w", err)
+	}
+	if opts.Rotation.MaxAge, err = time.ParseDuration(cfg.LogRotateInterval); err != nil {
+		return opts, fmt.Errorf("LOG_ROTATE_INTERVAL: %w", err)
+	}
+	if opts.Retention, err = ParseRetention(cfg.LogRetention); err != nil {
+		return opts, fmt.Errorf("LOG_RETENTION: %w", err)
+	}
+	return opts, nil
+}
+
+// DefaultPath — путь хранилища в каталоге логов: сам каталог для файлов, logs.db для bbolt.
+func DefaultPath(backend, logsDir string) string {
+	if backend == BackendBolt {
+		return filepath.Join(logsDir, "logs.db")
+	}
+	return logsDir
+}
+
+// Open открывает хранилище backend: каталог для file, файл базы для bolt;
+// memory путь не использует.
+func Open(backend, path string) (LogStore, error) {
+	switch backend {
+	case "", BackendFile:
+		return NewFileStore(path, FileOptions{})
+	case BackendBolt:
+		return NewBoltStore(path)
+	case BackendMemory:
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("%w %q: expected file, bolt or memory", ErrUnknownBackend, backend)
+	}
+}
+
+type recordFields struct {
+	ID                string `json:"id"`
+	Source            string `json:"source"`
+	TimestampReceived int64  `json:"timestamp_received"`
+	RequestID         string `json:"request_id"`
+	Path              string `json:"path"`
+}
+
+func parseRecord(collection string, data []byte) (Record, error) {
+	var fields recordFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return Record{}, fmt.Errorf("invalid log record: %w", err)
+	}
+	if fields.ID == "" {
+		return Record{}, ErrInvalidRecord
+	}
+	requestID := fields.RequestID
+	if requestID == "" {
+		requestID = fields.Path
+	}
+	return Record{
+		Collection: collection,
+		ID:         fields.ID,
+		Timestamp:  fields.TimestampReceived,
+		Source:     fields.Source,
+		RequestID:  requestID,
+		Data:       data,
+	}, nil
+}
+
+// parseBatch разбирает записи пачки и проверяет, что их id не повторяются
+// ни внутри пачки, ни среди уже сохранённых (exists).
+func parseBatch(collection string, records [][]byte, exists func(id string) bool) ([]Record, error) {
+	parsed := make([]Record, 0, len(records))
+	seen := make(map[string]bool, len(records))
+	for _, data := range records {
+		r, err := parseRecord(collection, data)
+		if err != nil {
+			return nil, err
+		}
+		if seen[r.ID] || exists(r.ID) {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateID, r.ID)
+		}
+		seen[r.ID] = true
+		parsed = append(parsed, r)
+	}
+	return parsed, nil
+}
+
+func (o ScanOptions) matchCollection(collection string) bool {
+	if len(o.Collections) == 0 {
+		return true
+	}
+	for _, c := range o.Collections {
+		if c == collection {
+			return true
+		}
+	}
+	return false
+}
+
+func (o ScanOptions) match(r Record) bool {
+	if !o.matchCollection(r.Collection) {
+		return false
+	}
+	if o.From != 0 && r.Timestamp < o.From {
+		return false
+	}
+	if o.To != 0 && r.Timestamp > o.To {
+		return false
+	}
+	if o.RequestID != "" && r.RequestID != o.RequestID {
+		return false
+	}
+	if len(o.Sources) == 0 {
+		return true
+	}
+	for _, s := range o.Sources {
+		if strings.EqualFold(s, r.Source) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o ScanOptions) indexed() bool {
+	return len(o.Sources) > 0 || o.From != 0 || o.To != 0 || o.RequestID != ""
+}
+
+// Migrate копирует все записи src в dst. Записи, id которых уже есть в dst,
+// пропускаются, поэтому прерванную миграцию можно повторить.
+func Migrate(src, dst LogStore) (copied, skipped int, err error) {
+	scanErr := src.Scan(ScanOptions{}, func(r Record) bool {
+		if err = dst.Append(r.Collection, r.Data); errors.Is(err, ErrDuplicateID) {
+			err = nil
+			skipped++
+			return true
+		}
+		if err != nil {
+			return false
+		}
+		copied++
+		return true
+	})
+	if err == nil {
+		err = scanErr
+	}
+	if err == nil {
+		err = dst.Sync()
+	}
+	return copied, skipped, err
+}
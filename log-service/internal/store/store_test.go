@@ -0,0 +1,306 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func record(id, source string, received int64) []byte {
+	return []byte(fmt.Sprintf(`{"level":"info","id":%q,"message":"{}","source":%q,"timestamp_received":%d}`, id, source, received))
+}
+
+func openStore(t *testing.T, backend string) LogStore {
+	t.Helper()
+	s, err := Open(backend, DefaultPath(backend, t.TempDir()))
+	if err != nil {
+		t.Fatalf("failed to open %s store: %v", backend, err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func scanIDs(t *testing.T, s LogStore, opts ScanOptions) []string {
+	t.Helper()
+	ids := []string{}
+	if err := s.Scan(opts, func(r Record) bool {
+		ids = append(ids, r.ID)
+		return true
+	}); err != nil {
+		t.Fatalf("failed to scan: %v", err)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+var backends = []string{BackendFile, BackendBolt, BackendMemory}
+
+func TestLogStore(t *testing.T) {
+	for _, backend := range backends {
+		t.Run(backend, func(t *testing.T) {
+			s := openStore(t, backend)
+			for _, r := range []struct {
+				collection string
+				data       []byte
+			}{
+				{"http_logs.json", record("a", "HTTP-server", 100)},
+				{"http_logs.json", record("b", "HTTP-server", 300)},
+				{"business_logs.json", record("c", "business-server", 200)},
+			} {
+				if err := s.Append(r.collection, r.data); err != nil {
+					t.Fatalf("failed to append: %v", err)
+				}
+			}
+			if err := s.Append("http_logs.json", []byte(`{"msg":"no id"}`)); err == nil {
+				t.Errorf("expected record without id to be rejected")
+			}
+			if err := s.Sync(); err != nil {
+				t.Fatalf("failed to sync: %v", err)
+			}
+
+			data, err := s.Get("http_logs.json", "b")
+			if err != nil || string(data) != string(record("b", "HTTP-server", 300)) {
+				t.Fatalf("expected record b, got %q, %v", data, err)
+			}
+			if _, err := s.Get("business_logs.json", "b"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("expected ErrNotFound for a record in another collection, got %v", err)
+			}
+			if _, err := s.Get("missing_logs.json", "b"); !errors.Is(err, ErrUnknownCollection) {
+				t.Errorf("expected ErrUnknownCollection, got %v", err)
+			}
+
+			collections, err := s.Collections()
+			if err != nil || !reflect.DeepEqual(collections, []string{"business_logs.json", "http_logs.json"}) {
+				t.Errorf("expected both collections, got %v, %v", collections, err)
+			}
+
+			tests := []struct {
+				name string
+				opts ScanOptions
+				want []string
+			}{
+				{name: "everything", want: []string{"a", "b", "c"}},
+				{name: "collection", opts: ScanOptions{Collections: []string{"http_logs.json"}}, want: []string{"a", "b"}},
+				{name: "source ignoring case", opts: ScanOptions{Sources: []string{"business-SERVER"}}, want: []string{"c"}},
+				{name: "time range", opts: ScanOptions{From: 150, To: 300}, want: []string{"b", "c"}},
+				{name: "source and time", opts: ScanOptions{Sources: []string{"HTTP-server"}, To: 200}, want: []string{"a"}},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					if got := scanIDs(t, s, tt.opts); !reflect.DeepEqual(got, tt.want) {
+						t.Errorf("expected %v, got %v", tt.want, got)
+					}
+				})
+			}
+
+			if err := s.Delete("http_logs.json", "a"); err != nil {
+				t.Fatalf("failed to delete: %v", err)
+			}
+			if err := s.Delete("http_logs.json", "a"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("expected second delete to fail with ErrNotFound, got %v", err)
+			}
+			if _, err := s.Get("http_logs.json", "a"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("expected deleted record to be gone, got %v", err)
+			}
+			if got := scanIDs(t, s, ScanOptions{From: 1}); !reflect.DeepEqual(got, []string{"b", "c"}) {
+				t.Errorf("expected deleted record to leave indexes, got %v", got)
+			}
+		})
+	}
+}
+
+func TestScanByRequest(t *testing.T) {
+	for _, backend := range backends {
+		t.Run(backend, func(t *testing.T) {
+			s := openStore(t, backend)
+			s.Append("http_logs.json", []byte(`{"id":"req","request_id":"req","path":"/process","timestamp_received":100}`))
+			s.Append("http_logs.json", []byte(`{"id":"other","request_id":"other","path":"/process","timestamp_received":150}`))
+			s.Append("business_logs.json", []byte(`{"id":"res","request_id":"req","parent_id":"req","timestamp_received":200}`))
+			// Старая запись без request_id относится к запросу через path
+			s.Append("business_logs.json", []byte(`{"id":"legacy","path":"req","timestamp_received":50}`))
+
+			if got := scanIDs(t, s, ScanOptions{RequestID: "req"}); !reflect.DeepEqual(got, []string{"legacy", "req", "res"}) {
+				t.Errorf("expected records of req, got %v", got)
+			}
+			if got := scanIDs(t, s, ScanOptions{RequestID: "req", Collections: []string{"business_logs.json"}, From: 100}); !reflect.DeepEqual(got, []string{"res"}) {
+				t.Errorf("expected request filter to combine with the others, got %v", got)
+			}
+		})
+	}
+}
+
+func TestScanStopsEarly(t *testing.T) {
+	for _, backend := range backends {
+		t.Run(backend, func(t *testing.T) {
+			s := openStore(t, backend)
+			// Больше пачки bbolt, чтобы остановка проверялась и между пачками
+			for i := 0; i < boltScanBatch+10; i++ {
+				if err := s.Append("http_logs.json", record(fmt.Sprintf("id%05d", i), "HTTP-server", int64(i+1))); err != nil {
+					t.Fatalf("failed to append: %v", err)
+				}
+			}
+
+			for _, opts := range []ScanOptions{{}, {From: 1}} {
+				seen := 0
+				if err := s.Scan(opts, func(Record) bool {
+					seen++
+					return seen < boltScanBatch+5
+				}); err != nil {
+					t.Fatalf("failed to scan: %v", err)
+				}
+				if seen != boltScanBatch+5 {
+					t.Errorf("expected scan with %+v to stop after %d records, got %d", opts, boltScanBatch+5, seen)
+				}
+			}
+		})
+	}
+}
+
+func TestScanByTime(t *testing.T) {
+	for _, backend := range []string{BackendFile, BackendMemory} {
+		t.Run(backend, func(t *testing.T) {
+			s := openStore(t, backend)
+			scanner, ok := s.(TimeScanner)
+			if !ok {
+				t.Fatalf("expected %s store to scan by time", backend)
+			}
+			// Больше пачки чтения, записи одной миллисекунды упорядочены по id
+			var want []string
+			for i := 0; i < fileScanBatch+10; i++ {
+				collection := []string{"http_logs.json", "business_logs.json"}[i%2]
+				id := fmt.Sprintf("id%05d", fileScanBatch+10-i)
+				if err := s.Append(collection, record(id, "HTTP-server", int64(i/2))); err != nil {
+					t.Fatalf("failed to append: %v", err)
+				}
+			}
+			for ts := 0; ts < (fileScanBatch+10)/2; ts++ {
+				a, b := fmt.Sprintf("id%05d", fileScanBatch+10-2*ts), fmt.Sprintf("id%05d", fileScanBatch+10-2*ts-1)
+				want = append(want, b, a)
+			}
+
+			scan := func(opts ScanOptions, descending bool, limit int) []string {
+				var ids []string
+				if err := scanner.ScanByTime(opts, descending, func(r Record) bool {
+					ids = append(ids, r.ID)
+					return len(ids) < limit
+				}); err != nil {
+					t.Fatalf("failed to scan: %v", err)
+				}
+				return ids
+			}
+
+			if got := scan(ScanOptions{}, false, len(want)+1); !reflect.DeepEqual(got, want) {
+				t.Errorf("expected records by time, got %v", got)
+			}
+			reversed := slices.Clone(want)
+			slices.Reverse(reversed)
+			if got := scan(ScanOptions{}, true, 3); !reflect.DeepEqual(got, reversed[:3]) {
+				t.Errorf("expected the newest records first, got %v", got)
+			}
+			if got := scan(ScanOptions{From: 10, To: 11, Collections: []string{"http_logs.json"}}, false, 10); !reflect.DeepEqual(got, []string{want[21], want[23]}) {
+				t.Errorf("expected a time range of one collection, got %v", got)
+			}
+		})
+	}
+}
+
+func TestStoreReopen(t *testing.T) {
+	for _, backend := range []string{BackendFile, BackendBolt} {
+		t.Run(backend, func(t *testing.T) {
+			path := DefaultPath(backend, t.TempDir())
+			s, err := Open(backend, path)
+			if err != nil {
+				t.Fatalf("failed to open store: %v", err)
+			}
+			s.Append("http_logs.json", record("a", "HTTP-server", 100))
+			s.Append("http_logs.json", record("b", "HTTP-server", 200))
+			s.Delete("http_logs.json", "a")
+			if err := s.Close(); err != nil {
+				t.Fatalf("failed to close store: %v", err)
+			}
+
+			reopened, err := Open(backend, path)
+			if err != nil {
+				t.Fatalf("failed to reopen store: %v", err)
+			}
+			defer reopened.Close()
+			if got := scanIDs(t, reopened, ScanOptions{}); !reflect.DeepEqual(got, []string{"b"}) {
+				t.Errorf("expected only b to survive reopening, got %v", got)
+			}
+		})
+	}
+}
+
+func TestFileStoreRejectsPaths(t *testing.T) {
+	s := openStore(t, BackendFile)
+	for _, name := range []string{"../http_logs.json", filepath.Join("sub", "x.json"), ".hidden", "http_logs.json.idx", "logs.db"} {
+		if _, err := s.Get(name, "a"); !errors.Is(err, ErrUnknownCollection) {
+			t.Errorf("expected %q to be rejected, got %v", name, err)
+		}
+		if err := s.Append(name, record("a", "HTTP-server", 1)); !errors.Is(err, ErrUnknownCollection) {
+			t.Errorf("expected append to %q to be rejected, got %v", name, err)
+		}
+	}
+}
+
+func TestStoreRejectsDuplicateID(t *testing.T) {
+	for _, backend := range backends {
+		t.Run(backend, func(t *testing.T) {
+			s := openStore(t, backend)
+			if err := s.Append("http_logs.json", record("a", "HTTP-server", 100)); err != nil {
+				t.Fatalf("failed to append: %v", err)
+			}
+
+			// Id уникален во всём хранилище, а не только в коллекции
+			for _, collection := range []string{"http_logs.json", "business_logs.json"} {
+				if err := s.Append(collection, record("a", "business-server", 200)); !errors.Is(err, ErrDuplicateID) {
+					t.Errorf("expected duplicate id in %s to be rejected, got %v", collection, err)
+				}
+			}
+			if err := s.AppendBatch("http_logs.json", [][]byte{record("b", "HTTP-server", 300), record("b", "HTTP-server", 400)}); !errors.Is(err, ErrDuplicateID) {
+				t.Errorf("expected id repeated within a batch to be rejected, got %v", err)
+			}
+			if data, err := s.Get("http_logs.json", "a"); err != nil || string(data) != string(record("a", "HTTP-server", 100)) {
+				t.Errorf("expected the first record to stay, got %q, %v", data, err)
+			}
+
+			// Удалённый id можно записать снова, например при восстановлении из корзины
+			if err := s.Delete("http_logs.json", "a"); err != nil {
+				t.Fatalf("failed to delete: %v", err)
+			}
+			if err := s.Append("http_logs.json", record("a", "HTTP-server", 500)); err != nil {
+				t.Errorf("expected deleted id to be accepted again, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	src := openStore(t, BackendFile)
+	dst := openStore(t, BackendBolt)
+	src.Append("http_logs.json", record("a", "HTTP-server", 100))
+	src.Append("business_logs.json", record("c", "business-server", 200))
+	// Запись уже перенесена прерванной миграцией
+	dst.Append("http_logs.json", record("a", "HTTP-server", 100))
+
+	copied, skipped, err := Migrate(src, dst)
+	if err != nil || copied != 1 || skipped != 1 {
+		t.Fatalf("expected 1 copied and 1 skipped, got %d, %d, %v", copied, skipped, err)
+	}
+	if data, err := dst.Get("business_logs.json", "c"); err != nil || string(data) != string(record("c", "business-server", 200)) {
+		t.Errorf("expected c to be migrated, got %q, %v", data, err)
+	}
+	if copied, skipped, _ := Migrate(src, dst); copied != 0 || skipped != 2 {
+		t.Errorf("expected repeated migration to skip everything, got %d copied, %d skipped", copied, skipped)
+	}
+}
+
+func TestOpenUnknownBackend(t *testing.T) {
+	if _, err := Open("redis", t.TempDir()); !errors.Is(err, ErrUnknownBackend) {
+		t.Errorf("expected ErrUnknownBackend, got %v", err)
+	}
+}
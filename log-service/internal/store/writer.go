@@ -0,0 +1,29 @@
+package store
+
+import "bytes"
+
+// Writer направляет вывод zap в коллекцию хранилища: каждая строка — одна запись.
+type Writer struct {
+	store      LogStore
+	collection string
+}
+
+func NewWriter(s LogStore, collection string) *Writer {
+	return &Writer{store: s, collection: collection}
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(p, []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if err := w.store.Append(w.collection, bytes.Clone(line)); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (w *Writer) Sync() error {
+	return w.store.Sync()
+}
@@ -0,0 +1,135 @@
+package tail
+
+import (
+	"bytes"
+	"log-service/internal/metrics"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultBuffer — сколько записей ждёт подписчика, прежде чем новые
+// начнут пропускаться
+const DefaultBuffer = 256
+
+// Entry — запись коллекции в том виде, в каком она сохранена.
+type Entry struct {
+	Collection string
+	Line       []byte
+}
+
+// Hub раздаёт новые записи подписчикам живого потока. Публикация никогда
+// не ждёт подписчика: у каждого свой буфер, и если он заполнен, запись для
+// этого подписчика пропускается и учитывается в Dropped.
+type Hub struct {
+	mu     sync.RWMutex
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: make(map[*Subscription]struct{})}
+}
+
+// Subscription получает записи, для которых match вернул true.
+type Subscription struct {
+	hub     *Hub
+	match   func(collection string, line []byte) bool
+	entries chan Entry
+	dropped atomic.Int64
+	once    sync.Once
+}
+
+// Subscribe регистрирует подписчика с буфером на buffer записей. Канал
+// Entries закрывается при Unsubscribe или закрытии хаба.
+func (h *Hub) Subscribe(match func(collection string, line []byte) bool, buffer int) *Subscription {
+	if buffer <= 0 {
+		buffer = DefaultBuffer
+	}
+	s := &Subscription{hub: h, match: match, entries: make(chan Entry, buffer)}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(s.entries)
+		return s
+	}
+	h.subs[s] = struct{}{}
+	metrics.TailSubscribers.Inc()
+	return s
+}
+
+func (s *Subscription) Entries() <-chan Entry {
+	return s.entries
+}
+
+// Dropped возвращает число пропущенных с прошлого вызова записей и обнуляет его.
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Swap(0)
+}
+
+func (s *Subscription) Unsubscribe() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	if _, ok := s.hub.subs[s]; ok {
+		delete(s.hub.subs, s)
+		metrics.TailSubscribers.Dec()
+		s.close()
+	}
+}
+
+func (s *Subscription) close() {
+	s.once.Do(func() { close(s.entries) })
+}
+
+// Publish отправляет запись всем подходящим подписчикам.
+func (h *Hub) Publish(collection string, line []byte) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for s := range h.subs {
+		if s.match != nil && !s.match(collection, line) {
+			continue
+		}
+		select {
+		case s.entries <- Entry{Collection: collection, Line: line}:
+		default:
+			s.dropped.Add(1)
+			metrics.TailDropped.Inc()
+		}
+	}
+}
+
+// Close отписывает всех: их потоки завершаются.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for s := range h.subs {
+		delete(h.subs, s)
+		metrics.TailSubscribers.Dec()
+		s.close()
+	}
+}
+
+// Writer публикует вывод zap: каждая строка — одна запись коллекции.
+type Writer struct {
+	hub        *Hub
+	collection string
+}
+
+func (h *Hub) Writer(collection string) *Writer {
+	return &Writer{hub: h, collection: collection}
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(p, []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		w.hub.Publish(w.collection, bytes.Clone(line))
+	}
+	return len(p), nil
+}
+
+func (w *Writer) Sync() error {
+	return nil
+}
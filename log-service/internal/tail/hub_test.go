@@ -0,0 +1,62 @@
+package tail
+
+import (
+	"testing"
+)
+
+func TestSlowSubscriberDropsOnlyItsOwnEntries(t *testing.T) {
+	hub := NewHub()
+	defer hub.Close()
+
+	slow := hub.Subscribe(nil, 2)
+	fast := hub.Subscribe(nil, 10)
+	onlyBusiness := hub.Subscribe(func(collection string, line []byte) bool {
+		return collection == "business_logs.json"
+	}, 10)
+
+	w := hub.Writer("http_logs.json")
+	if _, err := w.Write([]byte("{\"id\":\"1\"}\n{\"id\":\"2\"}\n{\"id\":\"3\"}\n")); err != nil {
+		t.Fatalf("failed to publish: %v", err)
+	}
+	hub.Publish("business_logs.json", []byte(`{"id":"4"}`))
+
+	if got := len(slow.Entries()); got != 2 {
+		t.Errorf("expected slow subscriber to keep 2 entries, got %d", got)
+	}
+	if got := slow.Dropped(); got != 2 {
+		t.Errorf("expected slow subscriber to drop 2 entries, got %d", got)
+	}
+	if got := slow.Dropped(); got != 0 {
+		t.Errorf("expected dropped counter to reset, got %d", got)
+	}
+	if got := len(fast.Entries()); got != 4 || fast.Dropped() != 0 {
+		t.Errorf("expected fast subscriber to get all 4 entries, got %d", got)
+	}
+	if e := <-onlyBusiness.Entries(); e.Collection != "business_logs.json" || string(e.Line) != `{"id":"4"}` {
+		t.Errorf("expected only the business entry, got %+v", e)
+	}
+}
+
+func TestUnsubscribeAndCloseEndStreams(t *testing.T) {
+	hub := NewHub()
+	first := hub.Subscribe(nil, 1)
+	second := hub.Subscribe(nil, 1)
+
+	first.Unsubscribe()
+	first.Unsubscribe()
+	if _, ok := <-first.Entries(); ok {
+		t.Errorf("expected unsubscribed channel to be closed")
+	}
+
+	hub.Close()
+	if _, ok := <-second.Entries(); ok {
+		t.Errorf("expected channel to be closed with the hub")
+	}
+	second.Unsubscribe()
+
+	late := hub.Subscribe(nil, 1)
+	if _, ok := <-late.Entries(); ok {
+		t.Errorf("expected subscription to a closed hub to be closed")
+	}
+	hub.Publish("http_logs.json", []byte(`{}`))
+}
@@ -0,0 +1,60 @@
+package tracing
+
+import (
+	"context"
+	kafka "github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"log-service/gen"
+)
+
+// KafkaCarrier передаёт контекст trace в заголовках сообщения Kafka (traceparent, baggage).
+type KafkaCarrier struct {
+	Headers *[]kafka.Header
+}
+
+func (c KafkaCarrier) Get(key string) string {
+	for _, h := range *c.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c KafkaCarrier) Set(key, value string) {
+	for i, h := range *c.Headers {
+		if h.Key == key {
+			(*c.Headers)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c.Headers = append(*c.Headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c KafkaCarrier) Keys() []string {
+	keys := make([]string, 0, len(*c.Headers))
+	for _, h := range *c.Headers {
+		keys = append(keys, h.Key)
+	}
+	return keys
+}
+
+// InjectKafka записывает контекст trace из ctx в заголовки msg.
+func InjectKafka(ctx context.Context, msg *kafka.Message) {
+	otel.GetTextMapPropagator().Inject(ctx, KafkaCarrier{Headers: &msg.Headers})
+}
+
+// InjectMetadata сохраняет контекст trace в метаданных записи: через outbox
+// он доходит до отправки в Kafka вместе с самой записью.
+func InjectMetadata(ctx context.Context, entry *gen.LogEntry) {
+	if entry.Metadata == nil {
+		entry.Metadata = make(map[string]string)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(entry.Metadata))
+}
+
+// ExtractMetadata восстанавливает контекст trace, сохранённый InjectMetadata.
+func ExtractMetadata(ctx context.Context, entry *gen.LogEntry) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(entry.GetMetadata()))
+}
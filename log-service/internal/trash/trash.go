@@ -0,0 +1,316 @@
+package trash
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"log-service/internal/metrics"
+	"log-service/internal/store"
+	"os"
+	"sync"
+	"time"
+)
+
+// Как часто корзина стирает записи с истёкшим сроком восстановления
+const purgeInterval = 10 * time.Minute
+
+var (
+	ErrNotDeleted   = errors.New("log is not in trash")
+	ErrHashMismatch = errors.New("deleted log does not match its recorded hash")
+)
+
+const (
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+	ActionPurge   = "purge"
+)
+
+// AuditRecord — строка журнала аудита: удаление, восстановление или
+// окончательное стирание записи.
+type AuditRecord struct {
+	Time       time.Time `json:"time"`
+	Action     string    `json:"action"`
+	Collection string    `json:"collection"`
+	ID         string    `json:"id"`
+	Actor      string    `json:"actor"`
+	Reason     string    `json:"reason,omitempty"`
+	// sha256 исходной записи
+	Hash string `json:"hash"`
+}
+
+// deleted — запись корзины. id, source и timestamp_received нужны хранилищу;
+// timestamp_received — время удаления, по нему корзина находит записи для стирания.
+type deleted struct {
+	ID        string `json:"id"`
+	Source    string `json:"source"`
+	DeletedAt int64  `json:"timestamp_received"`
+	DeletedBy string `json:"deleted_by"`
+	Reason    string `json:"reason,omitempty"`
+	Hash      string `json:"hash"`
+	// Исходная запись строкой: так она восстанавливается байт в байт
+	Record string `json:"record"`
+}
+
+// Trash делает удаление обратимым: запись переносится из хранилища логов в
+// корзину — отдельное хранилище тех же коллекций — и стирается из неё
+// окончательно через grace. Каждое удаление, восстановление и стирание
+// пишется в журнал аудита (JSON lines).
+type Trash struct {
+	logs  store.LogStore
+	bin   store.LogStore
+	grace time.Duration
+	now   func() time.Time
+
+	// Удаление и восстановление одной записи не должны пересекаться
+	mu    sync.Mutex
+	audit *os.File
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// New открывает журнал аудита auditPath и запускает фоновое стирание записей
+// корзины bin старше grace.
+func New(logs, bin store.LogStore, auditPath string, grace time.Duration) (*Trash, error) {
+	audit, err := os.OpenFile(auditPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	t := &Trash{
+		logs:  logs,
+		bin:   bin,
+		grace: grace,
+		now:   time.Now,
+		audit: audit,
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go t.purgeLoop()
+	return t, nil
+}
+
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Ref указывает на запись коллекции.
+type Ref struct {
+	Collection string
+	ID         string
+}
+
+// Delete переносит запись id коллекции в корзину и возвращает время, когда
+// она будет стёрта окончательно.
+func (t *Trash) Delete(collection, id, actor, reason string) (time.Time, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	r, err := t.moveToBin(Ref{Collection: collection, ID: id}, actor, reason, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := t.record(r); err != nil {
+		return time.Time{}, err
+	}
+	return now.Add(t.grace), nil
+}
+
+// DeleteBatch переносит в корзину записи refs и сбрасывает журнал аудита на
+// диск один раз на пачку. Записи, которых уже нет, пропускаются; возвращается
+// число перенесённых.
+func (t *Trash) DeleteBatch(refs []Ref, actor, reason string) (int, time.Time, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	var audit []AuditRecord
+	var err error
+	for _, ref := range refs {
+		r, moveErr := t.moveToBin(ref, actor, reason, now)
+		if errors.Is(moveErr, store.ErrNotFound) {
+			continue
+		}
+		if moveErr != nil {
+			err = moveErr
+			break
+		}
+		audit = append(audit, r)
+	}
+	// Уже перенесённые записи аудируются и при ошибке посреди пачки
+	if len(audit) > 0 {
+		err = errors.Join(err, t.record(audit...))
+	}
+	return len(audit), now.Add(t.grace), err
+}
+
+// moveToBin копирует запись в корзину и удаляет её из логов. Вызывается под t.mu.
+func (t *Trash) moveToBin(ref Ref, actor, reason string, now time.Time) (AuditRecord, error) {
+	data, err := t.logs.Get(ref.Collection, ref.ID)
+	if err != nil {
+		return AuditRecord{}, err
+	}
+	var fields struct {
+		Source string `json:"source"`
+	}
+	json.Unmarshal(data, &fields)
+
+	d := deleted{
+		ID:        ref.ID,
+		Source:    fields.Source,
+		DeletedAt: now.UnixMilli(),
+		DeletedBy: actor,
+		Reason:    reason,
+		Hash:      hash(data),
+		Record:    string(data),
+	}
+	line, err := json.Marshal(d)
+	if err != nil {
+		return AuditRecord{}, fmt.Errorf("failed to move log to trash: %w", err)
+	}
+
+	// Сначала копия в корзине: после сбоя между шагами запись остаётся в
+	// логах, и повторное удаление заменит копию
+	err = t.bin.Append(ref.Collection, line)
+	if errors.Is(err, store.ErrDuplicateID) {
+		if err = t.bin.Delete(ref.Collection, ref.ID); err == nil {
+			err = t.bin.Append(ref.Collection, line)
+		}
+	}
+	if err != nil {
+		return AuditRecord{}, fmt.Errorf("failed to move log to trash: %w", err)
+	}
+	if err := t.logs.Delete(ref.Collection, ref.ID); err != nil {
+		return AuditRecord{}, err
+	}
+	metrics.LogsSoftDeleted.WithLabelValues(ref.Collection).Inc()
+	return AuditRecord{Time: now, Action: ActionDelete, Collection: ref.Collection, ID: ref.ID, Actor: actor, Reason: reason, Hash: d.Hash}, nil
+}
+
+// Restore возвращает запись id из корзины в коллекцию. Запись, которая уже
+// стёрта, восстановить нельзя (ErrNotDeleted).
+func (t *Trash) Restore(collection, id, actor, reason string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	line, err := t.bin.Get(collection, id)
+	if errors.Is(err, store.ErrNotFound) || errors.Is(err, store.ErrUnknownCollection) {
+		return ErrNotDeleted
+	}
+	if err != nil {
+		return err
+	}
+	var d deleted
+	if err := json.Unmarshal(line, &d); err != nil {
+		return fmt.Errorf("invalid trash record: %w", err)
+	}
+	if hash([]byte(d.Record)) != d.Hash {
+		return ErrHashMismatch
+	}
+
+	// Запись уже в логах, если восстановление прервалось после возврата
+	if _, err := t.logs.Get(collection, id); errors.Is(err, store.ErrNotFound) || errors.Is(err, store.ErrUnknownCollection) {
+		if err := t.logs.Append(collection, []byte(d.Record)); err != nil {
+			return fmt.Errorf("failed to restore log: %w", err)
+		}
+	} else if err != nil {
+		return err
+	}
+	if err := t.bin.Delete(collection, id); err != nil {
+		return err
+	}
+	if err := t.record(AuditRecord{Time: t.now(), Action: ActionRestore, Collection: collection, ID: id, Actor: actor, Reason: reason, Hash: d.Hash}); err != nil {
+		return err
+	}
+	metrics.LogsRestored.WithLabelValues(collection).Inc()
+	return nil
+}
+
+// Purge стирает из корзины записи, удалённые раньше now-grace, и возвращает их число.
+func (t *Trash) Purge(now time.Time) (int, error) {
+	var expired []store.Record
+	err := t.bin.Scan(store.ScanOptions{From: 1, To: now.Add(-t.grace).UnixMilli()}, func(r store.Record) bool {
+		expired = append(expired, r)
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	purged := 0
+	var errs []error
+	var audit []AuditRecord
+	for _, r := range expired {
+		var d deleted
+		json.Unmarshal(r.Data, &d)
+		err := t.bin.Delete(r.Collection, r.ID)
+		if errors.Is(err, store.ErrNotFound) {
+			continue // Восстановлена, пока собирался список
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		purged++
+		metrics.LogsPurged.WithLabelValues(r.Collection).Inc()
+		audit = append(audit, AuditRecord{Time: now, Action: ActionPurge, Collection: r.Collection, ID: r.ID, Actor: "system", Reason: "grace period expired", Hash: d.Hash})
+	}
+	if len(audit) > 0 {
+		errs = append(errs, t.record(audit...))
+	}
+	return purged, errors.Join(errs...)
+}
+
+func (t *Trash) purgeLoop() {
+	defer close(t.done)
+
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-ticker.C:
+		}
+		if n, err := t.Purge(t.now()); err != nil {
+			log.Printf("Trash purge failed: %v", err)
+		} else if n > 0 {
+			log.Printf("Purged %d deleted logs", n)
+		}
+	}
+}
+
+// record дописывает записи в журнал аудита и сбрасывает его на диск. Вызывается под t.mu.
+func (t *Trash) record(records ...AuditRecord) error {
+	var buf []byte
+	for _, r := range records {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+	if _, err := t.audit.Write(buf); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return t.audit.Sync()
+}
+
+// Close останавливает фоновое стирание и закрывает корзину и журнал аудита.
+// Хранилище логов закрывает его владелец.
+func (t *Trash) Close() error {
+	t.closeOnce.Do(func() { close(t.stop) })
+	<-t.done
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return errors.Join(t.bin.Close(), t.audit.Close())
+}
@@ -0,0 +1,124 @@
+package trash
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"log-service/internal/store"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const original = `{"level":"info","id":"a","message":"{\"op\":\"<sum>\"}","source":"HTTP-server","timestamp_received":100}`
+
+func newTrash(t *testing.T, backend string) (*Trash, store.LogStore, string) {
+	t.Helper()
+	dir := t.TempDir()
+	logs, err := store.Open(backend, store.DefaultPath(backend, dir))
+	if err != nil {
+		t.Fatalf("failed to open log store: %v", err)
+	}
+	bin, err := store.Open(backend, store.DefaultPath(backend, filepath.Join(dir, "trash")))
+	if err != nil {
+		t.Fatalf("failed to open trash store: %v", err)
+	}
+	audit := filepath.Join(dir, "audit.jsonl")
+	tr, err := New(logs, bin, audit, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to open trash: %v", err)
+	}
+	t.Cleanup(func() {
+		tr.Close()
+		logs.Close()
+	})
+	if err := logs.Append("http_logs.json", []byte(original)); err != nil {
+		t.Fatalf("failed to append: %v", err)
+	}
+	return tr, logs, audit
+}
+
+func readAudit(t *testing.T, path string) []AuditRecord {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	defer file.Close()
+
+	var records []AuditRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var r AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("invalid audit line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+func TestDeleteAndRestore(t *testing.T) {
+	for _, backend := range []string{store.BackendFile, store.BackendBolt, store.BackendMemory} {
+		t.Run(backend, func(t *testing.T) {
+			tr, logs, audit := newTrash(t, backend)
+			now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+			tr.now = func() time.Time { return now }
+
+			purgeAt, err := tr.Delete("http_logs.json", "a", "ops", "GDPR request")
+			if err != nil || !purgeAt.Equal(now.Add(time.Hour)) {
+				t.Fatalf("expected log to be purged after the grace period, got %v, %v", purgeAt, err)
+			}
+			if _, err := logs.Get("http_logs.json", "a"); !errors.Is(err, store.ErrNotFound) {
+				t.Errorf("expected deleted log to be hidden, got %v", err)
+			}
+			if _, err := tr.Delete("http_logs.json", "a", "ops", ""); !errors.Is(err, store.ErrNotFound) {
+				t.Errorf("expected second delete to fail with ErrNotFound, got %v", err)
+			}
+
+			if err := tr.Restore("http_logs.json", "a", "admin", "deleted by mistake"); err != nil {
+				t.Fatalf("failed to restore: %v", err)
+			}
+			if data, err := logs.Get("http_logs.json", "a"); err != nil || string(data) != original {
+				t.Errorf("expected log to be restored byte for byte, got %q, %v", data, err)
+			}
+			if err := tr.Restore("http_logs.json", "a", "admin", ""); !errors.Is(err, ErrNotDeleted) {
+				t.Errorf("expected second restore to fail with ErrNotDeleted, got %v", err)
+			}
+
+			want := []AuditRecord{
+				{Time: now, Action: ActionDelete, Collection: "http_logs.json", ID: "a", Actor: "ops", Reason: "GDPR request", Hash: hash([]byte(original))},
+				{Time: now, Action: ActionRestore, Collection: "http_logs.json", ID: "a", Actor: "admin", Reason: "deleted by mistake", Hash: hash([]byte(original))},
+			}
+			if got := readAudit(t, audit); !reflect.DeepEqual(got, want) {
+				t.Errorf("expected audit %+v, got %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestPurgeAfterGracePeriod(t *testing.T) {
+	tr, _, audit := newTrash(t, store.BackendFile)
+	now := time.Now()
+	tr.now = func() time.Time { return now }
+
+	if _, err := tr.Delete("http_logs.json", "a", "ops", ""); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if n, err := tr.Purge(now.Add(30 * time.Minute)); err != nil || n != 0 {
+		t.Fatalf("expected nothing to be purged within the grace period, got %d, %v", n, err)
+	}
+	if n, err := tr.Purge(now.Add(2 * time.Hour)); err != nil || n != 1 {
+		t.Fatalf("expected the log to be purged, got %d, %v", n, err)
+	}
+	if err := tr.Restore("http_logs.json", "a", "admin", ""); !errors.Is(err, ErrNotDeleted) {
+		t.Errorf("expected purged log not to be restorable, got %v", err)
+	}
+
+	records := readAudit(t, audit)
+	if len(records) != 2 || records[1].Action != ActionPurge || records[1].Hash != hash([]byte(original)) {
+		t.Errorf("expected purge to be audited with the original hash, got %+v", records)
+	}
+}
@@ -1,12 +1,135 @@
 package utils
 
-import "math/rand"
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"strings"
+	"sync"
+	"time"
+)
 
-func GenerateID(logLength int) string {
-	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	logLengthBytes := make([]rune, logLength)
-	for i := range logLengthBytes {
-		logLengthBytes[i] = rune(letters[rand.Intn(len(letters))])
+// IDGenerator выдаёт id новых записей.
+type IDGenerator interface {
+	NewID() string
+}
+
+// Алфавит base32 Crockford: без I, L, O и U, порядок символов совпадает с порядком значений
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULIDLength — длина id в формате ULID
+const ULIDLength = 26
+
+// ULIDGenerator выдаёт id в формате ULID: 48 бит времени в миллисекундах и
+// 80 бит случайности в base32 Crockford. Строки сравниваются в порядке времени,
+// а id одного генератора строго возрастают: в пределах миллисекунды случайная
+// часть увеличивается на единицу.
+type ULIDGenerator struct {
+	// Номер узла занимает первые два байта случайной части, так что id
+	// экземпляров с разными номерами не совпадают
+	node    uint16
+	hasNode bool
+	now     func() time.Time
+
+	mu     sync.Mutex
+	lastMs uint64
+	last   [10]byte
+}
+
+func NewULIDGenerator() *ULIDGenerator {
+	return &ULIDGenerator{now: time.Now}
+}
+
+// NewNodeULIDGenerator выдаёт id, помеченные номером узла node.
+func NewNodeULIDGenerator(node uint16) *ULIDGenerator {
+	return &ULIDGenerator{node: node, hasNode: true, now: time.Now}
+}
+
+func (g *ULIDGenerator) NewID() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := uint64(g.now().UnixMilli())
+	if ms <= g.lastMs && g.increment() {
+		ms = g.lastMs
+	} else {
+		if ms <= g.lastMs {
+			// Случайная часть исчерпана: id уходит в следующую миллисекунду
+			ms = g.lastMs + 1
+		}
+		g.lastMs = ms
+		g.randomize()
+	}
+
+	var id [16]byte
+	binary.BigEndian.PutUint64(id[:8], ms<<16)
+	copy(id[6:], g.last[:])
+	return encodeULID(id)
+}
+
+// randomize заполняет случайную часть, сохраняя номер узла.
+func (g *ULIDGenerator) randomize() {
+	_, _ = rand.Read(g.last[:])
+	if g.hasNode {
+		binary.BigEndian.PutUint16(g.last[:2], g.node)
+	}
+}
+
+// increment увеличивает случайную часть на единицу и возвращает false при переполнении.
+func (g *ULIDGenerator) increment() bool {
+	start := 0
+	if g.hasNode {
+		start = 2
+	}
+	for i := len(g.last) - 1; i >= start; i-- {
+		g.last[i]++
+		if g.last[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeULID записывает 128 бит id 26 символами по 5 бит, старшие два бита первого символа нулевые.
+func encodeULID(id [16]byte) string {
+	bit := func(n int) byte {
+		if n >= 128 {
+			return 0
+		}
+		return id[15-n/8] >> (n % 8) & 1
+	}
+	var out [ULIDLength]byte
+	for i := range out {
+		low := 5 * (ULIDLength - 1 - i)
+		var v byte
+		for j := 4; j >= 0; j-- {
+			v = v<<1 | bit(low+j)
+		}
+		out[i] = crockford[v]
+	}
+	return string(out[:])
+}
+
+// IsULID сообщает, что id выдан ULIDGenerator; такие id упорядочены по времени.
+func IsULID(id string) bool {
+	if len(id) != ULIDLength || id[0] > '7' {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if strings.IndexByte(crockford, id[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ULIDTime возвращает время, записанное в id, или false, если id не в формате ULID.
+func ULIDTime(id string) (time.Time, bool) {
+	if !IsULID(id) {
+		return time.Time{}, false
+	}
+	var ms int64
+	for i := 0; i < 10; i++ {
+		ms = ms<<5 | int64(strings.IndexByte(crockford, id[i]))
 	}
-	return string(logLengthBytes)
+	return time.UnixMilli(ms), true
 }
@@ -1,46 +1,111 @@
 package utils
 
 import (
-	"strings"
+	"sort"
 	"testing"
+	"time"
 )
 
-func TestGenerateID(t *testing.T) {
+func TestULIDGenerator(t *testing.T) {
+	clock := time.UnixMilli(1748736000000)
 	tests := []struct {
-		name      string
-		length    int
-		expectLen int
+		name string
+		gen  *ULIDGenerator
 	}{
-		{
-			name:      "generate id of length 10",
-			length:    10,
-			expectLen: 10,
-		},
-		{
-			name:      "generate id of length 0",
-			length:    0,
-			expectLen: 0,
-		},
-		{
-			name:      "generate id of length 100",
-			length:    100,
-			expectLen: 100,
-		},
+		{name: "without node", gen: NewULIDGenerator()},
+		{name: "with node", gen: NewNodeULIDGenerator(0xBEEF)},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			id := GenerateID(tt.length)
-			if len(id) != tt.expectLen {
-				t.Errorf("expected length %d, got %d", tt.expectLen, len(id))
+			now := clock
+			tt.gen.now = func() time.Time { return now }
+
+			var ids []string
+			for i := 0; i < 1000; i++ {
+				// Часы стоят, идут и отступают назад
+				switch i % 3 {
+				case 1:
+					now = now.Add(time.Millisecond)
+				case 2:
+					now = now.Add(-2 * time.Millisecond)
+				}
+				ids = append(ids, tt.gen.NewID())
 			}
 
-			// Проверка допустимых символов
-			const allowed = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-			for _, r := range id {
-				if !strings.ContainsRune(allowed, r) {
-					t.Errorf("id contains invalid character: %q", r)
+			if !sort.StringsAreSorted(ids) {
+				t.Errorf("expected ids of one generator to increase")
+			}
+			seen := map[string]bool{}
+			for _, id := range ids {
+				if !IsULID(id) {
+					t.Fatalf("expected %q to be a ULID", id)
+				}
+				if seen[id] {
+					t.Fatalf("duplicate id %q", id)
 				}
+				seen[id] = true
+			}
+
+			first, ok := ULIDTime(ids[0])
+			if !ok || !first.Equal(clock) {
+				t.Errorf("expected first id to carry %v, got %v", clock, first)
+			}
+		})
+	}
+}
+
+func TestULIDGeneratorNode(t *testing.T) {
+	a, b := NewNodeULIDGenerator(1), NewNodeULIDGenerator(2)
+	now := func() time.Time { return time.UnixMilli(1748736000000) }
+	a.now, b.now = now, now
+
+	// Номер узла идёт сразу за временем: в одну миллисекунду id первого узла меньше
+	if idA, idB := a.NewID(), b.NewID(); idA[:14] == idB[:14] || idA >= idB {
+		t.Errorf("expected node to separate ids, got %s and %s", idA, idB)
+	}
+}
+
+func TestULIDGeneratorOverflow(t *testing.T) {
+	g := NewNodeULIDGenerator(7)
+	g.now = func() time.Time { return time.UnixMilli(1000) }
+	first := g.NewID()
+	for i := 2; i < len(g.last); i++ {
+		g.last[i] = 0xFF
+	}
+
+	next := g.NewID()
+	if next <= first {
+		t.Fatalf("expected id after overflow to increase, got %s after %s", next, first)
+	}
+	if ts, _ := ULIDTime(next); ts.UnixMilli() != 1001 {
+		t.Errorf("expected overflow to move to the next millisecond, got %d", ts.UnixMilli())
+	}
+}
+
+func TestULIDTime(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		ms   int64
+		ok   bool
+	}{
+		{name: "epoch", id: "00000000000000000000000000", ms: 0, ok: true},
+		{name: "maximum", id: "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", ms: 1<<48 - 1, ok: true},
+		{name: "known id", id: "01JWMCKG00ZZZZZZZZZZZZZZZZ", ms: 1748736000000, ok: true},
+		{name: "legacy id", id: "aB3dE5gH7j"},
+		{name: "overflowing first character", id: "80000000000000000000000000"},
+		{name: "letter outside alphabet", id: "0000000000000000000000000U"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, ok := ULIDTime(tt.id)
+			if ok != tt.ok {
+				t.Fatalf("expected ok %v, got %v", tt.ok, ok)
+			}
+			if ok && ts.UnixMilli() != tt.ms {
+				t.Errorf("expected %d, got %d", tt.ms, ts.UnixMilli())
 			}
 		})
 	}